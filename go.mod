@@ -2,4 +2,9 @@ module github.com/JaimeStill/go-agents
 
 go 1.25.2
 
-require github.com/google/uuid v1.6.0
+require (
+	github.com/BurntSushi/toml v1.6.0
+	github.com/dlclark/regexp2 v1.11.5
+	github.com/google/uuid v1.6.0
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -7,11 +7,14 @@ import (
 
 	"github.com/JaimeStill/go-agents/pkg/client"
 	"github.com/JaimeStill/go-agents/pkg/config"
+	"github.com/JaimeStill/go-agents/pkg/guardrails"
 	"github.com/JaimeStill/go-agents/pkg/model"
 	"github.com/JaimeStill/go-agents/pkg/protocol"
 	"github.com/JaimeStill/go-agents/pkg/providers"
 	"github.com/JaimeStill/go-agents/pkg/request"
 	"github.com/JaimeStill/go-agents/pkg/response"
+	"github.com/JaimeStill/go-agents/pkg/tokenizer"
+	"github.com/JaimeStill/go-agents/pkg/usage"
 	"github.com/google/uuid"
 )
 
@@ -63,6 +66,39 @@ type Agent interface {
 	// Embed executes an embeddings protocol request.
 	// Returns the parsed embeddings response or an error.
 	Embed(ctx context.Context, input string, opts ...map[string]any) (*response.EmbeddingsResponse, error)
+
+	// GenerateImage executes an image generation protocol request.
+	// Options include "size", "n", "quality", and "response_format" ("url" or "b64_json").
+	// Returns the parsed image generation response or an error.
+	GenerateImage(ctx context.Context, prompt string, opts ...map[string]any) (*response.ImageGenerationResponse, error)
+
+	// Transcribe executes a transcription protocol request for an audio recording.
+	// Filename names the upload; its extension identifies the audio format.
+	// Options include "language", "prompt", "response_format", and "timestamp_granularities".
+	// Returns the parsed transcription response or an error.
+	Transcribe(ctx context.Context, filename string, audio []byte, opts ...map[string]any) (*response.TranscriptionResponse, error)
+
+	// Speak executes a speech protocol request, synthesizing audio from text.
+	// Options include "voice", "response_format", and "speed".
+	// Returns the synthesized audio or an error.
+	Speak(ctx context.Context, text string, opts ...map[string]any) (*response.SpeechResponse, error)
+
+	// SpeakStream executes a streaming speech protocol request.
+	// Returns a channel of chunks carrying raw audio as it is synthesized, or an error.
+	SpeakStream(ctx context.Context, text string, opts ...map[string]any) (<-chan *response.StreamingChunk, error)
+
+	// Rerank orders documents by their relevance to query.
+	// Uses the rerank protocol when the model has a rerank capability and
+	// otherwise asks the model to score the documents over the chat protocol.
+	// Options include "top_n" and "return_documents".
+	// Returns results ordered by descending relevance or an error.
+	Rerank(ctx context.Context, query string, documents []string, opts ...map[string]any) (*response.RerankResponse, error)
+
+	// Moderate executes a moderation protocol request, classifying input
+	// against content policy categories. Guardrails are not applied, so agents
+	// can serve as moderators for guardrails.Moderation checks.
+	// Returns the parsed moderation response or an error.
+	Moderate(ctx context.Context, input string, opts ...map[string]any) (*response.ModerationResponse, error)
 }
 
 // agent implements the Agent interface.
@@ -72,28 +108,81 @@ type agent struct {
 	provider     providers.Provider
 	model        *model.Model
 	systemPrompt string
+	usage        *usage.Tracker
+	tokenizer    tokenizer.Tokenizer
+	clientOpts   []client.Option
+	guardrails   *guardrails.Guardrails
+}
+
+// Option configures optional agent behavior at creation time.
+type Option func(*agent)
+
+// WithUsage attaches a usage tracker to the agent.
+// Every successful request is recorded on the tracker, and requests are
+// rejected with usage.ErrBudgetExceeded once the tracker's budget is exhausted.
+func WithUsage(t *usage.Tracker) Option {
+	return func(a *agent) {
+		a.usage = t
+	}
+}
+
+// WithClientOptions passes options to the agent's client, such as
+// client.WithTransport or client.WithCache.
+func WithClientOptions(opts ...client.Option) Option {
+	return func(a *agent) {
+		a.clientOpts = append(a.clientOpts, opts...)
+	}
 }
 
 // New creates a new Agent from configuration.
 // Creates provider, model, and client from configuration.
 // Assigns a unique UUIDv7 identifier for orchestration and tracking.
 // Returns an error if provider creation fails.
-func New(cfg *config.AgentConfig) (Agent, error) {
+func New(cfg *config.AgentConfig, opts ...Option) (Agent, error) {
 	p, err := providers.Create(cfg.Provider)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create provider: %w", err)
 	}
 
 	m := model.New(cfg.Model)
-	c := client.New(cfg.Client)
 
-	return &agent{
+	a := &agent{
 		id:           uuid.Must(uuid.NewV7()).String(),
-		client:       c,
 		provider:     p,
 		model:        m,
 		systemPrompt: cfg.SystemPrompt,
-	}, nil
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	a.client = client.New(cfg.Client, a.clientOpts...)
+
+	if a.tokenizer == nil && a.contextEnabled() {
+		if m.Tokenizer != "" && m.Tokenizer != tokenizer.HeuristicName {
+			t, err := tokenizer.Get(m.Tokenizer)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load tokenizer: %w", err)
+			}
+			a.tokenizer = t
+		} else {
+			a.tokenizer = tokenizer.ForModel(m.Name, m.Tokenizer)
+		}
+	}
+
+	return a, nil
+}
+
+// NewFromProfile creates a new Agent from the named profile in profiles.
+// Returns an error wrapping config.ErrProfileNotFound if the profile is not defined.
+func NewFromProfile(profiles *config.Profiles, name string, opts ...Option) (Agent, error) {
+	cfg, err := profiles.Get(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load profile: %w", err)
+	}
+
+	return New(cfg, opts...)
 }
 
 func (a *agent) ID() string {
@@ -120,12 +209,25 @@ func (a *agent) Model() *model.Model {
 // Merges model's configured chat options with runtime opts.
 // Returns parsed ChatResponse or error.
 func (a *agent) Chat(ctx context.Context, prompt string, opts ...map[string]any) (*response.ChatResponse, error) {
-	messages := a.initMessages(prompt)
 	options := a.mergeOptions(protocol.Chat, opts...)
 
+	if err := a.checkSupport(protocol.Chat, false, options, nil); err != nil {
+		return nil, err
+	}
+
+	prompt, err := a.checkInput(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := a.preflight(a.initMessages(prompt), 0, options)
+	if err != nil {
+		return nil, err
+	}
+
 	req := request.NewChat(a.provider, a.model, messages, options)
 
-	result, err := a.client.Execute(ctx, req)
+	result, err := a.execute(ctx, req, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -135,21 +237,45 @@ func (a *agent) Chat(ctx context.Context, prompt string, opts ...map[string]any)
 		return nil, fmt.Errorf("unexpected response type: %T", result)
 	}
 
+	if err := a.checkChat(ctx, resp); err != nil {
+		return nil, err
+	}
+
 	return resp, nil
 }
 
 // ChatStream executes a streaming chat protocol request.
 // Merges model's configured chat options with runtime opts.
-// Automatically sets stream: true in options.
+// Automatically sets stream: true in options, and requests usage on the final
+// chunk when a usage tracker is attached.
 // Returns a channel of StreamingChunk or error.
 func (a *agent) ChatStream(ctx context.Context, prompt string, opts ...map[string]any) (<-chan *response.StreamingChunk, error) {
-	messages := a.initMessages(prompt)
 	options := a.mergeOptions(protocol.Chat, opts...)
 	options["stream"] = true
+	a.includeStreamUsage(options)
+
+	if err := a.checkSupport(protocol.Chat, true, options, nil); err != nil {
+		return nil, err
+	}
+
+	prompt, err := a.checkInput(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := a.preflight(a.initMessages(prompt), 0, options)
+	if err != nil {
+		return nil, err
+	}
 
 	req := request.NewChat(a.provider, a.model, messages, options)
 
-	return a.client.ExecuteStream(ctx, req)
+	stream, err := a.executeStream(ctx, req, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.checkStream(ctx, stream), nil
 }
 
 // Vision executes a vision protocol request with images.
@@ -158,7 +284,6 @@ func (a *agent) ChatStream(ctx context.Context, prompt string, opts ...map[strin
 // Extracts vision_options from opts if present, separating them from model options.
 // Returns parsed ChatResponse or error.
 func (a *agent) Vision(ctx context.Context, prompt string, images []string, opts ...map[string]any) (*response.ChatResponse, error) {
-	messages := a.initMessages(prompt)
 	options := a.mergeOptions(protocol.Vision, opts...)
 
 	// Extract vision_options
@@ -170,9 +295,23 @@ func (a *agent) Vision(ctx context.Context, prompt string, images []string, opts
 		}
 	}
 
+	if err := a.checkSupport(protocol.Vision, false, options, images); err != nil {
+		return nil, err
+	}
+
+	prompt, err := a.checkInput(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := a.preflight(a.initMessages(prompt), a.imageTokens(images, visionOptions), options)
+	if err != nil {
+		return nil, err
+	}
+
 	req := request.NewVision(a.provider, a.model, messages, images, visionOptions, options)
 
-	result, err := a.client.Execute(ctx, req)
+	result, err := a.execute(ctx, req, len(images))
 	if err != nil {
 		return nil, err
 	}
@@ -182,18 +321,23 @@ func (a *agent) Vision(ctx context.Context, prompt string, images []string, opts
 		return nil, fmt.Errorf("unexpected response type: %T", result)
 	}
 
+	if err := a.checkChat(ctx, resp); err != nil {
+		return nil, err
+	}
+
 	return resp, nil
 }
 
 // VisionStream executes a streaming vision protocol request with images.
 // Merges model's configured vision options with runtime opts.
 // Extracts vision_options from opts if present, separating them from model options.
-// Automatically sets stream: true in options.
+// Automatically sets stream: true in options, and requests usage on the final
+// chunk when a usage tracker is attached.
 // Returns a channel of StreamingChunk or error.
 func (a *agent) VisionStream(ctx context.Context, prompt string, images []string, opts ...map[string]any) (<-chan *response.StreamingChunk, error) {
-	messages := a.initMessages(prompt)
 	options := a.mergeOptions(protocol.Vision, opts...)
 	options["stream"] = true
+	a.includeStreamUsage(options)
 
 	// Extract vision_options
 	var visionOptions map[string]any
@@ -204,19 +348,43 @@ func (a *agent) VisionStream(ctx context.Context, prompt string, images []string
 		}
 	}
 
+	if err := a.checkSupport(protocol.Vision, true, options, images); err != nil {
+		return nil, err
+	}
+
+	prompt, err := a.checkInput(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := a.preflight(a.initMessages(prompt), a.imageTokens(images, visionOptions), options)
+	if err != nil {
+		return nil, err
+	}
+
 	req := request.NewVision(a.provider, a.model, messages, images, visionOptions, options)
 
-	return a.client.ExecuteStream(ctx, req)
+	stream, err := a.executeStream(ctx, req, len(images))
+	if err != nil {
+		return nil, err
+	}
+
+	return a.checkStream(ctx, stream), nil
 }
 
 // Tools executes a tools protocol request with function definitions.
 // Converts agent.Tool structs to providers.ToolDefinition format.
 // Merges model's configured tools options with runtime opts.
+// The "tool_choice" option accepts "auto", "none", "required", or a
+// *providers.ToolChoice, and "parallel_tool_calls" accepts a bool.
 // Returns parsed ToolsResponse with tool calls or error.
 func (a *agent) Tools(ctx context.Context, prompt string, tools []Tool, opts ...map[string]any) (*response.ToolsResponse, error) {
-	messages := a.initMessages(prompt)
 	options := a.mergeOptions(protocol.Tools, opts...)
 
+	if err := a.checkSupport(protocol.Tools, false, options, nil); err != nil {
+		return nil, err
+	}
+
 	// Convert agent.Tool to providers.ToolDefinition
 	toolDefs := make([]providers.ToolDefinition, len(tools))
 	for i, tool := range tools {
@@ -224,12 +392,23 @@ func (a *agent) Tools(ctx context.Context, prompt string, tools []Tool, opts ...
 			Name:        tool.Name,
 			Description: tool.Description,
 			Parameters:  tool.Parameters,
+			Strict:      tool.Strict,
 		}
 	}
 
+	prompt, err := a.checkInput(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := a.preflight(a.initMessages(prompt), a.toolTokens(toolDefs), options)
+	if err != nil {
+		return nil, err
+	}
+
 	req := request.NewTools(a.provider, a.model, messages, toolDefs, options)
 
-	result, err := a.client.Execute(ctx, req)
+	result, err := a.execute(ctx, req, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -239,6 +418,10 @@ func (a *agent) Tools(ctx context.Context, prompt string, tools []Tool, opts ...
 		return nil, fmt.Errorf("unexpected response type: %T", result)
 	}
 
+	if err := a.checkTools(ctx, resp); err != nil {
+		return nil, err
+	}
+
 	return resp, nil
 }
 
@@ -248,9 +431,23 @@ func (a *agent) Tools(ctx context.Context, prompt string, tools []Tool, opts ...
 func (a *agent) Embed(ctx context.Context, input string, opts ...map[string]any) (*response.EmbeddingsResponse, error) {
 	options := a.mergeOptions(protocol.Embeddings, opts...)
 
+	if err := a.checkSupport(protocol.Embeddings, false, options, nil); err != nil {
+		return nil, err
+	}
+
+	input, err := a.checkInput(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	input, err = a.preflightInput(input)
+	if err != nil {
+		return nil, err
+	}
+
 	req := request.NewEmbeddings(a.provider, a.model, input, options)
 
-	result, err := a.client.Execute(ctx, req)
+	result, err := a.execute(ctx, req, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -263,6 +460,288 @@ func (a *agent) Embed(ctx context.Context, input string, opts ...map[string]any)
 	return resp, nil
 }
 
+// GenerateImage executes an image generation protocol request.
+// Merges model's configured image_generation options with runtime opts.
+// The requested image count is recorded as images on the usage tracker.
+// Returns parsed ImageGenerationResponse or error.
+func (a *agent) GenerateImage(ctx context.Context, prompt string, opts ...map[string]any) (*response.ImageGenerationResponse, error) {
+	options := a.mergeOptions(protocol.ImageGeneration, opts...)
+
+	if err := a.checkSupport(protocol.ImageGeneration, false, options, nil); err != nil {
+		return nil, err
+	}
+
+	prompt, err := a.checkInput(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	req := request.NewImageGeneration(a.provider, a.model, prompt, options)
+
+	result, err := a.execute(ctx, req, req.Count())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, ok := result.(*response.ImageGenerationResponse)
+	if !ok {
+		return nil, fmt.Errorf("unexpected response type: %T", result)
+	}
+
+	return resp, nil
+}
+
+// Transcribe executes a transcription protocol request.
+// Merges model's configured transcription options with runtime opts.
+// Returns parsed TranscriptionResponse or error.
+func (a *agent) Transcribe(ctx context.Context, filename string, audio []byte, opts ...map[string]any) (*response.TranscriptionResponse, error) {
+	options := a.mergeOptions(protocol.Transcription, opts...)
+
+	if err := a.checkSupport(protocol.Transcription, false, options, nil); err != nil {
+		return nil, err
+	}
+
+	req := request.NewTranscription(a.provider, a.model, filename, audio, options)
+
+	result, err := a.execute(ctx, req, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, ok := result.(*response.TranscriptionResponse)
+	if !ok {
+		return nil, fmt.Errorf("unexpected response type: %T", result)
+	}
+
+	if a.guardrails != nil {
+		resp.Text, _, err = a.guardrails.Apply(ctx, guardrails.Output, resp.Text)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
+
+// Speak executes a speech protocol request.
+// Merges model's configured speech options with runtime opts.
+// Returns parsed SpeechResponse or error.
+func (a *agent) Speak(ctx context.Context, text string, opts ...map[string]any) (*response.SpeechResponse, error) {
+	options := a.mergeOptions(protocol.Speech, opts...)
+
+	if err := a.checkSupport(protocol.Speech, false, options, nil); err != nil {
+		return nil, err
+	}
+
+	text, err := a.checkInput(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+
+	req := request.NewSpeech(a.provider, a.model, text, options)
+
+	result, err := a.execute(ctx, req, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, ok := result.(*response.SpeechResponse)
+	if !ok {
+		return nil, fmt.Errorf("unexpected response type: %T", result)
+	}
+
+	return resp, nil
+}
+
+// SpeakStream executes a streaming speech protocol request.
+// Merges model's configured speech options with runtime opts.
+// Speech streams raw audio as the response body arrives, so no stream
+// option is added to the request.
+// Returns a channel of StreamingChunk carrying Audio or error.
+func (a *agent) SpeakStream(ctx context.Context, text string, opts ...map[string]any) (<-chan *response.StreamingChunk, error) {
+	options := a.mergeOptions(protocol.Speech, opts...)
+
+	if err := a.checkSupport(protocol.Speech, true, options, nil); err != nil {
+		return nil, err
+	}
+
+	text, err := a.checkInput(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+
+	req := request.NewSpeech(a.provider, a.model, text, options)
+
+	return a.executeStream(ctx, req, 0)
+}
+
+// Moderate executes a moderation protocol request.
+// Merges model's configured moderation options with runtime opts.
+// Guardrails are not applied.
+// Returns parsed ModerationResponse or error.
+func (a *agent) Moderate(ctx context.Context, input string, opts ...map[string]any) (*response.ModerationResponse, error) {
+	options := a.mergeOptions(protocol.Moderation, opts...)
+
+	if err := a.checkSupport(protocol.Moderation, false, options, nil); err != nil {
+		return nil, err
+	}
+
+	req := request.NewModeration(a.provider, a.model, input, options)
+
+	result, err := a.execute(ctx, req, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, ok := result.(*response.ModerationResponse)
+	if !ok {
+		return nil, fmt.Errorf("unexpected response type: %T", result)
+	}
+
+	return resp, nil
+}
+
+// execute runs a standard request through the client.
+// Enforces the usage budget before sending and records usage on success.
+// Responses served from the client cache are not recorded, as they were not billed.
+func (a *agent) execute(ctx context.Context, req request.Request, images int) (any, error) {
+	if a.usage == nil {
+		return a.client.Execute(ctx, req)
+	}
+
+	if err := a.usage.Check(); err != nil {
+		return nil, err
+	}
+
+	ctx, cache := client.WithCacheReport(ctx)
+	result, err := a.client.Execute(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !cache.Hit {
+		a.recordUsage(ctx, req.Protocol(), usageOf(result), images)
+	}
+
+	return result, nil
+}
+
+// executeStream runs a streaming request through the client.
+// Enforces the usage budget before sending. When a usage tracker is attached,
+// chunks are forwarded through an intermediate channel so usage reported on
+// the final chunk can be recorded. Streams replayed from the client cache are
+// returned as is, as they were not billed.
+func (a *agent) executeStream(ctx context.Context, req request.Request, images int) (<-chan *response.StreamingChunk, error) {
+	if a.usage == nil {
+		return a.client.ExecuteStream(ctx, req)
+	}
+
+	if err := a.usage.Check(); err != nil {
+		return nil, err
+	}
+
+	ctx, cache := client.WithCacheReport(ctx)
+	stream, err := a.client.ExecuteStream(ctx, req)
+	if err != nil || cache.Hit {
+		return stream, err
+	}
+
+	output := make(chan *response.StreamingChunk)
+	go func() {
+		defer close(output)
+
+		var tokens *response.TokenUsage
+		defer func() {
+			a.recordUsage(ctx, req.Protocol(), tokens, images)
+		}()
+
+		for chunk := range stream {
+			if chunk.Usage != nil {
+				tokens = chunk.Usage
+			}
+			select {
+			case output <- chunk:
+			case <-ctx.Done():
+				drain(stream)
+				return
+			}
+		}
+	}()
+
+	return output, nil
+}
+
+// drain discards the remaining chunks of a stream whose consumer has gone
+// away, so the producer is not left blocked on a send.
+func drain(stream <-chan *response.StreamingChunk) {
+	for range stream {
+	}
+}
+
+// includeStreamUsage sets stream_options.include_usage when a usage tracker is
+// attached, so OpenAI-compatible providers report usage on the final chunk.
+// An include_usage value set by the caller is kept.
+func (a *agent) includeStreamUsage(options map[string]any) {
+	if a.usage == nil {
+		return
+	}
+
+	streamOptions, _ := options["stream_options"].(map[string]any)
+	if _, set := streamOptions["include_usage"]; set {
+		return
+	}
+
+	streamOptions = maps.Clone(streamOptions)
+	if streamOptions == nil {
+		streamOptions = make(map[string]any)
+	}
+	streamOptions["include_usage"] = true
+	options["stream_options"] = streamOptions
+}
+
+// recordUsage records token usage on the attached tracker.
+// Does nothing if no tracker is attached or the provider did not report usage.
+func (a *agent) recordUsage(ctx context.Context, proto protocol.Protocol, tokens *response.TokenUsage, images int) {
+	if a.usage == nil || tokens == nil {
+		return
+	}
+
+	a.usage.Record(usage.Record{
+		AgentID:          a.id,
+		Model:            a.model.Name,
+		Protocol:         proto,
+		Tags:             usage.Tags(ctx),
+		PromptTokens:     tokens.PromptTokens,
+		CompletionTokens: tokens.CompletionTokens,
+		ReasoningTokens:  tokens.ReasoningTokens(),
+		Images:           images,
+	})
+}
+
+// usageOf extracts token usage from a parsed protocol response.
+func usageOf(result any) *response.TokenUsage {
+	switch r := result.(type) {
+	case *response.ChatResponse:
+		return r.Usage
+	case *response.ToolsResponse:
+		return r.Usage
+	case *response.EmbeddingsResponse:
+		return r.Usage
+	case *response.TranscriptionResponse:
+		return r.Usage
+	case *response.RerankResponse:
+		return r.Usage
+	case *response.ImageGenerationResponse:
+		// Image models often report no token usage; record the images regardless.
+		if r.Usage == nil {
+			return &response.TokenUsage{}
+		}
+		return r.Usage
+	default:
+		return nil
+	}
+}
+
 // mergeOptions creates options by merging model defaults with runtime options.
 func (a *agent) mergeOptions(proto protocol.Protocol, opts ...map[string]any) map[string]any {
 	options := make(map[string]any)
@@ -303,4 +782,8 @@ type Tool struct {
 	// Parameters is a JSON Schema defining the function's parameters.
 	// Uses the format: {"type": "object", "properties": {...}, "required": [...]}
 	Parameters map[string]any `json:"parameters"`
+
+	// Strict requests exact schema adherence for the function's arguments.
+	// Requires "additionalProperties": false and every property listed in "required".
+	Strict bool `json:"strict,omitempty"`
 }
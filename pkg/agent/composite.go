@@ -0,0 +1,526 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JaimeStill/go-agents/pkg/client"
+	"github.com/JaimeStill/go-agents/pkg/model"
+	"github.com/JaimeStill/go-agents/pkg/providers"
+	"github.com/JaimeStill/go-agents/pkg/response"
+	"github.com/google/uuid"
+)
+
+// DefaultFallbackCategories are the error categories that move a composite
+// request on to the next member: throttling, provider failures, timeouts,
+// network failures, prompts too large for the member's context window, and
+// requests the member's model does not support.
+var DefaultFallbackCategories = []client.ErrorCategory{
+	client.CategoryRateLimit,
+	client.CategoryServer,
+	client.CategoryTimeout,
+	client.CategoryNetwork,
+	client.CategoryContextLength,
+	client.CategoryUnsupported,
+}
+
+// hedgeMinSamples is the number of observed latencies required before the
+// latency percentile replaces the initial hedge delay.
+const hedgeMinSamples = 10
+
+// latencyWindow is the number of recent latencies kept for hedge delay estimation.
+const latencyWindow = 100
+
+// Member is an agent participating in a composite.
+type Member struct {
+	// Agent serves requests routed to this member.
+	Agent Agent
+
+	// Weight is the member's relative share of traffic within its tier.
+	// Zero or negative weights are treated as 1.
+	Weight int
+}
+
+// Tier is a group of equivalent members. Requests are load-balanced across
+// a tier's members by weight before falling back to the next tier.
+type Tier []Member
+
+// Attempt records a single member's attempt at serving a composite request.
+type Attempt struct {
+	// AgentID identifies the member agent.
+	AgentID string
+
+	// Err is the error returned by the member, or nil on success.
+	Err error
+
+	// Category classifies Err.
+	Category client.ErrorCategory
+
+	// Latency is the time the member took to respond.
+	Latency time.Duration
+
+	// Hedge reports whether the attempt was started as a hedge while an
+	// earlier attempt was still in flight.
+	Hedge bool
+}
+
+// ServeReport describes which member served a composite request.
+type ServeReport struct {
+	// AgentID identifies the member that served the response.
+	// Empty if every member failed.
+	AgentID string
+
+	// Provider is the serving member's provider name.
+	Provider string
+
+	// Model is the serving member's model name.
+	Model string
+
+	// Attempts lists every attempt in the order they completed.
+	Attempts []Attempt
+}
+
+type serveReportKey struct{}
+
+// WithServeReport returns a context that collects a ServeReport for a composite
+// request executed with it. The report is populated when the call returns.
+func WithServeReport(ctx context.Context) (context.Context, *ServeReport) {
+	report := &ServeReport{}
+	return context.WithValue(ctx, serveReportKey{}, report), report
+}
+
+// CompositeError is returned when no member of a composite could serve a request.
+// Unwraps to every member error for use with errors.Is and errors.As.
+type CompositeError struct {
+	Attempts []Attempt
+}
+
+func (e *CompositeError) Error() string {
+	if len(e.Attempts) == 0 {
+		return "composite: no members available"
+	}
+
+	parts := make([]string, len(e.Attempts))
+	for i, attempt := range e.Attempts {
+		parts[i] = fmt.Sprintf("%s (%s): %v", attempt.AgentID, attempt.Category, attempt.Err)
+	}
+	return "composite: all members failed: " + strings.Join(parts, "; ")
+}
+
+// Unwrap returns the member errors.
+func (e *CompositeError) Unwrap() []error {
+	errs := make([]error, len(e.Attempts))
+	for i, attempt := range e.Attempts {
+		errs[i] = attempt.Err
+	}
+	return errs
+}
+
+// CompositeOption configures a composite agent.
+type CompositeOption func(*Composite)
+
+// WithFallbackOn sets the error categories that move a request on to the next member.
+// Errors in other categories are returned immediately.
+func WithFallbackOn(categories ...client.ErrorCategory) CompositeOption {
+	return func(c *Composite) {
+		c.fallbackOn = categories
+	}
+}
+
+// WithHedging enables request hedging for non-streaming calls.
+// When an attempt has not completed after the given latency percentile
+// (0 < percentile < 1) of recent successful requests, the next member is
+// started concurrently and the first success is returned. Each further
+// elapsed delay starts another member while members remain.
+// initial is the hedge delay used until enough latencies have been observed;
+// zero disables hedging until then.
+func WithHedging(percentile float64, initial time.Duration) CompositeOption {
+	return func(c *Composite) {
+		c.hedging = true
+		c.percentile = percentile
+		c.initialHedge = initial
+	}
+}
+
+// WithServeCallback registers a function called with the ServeReport of every
+// composite request, including failed ones. Useful for logging and metrics.
+func WithServeCallback(fn func(*ServeReport)) CompositeOption {
+	return func(c *Composite) {
+		c.onServe = fn
+	}
+}
+
+var _ Agent = (*Composite)(nil)
+
+// Composite is an Agent that routes each request across member agents.
+// Tiers are tried in order; within a tier, members are chosen by weighted
+// random selection. Requests fall back to the next member on errors in the
+// configured categories and may be hedged after a latency percentile.
+//
+// Client, Provider, and Model return those of the first member of the first tier.
+// Streaming calls fall back only on errors returned before the stream starts
+// and are never hedged.
+type Composite struct {
+	id    string
+	tiers []Tier
+
+	fallbackOn   []client.ErrorCategory
+	hedging      bool
+	percentile   float64
+	initialHedge time.Duration
+	onServe      func(*ServeReport)
+
+	mutex     sync.Mutex
+	latencies []time.Duration
+	rng       *rand.Rand
+}
+
+// NewComposite creates a composite agent over ordered tiers of members.
+// Returns an error if no tier contains a member.
+func NewComposite(tiers []Tier, opts ...CompositeOption) (*Composite, error) {
+	var filtered []Tier
+	for _, tier := range tiers {
+		members := slices.DeleteFunc(slices.Clone(tier), func(m Member) bool {
+			return m.Agent == nil
+		})
+		if len(members) > 0 {
+			filtered = append(filtered, members)
+		}
+	}
+
+	if len(filtered) == 0 {
+		return nil, fmt.Errorf("composite requires at least one member")
+	}
+
+	c := &Composite{
+		id:         uuid.Must(uuid.NewV7()).String(),
+		tiers:      filtered,
+		fallbackOn: DefaultFallbackCategories,
+		rng:        rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
+}
+
+// NewFallback creates a composite that tries each agent in order,
+// one agent per tier.
+func NewFallback(agents []Agent, opts ...CompositeOption) (*Composite, error) {
+	tiers := make([]Tier, len(agents))
+	for i, a := range agents {
+		tiers[i] = Tier{{Agent: a}}
+	}
+	return NewComposite(tiers, opts...)
+}
+
+// ID returns the composite's own identifier.
+func (c *Composite) ID() string {
+	return c.id
+}
+
+// Client returns the client of the primary member.
+func (c *Composite) Client() client.Client {
+	return c.primary().Client()
+}
+
+// Provider returns the provider of the primary member.
+func (c *Composite) Provider() providers.Provider {
+	return c.primary().Provider()
+}
+
+// Model returns the model of the primary member.
+func (c *Composite) Model() *model.Model {
+	return c.primary().Model()
+}
+
+// Chat executes a chat request against the composite's members.
+func (c *Composite) Chat(ctx context.Context, prompt string, opts ...map[string]any) (*response.ChatResponse, error) {
+	return route(ctx, c, true, func(ctx context.Context, a Agent) (*response.ChatResponse, error) {
+		return a.Chat(ctx, prompt, opts...)
+	})
+}
+
+// ChatStream executes a streaming chat request against the composite's members.
+func (c *Composite) ChatStream(ctx context.Context, prompt string, opts ...map[string]any) (<-chan *response.StreamingChunk, error) {
+	return route(ctx, c, false, func(ctx context.Context, a Agent) (<-chan *response.StreamingChunk, error) {
+		return a.ChatStream(ctx, prompt, opts...)
+	})
+}
+
+// Vision executes a vision request against the composite's members.
+func (c *Composite) Vision(ctx context.Context, prompt string, images []string, opts ...map[string]any) (*response.ChatResponse, error) {
+	return route(ctx, c, true, func(ctx context.Context, a Agent) (*response.ChatResponse, error) {
+		return a.Vision(ctx, prompt, images, opts...)
+	})
+}
+
+// VisionStream executes a streaming vision request against the composite's members.
+func (c *Composite) VisionStream(ctx context.Context, prompt string, images []string, opts ...map[string]any) (<-chan *response.StreamingChunk, error) {
+	return route(ctx, c, false, func(ctx context.Context, a Agent) (<-chan *response.StreamingChunk, error) {
+		return a.VisionStream(ctx, prompt, images, opts...)
+	})
+}
+
+// Tools executes a tools request against the composite's members.
+func (c *Composite) Tools(ctx context.Context, prompt string, tools []Tool, opts ...map[string]any) (*response.ToolsResponse, error) {
+	return route(ctx, c, true, func(ctx context.Context, a Agent) (*response.ToolsResponse, error) {
+		return a.Tools(ctx, prompt, tools, opts...)
+	})
+}
+
+// Embed executes an embeddings request against the composite's members.
+func (c *Composite) Embed(ctx context.Context, input string, opts ...map[string]any) (*response.EmbeddingsResponse, error) {
+	return route(ctx, c, true, func(ctx context.Context, a Agent) (*response.EmbeddingsResponse, error) {
+		return a.Embed(ctx, input, opts...)
+	})
+}
+
+// GenerateImage executes an image generation request against the composite's members.
+func (c *Composite) GenerateImage(ctx context.Context, prompt string, opts ...map[string]any) (*response.ImageGenerationResponse, error) {
+	return route(ctx, c, true, func(ctx context.Context, a Agent) (*response.ImageGenerationResponse, error) {
+		return a.GenerateImage(ctx, prompt, opts...)
+	})
+}
+
+// Transcribe executes a transcription request against the composite's members.
+func (c *Composite) Transcribe(ctx context.Context, filename string, audio []byte, opts ...map[string]any) (*response.TranscriptionResponse, error) {
+	return route(ctx, c, true, func(ctx context.Context, a Agent) (*response.TranscriptionResponse, error) {
+		return a.Transcribe(ctx, filename, audio, opts...)
+	})
+}
+
+// Speak executes a speech request against the composite's members.
+func (c *Composite) Speak(ctx context.Context, text string, opts ...map[string]any) (*response.SpeechResponse, error) {
+	return route(ctx, c, true, func(ctx context.Context, a Agent) (*response.SpeechResponse, error) {
+		return a.Speak(ctx, text, opts...)
+	})
+}
+
+// SpeakStream executes a streaming speech request against the composite's members.
+func (c *Composite) SpeakStream(ctx context.Context, text string, opts ...map[string]any) (<-chan *response.StreamingChunk, error) {
+	return route(ctx, c, false, func(ctx context.Context, a Agent) (<-chan *response.StreamingChunk, error) {
+		return a.SpeakStream(ctx, text, opts...)
+	})
+}
+
+// Rerank executes a rerank request against the composite's members.
+func (c *Composite) Rerank(ctx context.Context, query string, documents []string, opts ...map[string]any) (*response.RerankResponse, error) {
+	return route(ctx, c, true, func(ctx context.Context, a Agent) (*response.RerankResponse, error) {
+		return a.Rerank(ctx, query, documents, opts...)
+	})
+}
+
+// Moderate executes a moderation request against the composite's members.
+func (c *Composite) Moderate(ctx context.Context, input string, opts ...map[string]any) (*response.ModerationResponse, error) {
+	return route(ctx, c, true, func(ctx context.Context, a Agent) (*response.ModerationResponse, error) {
+		return a.Moderate(ctx, input, opts...)
+	})
+}
+
+// primary returns the first member of the first tier.
+func (c *Composite) primary() Agent {
+	return c.tiers[0][0].Agent
+}
+
+// candidates returns members in the order they should be attempted:
+// tier by tier, with each tier ordered by weighted random selection.
+func (c *Composite) candidates() []Agent {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var ordered []Agent
+	for _, tier := range c.tiers {
+		remaining := slices.Clone(tier)
+		for len(remaining) > 0 {
+			total := 0
+			for _, m := range remaining {
+				total += max(m.Weight, 1)
+			}
+
+			pick := c.rng.Intn(total)
+			for i, m := range remaining {
+				pick -= max(m.Weight, 1)
+				if pick < 0 {
+					ordered = append(ordered, m.Agent)
+					remaining = slices.Delete(remaining, i, i+1)
+					break
+				}
+			}
+		}
+	}
+	return ordered
+}
+
+// shouldFallback reports whether an error category moves the request to the next member.
+func (c *Composite) shouldFallback(category client.ErrorCategory) bool {
+	return slices.Contains(c.fallbackOn, category)
+}
+
+// hedgeDelay returns how long to wait on an in-flight attempt before hedging.
+// Returns zero if hedging is disabled or no delay is available yet.
+func (c *Composite) hedgeDelay() time.Duration {
+	if !c.hedging {
+		return 0
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if len(c.latencies) < hedgeMinSamples {
+		return c.initialHedge
+	}
+
+	sorted := slices.Clone(c.latencies)
+	slices.Sort(sorted)
+
+	index := min(int(float64(len(sorted))*c.percentile), len(sorted)-1)
+	return sorted[max(index, 0)]
+}
+
+// observe records a successful latency for hedge delay estimation.
+func (c *Composite) observe(latency time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.latencies = append(c.latencies, latency)
+	if len(c.latencies) > latencyWindow {
+		c.latencies = c.latencies[len(c.latencies)-latencyWindow:]
+	}
+}
+
+// report delivers a ServeReport to the request context and serve callback.
+func (c *Composite) report(ctx context.Context, served Agent, attempts []Attempt) {
+	report := ServeReport{Attempts: attempts}
+	if served != nil {
+		report.AgentID = served.ID()
+		if p := served.Provider(); p != nil {
+			report.Provider = p.Name()
+		}
+		if m := served.Model(); m != nil {
+			report.Model = m.Name
+		}
+	}
+
+	if target, ok := ctx.Value(serveReportKey{}).(*ServeReport); ok {
+		*target = report
+	}
+
+	if c.onServe != nil {
+		c.onServe(&report)
+	}
+}
+
+// categorize classifies a member error, recognizing agent-level errors
+// in addition to client categories.
+func categorize(err error) client.ErrorCategory {
+	if errors.Is(err, ErrContextLengthExceeded) {
+		return client.CategoryContextLength
+	}
+	if errors.Is(err, ErrModelUnsupported) {
+		return client.CategoryUnsupported
+	}
+	return client.Categorize(err)
+}
+
+// outcome is the result of a single member attempt.
+type outcome[T any] struct {
+	agent   Agent
+	value   T
+	err     error
+	latency time.Duration
+	hedge   bool
+}
+
+// route executes op against composite members until one succeeds.
+// Attempts proceed sequentially, falling back on configured error categories.
+// When hedge is true and a hedge delay is available, the next member is
+// started if the in-flight attempt has not completed within the delay.
+// Outstanding attempts are cancelled once a result is returned.
+func route[T any](ctx context.Context, c *Composite, hedge bool, op func(context.Context, Agent) (T, error)) (T, error) {
+	var zero T
+
+	candidates := c.candidates()
+	results := make(chan outcome[T], len(candidates))
+
+	// Cancel outstanding attempts when returning. Streaming results must
+	// outlive route, so only hedged (non-streaming) calls use a child context.
+	attemptCtx := ctx
+	if hedge {
+		var cancel context.CancelFunc
+		attemptCtx, cancel = context.WithCancel(ctx)
+		defer cancel()
+	}
+
+	next, inflight := 0, 0
+	launch := func(hedged bool) {
+		member := candidates[next]
+		next++
+		inflight++
+
+		go func() {
+			start := time.Now()
+			value, err := op(attemptCtx, member)
+			results <- outcome[T]{agent: member, value: value, err: err, latency: time.Since(start), hedge: hedged}
+		}()
+	}
+
+	var attempts []Attempt
+	launch(false)
+
+	for {
+		var timer <-chan time.Time
+		if hedge && next < len(candidates) {
+			if delay := c.hedgeDelay(); delay > 0 {
+				timer = time.After(delay)
+			}
+		}
+
+		select {
+		case <-timer:
+			launch(true)
+
+		case r := <-results:
+			inflight--
+
+			category := categorize(r.err)
+			attempts = append(attempts, Attempt{
+				AgentID:  r.agent.ID(),
+				Err:      r.err,
+				Category: category,
+				Latency:  r.latency,
+				Hedge:    r.hedge,
+			})
+
+			if r.err == nil {
+				c.observe(r.latency)
+				c.report(ctx, r.agent, attempts)
+				return r.value, nil
+			}
+
+			if ctx.Err() != nil || !c.shouldFallback(category) {
+				c.report(ctx, nil, attempts)
+				return zero, r.err
+			}
+
+			if next < len(candidates) {
+				launch(false)
+			} else if inflight == 0 {
+				c.report(ctx, nil, attempts)
+				return zero, &CompositeError{Attempts: attempts}
+			}
+
+		case <-ctx.Done():
+			c.report(ctx, nil, attempts)
+			return zero, ctx.Err()
+		}
+	}
+}
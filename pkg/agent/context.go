@@ -0,0 +1,154 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/JaimeStill/go-agents/pkg/config"
+	"github.com/JaimeStill/go-agents/pkg/protocol"
+	"github.com/JaimeStill/go-agents/pkg/providers"
+	"github.com/JaimeStill/go-agents/pkg/tokenizer"
+)
+
+// ErrContextLengthExceeded indicates a request's estimated size exceeds the model's context window.
+var ErrContextLengthExceeded = errors.New("context length exceeded")
+
+// ContextError describes a request rejected by the pre-flight context check.
+// Unwraps to ErrContextLengthExceeded.
+type ContextError struct {
+	// Estimated is the estimated number of prompt tokens.
+	Estimated int
+
+	// Reserved is the number of tokens reserved for the completion.
+	Reserved int
+
+	// Window is the model's context window.
+	Window int
+
+	// Tokenizer is the encoding used for the estimate.
+	Tokenizer string
+}
+
+func (e *ContextError) Error() string {
+	return fmt.Sprintf(
+		"%s: estimated %d prompt tokens + %d reserved for output exceeds context window of %d (%s)",
+		ErrContextLengthExceeded, e.Estimated, e.Reserved, e.Window, e.Tokenizer,
+	)
+}
+
+// Unwrap returns ErrContextLengthExceeded for use with errors.Is.
+func (e *ContextError) Unwrap() error {
+	return ErrContextLengthExceeded
+}
+
+// WithTokenizer overrides the tokenizer used for pre-flight context checks.
+// By default the tokenizer is the model's configured encoding, which must be
+// available, or is detected from the model name, falling back to the heuristic
+// estimator for models without a known vocabulary.
+func WithTokenizer(t tokenizer.Tokenizer) Option {
+	return func(a *agent) {
+		a.tokenizer = t
+	}
+}
+
+// contextEnabled reports whether the pre-flight context check applies to this agent.
+func (a *agent) contextEnabled() bool {
+	return a.model.ContextWindow > 0 && a.model.ContextPolicy != config.ContextPolicyNone
+}
+
+// preflight estimates the prompt size of a message-based request and applies
+// the model's context policy. extra accounts for non-message prompt content
+// such as images and tool definitions.
+// Returns the messages to send, which are truncated under the truncate policy,
+// or a *ContextError if the request cannot fit.
+func (a *agent) preflight(messages []protocol.Message, extra int, options map[string]any) ([]protocol.Message, error) {
+	if !a.contextEnabled() {
+		return messages, nil
+	}
+
+	window := a.model.ContextWindow
+	reserved := a.outputReserve(options)
+	estimated := tokenizer.CountMessages(a.tokenizer, messages) + extra
+
+	if estimated+reserved <= window {
+		return messages, nil
+	}
+
+	if a.model.ContextPolicy == config.ContextPolicyTruncate && len(messages) > 0 {
+		last := messages[len(messages)-1]
+		if text, ok := last.Content.(string); ok {
+			keep := a.tokenizer.Count(text) - (estimated + reserved - window)
+			if keep > 0 {
+				truncated := make([]protocol.Message, len(messages))
+				copy(truncated, messages)
+				truncated[len(truncated)-1] = protocol.NewMessage(last.Role, a.tokenizer.Truncate(text, keep))
+				return truncated, nil
+			}
+		}
+	}
+
+	return nil, &ContextError{
+		Estimated: estimated,
+		Reserved:  reserved,
+		Window:    window,
+		Tokenizer: a.tokenizer.Name(),
+	}
+}
+
+// preflightInput applies the model's context policy to embeddings input.
+// Embeddings produce no completion, so no output tokens are reserved.
+func (a *agent) preflightInput(input string) (string, error) {
+	if !a.contextEnabled() {
+		return input, nil
+	}
+
+	window := a.model.ContextWindow
+	estimated := a.tokenizer.Count(input)
+
+	if estimated <= window {
+		return input, nil
+	}
+
+	if a.model.ContextPolicy == config.ContextPolicyTruncate {
+		return a.tokenizer.Truncate(input, window), nil
+	}
+
+	return "", &ContextError{
+		Estimated: estimated,
+		Window:    window,
+		Tokenizer: a.tokenizer.Name(),
+	}
+}
+
+// imageTokens estimates the prompt tokens for vision images.
+func (a *agent) imageTokens(images []string, visionOptions map[string]any) int {
+	if !a.contextEnabled() {
+		return 0
+	}
+
+	detail, _ := visionOptions["detail"].(string)
+
+	total := 0
+	for _, img := range images {
+		total += tokenizer.ImageTokens(img, detail)
+	}
+	return total
+}
+
+// outputReserve returns the completion budget to reserve when checking the context window.
+// Uses max_completion_tokens or max_tokens from the request options,
+// falling back to the model's MaxOutputTokens.
+func (a *agent) outputReserve(options map[string]any) int {
+	if _, tokens, ok := requestedOutput(options); ok {
+		return tokens
+	}
+	return a.model.MaxOutputTokens
+}
+
+// toolTokens estimates the prompt tokens for tool definitions.
+func (a *agent) toolTokens(tools []providers.ToolDefinition) int {
+	if !a.contextEnabled() || len(tools) == 0 {
+		return 0
+	}
+	return tokenizer.CountJSON(a.tokenizer, tools)
+}
@@ -21,6 +21,16 @@
 //	    Tools(ctx context.Context, prompt string, tools []Tool, opts ...map[string]any) (*types.ToolsResponse, error)
 //
 //	    Embed(ctx context.Context, input string, opts ...map[string]any) (*types.EmbeddingsResponse, error)
+//
+//	    GenerateImage(ctx context.Context, prompt string, opts ...map[string]any) (*types.ImageGenerationResponse, error)
+//
+//	    Transcribe(ctx context.Context, filename string, audio []byte, opts ...map[string]any) (*types.TranscriptionResponse, error)
+//	    Speak(ctx context.Context, text string, opts ...map[string]any) (*types.SpeechResponse, error)
+//	    SpeakStream(ctx context.Context, text string, opts ...map[string]any) (<-chan *types.StreamingChunk, error)
+//
+//	    Rerank(ctx context.Context, query string, documents []string, opts ...map[string]any) (*types.RerankResponse, error)
+//
+//	    Moderate(ctx context.Context, input string, opts ...map[string]any) (*types.ModerationResponse, error)
 //	}
 //
 // # Creating an Agent
@@ -149,10 +159,22 @@
 //
 //	// Process tool calls
 //	for _, toolCall := range response.ToolCalls() {
-//	    fmt.Printf("Tool: %s\n", toolCall.Name())
-//	    fmt.Printf("Arguments: %s\n", toolCall.Arguments())
+//	    fmt.Printf("Tool: %s\n", toolCall.Function.Name)
+//	    fmt.Printf("Arguments: %s\n", toolCall.Function.Arguments)
 //	}
 //
+// The tool_choice and parallel_tool_calls options control tool use. Each
+// provider maps them to its wire format and rejects combinations it cannot honor:
+//
+//	response, err := agent.Tools(ctx, prompt, tools, map[string]any{
+//	    "tool_choice":         providers.ToolChoiceFor("get_weather"),
+//	    "parallel_tool_calls": false,
+//	})
+//
+// To continue the conversation, append response.Message() and a
+// protocol.NewToolResultMessage for each call to the history, and send it
+// with request.NewTools.
+//
 // # Embeddings Protocol
 //
 // Text vectorization for semantic search:
@@ -172,6 +194,93 @@
 //	}
 //	response, err := agent.Embed(ctx, "text to embed", options)
 //
+// # Image Generation Protocol
+//
+// Image generation from a text prompt:
+//
+//	response, err := agent.GenerateImage(ctx, "A lighthouse at dusk", map[string]any{
+//	    "size":            "1024x1024",
+//	    "n":               2,
+//	    "response_format": "b64_json",
+//	})
+//
+//	for _, image := range response.Data {
+//	    data, _ := image.Bytes()
+//	    os.WriteFile(name, data, 0644)
+//	}
+//
+// Images are returned as URLs or base64 data; Source returns either form
+// in a shape Vision accepts.
+//
+// # Audio Protocols
+//
+// Transcription of an audio upload, with segment and word timestamps:
+//
+//	audio, _ := os.ReadFile("meeting.mp3")
+//	response, err := agent.Transcribe(ctx, "meeting.mp3", audio, map[string]any{
+//	    "language":                "en",
+//	    "response_format":         "verbose_json",
+//	    "timestamp_granularities": []string{"segment"},
+//	})
+//
+//	for _, segment := range response.Segments {
+//	    fmt.Printf("%.1fs: %s\n", segment.Start, segment.Text)
+//	}
+//
+// Speech synthesis, returning the complete audio or streaming it as it is produced:
+//
+//	speech, err := agent.Speak(ctx, "The meeting starts in five minutes.", map[string]any{
+//	    "voice": "alloy",
+//	})
+//	os.WriteFile("reminder.mp3", speech.Audio, 0644)
+//
+//	chunks, err := agent.SpeakStream(ctx, "The meeting starts in five minutes.")
+//	for chunk := range chunks {
+//	    player.Write(chunk.Audio)
+//	}
+//
+// # Rerank Protocol
+//
+// Reranking orders retrieved documents by their relevance to a query:
+//
+//	response, err := agent.Rerank(ctx, "How do I rotate an API key?", documents, map[string]any{
+//	    "top_n":            5,
+//	    "return_documents": true,
+//	})
+//
+//	for _, result := range response.Results {
+//	    fmt.Printf("%.3f %s\n", result.RelevanceScore, result.Document)
+//	}
+//
+// Models that declare rerank among their protocols, or whose protocols are
+// unknown but that have a rerank capability, call a Cohere, Jina, or Text
+// Embeddings Inference style /rerank endpoint. Other models score the
+// documents over the chat protocol with ChatRerank, which also accepts any
+// Agent directly.
+//
+// # Moderation and Guardrails
+//
+// Moderate classifies text against content policy categories:
+//
+//	response, err := agent.Moderate(ctx, "text to classify")
+//	if response.Flagged() {
+//	    fmt.Println(response.Results[0].FlaggedCategories())
+//	}
+//
+// WithGuardrails runs input and output checks from package guardrails around
+// every other call. Checks can block the call, redact the text, or annotate it
+// with findings collected by guardrails.WithReport:
+//
+//	g := guardrails.New(
+//	    guardrails.WithInput(guardrails.PII(guardrails.Redact)),
+//	    guardrails.WithOutput(guardrails.Moderation(moderator, guardrails.Block)),
+//	)
+//	a, err := agent.New(cfg, agent.WithGuardrails(g))
+//
+// Blocked calls fail with an error wrapping guardrails.ErrBlocked. Streamed
+// output is checked after it has been delivered: a block arrives as a final
+// error chunk, and redactions are reported as findings but not applied.
+//
 // # System Prompt Injection
 //
 // When an agent is created with a system prompt, it's automatically prepended
@@ -192,7 +301,7 @@
 //  2. User: "How do I use channels?"
 //
 // Affects: Chat, ChatStream, Vision, VisionStream, Tools
-// Does not affect: Embed (embeddings protocol doesn't use messages)
+// Does not affect: Embed, GenerateImage, Transcribe, Speak, SpeakStream (these protocols don't use messages)
 //
 // # Options Management
 //
@@ -263,6 +372,16 @@
 //   - WithClient: Client identification
 //   - WithID: Unique error ID
 //
+// Requests the model cannot serve fail before they are sent with a
+// SupportError wrapping ErrModelUnsupported. The checks use the model's
+// protocols, streaming and JSON mode support, output limit, and image limits
+// from configuration or the built-in catalog (see config.CatalogModel):
+//
+//	_, err := agent.ChatStream(ctx, "Hello")
+//	if errors.Is(err, agent.ErrModelUnsupported) {
+//	    // e.g. an embeddings model, or streaming disabled for the model
+//	}
+//
 // # Context Cancellation
 //
 // All protocol methods respect context cancellation:
@@ -314,6 +433,35 @@
 //
 // This allows advanced usage while maintaining the convenience of agent methods.
 //
+// # Composite Agents
+//
+// A Composite wraps member agents behind the Agent interface. Tiers are
+// tried in order, members within a tier are load-balanced by weight, and
+// requests fall back to the next member on throttling, server errors,
+// timeouts, network failures, and context length errors:
+//
+//	composite, err := agent.NewComposite([]agent.Tier{
+//	    {{Agent: azureEast, Weight: 3}, {Agent: azureWest, Weight: 1}},
+//	    {{Agent: localOllama}},
+//	}, agent.WithHedging(0.95, 5*time.Second))
+//
+// WithServeReport records which member served a call:
+//
+//	ctx, report := agent.WithServeReport(ctx)
+//	resp, err := composite.Chat(ctx, "Hello")
+//	fmt.Println("served by", report.AgentID, report.Model)
+//
+// # Reloadable Agents
+//
+// A Reloadable swaps its underlying agent when given a new configuration.
+// Calls run on the agent that was current when they started, so in-flight
+// requests finish on the old instance. Watch connects it to a config.Watcher:
+//
+//	w, err := config.NewWatcher("config.yaml")
+//	reloadable, err := agent.NewReloadable(w.Config())
+//	reloadable.Watch(w)
+//	go w.Run(ctx)
+//
 // # Thread Safety
 //
 // Agents are safe for concurrent use. Multiple goroutines can call protocol methods
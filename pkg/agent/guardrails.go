@@ -0,0 +1,113 @@
+package agent
+
+import (
+	"context"
+	"strings"
+
+	"github.com/JaimeStill/go-agents/pkg/guardrails"
+	"github.com/JaimeStill/go-agents/pkg/protocol"
+	"github.com/JaimeStill/go-agents/pkg/response"
+)
+
+// WithGuardrails runs input and output checks around every call except Moderate.
+// Input checks run on prompts, embedding input, rerank queries, and speech
+// text; output checks run on the text content of chat, vision, and tools
+// choices and on transcripts. Redactions replace the checked text, and blocked
+// calls fail with a *guardrails.BlockedError.
+//
+// Streamed output is checked once the stream completes, after its content has
+// been delivered: a block is reported as a final chunk carrying the error.
+// Redactions are not applied to streamed content; they are reported as
+// findings only, so use the non-streaming calls when output must be redacted.
+func WithGuardrails(g *guardrails.Guardrails) Option {
+	return func(a *agent) {
+		a.guardrails = g
+	}
+}
+
+// checkInput runs the input guardrails on request text.
+// Returns the text to send, with redactions applied.
+func (a *agent) checkInput(ctx context.Context, text string) (string, error) {
+	if a.guardrails == nil {
+		return text, nil
+	}
+
+	text, _, err := a.guardrails.Apply(ctx, guardrails.Input, text)
+	return text, err
+}
+
+// checkOutput runs the output guardrails on the text content of response
+// messages, replacing redacted content in place.
+func (a *agent) checkOutput(ctx context.Context, messages ...*protocol.Message) error {
+	if a.guardrails == nil {
+		return nil
+	}
+
+	for _, msg := range messages {
+		content, ok := msg.Content.(string)
+		if !ok {
+			continue
+		}
+
+		text, _, err := a.guardrails.Apply(ctx, guardrails.Output, content)
+		if err != nil {
+			return err
+		}
+		msg.Content = text
+	}
+
+	return nil
+}
+
+// checkChat runs the output guardrails on every choice of a chat response.
+func (a *agent) checkChat(ctx context.Context, resp *response.ChatResponse) error {
+	messages := make([]*protocol.Message, len(resp.Choices))
+	for i := range resp.Choices {
+		messages[i] = &resp.Choices[i].Message
+	}
+	return a.checkOutput(ctx, messages...)
+}
+
+// checkTools runs the output guardrails on every choice of a tools response.
+// Tool call arguments are not checked.
+func (a *agent) checkTools(ctx context.Context, resp *response.ToolsResponse) error {
+	messages := make([]*protocol.Message, len(resp.Choices))
+	for i := range resp.Choices {
+		messages[i] = &resp.Choices[i].Message
+	}
+	return a.checkOutput(ctx, messages...)
+}
+
+// checkStream runs the output guardrails on the content of a stream once it
+// completes, forwarding chunks through an intermediate channel. If the context
+// is cancelled, the source stream is drained so its producer is released.
+func (a *agent) checkStream(ctx context.Context, stream <-chan *response.StreamingChunk) <-chan *response.StreamingChunk {
+	if a.guardrails == nil {
+		return stream
+	}
+
+	output := make(chan *response.StreamingChunk)
+	go func() {
+		defer close(output)
+
+		var content strings.Builder
+		for chunk := range stream {
+			content.WriteString(chunk.Content())
+			select {
+			case output <- chunk:
+			case <-ctx.Done():
+				drain(stream)
+				return
+			}
+		}
+
+		if _, _, err := a.guardrails.Apply(ctx, guardrails.Output, content.String()); err != nil {
+			select {
+			case output <- &response.StreamingChunk{Error: err}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+
+	return output
+}
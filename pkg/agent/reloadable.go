@@ -0,0 +1,242 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/JaimeStill/go-agents/pkg/client"
+	"github.com/JaimeStill/go-agents/pkg/config"
+	"github.com/JaimeStill/go-agents/pkg/model"
+	"github.com/JaimeStill/go-agents/pkg/providers"
+	"github.com/JaimeStill/go-agents/pkg/response"
+	"github.com/google/uuid"
+)
+
+// ReloadEvent reports the outcome of reloading a Reloadable agent.
+type ReloadEvent struct {
+	// Previous is the agent that was serving requests before the reload.
+	Previous Agent
+
+	// Current is the agent serving requests after the reload.
+	// Equal to Previous when Err is set.
+	Current Agent
+
+	// Config is the configuration that was applied, or nil when Err is set.
+	Config *config.AgentConfig
+
+	// Err is the validation, load, or creation error. The previous agent keeps serving.
+	Err error
+}
+
+var _ Agent = (*Reloadable)(nil)
+
+// Reloadable is an Agent whose provider, model, and client can be replaced
+// at runtime from a new configuration. Each call runs on the agent that was
+// current when it started, so in-flight requests and streams finish on the
+// old instance while new calls use the replacement.
+//
+// The Reloadable keeps its own ID across reloads. Client, Provider, and Model
+// return those of the current agent.
+type Reloadable struct {
+	id   string
+	opts []Option
+
+	current atomic.Pointer[Agent]
+	reload  sync.Mutex
+
+	subMutex    sync.Mutex
+	subscribers map[int]func(ReloadEvent)
+	nextSub     int
+}
+
+// NewReloadable creates a reloadable agent from configuration.
+// The options are applied to the initial agent and to every reloaded agent.
+// Returns an error if the configuration is invalid or agent creation fails.
+func NewReloadable(cfg *config.AgentConfig, opts ...Option) (*Reloadable, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	a, err := New(cfg, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Reloadable{
+		id:          uuid.Must(uuid.NewV7()).String(),
+		opts:        opts,
+		subscribers: make(map[int]func(ReloadEvent)),
+	}
+	r.current.Store(&a)
+
+	return r, nil
+}
+
+// Current returns the agent currently serving requests.
+func (r *Reloadable) Current() Agent {
+	return *r.current.Load()
+}
+
+// Reload validates cfg, creates a new agent from it, and atomically swaps it
+// in. On failure the current agent keeps serving. Subscribers are notified
+// of the outcome either way.
+func (r *Reloadable) Reload(cfg *config.AgentConfig) error {
+	r.reload.Lock()
+	defer r.reload.Unlock()
+
+	previous := r.Current()
+
+	next, err := r.build(cfg)
+	if err != nil {
+		r.notify(ReloadEvent{Previous: previous, Current: previous, Err: err})
+		return err
+	}
+
+	r.current.Store(&next)
+	r.notify(ReloadEvent{Previous: previous, Current: next, Config: cfg})
+
+	return nil
+}
+
+// Watch reloads the agent whenever w activates a new configuration and
+// forwards w's load and validation failures to subscribers.
+// Returns a function that stops watching.
+func (r *Reloadable) Watch(w *config.Watcher) (unsubscribe func()) {
+	return w.Subscribe(func(event config.ConfigEvent) {
+		if event.Err != nil {
+			current := r.Current()
+			r.notify(ReloadEvent{Previous: current, Current: current, Err: event.Err})
+			return
+		}
+		r.Reload(event.Config)
+	})
+}
+
+// Subscribe registers fn to be called after every reload attempt.
+// Callbacks run synchronously on the reloading goroutine in registration order.
+// Returns a function that removes the subscription.
+func (r *Reloadable) Subscribe(fn func(ReloadEvent)) (unsubscribe func()) {
+	r.subMutex.Lock()
+	defer r.subMutex.Unlock()
+
+	id := r.nextSub
+	r.nextSub++
+	r.subscribers[id] = fn
+
+	return func() {
+		r.subMutex.Lock()
+		defer r.subMutex.Unlock()
+		delete(r.subscribers, id)
+	}
+}
+
+// ID returns the reloadable agent's own identifier, which is stable across reloads.
+func (r *Reloadable) ID() string {
+	return r.id
+}
+
+// Client returns the client of the current agent.
+func (r *Reloadable) Client() client.Client {
+	return r.Current().Client()
+}
+
+// Provider returns the provider of the current agent.
+func (r *Reloadable) Provider() providers.Provider {
+	return r.Current().Provider()
+}
+
+// Model returns the model of the current agent.
+func (r *Reloadable) Model() *model.Model {
+	return r.Current().Model()
+}
+
+// Chat executes a chat request on the current agent.
+func (r *Reloadable) Chat(ctx context.Context, prompt string, opts ...map[string]any) (*response.ChatResponse, error) {
+	return r.Current().Chat(ctx, prompt, opts...)
+}
+
+// ChatStream executes a streaming chat request on the current agent.
+func (r *Reloadable) ChatStream(ctx context.Context, prompt string, opts ...map[string]any) (<-chan *response.StreamingChunk, error) {
+	return r.Current().ChatStream(ctx, prompt, opts...)
+}
+
+// Vision executes a vision request on the current agent.
+func (r *Reloadable) Vision(ctx context.Context, prompt string, images []string, opts ...map[string]any) (*response.ChatResponse, error) {
+	return r.Current().Vision(ctx, prompt, images, opts...)
+}
+
+// VisionStream executes a streaming vision request on the current agent.
+func (r *Reloadable) VisionStream(ctx context.Context, prompt string, images []string, opts ...map[string]any) (<-chan *response.StreamingChunk, error) {
+	return r.Current().VisionStream(ctx, prompt, images, opts...)
+}
+
+// Tools executes a tools request on the current agent.
+func (r *Reloadable) Tools(ctx context.Context, prompt string, tools []Tool, opts ...map[string]any) (*response.ToolsResponse, error) {
+	return r.Current().Tools(ctx, prompt, tools, opts...)
+}
+
+// Embed executes an embeddings request on the current agent.
+func (r *Reloadable) Embed(ctx context.Context, input string, opts ...map[string]any) (*response.EmbeddingsResponse, error) {
+	return r.Current().Embed(ctx, input, opts...)
+}
+
+// GenerateImage executes an image generation request on the current agent.
+func (r *Reloadable) GenerateImage(ctx context.Context, prompt string, opts ...map[string]any) (*response.ImageGenerationResponse, error) {
+	return r.Current().GenerateImage(ctx, prompt, opts...)
+}
+
+// Transcribe executes a transcription request on the current agent.
+func (r *Reloadable) Transcribe(ctx context.Context, filename string, audio []byte, opts ...map[string]any) (*response.TranscriptionResponse, error) {
+	return r.Current().Transcribe(ctx, filename, audio, opts...)
+}
+
+// Speak executes a speech request on the current agent.
+func (r *Reloadable) Speak(ctx context.Context, text string, opts ...map[string]any) (*response.SpeechResponse, error) {
+	return r.Current().Speak(ctx, text, opts...)
+}
+
+// SpeakStream executes a streaming speech request on the current agent.
+func (r *Reloadable) SpeakStream(ctx context.Context, text string, opts ...map[string]any) (<-chan *response.StreamingChunk, error) {
+	return r.Current().SpeakStream(ctx, text, opts...)
+}
+
+// Rerank executes a rerank request on the current agent.
+func (r *Reloadable) Rerank(ctx context.Context, query string, documents []string, opts ...map[string]any) (*response.RerankResponse, error) {
+	return r.Current().Rerank(ctx, query, documents, opts...)
+}
+
+// Moderate executes a moderation request on the current agent.
+func (r *Reloadable) Moderate(ctx context.Context, input string, opts ...map[string]any) (*response.ModerationResponse, error) {
+	return r.Current().Moderate(ctx, input, opts...)
+}
+
+// build validates cfg and creates an agent from it with the reloadable's options.
+func (r *Reloadable) build(cfg *config.AgentConfig) (Agent, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("reload requires a configuration")
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return New(cfg, r.opts...)
+}
+
+// notify calls every subscriber with event.
+func (r *Reloadable) notify(event ReloadEvent) {
+	r.subMutex.Lock()
+	subscribers := make([]func(ReloadEvent), 0, len(r.subscribers))
+	for id := range r.nextSub {
+		if fn, ok := r.subscribers[id]; ok {
+			subscribers = append(subscribers, fn)
+		}
+	}
+	r.subMutex.Unlock()
+
+	for _, fn := range subscribers {
+		fn(event)
+	}
+}
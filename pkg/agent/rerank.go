@@ -0,0 +1,171 @@
+package agent
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/JaimeStill/go-agents/pkg/protocol"
+	"github.com/JaimeStill/go-agents/pkg/request"
+	"github.com/JaimeStill/go-agents/pkg/response"
+)
+
+// rerankInstructions asks a chat model to score documents for ChatRerank.
+const rerankInstructions = `Rank the documents below by their relevance to the search query.
+Score every document from 0 (irrelevant) to 1 (directly answers the query).
+Respond with only a JSON array of numbers: one score per document, in document order.`
+
+// Rerank executes a rerank protocol request when the model supports rerank,
+// and otherwise ranks the documents with the chat protocol through ChatRerank,
+// where the chat guardrails apply to the scoring prompt.
+// Merges model's configured rerank options with runtime opts.
+// Returns RerankResponse with results ordered by descending relevance or error.
+func (a *agent) Rerank(ctx context.Context, query string, documents []string, opts ...map[string]any) (*response.RerankResponse, error) {
+	if !a.rerankNatively() {
+		return ChatRerank(ctx, a, query, documents, opts...)
+	}
+
+	options := a.mergeOptions(protocol.Rerank, opts...)
+
+	if err := a.checkSupport(protocol.Rerank, false, options, nil); err != nil {
+		return nil, err
+	}
+
+	query, err := a.checkInput(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	req := request.NewRerank(a.provider, a.model, query, documents, options)
+
+	result, err := a.execute(ctx, req, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, ok := result.(*response.RerankResponse)
+	if !ok {
+		return nil, fmt.Errorf("unexpected response type: %T", result)
+	}
+
+	rankResults(resp, documents, req.TopN(), req.ReturnDocuments())
+	return resp, nil
+}
+
+// rerankNatively reports whether Rerank uses the rerank protocol.
+// A model that declares its protocols, through configuration or the catalog,
+// uses it exactly when rerank is among them. A model with unknown protocols
+// uses it only when configured with a rerank capability.
+func (a *agent) rerankNatively() bool {
+	if len(a.model.Protocols) > 0 {
+		return a.model.Supports(protocol.Rerank)
+	}
+
+	_, ok := a.model.Options[protocol.Rerank]
+	return ok
+}
+
+// ChatRerank ranks documents by their relevance to query using an agent's
+// chat protocol, for models without a rerank endpoint. The model scores every
+// document from 0 to 1 in a single chat request.
+// The "top_n" and "return_documents" options behave as for Rerank; all other
+// options are passed to Chat.
+// Returns an error if the model's reply does not contain one score per document.
+func ChatRerank(ctx context.Context, a Agent, query string, documents []string, opts ...map[string]any) (*response.RerankResponse, error) {
+	if query == "" {
+		return nil, fmt.Errorf("query cannot be empty for rerank requests")
+	}
+	if len(documents) == 0 {
+		return nil, fmt.Errorf("documents cannot be empty for rerank requests")
+	}
+
+	options := make(map[string]any)
+	if len(opts) > 0 && opts[0] != nil {
+		maps.Copy(options, opts[0])
+	}
+
+	ranking, err := request.ExtractRerankOptions(options)
+	if err != nil {
+		return nil, err
+	}
+
+	var prompt strings.Builder
+	prompt.WriteString(rerankInstructions)
+	fmt.Fprintf(&prompt, "\n\nQuery: %s\n\nDocuments:\n", query)
+	for i, doc := range documents {
+		fmt.Fprintf(&prompt, "[%d] %s\n", i, strings.TrimSpace(doc))
+	}
+
+	chat, err := a.Chat(ctx, prompt.String(), options)
+	if err != nil {
+		return nil, err
+	}
+
+	scores, err := parseScores(chat.Content(), len(documents))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &response.RerankResponse{
+		ID:      chat.ID,
+		Model:   chat.Model,
+		Results: make([]response.RerankResult, len(scores)),
+		Usage:   chat.Usage,
+	}
+	for i, score := range scores {
+		resp.Results[i] = response.RerankResult{Index: i, RelevanceScore: score}
+	}
+
+	rankResults(resp, documents, ranking.TopN, ranking.ReturnDocuments)
+	return resp, nil
+}
+
+// parseScores extracts the JSON array of document scores from a chat reply,
+// ignoring any surrounding text or code fences. Scores are clamped to [0, 1].
+func parseScores(content string, count int) ([]float64, error) {
+	start := strings.Index(content, "[")
+	end := strings.LastIndex(content, "]")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("rerank reply contained no score array: %q", content)
+	}
+
+	var scores []float64
+	if err := json.Unmarshal([]byte(content[start:end+1]), &scores); err != nil {
+		return nil, fmt.Errorf("failed to parse rerank scores: %w", err)
+	}
+
+	if len(scores) != count {
+		return nil, fmt.Errorf("rerank reply scored %d documents, expected %d", len(scores), count)
+	}
+
+	for i, score := range scores {
+		scores[i] = min(max(score, 0), 1)
+	}
+	return scores, nil
+}
+
+// rankResults orders results by descending relevance, keeps the first topN
+// (all when zero), and fills in document text when it was requested but not
+// returned by the server.
+func rankResults(resp *response.RerankResponse, documents []string, topN int, returnDocuments bool) {
+	slices.SortStableFunc(resp.Results, func(a, b response.RerankResult) int {
+		return cmp.Compare(b.RelevanceScore, a.RelevanceScore)
+	})
+
+	if topN > 0 && len(resp.Results) > topN {
+		resp.Results = resp.Results[:topN]
+	}
+
+	if !returnDocuments {
+		return
+	}
+	for i, result := range resp.Results {
+		if result.Document == "" && result.Index >= 0 && result.Index < len(documents) {
+			resp.Results[i].Document = documents[result.Index]
+		}
+	}
+}
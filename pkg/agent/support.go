@@ -0,0 +1,122 @@
+package agent
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/JaimeStill/go-agents/pkg/protocol"
+)
+
+// ErrModelUnsupported indicates a request uses a protocol or feature the model does not support.
+var ErrModelUnsupported = errors.New("not supported by model")
+
+// SupportError describes a request rejected before it was sent because the
+// model's metadata shows it cannot serve it. Unwraps to ErrModelUnsupported.
+type SupportError struct {
+	// Model is the model name.
+	Model string
+
+	// Protocol is the protocol of the rejected request.
+	Protocol protocol.Protocol
+
+	// Reason describes the unsupported protocol, feature, or limit.
+	Reason string
+}
+
+func (e *SupportError) Error() string {
+	return fmt.Sprintf("%s: %s %s request: %s", ErrModelUnsupported, e.Model, e.Protocol, e.Reason)
+}
+
+// Unwrap returns ErrModelUnsupported for use with errors.Is.
+func (e *SupportError) Unwrap() error {
+	return ErrModelUnsupported
+}
+
+// checkSupport validates a request against the model's metadata before it is
+// sent: the protocol, streaming, JSON response formats, the requested output
+// tokens, and the number and inline size of images. Unknown metadata is not checked.
+func (a *agent) checkSupport(proto protocol.Protocol, stream bool, options map[string]any, images []string) error {
+	m := a.model
+	reject := func(format string, args ...any) error {
+		return &SupportError{Model: m.Name, Protocol: proto, Reason: fmt.Sprintf(format, args...)}
+	}
+
+	if !m.Supports(proto) {
+		return reject("protocol not supported (supports %v)", m.Protocols)
+	}
+
+	if stream && !m.SupportsStreaming() {
+		return reject("streaming not supported")
+	}
+
+	if format := jsonResponseFormat(options); format != "" && !m.SupportsJSONMode() {
+		return reject("response_format %q not supported", format)
+	}
+
+	if limit := m.MaxOutputTokens; limit > 0 {
+		if key, tokens, ok := requestedOutput(options); ok && tokens > limit {
+			return reject("%s %d exceeds the maximum output of %d tokens", key, tokens, limit)
+		}
+	}
+
+	if m.MaxImages > 0 && len(images) > m.MaxImages {
+		return reject("%d images exceed the limit of %d", len(images), m.MaxImages)
+	}
+
+	if m.MaxImageBytes > 0 {
+		for i, img := range images {
+			if size := dataURISize(img); size > m.MaxImageBytes {
+				return reject("image %d is %d bytes, exceeding the limit of %d", i, size, m.MaxImageBytes)
+			}
+		}
+	}
+
+	return nil
+}
+
+// requestedOutput returns the max_completion_tokens or max_tokens option and its key.
+func requestedOutput(options map[string]any) (string, int, bool) {
+	for _, key := range []string{"max_completion_tokens", "max_tokens"} {
+		switch v := options[key].(type) {
+		case int:
+			return key, v, true
+		case int64:
+			return key, int(v), true
+		case float64:
+			return key, int(v), true
+		}
+	}
+	return "", 0, false
+}
+
+// jsonResponseFormat returns the type of a JSON response_format option
+// ("json_object" or "json_schema"), or empty if none is requested.
+func jsonResponseFormat(options map[string]any) string {
+	var format string
+	switch v := options["response_format"].(type) {
+	case string:
+		format = v
+	case map[string]any:
+		format, _ = v["type"].(string)
+	}
+
+	if strings.HasPrefix(format, "json") {
+		return format
+	}
+	return ""
+}
+
+// dataURISize returns the decoded size of a base64 data URI image,
+// or 0 for image URLs, whose size is not known before they are fetched.
+func dataURISize(img string) int {
+	if !strings.HasPrefix(img, "data:") {
+		return 0
+	}
+	_, data, ok := strings.Cut(img, ";base64,")
+	if !ok {
+		return 0
+	}
+	return base64.StdEncoding.DecodedLen(len(data)) - strings.Count(data[max(len(data)-2, 0):], "=")
+}
@@ -0,0 +1,130 @@
+package cassette
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"unicode/utf8"
+
+	"github.com/JaimeStill/go-agents/pkg/config"
+)
+
+// Version is the cassette file format version written by Save.
+const Version = 1
+
+// Cassette is a recorded sequence of HTTP interactions.
+type Cassette struct {
+	Version      int           `json:"version"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a single recorded request and its response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded HTTP request with secrets scrubbed.
+type Request struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    Body        `json:"body,omitempty"`
+}
+
+// Response is a recorded HTTP response with secrets scrubbed.
+// Server-sent event streams are recorded as Chunks instead of Body.
+type Response struct {
+	Status  int         `json:"status"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    Body        `json:"body,omitempty"`
+	Chunks  []Chunk     `json:"chunks,omitempty"`
+}
+
+// Chunk is one server-sent event of a recorded stream.
+type Chunk struct {
+	// Data is the raw event text, including its trailing blank line.
+	Data string `json:"data"`
+
+	// Delay is the time between the previous event (or the response headers)
+	// and this event.
+	Delay config.Duration `json:"delay"`
+}
+
+// Body is a recorded message body. Valid UTF-8 is stored as a string and
+// binary content as a base64 object, so JSON payloads stay readable in
+// cassette files.
+type Body []byte
+
+// MarshalJSON encodes the body as a string, or as {"base64": "..."} when it is not valid UTF-8.
+func (b Body) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		return json.Marshal(string(b))
+	}
+	return json.Marshal(map[string]string{"base64": base64.StdEncoding.EncodeToString(b)})
+}
+
+// UnmarshalJSON decodes a body written by MarshalJSON.
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*b = Body(s)
+		return nil
+	}
+
+	var encoded struct {
+		Base64 string `json:"base64"`
+	}
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return fmt.Errorf("invalid cassette body: %w", err)
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(encoded.Base64)
+	if err != nil {
+		return fmt.Errorf("invalid cassette body: %w", err)
+	}
+	*b = decoded
+	return nil
+}
+
+// Load reads a cassette file.
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+
+	if c.Version != Version {
+		return nil, fmt.Errorf("cassette %s has unsupported version %d", path, c.Version)
+	}
+
+	return &c, nil
+}
+
+// Save writes the cassette to path as indented JSON, creating parent directories as needed.
+func (c *Cassette) Save(path string) error {
+	c.Version = Version
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cassette: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+
+	return nil
+}
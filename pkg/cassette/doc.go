@@ -0,0 +1,45 @@
+// Package cassette records and replays HTTP exchanges with LLM providers.
+//
+// A Recorder is an http.RoundTripper installed on a client with
+// client.WithTransport. In record mode it forwards requests to the real
+// provider and captures each exchange; in replay mode it serves the recorded
+// responses without network access. Because only the transport is replaced,
+// replayed tests exercise the real request marshaling, provider preparation,
+// and response parsing.
+//
+// # Recording and Replaying
+//
+//	rec, err := cassette.New("testdata/chat.json")
+//	if err != nil {
+//	    t.Fatal(err)
+//	}
+//	t.Cleanup(func() { rec.Save() })
+//
+//	a, err := agent.New(cfg, agent.WithClientOptions(client.WithTransport(rec)))
+//
+// The default ModeAuto replays an existing cassette and records a missing one.
+// Set GOAGENTS_CASSETTE_MODE (ModeEnv) to "record" to refresh cassettes against
+// live providers, or to "replay" in CI to fail instead of reaching the network.
+//
+// # Streams
+//
+// Server-sent event streams are recorded as Chunks, one per event, with the
+// delay since the previous event. Replay delivers them immediately unless
+// WithRealtime is set.
+//
+// # Scrubbing
+//
+// Secrets are redacted before interactions are stored: the headers in
+// DefaultScrubHeaders, the query parameters in DefaultScrubParams, any literal
+// values passed to WithRedact, and anything removed by WithScrubber functions.
+// Incoming requests are scrubbed the same way during replay so they match
+// their recordings.
+//
+// # Matching
+//
+// Replayed requests are matched with DefaultMatcher: method, URL (ignoring
+// query parameter order), and body (comparing JSON structurally). Compose
+// MatchMethod, MatchURL, MatchBody, and MatchBodyIgnoring with MatchAll for
+// other policies. Each recorded interaction is served once; identical
+// requests replay successive recordings in order.
+package cassette
@@ -0,0 +1,82 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+)
+
+// Matcher reports whether an incoming request matches a recorded request.
+// Both requests have been scrubbed, so redacted values compare equal.
+type Matcher func(incoming, recorded *Request) bool
+
+// DefaultMatcher matches requests by method, URL, and normalized body.
+var DefaultMatcher = MatchAll(MatchMethod, MatchURL, MatchBody)
+
+// MatchAll returns a Matcher that requires every matcher to match.
+func MatchAll(matchers ...Matcher) Matcher {
+	return func(incoming, recorded *Request) bool {
+		for _, m := range matchers {
+			if !m(incoming, recorded) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// MatchMethod matches requests with the same HTTP method.
+func MatchMethod(incoming, recorded *Request) bool {
+	return incoming.Method == recorded.Method
+}
+
+// MatchURL matches requests with the same URL. Query parameters are
+// compared regardless of order.
+func MatchURL(incoming, recorded *Request) bool {
+	return normalizeURL(incoming.URL) == normalizeURL(recorded.URL)
+}
+
+// MatchBody matches requests with equivalent bodies.
+// JSON bodies are compared structurally, ignoring key order and whitespace.
+func MatchBody(incoming, recorded *Request) bool {
+	return bytes.Equal(normalizeBody(incoming.Body, nil), normalizeBody(recorded.Body, nil))
+}
+
+// MatchBodyIgnoring matches requests with equivalent JSON bodies after
+// removing the given top-level fields, such as "user" or "seed".
+func MatchBodyIgnoring(fields ...string) Matcher {
+	return func(incoming, recorded *Request) bool {
+		return bytes.Equal(normalizeBody(incoming.Body, fields), normalizeBody(recorded.Body, fields))
+	}
+}
+
+// normalizeURL re-encodes the query string with sorted keys.
+func normalizeURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	u.RawQuery = u.Query().Encode()
+	return u.String()
+}
+
+// normalizeBody re-encodes a JSON body with sorted keys and without the
+// ignored top-level fields. Non-JSON bodies are returned unchanged.
+func normalizeBody(body []byte, ignore []string) []byte {
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return body
+	}
+
+	if obj, ok := v.(map[string]any); ok {
+		for _, field := range ignore {
+			delete(obj, field)
+		}
+	}
+
+	normalized, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return normalized
+}
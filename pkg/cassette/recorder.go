@@ -0,0 +1,527 @@
+package cassette
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JaimeStill/go-agents/pkg/config"
+)
+
+// ModeEnv is the environment variable that selects the recorder mode
+// when no mode is set with WithMode.
+const ModeEnv = "GOAGENTS_CASSETTE_MODE"
+
+// Redacted replaces scrubbed secret values in recorded interactions.
+const Redacted = "REDACTED"
+
+// DefaultScrubHeaders are the request and response headers whose values are redacted.
+var DefaultScrubHeaders = []string{
+	"Authorization",
+	"Api-Key",
+	"X-Api-Key",
+	"Ocp-Apim-Subscription-Key",
+	"Cookie",
+	"Set-Cookie",
+}
+
+// DefaultScrubParams are the URL query parameters whose values are redacted.
+var DefaultScrubParams = []string{"api-key", "api_key", "key", "sig"}
+
+// ErrNoInteraction is returned in replay mode when no unused recorded
+// interaction matches a request.
+var ErrNoInteraction = errors.New("no matching cassette interaction")
+
+// Mode selects whether a Recorder records or replays interactions.
+type Mode string
+
+const (
+	// ModeAuto replays the cassette if its file exists and records it otherwise.
+	ModeAuto Mode = "auto"
+
+	// ModeRecord sends requests to the real transport and records them,
+	// replacing any existing cassette on Save.
+	ModeRecord Mode = "record"
+
+	// ModeReplay serves requests from the cassette without network access.
+	ModeReplay Mode = "replay"
+)
+
+// ParseMode parses a mode name: "auto", "record", or "replay".
+func ParseMode(name string) (Mode, error) {
+	switch mode := Mode(strings.ToLower(name)); mode {
+	case ModeAuto, ModeRecord, ModeReplay:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown cassette mode %q (expected auto, record, or replay)", name)
+	}
+}
+
+// Option configures a Recorder.
+type Option func(*Recorder)
+
+// WithMode sets the recorder mode, overriding ModeEnv.
+func WithMode(mode Mode) Option {
+	return func(r *Recorder) {
+		r.mode = mode
+	}
+}
+
+// WithRealTransport sets the transport used to reach the provider while
+// recording. The default is http.DefaultTransport.
+func WithRealTransport(rt http.RoundTripper) Option {
+	return func(r *Recorder) {
+		r.transport = rt
+	}
+}
+
+// WithMatcher sets how incoming requests are matched to recorded ones
+// during replay. The default is DefaultMatcher.
+func WithMatcher(m Matcher) Option {
+	return func(r *Recorder) {
+		r.matcher = m
+	}
+}
+
+// WithScrubHeaders redacts the values of additional headers.
+func WithScrubHeaders(names ...string) Option {
+	return func(r *Recorder) {
+		r.headers = append(r.headers, names...)
+	}
+}
+
+// WithScrubParams redacts the values of additional URL query parameters.
+func WithScrubParams(names ...string) Option {
+	return func(r *Recorder) {
+		r.params = append(r.params, names...)
+	}
+}
+
+// WithRedact replaces every occurrence of the given secret values, such as
+// tokens loaded from the environment, in recorded URLs, headers, and bodies.
+// Empty values are ignored.
+func WithRedact(secrets ...string) Option {
+	return func(r *Recorder) {
+		for _, s := range secrets {
+			if s != "" {
+				r.secrets = append(r.secrets, s)
+			}
+		}
+	}
+}
+
+// WithScrubber adds a function that scrubs each interaction before it is
+// recorded. Scrubbers also run on incoming requests during replay so that
+// matching compares scrubbed values.
+func WithScrubber(fn func(*Interaction)) Option {
+	return func(r *Recorder) {
+		r.scrubbers = append(r.scrubbers, fn)
+	}
+}
+
+// WithRealtime replays recorded streams with their original delays between
+// events. By default streams are replayed without delay.
+func WithRealtime() Option {
+	return func(r *Recorder) {
+		r.realtime = true
+	}
+}
+
+// Recorder is an http.RoundTripper that records provider exchanges into a
+// cassette file or replays them from it. Install it on a client with
+// client.WithTransport.
+//
+// In replay mode each recorded interaction is served at most once, in
+// recorded order among interactions that match, so repeated identical
+// requests replay successive responses.
+type Recorder struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+	matcher   Matcher
+	headers   []string
+	params    []string
+	secrets   []string
+	scrubbers []func(*Interaction)
+	realtime  bool
+
+	mutex    sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+// New creates a recorder for the cassette file at path.
+// In replay mode, or auto mode when the file exists, the cassette is loaded
+// immediately. Returns an error if the mode is invalid or the cassette
+// cannot be loaded.
+func New(path string, opts ...Option) (*Recorder, error) {
+	r := &Recorder{
+		path:      path,
+		transport: http.DefaultTransport,
+		matcher:   DefaultMatcher,
+		headers:   slices.Clone(DefaultScrubHeaders),
+		params:    slices.Clone(DefaultScrubParams),
+	}
+
+	if env := os.Getenv(ModeEnv); env != "" {
+		mode, err := ParseMode(env)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ModeEnv, err)
+		}
+		r.mode = mode
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	switch r.mode {
+	case "", ModeAuto:
+		if _, err := os.Stat(path); err == nil {
+			r.mode = ModeReplay
+		} else {
+			r.mode = ModeRecord
+		}
+	case ModeRecord, ModeReplay:
+	default:
+		return nil, fmt.Errorf("unknown cassette mode %q", r.mode)
+	}
+
+	if r.mode == ModeReplay {
+		c, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		r.cassette = c
+		r.used = make([]bool, len(c.Interactions))
+	} else {
+		r.cassette = &Cassette{Version: Version}
+	}
+
+	return r, nil
+}
+
+// Mode returns the resolved mode: ModeRecord or ModeReplay.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Save writes recorded interactions to the cassette file.
+// Does nothing in replay mode.
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.cassette.Save(r.path)
+}
+
+// Unused returns the recorded interactions that have not been replayed.
+// Useful for asserting that a test exercised the whole cassette.
+func (r *Recorder) Unused() []Interaction {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var unused []Interaction
+	for i, used := range r.used {
+		if !used {
+			unused = append(unused, r.cassette.Interactions[i])
+		}
+	}
+	return unused
+}
+
+// RoundTrip records or replays a single HTTP exchange.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, body)
+	}
+	return r.record(req, body)
+}
+
+// record forwards the request to the real transport and records the exchange.
+// Event streams are recorded as the caller reads them.
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	interaction := Interaction{
+		Request: Request{
+			Method:  req.Method,
+			URL:     req.URL.String(),
+			Headers: req.Header.Clone(),
+			Body:    body,
+		},
+		Response: Response{
+			Status:  resp.StatusCode,
+			Headers: resp.Header.Clone(),
+		},
+	}
+
+	if isEventStream(resp) {
+		resp.Body = &streamRecorder{
+			body: resp.Body,
+			last: time.Now(),
+			done: func(chunks []Chunk) {
+				interaction.Response.Chunks = chunks
+				r.append(interaction)
+			},
+		}
+		return resp, nil
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response for cassette: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+
+	interaction.Response.Body = data
+	r.append(interaction)
+
+	return resp, nil
+}
+
+// append scrubs and stores a recorded interaction.
+func (r *Recorder) append(interaction Interaction) {
+	r.scrub(&interaction)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+}
+
+// replay serves the first unused recorded interaction matching the request.
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	incoming := Interaction{Request: Request{
+		Method:  req.Method,
+		URL:     req.URL.String(),
+		Headers: req.Header.Clone(),
+		Body:    body,
+	}}
+	r.scrub(&incoming)
+
+	r.mutex.Lock()
+	var match *Interaction
+	for i := range r.cassette.Interactions {
+		if !r.used[i] && r.matcher(&incoming.Request, &r.cassette.Interactions[i].Request) {
+			r.used[i] = true
+			match = &r.cassette.Interactions[i]
+			break
+		}
+	}
+	r.mutex.Unlock()
+
+	if match == nil {
+		return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, incoming.Request.Method, incoming.Request.URL)
+	}
+
+	resp := &http.Response{
+		StatusCode: match.Response.Status,
+		Status:     fmt.Sprintf("%d %s", match.Response.Status, http.StatusText(match.Response.Status)),
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     match.Response.Headers.Clone(),
+		Request:    req,
+	}
+	if resp.Header == nil {
+		resp.Header = make(http.Header)
+	}
+
+	switch {
+	case len(match.Response.Chunks) > 0 && r.realtime:
+		resp.Body = replayStream(req, match.Response.Chunks)
+		resp.ContentLength = -1
+	case len(match.Response.Chunks) > 0:
+		var data bytes.Buffer
+		for _, chunk := range match.Response.Chunks {
+			data.WriteString(chunk.Data)
+		}
+		resp.Body = io.NopCloser(&data)
+		resp.ContentLength = int64(data.Len())
+	default:
+		resp.Body = io.NopCloser(bytes.NewReader(match.Response.Body))
+		resp.ContentLength = int64(len(match.Response.Body))
+	}
+
+	return resp, nil
+}
+
+// scrub redacts secrets from an interaction and applies custom scrubbers.
+func (r *Recorder) scrub(interaction *Interaction) {
+	req := &interaction.Request
+	resp := &interaction.Response
+
+	if u, err := url.Parse(req.URL); err == nil {
+		query := u.Query()
+		changed := false
+		for _, param := range r.params {
+			if query.Has(param) {
+				query.Set(param, Redacted)
+				changed = true
+			}
+		}
+		if changed {
+			u.RawQuery = query.Encode()
+			req.URL = u.String()
+		}
+	}
+
+	for _, name := range r.headers {
+		for _, h := range []http.Header{req.Headers, resp.Headers} {
+			if h.Get(name) != "" {
+				h.Set(name, Redacted)
+			}
+		}
+	}
+
+	if len(r.secrets) > 0 {
+		req.URL = r.redact(req.URL)
+		req.Body = Body(r.redact(string(req.Body)))
+		resp.Body = Body(r.redact(string(resp.Body)))
+		for i := range resp.Chunks {
+			resp.Chunks[i].Data = r.redact(resp.Chunks[i].Data)
+		}
+		for _, h := range []http.Header{req.Headers, resp.Headers} {
+			for _, values := range h {
+				for i := range values {
+					values[i] = r.redact(values[i])
+				}
+			}
+		}
+	}
+
+	for _, fn := range r.scrubbers {
+		fn(interaction)
+	}
+}
+
+// redact replaces every configured secret in s.
+func (r *Recorder) redact(s string) string {
+	for _, secret := range r.secrets {
+		s = strings.ReplaceAll(s, secret, Redacted)
+	}
+	return s
+}
+
+// readRequestBody reads the request body and restores it for the real transport.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	data, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request for cassette: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(data))
+
+	return data, nil
+}
+
+// isEventStream reports whether the response is a server-sent event stream.
+func isEventStream(resp *http.Response) bool {
+	return strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream")
+}
+
+// streamRecorder passes an event stream through to the caller while
+// splitting it into timed chunks. The chunks are reported once the stream
+// ends or is closed.
+type streamRecorder struct {
+	body    io.ReadCloser
+	pending []byte
+	chunks  []Chunk
+	last    time.Time
+	once    sync.Once
+	done    func([]Chunk)
+}
+
+func (s *streamRecorder) Read(p []byte) (int, error) {
+	n, err := s.body.Read(p)
+	if n > 0 {
+		s.pending = append(s.pending, p[:n]...)
+		for {
+			i := bytes.Index(s.pending, []byte("\n\n"))
+			if i < 0 {
+				break
+			}
+			s.emit(s.pending[:i+2])
+			s.pending = s.pending[i+2:]
+		}
+	}
+	if err == io.EOF {
+		s.finish()
+	}
+	return n, err
+}
+
+func (s *streamRecorder) Close() error {
+	s.finish()
+	return s.body.Close()
+}
+
+// emit records one event with the delay since the previous event.
+func (s *streamRecorder) emit(data []byte) {
+	now := time.Now()
+	s.chunks = append(s.chunks, Chunk{
+		Data:  string(data),
+		Delay: config.Duration(now.Sub(s.last)),
+	})
+	s.last = now
+}
+
+// finish records any partial trailing event and reports the chunks once.
+func (s *streamRecorder) finish() {
+	s.once.Do(func() {
+		if len(s.pending) > 0 {
+			s.emit(s.pending)
+			s.pending = nil
+		}
+		s.done(s.chunks)
+	})
+}
+
+// replayStream returns a body that writes recorded chunks with their
+// original delays, stopping early if the request is cancelled.
+func replayStream(req *http.Request, chunks []Chunk) io.ReadCloser {
+	reader, writer := io.Pipe()
+
+	go func() {
+		ctx := req.Context()
+		for _, chunk := range chunks {
+			select {
+			case <-time.After(chunk.Delay.ToDuration()):
+			case <-ctx.Done():
+				writer.CloseWithError(ctx.Err())
+				return
+			}
+			if _, err := writer.Write([]byte(chunk.Data)); err != nil {
+				return
+			}
+		}
+		writer.Close()
+	}()
+
+	return reader
+}
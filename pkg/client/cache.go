@@ -0,0 +1,291 @@
+package client
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/JaimeStill/go-agents/pkg/config"
+)
+
+// DefaultCacheCapacity is the number of entries held by a MemoryCache when no capacity is set.
+const DefaultCacheCapacity = 256
+
+// Cache stores raw response payloads keyed by request content.
+// Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the payload for key and whether a valid entry was found.
+	// Expired entries are treated as missing.
+	Get(key string) ([]byte, bool)
+
+	// Set stores a payload for key. A ttl of zero means the entry never expires.
+	Set(key string, value []byte, ttl time.Duration)
+
+	// Delete removes the entry for key if present.
+	Delete(key string)
+}
+
+// CacheKey derives a cache key from the request identity.
+// The key is the hex-encoded SHA-256 of the provider name, endpoint URL,
+// model name, and marshaled request body.
+func CacheKey(provider, endpoint, model string, body []byte) string {
+	h := sha256.New()
+	for _, part := range []string{provider, endpoint, model} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+type cacheBypassKey struct{}
+
+// WithCacheBypass returns a context that skips the response cache for requests
+// executed with it. The response is neither read from nor written to the cache.
+func WithCacheBypass(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cacheBypassKey{}, true)
+}
+
+// cacheBypassed reports whether the context requests a cache bypass.
+func cacheBypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(cacheBypassKey{}).(bool)
+	return bypass
+}
+
+// CacheReport describes whether a request was served from the response cache.
+type CacheReport struct {
+	// Hit is true if the response was read from the cache instead of the provider.
+	Hit bool
+}
+
+type cacheReportKey struct{}
+
+// WithCacheReport returns a context that collects a CacheReport for a request
+// executed with it. The report is populated when the call returns.
+func WithCacheReport(ctx context.Context) (context.Context, *CacheReport) {
+	report := &CacheReport{}
+	return context.WithValue(ctx, cacheReportKey{}, report), report
+}
+
+// reportCacheHit marks the request's CacheReport, if any, as served from the cache.
+func reportCacheHit(ctx context.Context) {
+	if report, ok := ctx.Value(cacheReportKey{}).(*CacheReport); ok {
+		report.Hit = true
+	}
+}
+
+// cachedResponse is the cache entry of a non-streaming response.
+// ContentType preserves the server-reported type of binary responses,
+// which cannot be recovered by re-parsing the body.
+type cachedResponse struct {
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body"`
+}
+
+// NewCache creates a Cache from configuration.
+// Returns nil if cfg is nil.
+func NewCache(cfg *config.CacheConfig) Cache {
+	if cfg == nil {
+		return nil
+	}
+
+	if cfg.Backend == config.CacheBackendDisk {
+		return NewDiskCache(cfg.Dir)
+	}
+
+	return NewMemoryCache(cfg.MaxEntries())
+}
+
+// MemoryCache is an in-memory Cache that evicts the least recently used entry
+// when full.
+type MemoryCache struct {
+	capacity int
+
+	mutex   sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type memoryEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryCache creates an LRU cache holding at most capacity entries.
+// A capacity of zero or less uses DefaultCacheCapacity.
+func NewMemoryCache(capacity int) *MemoryCache {
+	if capacity <= 0 {
+		capacity = DefaultCacheCapacity
+	}
+
+	return &MemoryCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Get returns the payload for key and marks it as recently used.
+func (c *MemoryCache) Get(key string) ([]byte, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*memoryEntry)
+	if expired(entry.expires) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+	return entry.value, true
+}
+
+// Set stores a payload for key, evicting the least recently used entry if full.
+func (c *MemoryCache) Set(key string, value []byte, ttl time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry := &memoryEntry{key: key, value: value, expires: expiry(ttl)}
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*memoryEntry).key)
+	}
+}
+
+// Delete removes the entry for key.
+func (c *MemoryCache) Delete(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+	}
+}
+
+// Len returns the number of entries currently held.
+func (c *MemoryCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.order.Len()
+}
+
+// DiskCache is a Cache that stores each entry as a JSON file in a directory.
+// Entries persist across process restarts.
+type DiskCache struct {
+	dir   string
+	mutex sync.Mutex
+}
+
+type diskEntry struct {
+	Expires time.Time `json:"expires,omitzero"`
+	Value   []byte    `json:"value"`
+}
+
+// NewDiskCache creates a disk cache rooted at dir.
+// The directory is created on first write.
+func NewDiskCache(dir string) *DiskCache {
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "go-agents-cache")
+	}
+	return &DiskCache{dir: dir}
+}
+
+// Get reads the entry for key, removing it if expired.
+// Unreadable entries are treated as missing.
+func (c *DiskCache) Get(key string) ([]byte, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	var entry diskEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+
+	if expired(entry.Expires) {
+		os.Remove(c.path(key))
+		return nil, false
+	}
+
+	return entry.Value, true
+}
+
+// Set writes the entry for key. Write failures are ignored so caching
+// never causes a request to fail.
+func (c *DiskCache) Set(key string, value []byte, ttl time.Duration) {
+	data, err := json.Marshal(diskEntry{Expires: expiry(ttl), Value: value})
+	if err != nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return
+	}
+
+	path := c.path(key)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+	}
+}
+
+// Delete removes the entry file for key.
+func (c *DiskCache) Delete(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	os.Remove(c.path(key))
+}
+
+// path returns the file path for a cache key.
+func (c *DiskCache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+// expiry returns the expiration time for a ttl, or the zero time if ttl is zero.
+func expiry(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(ttl)
+}
+
+// expired reports whether an expiration time has passed.
+// The zero time never expires.
+func expired(expires time.Time) bool {
+	return !expires.IsZero() && time.Now().After(expires)
+}
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ErrorCategory classifies a request failure by its likely cause.
+// Categories let callers decide whether a failure is worth retrying elsewhere.
+type ErrorCategory string
+
+const (
+	// CategoryNone indicates no error.
+	CategoryNone ErrorCategory = ""
+
+	// CategoryRateLimit indicates the provider throttled the request (HTTP 429).
+	CategoryRateLimit ErrorCategory = "rate_limit"
+
+	// CategoryServer indicates a provider-side failure (HTTP 5xx).
+	CategoryServer ErrorCategory = "server"
+
+	// CategoryTimeout indicates the request timed out.
+	CategoryTimeout ErrorCategory = "timeout"
+
+	// CategoryNetwork indicates a connection or DNS failure.
+	CategoryNetwork ErrorCategory = "network"
+
+	// CategoryAuth indicates rejected credentials or permissions (HTTP 401/403).
+	CategoryAuth ErrorCategory = "auth"
+
+	// CategoryContextLength indicates the prompt exceeded the model's context window.
+	CategoryContextLength ErrorCategory = "context_length"
+
+	// CategoryContentFilter indicates the provider's content filter rejected the request.
+	CategoryContentFilter ErrorCategory = "content_filter"
+
+	// CategoryUnsupported indicates the model does not support the requested capability.
+	CategoryUnsupported ErrorCategory = "unsupported"
+
+	// CategoryInvalidRequest indicates the provider rejected the request as malformed (other HTTP 4xx).
+	CategoryInvalidRequest ErrorCategory = "invalid_request"
+
+	// CategoryCanceled indicates the caller cancelled the request.
+	CategoryCanceled ErrorCategory = "canceled"
+
+	// CategoryUnknown indicates an error that matches no other category.
+	CategoryUnknown ErrorCategory = "unknown"
+)
+
+// Categorize classifies an error returned by Execute or ExecuteStream.
+// HTTP errors are classified by status code, refined by provider error codes
+// in the response body for context length and content filter rejections.
+func Categorize(err error) ErrorCategory {
+	if err == nil {
+		return CategoryNone
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return CategoryCanceled
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return CategoryTimeout
+	}
+
+	var httpErr *HTTPStatusError
+	if errors.As(err, &httpErr) {
+		return categorizeStatus(httpErr)
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return CategoryTimeout
+		}
+		return CategoryNetwork
+	}
+
+	return CategoryUnknown
+}
+
+// categorizeStatus classifies an HTTP status error.
+func categorizeStatus(err *HTTPStatusError) ErrorCategory {
+	switch code := err.StatusCode; {
+	case code == http.StatusTooManyRequests:
+		return CategoryRateLimit
+	case code == http.StatusRequestTimeout || code == http.StatusGatewayTimeout:
+		return CategoryTimeout
+	case code == http.StatusUnauthorized || code == http.StatusForbidden:
+		return CategoryAuth
+	case code >= 500:
+		return CategoryServer
+	}
+
+	body := string(err.Body)
+	switch {
+	case strings.Contains(body, "context_length_exceeded"),
+		strings.Contains(body, "maximum context length"):
+		return CategoryContextLength
+	case strings.Contains(body, "content_filter"):
+		return CategoryContentFilter
+	}
+
+	return CategoryInvalidRequest
+}
@@ -3,6 +3,7 @@ package client
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -10,6 +11,8 @@ import (
 	"time"
 
 	"github.com/JaimeStill/go-agents/pkg/config"
+	"github.com/JaimeStill/go-agents/pkg/protocol"
+	"github.com/JaimeStill/go-agents/pkg/providers"
 	"github.com/JaimeStill/go-agents/pkg/request"
 	"github.com/JaimeStill/go-agents/pkg/response"
 )
@@ -42,32 +45,76 @@ type Client interface {
 
 // client implements the Client interface with HTTP orchestration.
 type client struct {
-	config *config.ClientConfig
+	config    *config.ClientConfig
+	cache     Cache
+	cacheTTL  time.Duration
+	transport http.RoundTripper
 
 	mutex      sync.RWMutex
 	healthy    bool
 	lastHealth time.Time
 }
 
+// Option configures optional client behavior.
+type Option func(*client)
+
+// WithCache enables response caching with the given cache and entry TTL.
+// Overrides any cache configured through ClientConfig.Cache.
+// A ttl of zero means entries never expire.
+func WithCache(cache Cache, ttl time.Duration) Option {
+	return func(c *client) {
+		c.cache = cache
+		c.cacheTTL = ttl
+	}
+}
+
+// WithTransport sets the HTTP transport used for requests, such as a
+// cassette.Recorder for recording and replaying provider exchanges.
+// The transport replaces the connection pool configured through ClientConfig.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *client) {
+		c.transport = rt
+	}
+}
+
 // New creates a new Client from configuration.
-// Initializes HTTP settings and health tracking.
-func New(cfg *config.ClientConfig) Client {
-	return &client{
+// Initializes HTTP settings, health tracking, and the response cache when configured.
+func New(cfg *config.ClientConfig, opts ...Option) Client {
+	c := &client{
 		config:     cfg,
 		healthy:    true,
 		lastHealth: time.Now(),
 	}
+
+	if cfg.Cache != nil {
+		c.cache = NewCache(cfg.Cache)
+		c.cacheTTL = cfg.Cache.EntryTTL()
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
 }
 
 // HTTPClient creates and returns a configured HTTP client.
-// Each call creates a new client with timeout and connection pool settings from configuration.
+// Each call creates a new client with timeout and connection pool settings from configuration,
+// or with the transport set by WithTransport.
 func (c *client) HTTPClient() *http.Client {
+	if c.transport != nil {
+		return &http.Client{
+			Timeout:   c.config.RequestTimeout(),
+			Transport: c.transport,
+		}
+	}
+
 	return &http.Client{
-		Timeout: c.config.Timeout.ToDuration(),
+		Timeout: c.config.RequestTimeout(),
 		Transport: &http.Transport{
-			MaxIdleConns:        c.config.ConnectionPoolSize,
-			MaxIdleConnsPerHost: c.config.ConnectionPoolSize,
-			IdleConnTimeout:     c.config.ConnectionTimeout.ToDuration(),
+			MaxIdleConns:        c.config.PoolSize(),
+			MaxIdleConnsPerHost: c.config.PoolSize(),
+			IdleConnTimeout:     c.config.IdleTimeout(),
 		},
 	}
 }
@@ -99,6 +146,18 @@ func (c *client) execute(ctx context.Context, req request.Request) (any, error)
 		return nil, fmt.Errorf("failed to prepare request: %w", err)
 	}
 
+	// Serve identical requests from the cache when enabled
+	cacheKey := c.cacheKey(ctx, req, providerRequest)
+	if cacheKey != "" {
+		if data, ok := c.cache.Get(cacheKey); ok {
+			if result, err := parseCached(proto, data); err == nil {
+				reportCacheHit(ctx)
+				return result, nil
+			}
+			c.cache.Delete(cacheKey)
+		}
+	}
+
 	// Create HTTP request
 	httpReq, err := http.NewRequestWithContext(
 		ctx,
@@ -136,6 +195,17 @@ func (c *client) execute(ctx context.Context, req request.Request) (any, error)
 		}
 	}
 
+	// Capture the raw body for caching before the provider consumes it
+	var raw []byte
+	if cacheKey != "" {
+		raw, err = io.ReadAll(resp.Body)
+		if err != nil {
+			c.setHealthy(false)
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(raw))
+	}
+
 	// Process response through provider
 	result, err := provider.ProcessResponse(ctx, resp, proto)
 	if err != nil {
@@ -143,6 +213,16 @@ func (c *client) execute(ctx context.Context, req request.Request) (any, error)
 		return nil, err
 	}
 
+	if cacheKey != "" {
+		entry := cachedResponse{Body: raw}
+		if speech, ok := result.(*response.SpeechResponse); ok {
+			entry.ContentType = speech.ContentType
+		}
+		if data, err := json.Marshal(entry); err == nil {
+			c.cache.Set(cacheKey, data, c.cacheTTL)
+		}
+	}
+
 	c.setHealthy(true)
 	return result, nil
 }
@@ -179,6 +259,19 @@ func (c *client) executeStream(ctx context.Context, req request.Request) (<-chan
 		return nil, fmt.Errorf("failed to prepare streaming request: %w", err)
 	}
 
+	// Replay identical streams from the cache when enabled
+	cacheKey := c.cacheKey(ctx, req, providerRequest)
+	if cacheKey != "" {
+		if data, ok := c.cache.Get(cacheKey); ok {
+			var chunks []*response.StreamingChunk
+			if err := json.Unmarshal(data, &chunks); err == nil {
+				reportCacheHit(ctx)
+				return replayStream(ctx, chunks), nil
+			}
+			c.cache.Delete(cacheKey)
+		}
+	}
+
 	// Create HTTP request
 	httpReq, err := http.NewRequestWithContext(
 		ctx,
@@ -209,7 +302,11 @@ func (c *client) executeStream(ctx context.Context, req request.Request) (<-chan
 		bodyBytes, _ := io.ReadAll(resp.Body)
 		resp.Body.Close()
 		c.setHealthy(false)
-		return nil, fmt.Errorf("streaming request failed with status %d: %s", resp.StatusCode, string(bodyBytes))
+		return nil, fmt.Errorf("streaming request failed: %w", &HTTPStatusError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			Body:       bodyBytes,
+		})
 	}
 
 	// Process stream through provider
@@ -226,8 +323,15 @@ func (c *client) executeStream(ctx context.Context, req request.Request) (<-chan
 		defer close(output)
 		defer resp.Body.Close()
 
+		var recorded []*response.StreamingChunk
+		complete := true
+
 		for data := range stream {
 			if chunk, ok := data.(*response.StreamingChunk); ok {
+				if cacheKey != "" {
+					recorded = append(recorded, chunk)
+					complete = complete && chunk.Error == nil
+				}
 				select {
 				case output <- chunk:
 				case <-ctx.Done():
@@ -235,12 +339,73 @@ func (c *client) executeStream(ctx context.Context, req request.Request) (<-chan
 				}
 			}
 		}
+
+		// Only cache streams that ran to completion without errors
+		if cacheKey != "" && complete && ctx.Err() == nil {
+			if data, err := json.Marshal(recorded); err == nil {
+				c.cache.Set(cacheKey, data, c.cacheTTL)
+			}
+		}
+
 		c.setHealthy(true)
 	}()
 
 	return output, nil
 }
 
+// cacheKey returns the cache key for a prepared request.
+// Returns an empty string when caching is disabled or bypassed for the context.
+func (c *client) cacheKey(ctx context.Context, req request.Request, prepared *providers.Request) string {
+	if c.cache == nil || cacheBypassed(ctx) {
+		return ""
+	}
+
+	var modelName string
+	if m := req.Model(); m != nil {
+		modelName = m.Name
+	}
+
+	return CacheKey(req.Provider().Name(), prepared.URL, modelName, prepared.Body)
+}
+
+// parseCached parses a cached response entry, restoring the content type of
+// speech responses.
+func parseCached(proto protocol.Protocol, data []byte) (any, error) {
+	var entry cachedResponse
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+
+	result, err := response.Parse(proto, entry.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if speech, ok := result.(*response.SpeechResponse); ok && entry.ContentType != "" {
+		speech.ContentType = entry.ContentType
+	}
+
+	return result, nil
+}
+
+// replayStream emits cached chunks on a channel as if they were streamed.
+// The channel is closed after the last chunk or when the context is cancelled.
+func replayStream(ctx context.Context, chunks []*response.StreamingChunk) <-chan *response.StreamingChunk {
+	output := make(chan *response.StreamingChunk)
+	go func() {
+		defer close(output)
+
+		for _, chunk := range chunks {
+			select {
+			case output <- chunk:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return output
+}
+
 // IsHealthy returns the current health status.
 // Thread-safe for concurrent access via read mutex.
 func (c *client) IsHealthy() bool {
@@ -142,16 +142,21 @@
 //
 // The client creates HTTP clients with configured timeouts and connection pooling:
 //
-//	cfg := &config.TransportConfig{
-//	    Timeout:            config.Duration(30 * time.Second),  // Request timeout
-//	    ConnectionTimeout:  config.Duration(10 * time.Second),  // Idle connection timeout
-//	    ConnectionPoolSize: 10,                                  // Max idle connections
-//	    Provider:           providerConfig,
-//	}
+//	timeout, idle, poolSize := config.Duration(30*time.Second), config.Duration(10*time.Second), 10
+//
+//	cfg := config.DefaultClientConfig()
+//	cfg.Merge(&config.ClientConfig{
+//	    Timeout:            &timeout,  // Request timeout; 0 disables it
+//	    ConnectionTimeout:  &idle,     // Idle connection timeout
+//	    ConnectionPoolSize: &poolSize, // Max idle connections; 0 means no limit
+//	})
 //
 // Each protocol execution creates a new HTTP client with these settings.
 // Connection pooling is managed by the http.Transport to reuse connections efficiently.
 //
+// WithTransport replaces the transport, for example with a cassette.Recorder
+// that records and replays provider exchanges in tests.
+//
 // # Health Tracking
 //
 // The client tracks health status based on request success/failure:
@@ -245,6 +250,30 @@
 //	    fmt.Print(chunk.Content())
 //	}
 //
+// # Response Caching
+//
+// Identical requests can be served from a cache keyed by provider, endpoint,
+// model, and marshaled body. Enable caching through ClientConfig.Cache or
+// the WithCache option:
+//
+//	c := client.New(cfg, client.WithCache(client.NewMemoryCache(512), time.Hour))
+//
+// MemoryCache evicts the least recently used entry when full; DiskCache
+// persists entries across restarts. Non-streaming responses are cached as raw
+// bytes and re-parsed on a hit; speech responses keep the content type
+// reported by the server. Completed streams are cached as their chunks and
+// replayed in order. Use WithCacheBypass to skip the cache for a call:
+//
+//	result, err := c.Execute(client.WithCacheBypass(ctx), req)
+//
+// WithCacheReport reports whether a call was served from the cache:
+//
+//	ctx, report := client.WithCacheReport(ctx)
+//	result, err := c.Execute(ctx, req)
+//	if report.Hit {
+//	    // no provider request was made
+//	}
+//
 // # Thread Safety
 //
 // Clients are safe for concurrent use:
@@ -87,17 +87,17 @@ func calculateBackoff(attempt int, cfg config.RetryConfig) time.Duration {
 	maxAttempt := min(attempt, 10)
 
 	// Calculate exponential backoff: initialBackoff * (2^attempt)
-	delay := time.Duration(cfg.InitialBackoff) * time.Duration(1<<uint(maxAttempt))
+	delay := cfg.InitialDelay() * time.Duration(1<<uint(maxAttempt))
 
 	// Apply jitter (±25% randomization) if enabled
-	if cfg.Jitter {
+	if cfg.JitterEnabled() {
 		jitterRange := delay / 4
 		jitter := time.Duration(rand.Int63n(int64(jitterRange)*2)) - jitterRange
 		delay += jitter
 	}
 
 	// Cap at MaxBackoff
-	return min(delay, time.Duration(cfg.MaxBackoff))
+	return min(delay, cfg.MaxDelay())
 }
 
 // doWithRetry executes an operation with retry logic.
@@ -114,7 +114,8 @@ func doWithRetry[T any](
 	var result T
 	var lastErr error
 
-	for attempt := 0; attempt <= cfg.MaxRetries; attempt++ {
+	maxRetries := cfg.Retries()
+	for attempt := 0; attempt <= maxRetries; attempt++ {
 		// Check context cancellation before retry
 		if err := ctx.Err(); err != nil {
 			return result, fmt.Errorf("operation cancelled: %w", err)
@@ -132,7 +133,7 @@ func doWithRetry[T any](
 		}
 
 		// Don't sleep after last attempt
-		if attempt < cfg.MaxRetries {
+		if attempt < maxRetries {
 			delay := calculateBackoff(attempt, cfg)
 
 			select {
@@ -144,5 +145,5 @@ func doWithRetry[T any](
 		}
 	}
 
-	return result, fmt.Errorf("max retries (%d) exceeded: %w", cfg.MaxRetries, lastErr)
+	return result, fmt.Errorf("max retries (%d) exceeded: %w", maxRetries, lastErr)
 }
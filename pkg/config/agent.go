@@ -3,7 +3,6 @@ package config
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 )
 
 // AgentConfig defines the complete configuration for an agent.
@@ -64,14 +63,18 @@ func (c *AgentConfig) Merge(source *AgentConfig) {
 	}
 }
 
-// LoadAgentConfig loads an AgentConfig from a JSON file and merges it with defaults.
-// Returns an error if the file cannot be read or the JSON is invalid.
+// LoadAgentConfig loads an AgentConfig from a JSON, YAML, or TOML file and merges it with defaults.
+// The format is detected from the file extension (see FormatOf).
+// String values are expanded for ${VAR}, ${VAR:-default}, and file:// references
+// (see ExpandString), then GOAGENTS_ environment variables are overlaid (see ApplyEnv).
+// Returns an error if the file cannot be read, the JSON is invalid,
+// or a reference cannot be resolved.
 func LoadAgentConfig(filename string) (*AgentConfig, error) {
 	config := DefaultAgentConfig()
 
-	data, err := os.ReadFile(filename)
+	data, err := ReadConfigFile(filename)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+		return nil, err
 	}
 
 	var loaded AgentConfig
@@ -81,5 +84,9 @@ func LoadAgentConfig(filename string) (*AgentConfig, error) {
 
 	config.Merge(&loaded)
 
+	if err := ApplyEnv(&config, EnvPrefix); err != nil {
+		return nil, fmt.Errorf("failed to apply environment overrides: %w", err)
+	}
+
 	return &config, nil
 }
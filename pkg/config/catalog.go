@@ -0,0 +1,162 @@
+package config
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// imageBytesLimit is the per-image size limit of OpenAI vision models.
+const imageBytesLimit = 20 << 20
+
+var catalog = struct {
+	mu     sync.RWMutex
+	models map[string]*ModelConfig
+}{models: builtinCatalog()}
+
+// RegisterCatalogModel adds or replaces the catalog entry for a model family.
+// The entry describes the model's support metadata, token limits, and reasoning
+// settings; its Name and Capabilities are ignored. Thread-safe for concurrent registration.
+func RegisterCatalogModel(family string, entry *ModelConfig) {
+	catalog.mu.Lock()
+	defer catalog.mu.Unlock()
+	catalog.models[strings.ToLower(family)] = entry.clone()
+}
+
+// CatalogModel returns a copy of the built-in metadata for the named model.
+// A name matches a family exactly, as a dated snapshot ("gpt-4o-2024-08-06"),
+// or with an Ollama tag ("embeddinggemma:300m"), ignoring case. The returned
+// entry's Name is set to name. Returns false for models not in the catalog.
+func CatalogModel(name string) (*ModelConfig, bool) {
+	catalog.mu.RLock()
+	defer catalog.mu.RUnlock()
+
+	lower := strings.ToLower(name)
+	for _, family := range slices.Sorted(maps.Keys(catalog.models)) {
+		if !matchesFamily(lower, family) {
+			continue
+		}
+		entry := catalog.models[family].clone()
+		entry.Name = name
+		return entry, true
+	}
+	return nil, false
+}
+
+// Resolve returns the configuration layered over the catalog entry for its
+// model: fields set in c override the catalog's. An empty (non-null)
+// "protocols" list therefore disables the protocol check for a catalog model.
+// Returns a copy of c for models not in the catalog.
+func (c *ModelConfig) Resolve() *ModelConfig {
+	resolved, ok := CatalogModel(c.Name)
+	if !ok {
+		resolved = &ModelConfig{}
+	}
+	resolved.Merge(c)
+	return resolved
+}
+
+// matchesFamily reports whether name is family, a dated snapshot of it, or an Ollama tag of it.
+func matchesFamily(name, family string) bool {
+	rest, ok := strings.CutPrefix(name, family)
+	switch {
+	case !ok:
+		return false
+	case rest == "":
+		return true
+	case rest[0] == ':':
+		return true
+	case rest[0] == '-':
+		return len(rest) > 1 && unicode.IsDigit(rune(rest[1]))
+	default:
+		return false
+	}
+}
+
+// clone returns a deep copy of the entry's metadata without capabilities.
+func (c *ModelConfig) clone() *ModelConfig {
+	entry := &ModelConfig{}
+	entry.Merge(&ModelConfig{
+		ContextWindow:   c.ContextWindow,
+		MaxOutputTokens: c.MaxOutputTokens,
+		Tokenizer:       c.Tokenizer,
+		Reasoning:       c.Reasoning,
+		Protocols:       c.Protocols,
+		Streaming:       c.Streaming,
+		JSONMode:        c.JSONMode,
+		MaxImages:       c.MaxImages,
+		MaxImageBytes:   c.MaxImageBytes,
+	})
+	return entry
+}
+
+// builtinCatalog returns the metadata of well-known models.
+func builtinCatalog() map[string]*ModelConfig {
+	chat := func(window, output int, protocols ...string) *ModelConfig {
+		return &ModelConfig{
+			ContextWindow:   ptr(window),
+			MaxOutputTokens: ptr(output),
+			Protocols:       protocols,
+			Streaming:       ptr(true),
+			JSONMode:        ptr(true),
+		}
+	}
+	vision := func(window, output int) *ModelConfig {
+		m := chat(window, output, "chat", "vision", "tools")
+		m.MaxImageBytes = ptr(imageBytesLimit)
+		return m
+	}
+	reasoning := func(m *ModelConfig) *ModelConfig {
+		m.Reasoning = &ReasoningConfig{}
+		return m
+	}
+	only := func(protocol string, window int) *ModelConfig {
+		m := &ModelConfig{Protocols: []string{protocol}}
+		if window > 0 {
+			m.ContextWindow = ptr(window)
+		}
+		return m
+	}
+
+	o1Mini := reasoning(chat(128000, 65536, "chat"))
+	o1Mini.Reasoning.SystemRole = SystemRoleUser
+	o1Mini.JSONMode = ptr(false)
+
+	return map[string]*ModelConfig{
+		"gpt-4o":       vision(128000, 16384),
+		"gpt-4o-mini":  vision(128000, 16384),
+		"gpt-4-turbo":  vision(128000, 4096),
+		"gpt-4.1":      vision(1047576, 32768),
+		"gpt-4.1-mini": vision(1047576, 32768),
+		"gpt-4.1-nano": vision(1047576, 32768),
+		"o1":           reasoning(vision(200000, 100000)),
+		"o1-mini":      o1Mini,
+		"o3":           reasoning(vision(200000, 100000)),
+		"o3-mini":      reasoning(chat(200000, 100000, "chat", "tools")),
+		"o4-mini":      reasoning(vision(200000, 100000)),
+		"gpt-5":        reasoning(vision(400000, 128000)),
+		"gpt-5-mini":   reasoning(vision(400000, 128000)),
+		"gpt-5-nano":   reasoning(vision(400000, 128000)),
+
+		"text-embedding-3-small": only("embeddings", 8191),
+		"text-embedding-3-large": only("embeddings", 8191),
+		"text-embedding-ada-002": only("embeddings", 8191),
+		"nomic-embed-text":       only("embeddings", 8192),
+		"embeddinggemma":         only("embeddings", 2048),
+
+		"dall-e-3":    only("image_generation", 0),
+		"gpt-image-1": only("image_generation", 0),
+
+		"whisper-1":              only("transcription", 0),
+		"gpt-4o-transcribe":      only("transcription", 0),
+		"gpt-4o-mini-transcribe": only("transcription", 0),
+		"tts-1":                  only("speech", 0),
+		"tts-1-hd":               only("speech", 0),
+		"gpt-4o-mini-tts":        only("speech", 0),
+
+		"omni-moderation-latest": only("moderation", 0),
+		"text-moderation-latest": only("moderation", 0),
+	}
+}
@@ -4,30 +4,68 @@ import "time"
 
 // ClientConfig defines the configuration for the HTTP client layer.
 // It includes timeout settings, retry behavior, and connection pooling parameters.
+//
+// Timeout, ConnectionPoolSize, and ConnectionTimeout are pointers so an
+// explicit 0 (no timeout, no pool limit) can be distinguished from an unset
+// value when configurations are merged. Use RequestTimeout, PoolSize, and
+// IdleTimeout to read them; nil reads as 0.
 type ClientConfig struct {
-	Timeout            Duration    `json:"timeout"`
-	Retry              RetryConfig `json:"retry"`
-	ConnectionPoolSize int         `json:"connection_pool_size"`
-	ConnectionTimeout  Duration    `json:"connection_timeout"`
+	Timeout            *Duration    `json:"timeout,omitempty"`
+	Retry              RetryConfig  `json:"retry"`
+	ConnectionPoolSize *int         `json:"connection_pool_size,omitempty"`
+	ConnectionTimeout  *Duration    `json:"connection_timeout,omitempty"`
+	Cache              *CacheConfig `json:"cache,omitempty"`
+}
+
+// Cache backend identifiers for CacheConfig.Backend.
+const (
+	CacheBackendMemory = "memory"
+	CacheBackendDisk   = "disk"
+)
+
+// CacheConfig configures response caching for identical requests.
+// A nil CacheConfig on ClientConfig disables caching.
+//
+// Capacity and TTL are pointers so an explicit 0 (default capacity, no expiry)
+// can be distinguished from an unset value when configurations are merged.
+// Use MaxEntries and EntryTTL to read them; nil reads as 0.
+type CacheConfig struct {
+	// Backend selects the cache store: "memory" (default) or "disk".
+	Backend string `json:"backend,omitempty"`
+
+	// Capacity is the maximum number of entries held by the memory backend.
+	// Zero uses the default capacity.
+	Capacity *int `json:"capacity,omitempty"`
+
+	// Dir is the directory used by the disk backend.
+	Dir string `json:"dir,omitempty"`
+
+	// TTL is how long entries remain valid. Zero means entries never expire.
+	TTL *Duration `json:"ttl,omitempty"`
 }
 
 // RetryConfig configures retry behavior for failed requests.
 // Implements exponential backoff with jitter for transient failures.
+//
+// All fields are pointers so an explicit 0 or false can be distinguished
+// from an unset value when configurations are merged. A nil MaxRetries
+// disables retries and a nil Jitter disables jitter; use Retries,
+// InitialDelay, MaxDelay, Multiplier, and JitterEnabled to read them.
 type RetryConfig struct {
-	MaxRetries        int      `json:"max_retries"`
-	InitialBackoff    Duration `json:"initial_backoff"`
-	MaxBackoff        Duration `json:"max_backoff"`
-	BackoffMultiplier float64  `json:"backoff_multiplier"`
-	Jitter            bool     `json:"jitter"`
+	MaxRetries        *int      `json:"max_retries,omitempty"`
+	InitialBackoff    *Duration `json:"initial_backoff,omitempty"`
+	MaxBackoff        *Duration `json:"max_backoff,omitempty"`
+	BackoffMultiplier *float64  `json:"backoff_multiplier,omitempty"`
+	Jitter            *bool     `json:"jitter,omitempty"`
 }
 
 // DefaultClientConfig creates a ClientConfig with default values.
 func DefaultClientConfig() *ClientConfig {
 	return &ClientConfig{
-		Timeout:            Duration(2 * time.Minute),
+		Timeout:            ptr(Duration(2 * time.Minute)),
 		Retry:              DefaultRetryConfig(),
-		ConnectionPoolSize: 10,
-		ConnectionTimeout:  Duration(30 * time.Second),
+		ConnectionPoolSize: ptr(10),
+		ConnectionTimeout:  ptr(Duration(30 * time.Second)),
 	}
 }
 
@@ -35,45 +73,150 @@ func DefaultClientConfig() *ClientConfig {
 // Retries up to 3 times with exponential backoff starting at 1s, capped at 30s.
 func DefaultRetryConfig() RetryConfig {
 	return RetryConfig{
-		MaxRetries:        3,
-		InitialBackoff:    Duration(time.Second),
-		MaxBackoff:        Duration(30 * time.Second),
-		BackoffMultiplier: 2.0,
-		Jitter:            true,
+		MaxRetries:        ptr(3),
+		InitialBackoff:    ptr(Duration(time.Second)),
+		MaxBackoff:        ptr(Duration(30 * time.Second)),
+		BackoffMultiplier: ptr(2.0),
+		Jitter:            ptr(true),
 	}
 }
 
+// RequestTimeout returns the overall request timeout, handling nil pointer.
+// Zero means no timeout.
+func (c *ClientConfig) RequestTimeout() time.Duration {
+	return deref(c.Timeout).ToDuration()
+}
+
+// PoolSize returns the connection pool size, handling nil pointer.
+// Zero means no limit.
+func (c *ClientConfig) PoolSize() int {
+	return deref(c.ConnectionPoolSize)
+}
+
+// IdleTimeout returns the connection timeout applied to idle pooled
+// connections, handling nil pointer. Zero means no timeout.
+func (c *ClientConfig) IdleTimeout() time.Duration {
+	return deref(c.ConnectionTimeout).ToDuration()
+}
+
+// MaxEntries returns the memory backend capacity, handling nil pointer.
+// Zero means the default capacity.
+func (c *CacheConfig) MaxEntries() int {
+	return deref(c.Capacity)
+}
+
+// EntryTTL returns how long entries remain valid, handling nil pointer.
+// Zero means entries never expire.
+func (c *CacheConfig) EntryTTL() time.Duration {
+	return deref(c.TTL).ToDuration()
+}
+
+// Retries returns the maximum number of retries, handling nil pointer.
+func (c *RetryConfig) Retries() int {
+	return deref(c.MaxRetries)
+}
+
+// InitialDelay returns the initial backoff, handling nil pointer.
+func (c *RetryConfig) InitialDelay() time.Duration {
+	return deref(c.InitialBackoff).ToDuration()
+}
+
+// MaxDelay returns the maximum backoff, handling nil pointer.
+func (c *RetryConfig) MaxDelay() time.Duration {
+	return deref(c.MaxBackoff).ToDuration()
+}
+
+// Multiplier returns the backoff multiplier, handling nil pointer.
+func (c *RetryConfig) Multiplier() float64 {
+	return deref(c.BackoffMultiplier)
+}
+
+// JitterEnabled returns the jitter setting, handling nil pointer.
+func (c *RetryConfig) JitterEnabled() bool {
+	return deref(c.Jitter)
+}
+
 // Merge combines the source ClientConfig into this ClientConfig.
-// Positive values from source override the current values. Zero values are ignored.
+// Timeouts and the pool size are taken from source whenever they are set,
+// including explicit 0. Retry settings are merged with RetryConfig.Merge.
 func (c *ClientConfig) Merge(source *ClientConfig) {
-	if source.Timeout > 0 {
-		c.Timeout = source.Timeout
+	if source.Timeout != nil {
+		c.Timeout = ptr(*source.Timeout)
+	}
+
+	c.Retry.Merge(&source.Retry)
+
+	if source.ConnectionPoolSize != nil {
+		c.ConnectionPoolSize = ptr(*source.ConnectionPoolSize)
+	}
+
+	if source.ConnectionTimeout != nil {
+		c.ConnectionTimeout = ptr(*source.ConnectionTimeout)
+	}
+
+	if source.Cache != nil {
+		if c.Cache == nil {
+			c.Cache = &CacheConfig{}
+		}
+		c.Cache.Merge(source.Cache)
+	}
+}
+
+// Merge combines the source RetryConfig into this RetryConfig.
+// Every field is taken from source whenever it is set, including explicit 0 and false.
+func (c *RetryConfig) Merge(source *RetryConfig) {
+	if source.MaxRetries != nil {
+		c.MaxRetries = ptr(*source.MaxRetries)
+	}
+
+	if source.InitialBackoff != nil {
+		c.InitialBackoff = ptr(*source.InitialBackoff)
 	}
 
-	if source.Retry.MaxRetries > 0 {
-		c.Retry.MaxRetries = source.Retry.MaxRetries
+	if source.MaxBackoff != nil {
+		c.MaxBackoff = ptr(*source.MaxBackoff)
 	}
 
-	if source.Retry.InitialBackoff > 0 {
-		c.Retry.InitialBackoff = source.Retry.InitialBackoff
+	if source.BackoffMultiplier != nil {
+		c.BackoffMultiplier = ptr(*source.BackoffMultiplier)
 	}
 
-	if source.Retry.MaxBackoff > 0 {
-		c.Retry.MaxBackoff = source.Retry.MaxBackoff
+	if source.Jitter != nil {
+		c.Jitter = ptr(*source.Jitter)
 	}
+}
 
-	if source.Retry.BackoffMultiplier > 0 {
-		c.Retry.BackoffMultiplier = source.Retry.BackoffMultiplier
+// Merge combines the source CacheConfig into this CacheConfig.
+// Capacity and TTL are taken from source whenever they are set, including
+// explicit 0; other non-zero values from source override the current values.
+func (c *CacheConfig) Merge(source *CacheConfig) {
+	if source.Backend != "" {
+		c.Backend = source.Backend
 	}
 
-	// Jitter is boolean, always take source value if explicitly set
-	c.Retry.Jitter = source.Retry.Jitter
+	if source.Capacity != nil {
+		c.Capacity = ptr(*source.Capacity)
+	}
 
-	if source.ConnectionPoolSize > 0 {
-		c.ConnectionPoolSize = source.ConnectionPoolSize
+	if source.Dir != "" {
+		c.Dir = source.Dir
 	}
 
-	if source.ConnectionTimeout > 0 {
-		c.ConnectionTimeout = source.ConnectionTimeout
+	if source.TTL != nil {
+		c.TTL = ptr(*source.TTL)
+	}
+}
+
+// ptr returns a pointer to a copy of v.
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// deref returns the value p points to, or the zero value when p is nil.
+func deref[T any](p *T) T {
+	if p == nil {
+		var zero T
+		return zero
 	}
+	return *p
 }
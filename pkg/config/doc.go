@@ -26,4 +26,89 @@
 //
 // Duration values support human-readable strings ("24s", "1m", "2h") or
 // numeric nanoseconds for programmatic configuration.
+//
+// # Merging
+//
+// Loaded configurations are merged onto defaults with Merge. Empty strings
+// leave the base value unchanged. Numeric and boolean fields, such as timeouts,
+// pool sizes, retry and backoff settings, cache capacity and TTL, token limits,
+// and image limits, are pointers: a field absent from JSON is nil and keeps the
+// base value, while an explicit 0 or false overrides it. Accessors such as ClientConfig.RequestTimeout,
+// RetryConfig.Retries, and ModelConfig.Window read them with nil as zero. A provider option,
+// capability protocol, or capability option set to null removes the inherited
+// entry:
+//
+//	{
+//	  "client": {"retry": {"max_retries": 0}},
+//	  "provider": {"options": {"api_key": null}},
+//	  "model": {"capabilities": {"vision": null}}
+//	}
+//
+// # File Formats
+//
+// Configuration files may be JSON, YAML (.yaml, .yml), or TOML (.toml).
+// ReadConfigFile detects the format from the extension and converts the
+// document to JSON, so every format decodes into the same structs. Convert
+// translates documents between formats and Dump encodes a configuration value:
+//
+//	out, err := config.Dump(cfg, config.FormatYAML)
+//
+// # Environment and Secret References
+//
+// LoadAgentConfig expands ${VAR}, ${VAR:-default}, and file:// references in
+// every string value, then overlays GOAGENTS_ environment variables addressed
+// by JSON path (e.g. GOAGENTS_PROVIDER_BASE_URL). Unresolved references are
+// reported together with their paths and wrap ErrUnresolvedReference.
+// ReadConfigFile, ExpandJSON, and ApplyEnv expose the same behavior for
+// configuration types defined outside this package.
+//
+// # Profiles
+//
+// LoadProfiles reads a ProfileFile declaring named providers, models, and
+// agent profiles. An agent profile may extends another and reference providers
+// and models by name:
+//
+//	profiles, err := config.LoadProfiles("profiles.json")
+//	cfg, err := profiles.Get("gpt4o-entra")
+//
+// # JSON Schema
+//
+// AgentConfigSchema and ProfileFileSchema generate JSON Schemas for editor
+// validation and autocompletion. SchemaFor generates a schema for any type
+// built from configuration structs. Providers contribute option schemas with
+// RegisterProviderSchema.
+//
+// # Hot Reload
+//
+// A Watcher reloads a configuration file when it changes or on SIGHUP,
+// validates it, and notifies subscribers. Invalid configurations are reported
+// as a ConfigEvent with Err set and the previous configuration stays active:
+//
+//	w, err := config.NewWatcher("config.yaml")
+//	w.Subscribe(func(e config.ConfigEvent) { ... })
+//	go w.Run(ctx)
+//
+// # Model Catalog
+//
+// CatalogModel returns built-in metadata for well-known models: context and
+// output limits, supported protocols, streaming and JSON mode support, image
+// limits, and reasoning settings. ModelConfig.Resolve layers a configuration
+// over its catalog entry, and RegisterCatalogModel adds or replaces entries:
+//
+//	config.RegisterCatalogModel("my-finetune", &config.ModelConfig{
+//	    ContextWindow: 32768,
+//	    Protocols:     []string{"chat", "tools"},
+//	})
+//
+// # Validation
+//
+// Validate on AgentConfig and its nested configurations reports every problem
+// at once as ValidationErrors, each qualified by its JSON path:
+//
+//	if err := cfg.Validate(); err != nil {
+//	    log.Fatal(err) // invalid config (2 problems): provider.options.deployment: ...
+//	}
+//
+// Provider-specific checks are registered with RegisterProviderValidator,
+// typically through providers.Register.
 package config
@@ -0,0 +1,221 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/JaimeStill/go-agents/pkg/protocol"
+)
+
+// EnvPrefix is the environment variable prefix for the agent configuration overlay.
+const EnvPrefix = "GOAGENTS_"
+
+// ApplyEnv overlays environment variables onto a configuration struct.
+//
+// Each field is addressed by its JSON tag path, upper-cased and joined with
+// underscores after prefix. For example, with prefix GOAGENTS_:
+//
+//	GOAGENTS_NAME                        -> name
+//	GOAGENTS_PROVIDER_BASE_URL           -> provider.base_url
+//	GOAGENTS_CLIENT_RETRY_MAX_RETRIES    -> client.retry.max_retries
+//	GOAGENTS_PROVIDER_OPTIONS_API_KEY    -> provider.options["api_key"]
+//
+// Map fields collect every variable under their prefix, using the lower-cased
+// remainder as the key. Maps of maps match the longest existing key or
+// protocol name as the outer key, falling back to the first segment:
+//
+//	GOAGENTS_MODEL_CAPABILITIES_CHAT_MAX_TOKENS       -> model.capabilities["chat"]["max_tokens"]
+//	GOAGENTS_MODEL_CAPABILITIES_IMAGE_GENERATION_SIZE -> model.capabilities["image_generation"]["size"]
+//
+// Values are decoded as JSON when valid (numbers, booleans, objects) and
+// used as plain strings otherwise.
+// Nil pointer fields are allocated only when a variable targets them.
+// target must be a non-nil pointer to a struct.
+func ApplyEnv(target any, prefix string) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("env overlay target must be a non-nil pointer to a struct, got %T", target)
+	}
+
+	environ := make(map[string]string)
+	for _, entry := range os.Environ() {
+		if key, value, ok := strings.Cut(entry, "="); ok && strings.HasPrefix(key, prefix) {
+			environ[key] = value
+		}
+	}
+
+	if len(environ) == 0 {
+		return nil
+	}
+
+	var errs []error
+	applyEnvStruct(v.Elem(), prefix, environ, &errs)
+	return errors.Join(errs...)
+}
+
+// applyEnvStruct overlays environment values onto the fields of a struct value.
+func applyEnvStruct(v reflect.Value, prefix string, environ map[string]string, errs *[]error) {
+	t := v.Type()
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := jsonName(field)
+		if name == "" {
+			continue
+		}
+
+		applyEnvValue(v.Field(i), prefix+strings.ToUpper(name), environ, errs)
+	}
+}
+
+// applyEnvValue overlays environment values onto a single field.
+func applyEnvValue(v reflect.Value, key string, environ map[string]string, errs *[]error) {
+	switch {
+	case v.Kind() == reflect.Pointer && v.Type().Elem().Kind() == reflect.Struct:
+		if !hasEnvPrefix(environ, key+"_") {
+			return
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		applyEnvStruct(v.Elem(), key+"_", environ, errs)
+
+	case v.Kind() == reflect.Struct && !isScalarStruct(v):
+		applyEnvStruct(v, key+"_", environ, errs)
+
+	case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String:
+		applyEnvMap(v, key+"_", environ, errs)
+
+	default:
+		raw, ok := environ[key]
+		if !ok {
+			return
+		}
+		if err := decodeEnv(v, raw); err != nil {
+			*errs = append(*errs, fmt.Errorf("%s: %w", key, err))
+		}
+	}
+}
+
+// applyEnvMap sets map entries from every variable under prefix.
+func applyEnvMap(v reflect.Value, prefix string, environ map[string]string, errs *[]error) {
+	for key, raw := range environ {
+		name, ok := strings.CutPrefix(key, prefix)
+		if !ok || name == "" {
+			continue
+		}
+
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+
+		// Nested maps address entries as OUTER_INNER, e.g. CAPABILITIES_CHAT_MAX_TOKENS
+		if inner := v.Type().Elem(); inner.Kind() == reflect.Map && inner.Key().Kind() == reflect.String {
+			if outer, ok := envMapOuterKey(v, name); ok {
+				outerKey := reflect.ValueOf(strings.ToLower(outer))
+				entry := v.MapIndex(outerKey)
+				if !entry.IsValid() || entry.IsNil() {
+					entry = reflect.MakeMap(inner)
+				}
+				applyEnvMap(entry, prefix+outer+"_", map[string]string{key: raw}, errs)
+				v.SetMapIndex(outerKey, entry)
+				continue
+			}
+		}
+
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if err := decodeEnv(elem, raw); err != nil {
+			*errs = append(*errs, fmt.Errorf("%s: %w", key, err))
+			continue
+		}
+
+		v.SetMapIndex(reflect.ValueOf(strings.ToLower(name)), elem)
+	}
+}
+
+// envMapOuterKey returns the upper-cased outer key of a nested map variable.
+// Keys may contain underscores, so the longest existing map key or protocol
+// name followed by an underscore is matched first, e.g. IMAGE_GENERATION in
+// IMAGE_GENERATION_SIZE. Otherwise the name is split at its first underscore.
+func envMapOuterKey(v reflect.Value, name string) (string, bool) {
+	var known []string
+	for _, key := range v.MapKeys() {
+		known = append(known, strings.ToUpper(key.String()))
+	}
+	for _, p := range protocol.ValidProtocols() {
+		known = append(known, strings.ToUpper(string(p)))
+	}
+
+	outer := ""
+	for _, key := range known {
+		if len(key) > len(outer) && strings.HasPrefix(name, key+"_") {
+			outer = key
+		}
+	}
+	if outer != "" {
+		return outer, true
+	}
+
+	outer, _, ok := strings.Cut(name, "_")
+	return outer, ok
+}
+
+// decodeEnv assigns a raw environment value to v.
+// Strings are assigned directly. Other types are decoded as JSON,
+// retrying as a JSON string so values like durations ("30s") parse.
+// Interface values keep the raw string unless it is valid JSON.
+func decodeEnv(v reflect.Value, raw string) error {
+	if v.Kind() == reflect.String {
+		v.SetString(raw)
+		return nil
+	}
+
+	ptr := reflect.New(v.Type())
+	if err := json.Unmarshal([]byte(raw), ptr.Interface()); err != nil {
+		quoted, _ := json.Marshal(raw)
+		if err := json.Unmarshal(quoted, ptr.Interface()); err != nil {
+			return fmt.Errorf("invalid value %q for %s", raw, v.Type())
+		}
+	}
+
+	v.Set(ptr.Elem())
+	return nil
+}
+
+// isScalarStruct reports whether a struct value decodes from a single JSON value.
+func isScalarStruct(v reflect.Value) bool {
+	_, ok := v.Addr().Interface().(json.Unmarshaler)
+	return ok
+}
+
+// hasEnvPrefix reports whether any environment key starts with prefix.
+func hasEnvPrefix(environ map[string]string, prefix string) bool {
+	for key := range environ {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonName returns the JSON field name for a struct field, or "" if the field is skipped.
+func jsonName(field reflect.StructField) string {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
@@ -0,0 +1,198 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ErrUnresolvedReference indicates a ${VAR} or file:// reference in a
+// configuration value could not be resolved.
+var ErrUnresolvedReference = errors.New("unresolved reference")
+
+// FileReferencePrefix marks a string value whose content is read from a file.
+const FileReferencePrefix = "file://"
+
+// ExpandString expands references in a single configuration value.
+//
+//   - ${VAR} is replaced by the environment variable VAR; an unset variable is an error
+//   - ${VAR:-default} uses default when VAR is unset or empty
+//   - $${ is an escape for a literal ${
+//   - A value starting with file:// is replaced by the content of the named file,
+//     with trailing newlines removed. Relative paths resolve against baseDir.
+//
+// Environment references are expanded before file references, so a file path
+// may itself contain ${VAR}.
+func ExpandString(s, baseDir string) (string, error) {
+	expanded, err := expandEnv(s)
+	if err != nil {
+		return "", err
+	}
+
+	path, ok := strings.CutPrefix(expanded, FileReferencePrefix)
+	if !ok {
+		return expanded, nil
+	}
+
+	if !filepath.IsAbs(path) && baseDir != "" {
+		path = filepath.Join(baseDir, path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s%s: %w", ErrUnresolvedReference, FileReferencePrefix, path, err)
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// ExpandJSON expands references in every string value of a JSON document,
+// including values nested in objects and arrays such as provider options.
+// Object keys are not expanded.
+// All unresolved references are reported together, each qualified by its
+// JSON path (e.g. provider.options.token).
+func ExpandJSON(data []byte, baseDir string) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	var errs []error
+	doc = expandValue(doc, "", baseDir, &errs)
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return json.Marshal(doc)
+}
+
+// ReadConfigFile reads a configuration file, converts it to JSON, and expands
+// its references. The format is detected from the file extension (see FormatOf).
+// Relative file:// references resolve against the file's directory.
+func ReadConfigFile(filename string) ([]byte, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	data, err = ToJSON(data, FormatOf(filename))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+
+	expanded, err := ExpandJSON(data, filepath.Dir(filename))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+
+	return expanded, nil
+}
+
+// expandValue walks a decoded JSON value, expanding strings in place.
+// Errors are appended to errs with the value's path.
+func expandValue(v any, path, baseDir string, errs *[]error) any {
+	switch value := v.(type) {
+	case string:
+		expanded, err := ExpandString(value, baseDir)
+		if err != nil {
+			*errs = append(*errs, fmt.Errorf("%s: %w", displayPath(path), err))
+			return value
+		}
+		return expanded
+
+	case map[string]any:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+
+		for _, key := range keys {
+			value[key] = expandValue(value[key], joinPath(path, key), baseDir, errs)
+		}
+		return value
+
+	case []any:
+		for i, item := range value {
+			value[i] = expandValue(item, fmt.Sprintf("%s[%d]", path, i), baseDir, errs)
+		}
+		return value
+
+	default:
+		return v
+	}
+}
+
+// expandEnv replaces ${VAR} and ${VAR:-default} references in s.
+func expandEnv(s string) (string, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+
+	var b strings.Builder
+	rest := s
+
+	for {
+		start := strings.Index(rest, "${")
+		if start < 0 {
+			b.WriteString(rest)
+			return b.String(), nil
+		}
+
+		// $${ escapes a literal ${
+		if start > 0 && rest[start-1] == '$' {
+			b.WriteString(rest[:start-1])
+			b.WriteString("${")
+			rest = rest[start+2:]
+			continue
+		}
+
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("%w: unterminated ${ in %q", ErrUnresolvedReference, s)
+		}
+		end += start
+
+		b.WriteString(rest[:start])
+
+		name, fallback, hasDefault := strings.Cut(rest[start+2:end], ":-")
+		if name == "" {
+			return "", fmt.Errorf("%w: empty variable name in %q", ErrUnresolvedReference, s)
+		}
+
+		value, ok := os.LookupEnv(name)
+		switch {
+		case hasDefault && value == "":
+			value = fallback
+		case !ok:
+			return "", fmt.Errorf("%w: ${%s} is not set", ErrUnresolvedReference, name)
+		}
+
+		b.WriteString(value)
+		rest = rest[end+1:]
+	}
+}
+
+// joinPath appends a key to a dotted JSON path.
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// displayPath returns a path suitable for error messages.
+func displayPath(path string) string {
+	if path == "" {
+		return "(root)"
+	}
+	return path
+}
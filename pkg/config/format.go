@@ -0,0 +1,220 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Format identifies a configuration file encoding.
+type Format string
+
+// Supported configuration formats.
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+	FormatTOML Format = "toml"
+)
+
+// FormatOf detects the format of a configuration file from its extension:
+// .yaml and .yml are YAML, .toml is TOML, and anything else is JSON.
+func FormatOf(filename string) Format {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	case ".toml":
+		return FormatTOML
+	default:
+		return FormatJSON
+	}
+}
+
+// ParseFormat parses a format name such as "json", "yaml", "yml", or "toml".
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "json":
+		return FormatJSON, nil
+	case "yaml", "yml":
+		return FormatYAML, nil
+	case "toml":
+		return FormatTOML, nil
+	default:
+		return "", fmt.Errorf("unknown config format %q (expected json, yaml, or toml)", name)
+	}
+}
+
+// ToJSON converts a configuration document in the given format to JSON.
+// YAML and TOML documents are decoded into generic values and re-encoded,
+// so the result decodes into the same structs as an equivalent JSON file.
+// Duration values are written as strings ("24s") or integer nanoseconds in
+// every format.
+func ToJSON(data []byte, format Format) ([]byte, error) {
+	switch format {
+	case FormatJSON, "":
+		return data, nil
+
+	case FormatYAML:
+		var doc any
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML: %w", err)
+		}
+		if doc == nil {
+			doc = map[string]any{}
+		}
+		return json.Marshal(normalizeYAML(doc))
+
+	case FormatTOML:
+		var doc map[string]any
+		if err := toml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse TOML: %w", err)
+		}
+		return json.Marshal(doc)
+
+	default:
+		return nil, fmt.Errorf("unknown config format %q", format)
+	}
+}
+
+// Convert converts a configuration document between formats.
+// Converting to TOML drops null values, which TOML cannot represent.
+func Convert(data []byte, from, to Format) ([]byte, error) {
+	jsonData, err := ToJSON(data, from)
+	if err != nil {
+		return nil, err
+	}
+
+	switch to {
+	case FormatJSON, "":
+		var out bytes.Buffer
+		if err := json.Indent(&out, jsonData, "", "  "); err != nil {
+			return nil, fmt.Errorf("failed to format JSON: %w", err)
+		}
+		out.WriteByte('\n')
+		return out.Bytes(), nil
+
+	case FormatYAML:
+		// Decoding JSON into a node preserves key order; clearing the
+		// flow style emits block-style YAML.
+		var node yaml.Node
+		if err := yaml.Unmarshal(jsonData, &node); err != nil {
+			return nil, fmt.Errorf("failed to convert to YAML: %w", err)
+		}
+		blockStyle(&node)
+
+		var out bytes.Buffer
+		encoder := yaml.NewEncoder(&out)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(&node); err != nil {
+			return nil, fmt.Errorf("failed to encode YAML: %w", err)
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, fmt.Errorf("failed to encode YAML: %w", err)
+		}
+		return out.Bytes(), nil
+
+	case FormatTOML:
+		decoder := json.NewDecoder(bytes.NewReader(jsonData))
+		decoder.UseNumber()
+
+		var doc map[string]any
+		if err := decoder.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("failed to convert to TOML: %w", err)
+		}
+
+		var out bytes.Buffer
+		if err := toml.NewEncoder(&out).Encode(normalizeTOML(doc)); err != nil {
+			return nil, fmt.Errorf("failed to encode TOML: %w", err)
+		}
+		return out.Bytes(), nil
+
+	default:
+		return nil, fmt.Errorf("unknown config format %q", to)
+	}
+}
+
+// Dump encodes a configuration value, such as an AgentConfig, in the given format.
+// Field names and omitted fields follow the value's JSON encoding.
+func Dump(v any, format Format) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	return Convert(data, FormatJSON, format)
+}
+
+// normalizeYAML converts YAML mappings with non-string keys into
+// map[string]any so the document can be encoded as JSON.
+func normalizeYAML(v any) any {
+	switch value := v.(type) {
+	case map[string]any:
+		for key, item := range value {
+			value[key] = normalizeYAML(item)
+		}
+		return value
+
+	case map[any]any:
+		m := make(map[string]any, len(value))
+		for key, item := range value {
+			m[fmt.Sprint(key)] = normalizeYAML(item)
+		}
+		return m
+
+	case []any:
+		for i, item := range value {
+			value[i] = normalizeYAML(item)
+		}
+		return value
+
+	default:
+		return v
+	}
+}
+
+// normalizeTOML prepares a decoded JSON document for TOML encoding:
+// numbers become int64 or float64 and null values are removed.
+func normalizeTOML(v any) any {
+	switch value := v.(type) {
+	case map[string]any:
+		for key, item := range value {
+			if item == nil {
+				delete(value, key)
+				continue
+			}
+			value[key] = normalizeTOML(item)
+		}
+		return value
+
+	case []any:
+		items := value[:0]
+		for _, item := range value {
+			if item != nil {
+				items = append(items, normalizeTOML(item))
+			}
+		}
+		return items
+
+	case json.Number:
+		if i, err := value.Int64(); err == nil {
+			return i
+		}
+		f, _ := value.Float64()
+		return f
+
+	default:
+		return v
+	}
+}
+
+// blockStyle clears flow and quoting styles from a YAML node tree
+// so it is emitted in conventional block style.
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
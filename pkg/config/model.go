@@ -1,15 +1,75 @@
 package config
 
-import "maps"
+import "slices"
+
+// Context policies control how an agent handles prompts that exceed the context window.
+const (
+	// ContextPolicyNone sends requests without a pre-flight context check.
+	ContextPolicyNone = ""
+
+	// ContextPolicyError rejects requests whose estimated size exceeds the context window.
+	ContextPolicyError = "error"
+
+	// ContextPolicyTruncate shortens the user prompt to fit within the context window.
+	ContextPolicyTruncate = "truncate"
+)
+
+// System roles select the role that system messages are sent with to reasoning models.
+const (
+	// SystemRoleDeveloper sends system messages with the developer role,
+	// as OpenAI reasoning models expect. It is the default.
+	SystemRoleDeveloper = "developer"
+
+	// SystemRoleSystem keeps the system role.
+	SystemRoleSystem = "system"
+
+	// SystemRoleUser sends system messages as user messages, for models
+	// that accept neither system nor developer messages.
+	SystemRoleUser = "user"
+)
+
+// ReasoningConfig marks a model as a reasoning model (such as o4-mini or gpt-5-mini).
+// Requests to reasoning models send max_tokens as max_completion_tokens and
+// system messages with SystemRole.
+//
+// Effort is the default reasoning effort ("none", "minimal", "low", "medium",
+// or "high"); empty leaves it to the model. A "reasoning_effort" protocol
+// option overrides it. SystemRole is "developer" (the default), "system", or "user".
+type ReasoningConfig struct {
+	Effort     string `json:"effort,omitempty"`
+	SystemRole string `json:"system_role,omitempty"`
+}
 
 // ModelConfig defines the configuration for an LLM model.
 // Name is the model identifier (e.g., "gpt-4o", "claude-3-opus", "llama3.1:8b").
 // Capabilities maps protocol names to their default options.
 //
+// ContextWindow and MaxOutputTokens describe the model's token limits.
+// Tokenizer overrides the encoding used to estimate prompt size
+// (e.g., "cl100k_base", "o200k_base", "heuristic"); an explicit encoding
+// must have its vocabulary available. When empty it is detected from the
+// model name, falling back to the heuristic estimator. ContextPolicy selects
+// how oversized prompts are handled ("error", "truncate", or empty to disable
+// the check).
+// Reasoning marks a reasoning model; see ReasoningConfig.
+//
+// Protocols, Streaming, JSONMode, MaxImages, and MaxImageBytes describe what
+// the model supports, so agents can reject unsupported calls before sending
+// them. Unset fields are unknown and not checked. Numeric limits are pointers
+// so an explicit 0 (no limit) overrides a catalog or base value when merged;
+// use Window, OutputLimit, ImageLimit, and ImageBytesLimit to read them. Resolve fills them, and the
+// token limits, from the built-in catalog for well-known models.
+//
 // Example JSON:
 //
 //	{
 //	  "name": "gpt-4o",
+//	  "context_window": 128000,
+//	  "max_output_tokens": 16384,
+//	  "context_policy": "error",
+//	  "protocols": ["chat", "vision", "tools"],
+//	  "json_mode": true,
+//	  "max_images": 10,
 //	  "capabilities": {
 //	    "chat": {
 //	      "temperature": 0.7,
@@ -22,8 +82,18 @@ import "maps"
 //	  }
 //	}
 type ModelConfig struct {
-	Name         string                      `json:"name,omitempty"`
-	Capabilities map[string]map[string]any   `json:"capabilities,omitempty"`
+	Name            string                    `json:"name,omitempty"`
+	ContextWindow   *int                      `json:"context_window,omitempty"`
+	MaxOutputTokens *int                      `json:"max_output_tokens,omitempty"`
+	Tokenizer       string                    `json:"tokenizer,omitempty"`
+	ContextPolicy   string                    `json:"context_policy,omitempty"`
+	Reasoning       *ReasoningConfig          `json:"reasoning,omitempty"`
+	Protocols       []string                  `json:"protocols,omitempty"`
+	Streaming       *bool                     `json:"streaming,omitempty"`
+	JSONMode        *bool                     `json:"json_mode,omitempty"`
+	MaxImages       *int                      `json:"max_images,omitempty"`
+	MaxImageBytes   *int                      `json:"max_image_bytes,omitempty"`
+	Capabilities    map[string]map[string]any `json:"capabilities,omitempty"`
 }
 
 // DefaultModelConfig creates a ModelConfig with initialized empty capabilities.
@@ -33,14 +103,86 @@ func DefaultModelConfig() *ModelConfig {
 	}
 }
 
+// Window returns the context window, handling nil pointer. Zero means unknown.
+func (c *ModelConfig) Window() int {
+	return deref(c.ContextWindow)
+}
+
+// OutputLimit returns the maximum output tokens, handling nil pointer. Zero means unknown.
+func (c *ModelConfig) OutputLimit() int {
+	return deref(c.MaxOutputTokens)
+}
+
+// ImageLimit returns the maximum images per request, handling nil pointer. Zero means no limit.
+func (c *ModelConfig) ImageLimit() int {
+	return deref(c.MaxImages)
+}
+
+// ImageBytesLimit returns the maximum decoded size of an inline image,
+// handling nil pointer. Zero means no limit.
+func (c *ModelConfig) ImageBytesLimit() int {
+	return deref(c.MaxImageBytes)
+}
+
 // Merge combines the source ModelConfig into this ModelConfig.
-// Non-empty name from source overrides the current value.
+// Non-empty name, tokenizer, context policy, and reasoning fields from source
+// override the current values. Token limits and support metadata are taken
+// from source whenever they are set, including explicit 0 and false.
 // Capabilities are merged at the protocol level.
+// A protocol or option set to null in source is removed.
 func (c *ModelConfig) Merge(source *ModelConfig) {
 	if source.Name != "" {
 		c.Name = source.Name
 	}
 
+	if source.ContextWindow != nil {
+		c.ContextWindow = ptr(*source.ContextWindow)
+	}
+
+	if source.MaxOutputTokens != nil {
+		c.MaxOutputTokens = ptr(*source.MaxOutputTokens)
+	}
+
+	if source.Tokenizer != "" {
+		c.Tokenizer = source.Tokenizer
+	}
+
+	if source.ContextPolicy != "" {
+		c.ContextPolicy = source.ContextPolicy
+	}
+
+	if source.Reasoning != nil {
+		if c.Reasoning == nil {
+			c.Reasoning = &ReasoningConfig{}
+		}
+		if source.Reasoning.Effort != "" {
+			c.Reasoning.Effort = source.Reasoning.Effort
+		}
+		if source.Reasoning.SystemRole != "" {
+			c.Reasoning.SystemRole = source.Reasoning.SystemRole
+		}
+	}
+
+	if source.Protocols != nil {
+		c.Protocols = slices.Clone(source.Protocols)
+	}
+
+	if source.Streaming != nil {
+		c.Streaming = ptr(*source.Streaming)
+	}
+
+	if source.JSONMode != nil {
+		c.JSONMode = ptr(*source.JSONMode)
+	}
+
+	if source.MaxImages != nil {
+		c.MaxImages = ptr(*source.MaxImages)
+	}
+
+	if source.MaxImageBytes != nil {
+		c.MaxImageBytes = ptr(*source.MaxImageBytes)
+	}
+
 	if source.Capabilities != nil {
 		if c.Capabilities == nil {
 			c.Capabilities = make(map[string]map[string]any)
@@ -48,12 +190,17 @@ func (c *ModelConfig) Merge(source *ModelConfig) {
 
 		// Merge each protocol's options
 		for protocol, options := range source.Capabilities {
-			if c.Capabilities[protocol] == nil {
-				// Protocol doesn't exist, copy entire options map
-				c.Capabilities[protocol] = options
-			} else {
+			switch {
+			case options == nil:
+				// Explicit null removes the protocol
+				delete(c.Capabilities, protocol)
+			case c.Capabilities[protocol] == nil:
+				// Protocol doesn't exist, copy options without null entries
+				c.Capabilities[protocol] = make(map[string]any, len(options))
+				mergeOptions(c.Capabilities[protocol], options)
+			default:
 				// Protocol exists, merge options
-				maps.Copy(c.Capabilities[protocol], options)
+				mergeOptions(c.Capabilities[protocol], options)
 			}
 		}
 	}
@@ -0,0 +1,265 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ErrProfileNotFound indicates a requested agent profile is not defined.
+var ErrProfileNotFound = errors.New("profile not found")
+
+// ProfileFile is the multi-document configuration format declaring named
+// providers, models, and agent profiles in a single file.
+//
+// An agent profile has the same fields as AgentConfig plus an optional extends
+// naming another agent profile to inherit from. Its provider and model may be
+// either the name of an entry in providers or models, or an inline configuration.
+//
+// Example JSON:
+//
+//	{
+//	  "providers": {
+//	    "azure-key":   {"name": "azure", "base_url": "https://example.openai.azure.com/openai",
+//	                    "options": {"deployment": "gpt-4o", "api_version": "2025-01-01-preview", "auth_type": "api_key"}},
+//	    "azure-entra": {"name": "azure", "base_url": "https://example.openai.azure.com/openai",
+//	                    "options": {"deployment": "gpt-4o", "api_version": "2025-01-01-preview", "auth_type": "bearer"}}
+//	  },
+//	  "models": {
+//	    "gpt-4o": {"name": "gpt-4o", "capabilities": {"chat": {"max_tokens": 4096}}}
+//	  },
+//	  "agents": {
+//	    "gpt4o-key":   {"system_prompt": "You are helpful.", "provider": "azure-key", "model": "gpt-4o"},
+//	    "gpt4o-entra": {"extends": "gpt4o-key", "provider": "azure-entra"}
+//	  }
+//	}
+type ProfileFile struct {
+	Providers map[string]json.RawMessage `json:"providers,omitempty"`
+	Models    map[string]json.RawMessage `json:"models,omitempty"`
+	Agents    map[string]json.RawMessage `json:"agents"`
+}
+
+// agentProfile is a single entry in ProfileFile.Agents.
+// Provider and Model shadow the embedded AgentConfig fields so they can hold
+// either a reference name or an inline configuration.
+type agentProfile struct {
+	AgentConfig
+	Extends  string          `json:"extends,omitempty"`
+	Provider json.RawMessage `json:"provider,omitempty"`
+	Model    json.RawMessage `json:"model,omitempty"`
+}
+
+// Profiles is a registry of named agent configurations loaded from a ProfileFile.
+type Profiles struct {
+	file ProfileFile
+}
+
+// LoadProfiles loads a profile file and resolves every agent profile.
+// String values are expanded as in LoadAgentConfig. Unknown provider, model,
+// or extends references and inheritance cycles are reported together.
+func LoadProfiles(filename string) (*Profiles, error) {
+	data, err := ReadConfigFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles, err := ParseProfiles(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+
+	return profiles, nil
+}
+
+// ParseProfiles parses and resolves an already expanded profile document.
+func ParseProfiles(data []byte) (*Profiles, error) {
+	var file ProfileFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse profiles: %w", err)
+	}
+
+	if len(file.Agents) == 0 {
+		return nil, fmt.Errorf("no agent profiles defined")
+	}
+
+	p := &Profiles{file: file}
+
+	var errs []error
+	for _, name := range p.Names() {
+		if _, err := p.resolve(name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return p, nil
+}
+
+// Names returns the agent profile names in sorted order.
+func (p *Profiles) Names() []string {
+	return slices.Sorted(maps.Keys(p.file.Agents))
+}
+
+// Get returns the agent configuration for the named profile, merged with
+// defaults and overlaid with GOAGENTS_ environment variables.
+// Each call returns a new AgentConfig that the caller may modify.
+// Returns an error wrapping ErrProfileNotFound if the profile is not defined.
+func (p *Profiles) Get(name string) (*AgentConfig, error) {
+	cfg, err := p.resolve(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ApplyEnv(cfg, EnvPrefix); err != nil {
+		return nil, fmt.Errorf("failed to apply environment overrides: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// resolve builds the named profile by merging its extends chain, root first,
+// onto the default configuration. Every layer is decoded afresh, so the
+// returned configuration shares no maps with other profiles.
+//
+// A provider or model given by name replaces the inherited one, while an
+// inline provider or model is merged into it. The agent name is not inherited:
+// it is the profile's own name field, or the profile name when unset.
+func (p *Profiles) resolve(name string) (*AgentConfig, error) {
+	chain, err := p.chain(name)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := DefaultAgentConfig()
+	for _, layerName := range chain {
+		profile, err := p.decodeAgent(layerName)
+		if err != nil {
+			return nil, err
+		}
+
+		layer, err := p.layer(layerName, profile)
+		if err != nil {
+			return nil, err
+		}
+
+		if isReference(profile.Provider) {
+			cfg.Provider = DefaultProviderConfig()
+		}
+		if isReference(profile.Model) {
+			cfg.Model = DefaultModelConfig()
+		}
+
+		layer.Name = ""
+		cfg.Merge(layer)
+	}
+
+	own, err := p.decodeAgent(name)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Name = name
+	if own.Name != "" {
+		cfg.Name = own.Name
+	}
+
+	return &cfg, nil
+}
+
+// chain returns the extends chain for name, ordered from root to name.
+func (p *Profiles) chain(name string) ([]string, error) {
+	var chain []string
+	seen := make(map[string]bool)
+
+	for current := name; current != ""; {
+		if _, ok := p.file.Agents[current]; !ok {
+			if current == name {
+				return nil, fmt.Errorf("%w: %s (available: %s)", ErrProfileNotFound, name, strings.Join(p.Names(), ", "))
+			}
+			return nil, fmt.Errorf("agents.%s.extends: %w: %s", chain[len(chain)-1], ErrProfileNotFound, current)
+		}
+
+		if seen[current] {
+			return nil, fmt.Errorf("agents.%s.extends: inheritance cycle through %s", name, current)
+		}
+		seen[current] = true
+		chain = append(chain, current)
+
+		profile, err := p.decodeAgent(current)
+		if err != nil {
+			return nil, err
+		}
+		current = profile.Extends
+	}
+
+	slices.Reverse(chain)
+	return chain, nil
+}
+
+// layer converts an agent profile into an AgentConfig,
+// resolving provider and model references.
+func (p *Profiles) layer(name string, profile *agentProfile) (*AgentConfig, error) {
+	cfg := profile.AgentConfig
+	path := joinPath("agents", name)
+
+	if len(profile.Provider) > 0 {
+		cfg.Provider = &ProviderConfig{}
+		if err := decodeReference(profile.Provider, p.file.Providers, joinPath(path, "provider"), "provider", cfg.Provider); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(profile.Model) > 0 {
+		cfg.Model = &ModelConfig{}
+		if err := decodeReference(profile.Model, p.file.Models, joinPath(path, "model"), "model", cfg.Model); err != nil {
+			return nil, err
+		}
+	}
+
+	return &cfg, nil
+}
+
+// isReference reports whether raw is a JSON string naming a provider or model.
+func isReference(raw json.RawMessage) bool {
+	raw = bytes.TrimSpace(raw)
+	return len(raw) > 0 && raw[0] == '"'
+}
+
+// decodeReference decodes raw into target. A JSON string is looked up in named;
+// an object is decoded as an inline configuration.
+func decodeReference(raw json.RawMessage, named map[string]json.RawMessage, path, kind string, target any) error {
+	if isReference(raw) {
+		var ref string
+		if err := json.Unmarshal(raw, &ref); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		data, ok := named[ref]
+		if !ok {
+			return fmt.Errorf("%s: unknown %s %q", path, kind, ref)
+		}
+		raw = data
+	}
+
+	if err := json.Unmarshal(raw, target); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	return nil
+}
+
+// decodeAgent decodes the raw agent profile with the given name.
+func (p *Profiles) decodeAgent(name string) (*agentProfile, error) {
+	var profile agentProfile
+	if err := json.Unmarshal(p.file.Agents[name], &profile); err != nil {
+		return nil, fmt.Errorf("agents.%s: %w", name, err)
+	}
+	return &profile, nil
+}
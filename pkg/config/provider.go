@@ -1,7 +1,5 @@
 package config
 
-import "maps"
-
 // ProviderConfig defines the configuration for an LLM provider.
 // It includes the provider name, base URL, and provider-specific options
 // (e.g., deployment, API version, authentication type).
@@ -22,6 +20,7 @@ func DefaultProviderConfig() *ProviderConfig {
 
 // Merge combines the source ProviderConfig into this ProviderConfig.
 // Non-empty name, base_url, and options from source override the current values.
+// An option set to null in source removes the option.
 func (c *ProviderConfig) Merge(source *ProviderConfig) {
 	if source.Name != "" {
 		c.Name = source.Name
@@ -35,6 +34,18 @@ func (c *ProviderConfig) Merge(source *ProviderConfig) {
 		if c.Options == nil {
 			c.Options = make(map[string]any)
 		}
-		maps.Copy(c.Options, source.Options)
+		mergeOptions(c.Options, source.Options)
+	}
+}
+
+// mergeOptions copies source options into dst.
+// Keys whose source value is nil (JSON null) are removed from dst.
+func mergeOptions(dst, source map[string]any) {
+	for key, value := range source {
+		if value == nil {
+			delete(dst, key)
+			continue
+		}
+		dst[key] = value
 	}
 }
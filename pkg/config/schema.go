@@ -0,0 +1,260 @@
+package config
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"sync"
+
+	"github.com/JaimeStill/go-agents/pkg/protocol"
+)
+
+// SchemaDialect is the JSON Schema dialect of generated schemas.
+const SchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// DurationPattern matches the duration strings accepted by Duration ("24s", "1m30s", "500ms").
+const DurationPattern = `^[-+]?(0|((\d+(\.\d*)?|\.\d+)(ns|us|µs|μs|ms|s|m|h))+)$`
+
+// Schema is a JSON Schema document or subschema.
+// Only the keywords needed to describe configuration files are modeled.
+type Schema struct {
+	Schema      string `json:"$schema,omitempty"`
+	ID          string `json:"$id,omitempty"`
+	Ref         string `json:"$ref,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	Type    string `json:"type,omitempty"`
+	Enum    []any  `json:"enum,omitempty"`
+	Const   any    `json:"const,omitempty"`
+	Pattern string `json:"pattern,omitempty"`
+
+	Properties    map[string]*Schema `json:"properties,omitempty"`
+	Required      []string           `json:"required,omitempty"`
+	PropertyNames *Schema            `json:"propertyNames,omitempty"`
+
+	// AdditionalProperties is false or a *Schema.
+	AdditionalProperties any `json:"additionalProperties,omitempty"`
+
+	Items *Schema `json:"items,omitempty"`
+
+	AnyOf []*Schema `json:"anyOf,omitempty"`
+	OneOf []*Schema `json:"oneOf,omitempty"`
+	AllOf []*Schema `json:"allOf,omitempty"`
+
+	If   *Schema `json:"if,omitempty"`
+	Then *Schema `json:"then,omitempty"`
+
+	Defs map[string]*Schema `json:"$defs,omitempty"`
+}
+
+var providerSchemas = struct {
+	mu      sync.RWMutex
+	schemas map[string]*Schema
+}{schemas: make(map[string]*Schema)}
+
+// RegisterProviderSchema registers the schema for a provider's options.
+// Generated ProviderConfig schemas apply it to options when name matches.
+// Thread-safe for concurrent registration.
+func RegisterProviderSchema(name string, options *Schema) {
+	providerSchemas.mu.Lock()
+	defer providerSchemas.mu.Unlock()
+	providerSchemas.schemas[name] = options
+}
+
+// AgentConfigSchema returns a JSON Schema describing agent configuration files,
+// including the option schemas of every registered provider.
+func AgentConfigSchema() *Schema {
+	s := SchemaFor(AgentConfig{})
+	s.Schema = SchemaDialect
+	s.Title = "go-agents agent configuration"
+	allowSchemaKeyword(s)
+	return s
+}
+
+// ProfileFileSchema returns a JSON Schema describing profile files (see ProfileFile).
+// Agent profiles accept a provider or model as a name or an inline configuration.
+func ProfileFileSchema() *Schema {
+	provider := SchemaFor(ProviderConfig{})
+	model := SchemaFor(ModelConfig{})
+
+	agent := SchemaFor(AgentConfig{})
+	agent.Properties["extends"] = &Schema{Type: "string", Description: "Name of the agent profile to inherit from"}
+	agent.Properties["provider"] = &Schema{OneOf: []*Schema{
+		{Type: "string", Description: "Name of an entry in providers"},
+		{Ref: "#/$defs/provider"},
+	}}
+	agent.Properties["model"] = &Schema{OneOf: []*Schema{
+		{Type: "string", Description: "Name of an entry in models"},
+		{Ref: "#/$defs/model"},
+	}}
+
+	s := &Schema{
+		Schema: SchemaDialect,
+		Title:  "go-agents profile file",
+		Type:   "object",
+		Properties: map[string]*Schema{
+			"providers": {Type: "object", AdditionalProperties: &Schema{Ref: "#/$defs/provider"}},
+			"models":    {Type: "object", AdditionalProperties: &Schema{Ref: "#/$defs/model"}},
+			"agents":    {Type: "object", AdditionalProperties: &Schema{Ref: "#/$defs/agent"}},
+		},
+		Required: []string{"agents"},
+		Defs: map[string]*Schema{
+			"provider": provider,
+			"model":    model,
+			"agent":    agent,
+		},
+	}
+	allowSchemaKeyword(s)
+	return s
+}
+
+// SchemaFor generates a JSON Schema for the type of v from its JSON field names.
+// Structs are closed to unknown properties, Duration accepts a duration string
+// or integer nanoseconds, and configuration types from this package are
+// refined with enums, capability protocol keys, and provider option schemas.
+// Types embedding configuration, such as a tool's own config struct, are refined the same way.
+func SchemaFor(v any) *Schema {
+	return schemaForType(reflect.TypeOf(v))
+}
+
+var durationType = reflect.TypeFor[Duration]()
+
+// schemaRefinements adjust generated schemas for configuration types
+// whose constraints cannot be derived from their Go types.
+var schemaRefinements = map[reflect.Type]func(*Schema){
+	reflect.TypeFor[ModelConfig]():     refineModelSchema,
+	reflect.TypeFor[ProviderConfig]():  refineProviderSchema,
+	reflect.TypeFor[CacheConfig]():     refineCacheSchema,
+	reflect.TypeFor[ReasoningConfig](): refineReasoningSchema,
+}
+
+func schemaForType(t reflect.Type) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+
+	if t == durationType {
+		return &Schema{
+			Description: `Duration string ("24s", "1m30s") or integer nanoseconds`,
+			AnyOf: []*Schema{
+				{Type: "string", Pattern: DurationPattern},
+				{Type: "integer"},
+			},
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return schemaForType(t.Elem())
+
+	case reflect.Struct:
+		s := &Schema{
+			Type:                 "object",
+			Properties:           make(map[string]*Schema),
+			AdditionalProperties: false,
+		}
+		for i := range t.NumField() {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if name := jsonName(field); name != "" {
+				s.Properties[name] = schemaForType(field.Type)
+			}
+		}
+		if refine, ok := schemaRefinements[t]; ok {
+			refine(s)
+		}
+		return s
+
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: schemaForType(t.Elem())}
+
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: schemaForType(t.Elem())}
+
+	case reflect.String:
+		return &Schema{Type: "string"}
+
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+
+	default:
+		return &Schema{}
+	}
+}
+
+// refineModelSchema constrains capability keys and protocols to the
+// supported protocols and context_policy to the known policies.
+func refineModelSchema(s *Schema) {
+	protocols := protocol.ValidProtocols()
+	names := make([]any, len(protocols))
+	properties := make(map[string]*Schema, len(protocols))
+	for i, p := range protocols {
+		names[i] = string(p)
+		properties[string(p)] = &Schema{Type: "object", Description: "Default options for the " + string(p) + " protocol"}
+	}
+
+	s.Properties["capabilities"] = &Schema{
+		Type:                 "object",
+		Description:          "Protocol names mapped to their default options",
+		Properties:           properties,
+		PropertyNames:        &Schema{Enum: names},
+		AdditionalProperties: &Schema{Type: "object"},
+	}
+
+	s.Properties["context_policy"].Enum = []any{ContextPolicyNone, ContextPolicyError, ContextPolicyTruncate}
+	s.Properties["protocols"].Items.Enum = names
+}
+
+// refineReasoningSchema constrains the reasoning effort and system role to their known values.
+func refineReasoningSchema(s *Schema) {
+	efforts := protocol.ReasoningEfforts()
+	values := make([]any, len(efforts))
+	for i, e := range efforts {
+		values[i] = string(e)
+	}
+	s.Properties["effort"].Enum = values
+	s.Properties["system_role"].Enum = []any{SystemRoleDeveloper, SystemRoleSystem, SystemRoleUser}
+}
+
+// refineProviderSchema applies each registered provider's option schema
+// when name selects that provider.
+func refineProviderSchema(s *Schema) {
+	providerSchemas.mu.RLock()
+	defer providerSchemas.mu.RUnlock()
+
+	for _, name := range slices.Sorted(maps.Keys(providerSchemas.schemas)) {
+		s.AllOf = append(s.AllOf, &Schema{
+			If: &Schema{
+				Properties: map[string]*Schema{"name": {Const: name}},
+				Required:   []string{"name"},
+			},
+			Then: &Schema{
+				Properties: map[string]*Schema{"options": providerSchemas.schemas[name]},
+			},
+		})
+	}
+}
+
+// refineCacheSchema constrains the cache backend to the known backends.
+func refineCacheSchema(s *Schema) {
+	s.Properties["backend"].Enum = []any{CacheBackendMemory, CacheBackendDisk}
+}
+
+// allowSchemaKeyword permits a top-level "$schema" property so configuration
+// files can reference their schema for editor support.
+func allowSchemaKeyword(s *Schema) {
+	if s.Properties == nil {
+		s.Properties = make(map[string]*Schema)
+	}
+	s.Properties["$schema"] = &Schema{Type: "string"}
+}
@@ -0,0 +1,333 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/JaimeStill/go-agents/pkg/protocol"
+)
+
+// ValidationError describes a single configuration problem.
+// Path is the JSON path of the offending field (e.g. provider.options.deployment).
+type ValidationError struct {
+	Path    string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// ValidationErrors aggregates every problem found while validating a configuration.
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+
+	if len(e) == 1 {
+		return "invalid config: " + messages[0]
+	}
+	return fmt.Sprintf("invalid config (%d problems):\n  %s", len(e), strings.Join(messages, "\n  "))
+}
+
+// Unwrap returns the individual validation errors for use with errors.As.
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Add records a problem at path.
+func (e *ValidationErrors) Add(path, format string, args ...any) {
+	*e = append(*e, &ValidationError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// err returns the aggregated errors, or nil if there are none.
+func (e ValidationErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// prefixed returns the errors with prefix prepended to each path.
+func (e ValidationErrors) prefixed(prefix string) ValidationErrors {
+	for _, err := range e {
+		err.Path = joinPath(prefix, err.Path)
+	}
+	return e
+}
+
+// ProviderValidator checks provider-specific settings such as required options.
+// Paths in returned errors are relative to the provider configuration
+// (e.g. options.deployment).
+type ProviderValidator func(c *ProviderConfig) ValidationErrors
+
+var providerValidators = struct {
+	mu         sync.RWMutex
+	validators map[string]ProviderValidator
+}{validators: make(map[string]ProviderValidator)}
+
+// RegisterProviderValidator registers a validator for the named provider.
+// Providers register validators alongside their factories via providers.Register.
+// Thread-safe for concurrent registration.
+func RegisterProviderValidator(name string, validator ProviderValidator) {
+	providerValidators.mu.Lock()
+	defer providerValidators.mu.Unlock()
+	providerValidators.validators[name] = validator
+}
+
+// RequireOptions reports each key that is missing from options or is an empty string.
+// Intended for use in ProviderValidator implementations.
+func RequireOptions(c *ProviderConfig, keys ...string) ValidationErrors {
+	var errs ValidationErrors
+	for _, key := range keys {
+		value, ok := c.Options[key]
+		if s, isString := value.(string); !ok || value == nil || (isString && s == "") {
+			errs.Add(joinPath("options", key), "is required for %s provider", c.Name)
+		}
+	}
+	return errs
+}
+
+// Validate checks the agent configuration and all nested configurations.
+// Returns ValidationErrors describing every problem found, or nil if valid.
+func (c *AgentConfig) Validate() error {
+	return c.validate().err()
+}
+
+func (c *AgentConfig) validate() ValidationErrors {
+	var errs ValidationErrors
+
+	if c.Client != nil {
+		errs = append(errs, c.Client.validate().prefixed("client")...)
+	}
+
+	if c.Provider == nil {
+		errs.Add("provider", "is required")
+	} else {
+		errs = append(errs, c.Provider.validate().prefixed("provider")...)
+	}
+
+	if c.Model == nil {
+		errs.Add("model", "is required")
+	} else {
+		errs = append(errs, c.Model.validate().prefixed("model")...)
+	}
+
+	return errs
+}
+
+// Validate checks timeouts, retry backoff settings, connection pooling, and cache settings.
+// Returns ValidationErrors describing every problem found, or nil if valid.
+func (c *ClientConfig) Validate() error {
+	return c.validate().err()
+}
+
+func (c *ClientConfig) validate() ValidationErrors {
+	var errs ValidationErrors
+
+	if c.RequestTimeout() < 0 {
+		errs.Add("timeout", "must not be negative")
+	}
+
+	if c.IdleTimeout() < 0 {
+		errs.Add("connection_timeout", "must not be negative")
+	}
+
+	if c.PoolSize() < 0 {
+		errs.Add("connection_pool_size", "must not be negative")
+	}
+
+	errs = append(errs, c.Retry.validate().prefixed("retry")...)
+
+	if c.Cache != nil {
+		errs = append(errs, c.Cache.validate().prefixed("cache")...)
+	}
+
+	return errs
+}
+
+// validate checks retry settings. Backoffs are only required when retries are enabled.
+func (c *RetryConfig) validate() ValidationErrors {
+	var errs ValidationErrors
+
+	if c.Retries() < 0 {
+		errs.Add("max_retries", "must not be negative")
+	}
+
+	if c.Retries() <= 0 {
+		return errs
+	}
+
+	if c.InitialDelay() <= 0 {
+		errs.Add("initial_backoff", "must be positive when retries are enabled")
+	}
+
+	if c.MaxDelay() <= 0 {
+		errs.Add("max_backoff", "must be positive when retries are enabled")
+	}
+
+	if c.InitialDelay() > 0 && c.MaxDelay() > 0 && c.InitialDelay() > c.MaxDelay() {
+		errs.Add("initial_backoff", "%s exceeds max_backoff %s", c.InitialDelay(), c.MaxDelay())
+	}
+
+	if c.Multiplier() < 0 {
+		errs.Add("backoff_multiplier", "must not be negative")
+	}
+
+	return errs
+}
+
+// validate checks cache backend and limits.
+func (c *CacheConfig) validate() ValidationErrors {
+	var errs ValidationErrors
+
+	switch c.Backend {
+	case "", CacheBackendMemory, CacheBackendDisk:
+	default:
+		errs.Add("backend", "unknown cache backend %q (expected %q or %q)", c.Backend, CacheBackendMemory, CacheBackendDisk)
+	}
+
+	if c.MaxEntries() < 0 {
+		errs.Add("capacity", "must not be negative")
+	}
+
+	if c.EntryTTL() < 0 {
+		errs.Add("ttl", "must not be negative")
+	}
+
+	return errs
+}
+
+// Validate checks the provider name and base URL, then runs the validator
+// registered for the provider, if any.
+// Returns ValidationErrors describing every problem found, or nil if valid.
+func (c *ProviderConfig) Validate() error {
+	return c.validate().err()
+}
+
+func (c *ProviderConfig) validate() ValidationErrors {
+	var errs ValidationErrors
+
+	if c.Name == "" {
+		errs.Add("name", "is required")
+	}
+
+	if c.BaseURL == "" {
+		errs.Add("base_url", "is required")
+	} else if u, err := url.Parse(c.BaseURL); err != nil {
+		errs.Add("base_url", "is not a valid URL: %v", err)
+	} else if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs.Add("base_url", "must be an absolute http or https URL, got %q", c.BaseURL)
+	}
+
+	providerValidators.mu.RLock()
+	validator, ok := providerValidators.validators[c.Name]
+	providerValidators.mu.RUnlock()
+
+	if ok {
+		errs = append(errs, validator(c)...)
+	}
+
+	return errs
+}
+
+// Validate checks the model name, capability protocol keys, token limits, context policy,
+// reasoning settings, and support metadata. A context policy may rely on the
+// catalog's context window for the model.
+// Returns ValidationErrors describing every problem found, or nil if valid.
+func (c *ModelConfig) Validate() error {
+	return c.validate().err()
+}
+
+func (c *ModelConfig) validate() ValidationErrors {
+	var errs ValidationErrors
+
+	if c.Name == "" {
+		errs.Add("name", "is required")
+	}
+
+	keys := make([]string, 0, len(c.Capabilities))
+	for key := range c.Capabilities {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		if !protocol.IsValid(key) {
+			errs.Add(joinPath("capabilities", key), "unknown protocol (expected one of: %s)", protocol.ProtocolStrings())
+		}
+	}
+
+	if c.Window() < 0 {
+		errs.Add("context_window", "must not be negative")
+	}
+
+	if c.OutputLimit() < 0 {
+		errs.Add("max_output_tokens", "must not be negative")
+	}
+
+	if c.Window() > 0 && c.OutputLimit() >= c.Window() {
+		errs.Add("max_output_tokens", "%d must be less than context_window %d", c.OutputLimit(), c.Window())
+	}
+
+	switch c.ContextPolicy {
+	case ContextPolicyNone, ContextPolicyError, ContextPolicyTruncate:
+	default:
+		errs.Add("context_policy", "unknown policy %q (expected %q or %q)", c.ContextPolicy, ContextPolicyError, ContextPolicyTruncate)
+	}
+
+	if c.ContextPolicy != ContextPolicyNone && c.Resolve().Window() == 0 {
+		errs.Add("context_policy", "requires context_window")
+	}
+
+	for i, p := range c.Protocols {
+		if !protocol.IsValid(p) {
+			errs.Add(fmt.Sprintf("protocols[%d]", i), "unknown protocol %q (expected one of: %s)", p, protocol.ProtocolStrings())
+		}
+	}
+
+	if len(c.Protocols) > 0 {
+		for _, key := range keys {
+			if protocol.IsValid(key) && !slices.Contains(c.Protocols, key) {
+				errs.Add(joinPath("capabilities", key), "protocol is not listed in protocols")
+			}
+		}
+	}
+
+	if c.ImageLimit() < 0 {
+		errs.Add("max_images", "must not be negative")
+	}
+
+	if c.ImageBytesLimit() < 0 {
+		errs.Add("max_image_bytes", "must not be negative")
+	}
+
+	if r := c.Reasoning; r != nil {
+		if r.Effort != "" {
+			if _, err := protocol.ParseReasoningEffort(r.Effort); err != nil {
+				errs.Add("reasoning.effort", "%v", err)
+			}
+		}
+
+		switch r.SystemRole {
+		case "", SystemRoleDeveloper, SystemRoleSystem, SystemRoleUser:
+		default:
+			errs.Add("reasoning.system_role", "unknown role %q (expected %q, %q, or %q)", r.SystemRole, SystemRoleDeveloper, SystemRoleSystem, SystemRoleUser)
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,240 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// DefaultWatchInterval is how often a Watcher checks its configuration for changes.
+const DefaultWatchInterval = 2 * time.Second
+
+// LoadFunc loads an AgentConfig from a file. LoadAgentConfig is the default.
+type LoadFunc func(filename string) (*AgentConfig, error)
+
+// ConfigEvent reports the outcome of a configuration reload.
+type ConfigEvent struct {
+	// Config is the newly active configuration, or nil when Err is set.
+	Config *AgentConfig
+
+	// Previous is the configuration that was active before the reload.
+	Previous *AgentConfig
+
+	// Err is the load or validation error. The previous configuration remains active.
+	Err error
+}
+
+// WatchOption configures a Watcher.
+type WatchOption func(*Watcher)
+
+// WithWatchInterval sets how often the configuration is checked for changes.
+// A non-positive interval disables polling, leaving only signals and Reload.
+func WithWatchInterval(interval time.Duration) WatchOption {
+	return func(w *Watcher) {
+		w.interval = interval
+	}
+}
+
+// WithReloadSignals sets the signals that force a reload. The default is SIGHUP.
+// Passing no signals disables signal handling.
+func WithReloadSignals(signals ...os.Signal) WatchOption {
+	return func(w *Watcher) {
+		w.signals = signals
+	}
+}
+
+// WithLoader sets the function used to load the configuration file,
+// for example to select a profile with LoadProfiles.
+func WithLoader(load LoadFunc) WatchOption {
+	return func(w *Watcher) {
+		w.load = load
+	}
+}
+
+// Watcher reloads an agent configuration file when it changes or when the
+// process receives a reload signal. Each reloaded configuration is validated
+// before it becomes active; invalid configurations are reported to subscribers
+// and the previous configuration is kept.
+//
+// Changes are detected by loading the configuration on every poll and
+// comparing it with the last loaded result, so updates to file:// secrets
+// referenced by the configuration are picked up as well as edits to the file.
+type Watcher struct {
+	filename string
+	interval time.Duration
+	signals  []os.Signal
+	load     LoadFunc
+
+	current atomic.Pointer[AgentConfig]
+
+	mutex   sync.Mutex
+	digest  [sha256.Size]byte
+	lastErr string
+
+	subMutex    sync.Mutex
+	subscribers map[int]func(ConfigEvent)
+	nextSub     int
+}
+
+// NewWatcher loads and validates the configuration file and returns a watcher for it.
+// Call Run to start watching. Returns an error if the initial configuration
+// cannot be loaded or is invalid.
+func NewWatcher(filename string, opts ...WatchOption) (*Watcher, error) {
+	w := &Watcher{
+		filename:    filename,
+		interval:    DefaultWatchInterval,
+		signals:     []os.Signal{syscall.SIGHUP},
+		load:        LoadAgentConfig,
+		subscribers: make(map[int]func(ConfigEvent)),
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	cfg, digest, err := w.loadValid()
+	if err != nil {
+		return nil, err
+	}
+
+	w.current.Store(cfg)
+	w.digest = digest
+
+	return w, nil
+}
+
+// Config returns the active configuration.
+// The returned configuration must not be modified.
+func (w *Watcher) Config() *AgentConfig {
+	return w.current.Load()
+}
+
+// Subscribe registers fn to be called after every reload attempt that
+// changes the configuration or fails. Callbacks run synchronously on the
+// watcher's goroutine in registration order.
+// Returns a function that removes the subscription.
+func (w *Watcher) Subscribe(fn func(ConfigEvent)) (unsubscribe func()) {
+	w.subMutex.Lock()
+	defer w.subMutex.Unlock()
+
+	id := w.nextSub
+	w.nextSub++
+	w.subscribers[id] = fn
+
+	return func() {
+		w.subMutex.Lock()
+		defer w.subMutex.Unlock()
+		delete(w.subscribers, id)
+	}
+}
+
+// Run watches for changes and reload signals until ctx is cancelled.
+func (w *Watcher) Run(ctx context.Context) {
+	var tick <-chan time.Time
+	if w.interval > 0 {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	var sig chan os.Signal
+	if len(w.signals) > 0 {
+		sig = make(chan os.Signal, 1)
+		signal.Notify(sig, w.signals...)
+		defer signal.Stop(sig)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick:
+			w.reload(false)
+		case <-sig:
+			w.reload(true)
+		}
+	}
+}
+
+// Reload re-reads the configuration immediately, as a reload signal does.
+// Subscribers are notified even if the configuration is unchanged.
+// Returns the load or validation error, if any.
+func (w *Watcher) Reload() error {
+	return w.reload(true)
+}
+
+// reload loads the configuration and activates it if it is valid.
+// Unless forced, unchanged configurations and repeated errors are not reported.
+func (w *Watcher) reload(force bool) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	previous := w.current.Load()
+
+	cfg, digest, err := w.loadValid()
+	if err != nil {
+		if force || err.Error() != w.lastErr {
+			w.lastErr = err.Error()
+			w.notify(ConfigEvent{Previous: previous, Err: err})
+		}
+		return err
+	}
+
+	changed := digest != w.digest
+	w.lastErr = ""
+
+	if !changed && !force {
+		return nil
+	}
+
+	w.digest = digest
+	w.current.Store(cfg)
+	w.notify(ConfigEvent{Config: cfg, Previous: previous})
+
+	return nil
+}
+
+// loadValid loads and validates the configuration, returning it with a
+// digest of its content for change detection.
+func (w *Watcher) loadValid() (*AgentConfig, [sha256.Size]byte, error) {
+	var digest [sha256.Size]byte
+
+	cfg, err := w.load(w.filename)
+	if err != nil {
+		return nil, digest, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, digest, fmt.Errorf("%s: %w", w.filename, err)
+	}
+
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, digest, fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	return cfg, sha256.Sum256(data), nil
+}
+
+// notify calls every subscriber with event.
+func (w *Watcher) notify(event ConfigEvent) {
+	w.subMutex.Lock()
+	subscribers := make([]func(ConfigEvent), 0, len(w.subscribers))
+	for id := range w.nextSub {
+		if fn, ok := w.subscribers[id]; ok {
+			subscribers = append(subscribers, fn)
+		}
+	}
+	w.subMutex.Unlock()
+
+	for _, fn := range subscribers {
+		fn(event)
+	}
+}
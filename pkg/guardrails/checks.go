@@ -0,0 +1,150 @@
+package guardrails
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/JaimeStill/go-agents/pkg/response"
+)
+
+// RedactedText replaces text removed by Denylist, Regex, and Moderation checks.
+const RedactedText = "[REDACTED]"
+
+// CheckFunc inspects text at a stage. See Check.
+type CheckFunc func(ctx context.Context, stage Stage, text string) (Verdict, error)
+
+type funcCheck struct {
+	name string
+	fn   CheckFunc
+}
+
+// Func creates a check from a Go function.
+func Func(name string, fn CheckFunc) Check {
+	return &funcCheck{name: name, fn: fn}
+}
+
+func (c *funcCheck) Name() string {
+	return c.name
+}
+
+func (c *funcCheck) Check(ctx context.Context, stage Stage, text string) (Verdict, error) {
+	return c.fn(ctx, stage, text)
+}
+
+type regexCheck struct {
+	name     string
+	action   Action
+	patterns []*regexp.Regexp
+}
+
+// Regex creates a check that matches text against patterns.
+// Redact replaces each match with RedactedText.
+func Regex(name string, action Action, patterns ...*regexp.Regexp) Check {
+	return &regexCheck{name: name, action: action, patterns: patterns}
+}
+
+// Denylist creates a check that matches whole words and phrases, ignoring case.
+// Redact replaces each match with RedactedText.
+func Denylist(action Action, terms ...string) Check {
+	quoted := make([]string, 0, len(terms))
+	for _, term := range terms {
+		if term = strings.TrimSpace(term); term != "" {
+			quoted = append(quoted, regexp.QuoteMeta(term))
+		}
+	}
+
+	var patterns []*regexp.Regexp
+	if len(quoted) > 0 {
+		patterns = append(patterns, regexp.MustCompile(`(?i)\b(?:`+strings.Join(quoted, "|")+`)\b`))
+	}
+
+	return &regexCheck{name: "denylist", action: action, patterns: patterns}
+}
+
+func (c *regexCheck) Name() string {
+	return c.name
+}
+
+func (c *regexCheck) Check(ctx context.Context, stage Stage, text string) (Verdict, error) {
+	var matches []string
+	redacted := text
+	for _, pattern := range c.patterns {
+		for _, match := range pattern.FindAllString(text, -1) {
+			matches = appendUnique(matches, strings.ToLower(match))
+		}
+		redacted = pattern.ReplaceAllLiteralString(redacted, RedactedText)
+	}
+
+	if len(matches) == 0 {
+		return Verdict{}, nil
+	}
+
+	return Verdict{
+		Action:   c.action,
+		Category: c.name,
+		Detail:   fmt.Sprintf("matched %q", matches),
+		Text:     redacted,
+	}, nil
+}
+
+// Moderator classifies text with a moderation model.
+// Agents implement Moderator.
+type Moderator interface {
+	Moderate(ctx context.Context, input string, opts ...map[string]any) (*response.ModerationResponse, error)
+}
+
+type moderationCheck struct {
+	moderator Moderator
+	action    Action
+	opts      []map[string]any
+}
+
+// Moderation creates a check that classifies text with a moderation endpoint.
+// Flagged text takes the action; Redact replaces the whole text with RedactedText.
+// The check's moderator must not run guardrails itself; Agent.Moderate does not.
+func Moderation(m Moderator, action Action, opts ...map[string]any) Check {
+	return &moderationCheck{moderator: m, action: action, opts: opts}
+}
+
+func (c *moderationCheck) Name() string {
+	return "moderation"
+}
+
+func (c *moderationCheck) Check(ctx context.Context, stage Stage, text string) (Verdict, error) {
+	resp, err := c.moderator.Moderate(ctx, text, c.opts...)
+	if err != nil {
+		return Verdict{}, err
+	}
+
+	var categories []string
+	for _, result := range resp.Results {
+		if result.Flagged {
+			for _, category := range result.FlaggedCategories() {
+				categories = appendUnique(categories, category)
+			}
+		}
+	}
+
+	if !resp.Flagged() {
+		return Verdict{}, nil
+	}
+
+	return Verdict{
+		Action:   c.action,
+		Category: strings.Join(categories, ","),
+		Detail:   "flagged by moderation model",
+		Text:     RedactedText,
+	}, nil
+}
+
+// appendUnique appends s to list if it is not already present.
+func appendUnique(list []string, s string) []string {
+	for _, item := range list {
+		if item == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
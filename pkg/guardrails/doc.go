@@ -0,0 +1,49 @@
+// Package guardrails runs input and output checks around agent calls.
+//
+// A Check inspects request or response text and returns a Verdict whose Action
+// passes, annotates, redacts, or blocks the text. Built-in checks are:
+//
+//   - Denylist: whole words and phrases, ignoring case
+//   - Regex: arbitrary patterns
+//   - PII: email addresses, phone numbers, card numbers, SSNs, and IP addresses
+//   - Moderation: a moderation endpoint, through any Moderator such as an agent
+//   - Func: a custom Go function
+//
+// Checks run in the order they were added; redactions apply to the text seen by
+// later checks, and the first block stops the pipeline. A check that returns an
+// error fails the call.
+//
+// # Attaching Guardrails
+//
+// Guardrails are attached to agents at creation time:
+//
+//	moderator, err := agent.New(moderationCfg)
+//
+//	g := guardrails.New(
+//	    guardrails.WithInput(
+//	        guardrails.PII(guardrails.Redact),
+//	        guardrails.Moderation(moderator, guardrails.Block),
+//	    ),
+//	    guardrails.WithOutput(
+//	        guardrails.Denylist(guardrails.Redact, "internal only"),
+//	    ),
+//	)
+//
+//	a, err := agent.New(cfg, agent.WithGuardrails(g))
+//
+// Blocked calls fail with a *BlockedError wrapping ErrBlocked. Output of
+// streaming calls is checked once the stream completes; redactions on it are
+// reported as findings but not applied, since the content was already delivered.
+//
+// # Findings
+//
+// Every check that matches produces a Finding. Findings are delivered to the
+// observer set with WithObserver and collected by a Report attached to the
+// request context:
+//
+//	ctx, report := guardrails.WithReport(ctx)
+//	resp, err := a.Chat(ctx, prompt)
+//	for _, f := range report.Findings() {
+//	    fmt.Printf("%s %s: %s (%s)\n", f.Stage, f.Check, f.Action, f.Category)
+//	}
+package guardrails
@@ -0,0 +1,233 @@
+package guardrails
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// Stage identifies when a check runs: on the request before it is sent, or on
+// the response before it is returned.
+type Stage string
+
+const (
+	// Input checks run on prompts and other request text.
+	Input Stage = "input"
+
+	// Output checks run on model responses.
+	Output Stage = "output"
+)
+
+// Action is the outcome of a check that matched.
+type Action string
+
+const (
+	// Pass lets the text through unchanged. It is the zero Action.
+	Pass Action = ""
+
+	// Annotate lets the text through unchanged and records a finding.
+	Annotate Action = "annotate"
+
+	// Redact replaces the text with the verdict's redacted text and records a finding.
+	Redact Action = "redact"
+
+	// Block rejects the call with a *BlockedError.
+	Block Action = "block"
+)
+
+// ErrBlocked indicates a guardrail check blocked a request or response.
+var ErrBlocked = errors.New("blocked by guardrail")
+
+// Check inspects request or response text.
+// Implementations must be safe for concurrent use.
+type Check interface {
+	// Name identifies the check in findings.
+	Name() string
+
+	// Check inspects text at a stage. A zero Verdict passes the text.
+	// A returned error fails the call, so checks fail closed.
+	Check(ctx context.Context, stage Stage, text string) (Verdict, error)
+}
+
+// Verdict is the result of a check.
+type Verdict struct {
+	// Action is what to do with the text.
+	Action Action
+
+	// Category classifies the match, such as "email" or "violence".
+	Category string
+
+	// Detail describes the match.
+	Detail string
+
+	// Text is the redacted text. Used only when Action is Redact.
+	Text string
+}
+
+// Finding records a check that matched request or response text.
+type Finding struct {
+	Check    string `json:"check"`
+	Stage    Stage  `json:"stage"`
+	Action   Action `json:"action"`
+	Category string `json:"category,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// BlockedError is returned when a check blocks a request or response.
+// Unwraps to ErrBlocked.
+type BlockedError struct {
+	Finding Finding
+}
+
+func (e *BlockedError) Error() string {
+	msg := fmt.Sprintf("%s: %s %s", ErrBlocked, e.Finding.Check, e.Finding.Stage)
+	if e.Finding.Category != "" {
+		msg += " (" + e.Finding.Category + ")"
+	}
+	if e.Finding.Detail != "" {
+		msg += ": " + e.Finding.Detail
+	}
+	return msg
+}
+
+// Unwrap returns ErrBlocked for use with errors.Is.
+func (e *BlockedError) Unwrap() error {
+	return ErrBlocked
+}
+
+// Guardrails runs input and output checks in the order they were added.
+// Redactions apply to the text seen by later checks, and the first block
+// stops the pipeline.
+type Guardrails struct {
+	input    []Check
+	output   []Check
+	observer func(Finding)
+}
+
+// Option configures Guardrails.
+type Option func(*Guardrails)
+
+// WithInput adds checks run on request text.
+func WithInput(checks ...Check) Option {
+	return func(g *Guardrails) {
+		g.input = append(g.input, checks...)
+	}
+}
+
+// WithOutput adds checks run on response text.
+func WithOutput(checks ...Check) Option {
+	return func(g *Guardrails) {
+		g.output = append(g.output, checks...)
+	}
+}
+
+// WithObserver registers a function called with every finding, including
+// blocks. Useful for logging and metrics.
+func WithObserver(fn func(Finding)) Option {
+	return func(g *Guardrails) {
+		g.observer = fn
+	}
+}
+
+// New creates Guardrails from options.
+func New(opts ...Option) *Guardrails {
+	g := &Guardrails{}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// Apply runs the checks for a stage on text.
+// Returns the text to use, with redactions applied, and the findings of the
+// checks that matched. Findings are also delivered to the observer and to a
+// Report attached to ctx. Returns a *BlockedError if a check blocks the text,
+// or the check's error if a check fails. Empty text is not checked.
+func (g *Guardrails) Apply(ctx context.Context, stage Stage, text string) (string, []Finding, error) {
+	checks := g.input
+	if stage == Output {
+		checks = g.output
+	}
+
+	if text == "" || len(checks) == 0 {
+		return text, nil, nil
+	}
+
+	var findings []Finding
+	for _, check := range checks {
+		verdict, err := check.Check(ctx, stage, text)
+		if err != nil {
+			return "", findings, fmt.Errorf("guardrail %s: %w", check.Name(), err)
+		}
+		if verdict.Action == Pass {
+			continue
+		}
+
+		finding := Finding{
+			Check:    check.Name(),
+			Stage:    stage,
+			Action:   verdict.Action,
+			Category: verdict.Category,
+			Detail:   verdict.Detail,
+		}
+		findings = append(findings, finding)
+		g.record(ctx, finding)
+
+		switch verdict.Action {
+		case Block:
+			return "", findings, &BlockedError{Finding: finding}
+		case Redact:
+			text = verdict.Text
+		}
+	}
+
+	return text, findings, nil
+}
+
+// record delivers a finding to the observer and the context's Report.
+func (g *Guardrails) record(ctx context.Context, finding Finding) {
+	if g.observer != nil {
+		g.observer(finding)
+	}
+	if report, ok := ctx.Value(reportKey{}).(*Report); ok {
+		report.add(finding)
+	}
+}
+
+// Report collects the findings of the calls made with a context.
+// Safe for concurrent use.
+type Report struct {
+	mutex    sync.Mutex
+	findings []Finding
+}
+
+type reportKey struct{}
+
+// WithReport returns a context that collects the findings of guardrail checks
+// run for calls made with it.
+func WithReport(ctx context.Context) (context.Context, *Report) {
+	report := &Report{}
+	return context.WithValue(ctx, reportKey{}, report), report
+}
+
+// Findings returns the findings collected so far, in the order they occurred.
+func (r *Report) Findings() []Finding {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return slices.Clone(r.findings)
+}
+
+// Blocked reports whether any collected finding blocked a call.
+func (r *Report) Blocked() bool {
+	return slices.ContainsFunc(r.Findings(), func(f Finding) bool {
+		return f.Action == Block
+	})
+}
+
+func (r *Report) add(finding Finding) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.findings = append(r.findings, finding)
+}
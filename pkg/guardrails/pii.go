@@ -0,0 +1,127 @@
+package guardrails
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Detector finds one kind of personally identifiable information.
+type Detector struct {
+	// Name categorizes matches and names the redaction placeholder,
+	// such as "email" for "[REDACTED_EMAIL]".
+	Name string
+
+	// Pattern matches candidate values.
+	Pattern *regexp.Regexp
+
+	// Validate rejects false positives among the candidates. Optional.
+	Validate func(match string) bool
+}
+
+// Built-in PII detectors.
+var (
+	// Email detects email addresses.
+	Email = Detector{
+		Name:    "email",
+		Pattern: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+	}
+
+	// Phone detects North American and international phone numbers.
+	Phone = Detector{
+		Name:    "phone",
+		Pattern: regexp.MustCompile(`(?:\+\d{1,3}[\s.-]?)?(?:\(\d{3}\)|\b\d{3})[\s.-]?\d{3}[\s.-]?\d{4}\b`),
+	}
+
+	// CreditCard detects payment card numbers that pass the Luhn checksum.
+	CreditCard = Detector{
+		Name:     "credit_card",
+		Pattern:  regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`),
+		Validate: luhn,
+	}
+
+	// SSN detects US social security numbers.
+	SSN = Detector{
+		Name:    "ssn",
+		Pattern: regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`),
+	}
+
+	// IPAddress detects IPv4 addresses.
+	IPAddress = Detector{
+		Name:    "ip_address",
+		Pattern: regexp.MustCompile(`\b(?:(?:25[0-5]|2[0-4]\d|1?\d?\d)\.){3}(?:25[0-5]|2[0-4]\d|1?\d?\d)\b`),
+	}
+)
+
+// DefaultDetectors are the detectors used by PII when none are given.
+// Card numbers are detected before phone numbers so long digit runs are
+// categorized as cards.
+var DefaultDetectors = []Detector{Email, CreditCard, SSN, Phone, IPAddress}
+
+type piiCheck struct {
+	action    Action
+	detectors []Detector
+}
+
+// PII creates a check that detects personally identifiable information.
+// Uses DefaultDetectors if no detectors are given. Redact replaces each match
+// with a placeholder naming its detector, such as "[REDACTED_EMAIL]".
+func PII(action Action, detectors ...Detector) Check {
+	if len(detectors) == 0 {
+		detectors = DefaultDetectors
+	}
+	return &piiCheck{action: action, detectors: detectors}
+}
+
+func (c *piiCheck) Name() string {
+	return "pii"
+}
+
+func (c *piiCheck) Check(ctx context.Context, stage Stage, text string) (Verdict, error) {
+	var categories []string
+	count := 0
+	for _, d := range c.detectors {
+		placeholder := "[REDACTED_" + strings.ToUpper(d.Name) + "]"
+		text = d.Pattern.ReplaceAllStringFunc(text, func(match string) string {
+			if d.Validate != nil && !d.Validate(match) {
+				return match
+			}
+			categories = appendUnique(categories, d.Name)
+			count++
+			return placeholder
+		})
+	}
+
+	if count == 0 {
+		return Verdict{}, nil
+	}
+
+	return Verdict{
+		Action:   c.action,
+		Category: strings.Join(categories, ","),
+		Detail:   fmt.Sprintf("%d match(es)", count),
+		Text:     text,
+	}, nil
+}
+
+// luhn reports whether the digits of s pass the Luhn checksum.
+func luhn(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && sum%10 == 0
+}
@@ -0,0 +1,33 @@
+// Package memory provides strategies for bounding multi-turn conversation history.
+//
+// A Strategy reduces a []protocol.Message history to the messages that should
+// be sent with the next request. Three strategies are provided:
+//
+//   - Window: keeps the last N messages
+//   - TokenWindow: keeps the most recent messages that fit a token budget
+//   - Summary: compresses older turns into a rolling summary using an agent
+//
+// All strategies preserve leading system messages. An assistant message
+// requesting tool calls and the tool results that follow it are treated as one
+// unit, so a window or summary never keeps a call without its results or a
+// result without its call; providers reject such histories.
+//
+// # Sessions
+//
+// Session combines a history with a strategy and an optional Store so
+// conversations survive restarts:
+//
+//	store, err := memory.NewFileStore("./sessions")
+//	strategy := memory.NewTokenWindow(4000, tokenizer.ForModel("gpt-4o", ""))
+//	session, err := memory.NewSession(ctx, "user-42", strategy, store)
+//
+//	session.Add(ctx, protocol.NewMessage("user", "Hello"))
+//	history := session.Messages()
+//
+// # Summarization
+//
+// Summary accepts any Summarizer, which agent.Agent satisfies:
+//
+//	summarizer, err := agent.New(cfg)
+//	strategy := memory.NewSummary(summarizer, 20, 6)
+package memory
@@ -0,0 +1,125 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/JaimeStill/go-agents/pkg/protocol"
+	"github.com/JaimeStill/go-agents/pkg/tokenizer"
+)
+
+// Strategy reduces a conversation history to the messages that should be sent.
+// Implementations must not modify the input slice.
+type Strategy interface {
+	// Apply returns the messages to keep from a conversation history.
+	Apply(ctx context.Context, messages []protocol.Message) ([]protocol.Message, error)
+}
+
+// Window keeps the most recent messages by count.
+// Leading system messages are always kept and do not count toward the size.
+// An assistant message requesting tool calls and the tool results that follow
+// it are kept or dropped together, since providers reject histories that
+// separate them.
+type Window struct {
+	size int
+}
+
+// NewWindow creates a sliding window strategy that keeps the last size messages.
+func NewWindow(size int) *Window {
+	return &Window{size: size}
+}
+
+// Apply returns leading system messages followed by at most the last size
+// messages. A tool call group that would be cut is dropped whole; the most
+// recent group is always kept when size is positive, even if it exceeds size.
+func (w *Window) Apply(ctx context.Context, messages []protocol.Message) ([]protocol.Message, error) {
+	system, rest := splitSystem(messages)
+
+	if w.size <= 0 {
+		return join(system), nil
+	}
+
+	start := len(rest)
+	for start > 0 {
+		i := groupStart(rest, start-1)
+		if len(rest)-i > w.size && start < len(rest) {
+			break
+		}
+		start = i
+	}
+
+	return join(system, rest[start:]), nil
+}
+
+// TokenWindow keeps the most recent messages that fit within a token budget.
+// Leading system messages are always kept and count toward the budget.
+// Tool call groups are kept or dropped together, as with Window.
+type TokenWindow struct {
+	budget    int
+	tokenizer tokenizer.Tokenizer
+}
+
+// NewTokenWindow creates a token-budget window strategy.
+// A nil tokenizer uses the heuristic tokenizer.
+func NewTokenWindow(budget int, t tokenizer.Tokenizer) *TokenWindow {
+	if t == nil {
+		t = tokenizer.NewHeuristic(tokenizer.DefaultCharsPerToken)
+	}
+	return &TokenWindow{budget: budget, tokenizer: t}
+}
+
+// Apply returns leading system messages followed by as many recent messages
+// as fit within the budget. Messages are dropped oldest first; the most recent
+// message or tool call group is always kept even if it alone exceeds the budget.
+func (w *TokenWindow) Apply(ctx context.Context, messages []protocol.Message) ([]protocol.Message, error) {
+	system, rest := splitSystem(messages)
+
+	// CountMessages includes fixed reply-priming overhead; count it once.
+	overhead := tokenizer.CountMessages(w.tokenizer, nil)
+	used := tokenizer.CountMessages(w.tokenizer, system)
+	start := len(rest)
+
+	for start > 0 {
+		i := groupStart(rest, start-1)
+		cost := tokenizer.CountMessages(w.tokenizer, rest[i:start]) - overhead
+		if used+cost > w.budget && start < len(rest) {
+			break
+		}
+		used += cost
+		start = i
+	}
+
+	return join(system, rest[start:]), nil
+}
+
+// splitSystem separates leading system messages from the rest of the conversation.
+func splitSystem(messages []protocol.Message) (system, rest []protocol.Message) {
+	i := 0
+	for i < len(messages) && messages[i].Role == "system" {
+		i++
+	}
+	return messages[:i], messages[i:]
+}
+
+// groupStart returns the index of the first message of the group containing
+// messages[i]. Tool results are grouped with the assistant message requesting
+// the calls that precede them; any other message is a group of its own.
+func groupStart(messages []protocol.Message, i int) int {
+	for i > 0 && messages[i].Role == "tool" {
+		i--
+	}
+	return i
+}
+
+// join concatenates message slices into a new slice.
+func join(parts ...[]protocol.Message) []protocol.Message {
+	total := 0
+	for _, part := range parts {
+		total += len(part)
+	}
+
+	result := make([]protocol.Message, 0, total)
+	for _, part := range parts {
+		result = append(result, part...)
+	}
+	return result
+}
@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+
+	"github.com/JaimeStill/go-agents/pkg/protocol"
+)
+
+// Session holds a conversation history, compacts it with a Strategy,
+// and optionally persists it to a Store.
+// Safe for concurrent use.
+type Session struct {
+	id       string
+	strategy Strategy
+	store    Store
+
+	mu       sync.Mutex
+	messages []protocol.Message
+}
+
+// NewSession creates a session, restoring its history from store if present.
+// A nil strategy keeps the full history; a nil store disables persistence.
+func NewSession(ctx context.Context, id string, strategy Strategy, store Store) (*Session, error) {
+	s := &Session{
+		id:       id,
+		strategy: strategy,
+		store:    store,
+	}
+
+	if store != nil {
+		messages, err := store.Load(ctx, id)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+		s.messages = messages
+	}
+
+	return s, nil
+}
+
+// ID returns the session identifier.
+func (s *Session) ID() string {
+	return s.id
+}
+
+// Messages returns a copy of the current history.
+func (s *Session) Messages() []protocol.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.messages)
+}
+
+// Add appends messages, applies the strategy, and persists the result.
+// The history is left unchanged if the strategy fails.
+func (s *Session) Add(ctx context.Context, messages ...protocol.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	history := append(slices.Clone(s.messages), messages...)
+
+	if s.strategy != nil {
+		compacted, err := s.strategy.Apply(ctx, history)
+		if err != nil {
+			return err
+		}
+		history = compacted
+	}
+
+	s.messages = history
+
+	if s.store != nil {
+		return s.store.Save(ctx, s.id, history)
+	}
+
+	return nil
+}
+
+// Clear removes the history and deletes it from the store.
+func (s *Session) Clear(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.messages = nil
+
+	if s.store != nil {
+		return s.store.Delete(ctx, s.id)
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/JaimeStill/go-agents/pkg/protocol"
+)
+
+// ErrNotFound indicates no session exists for the requested ID.
+var ErrNotFound = errors.New("session not found")
+
+// Store persists conversation histories by session ID.
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Load returns the stored messages for a session.
+	// Returns an error wrapping ErrNotFound if the session does not exist.
+	Load(ctx context.Context, id string) ([]protocol.Message, error)
+
+	// Save replaces the stored messages for a session.
+	Save(ctx context.Context, id string, messages []protocol.Message) error
+
+	// Delete removes a session. Deleting a missing session is not an error.
+	Delete(ctx context.Context, id string) error
+}
+
+// FileStore persists each session as a JSON file in a directory.
+type FileStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewFileStore creates a file store rooted at dir, creating the directory if needed.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create store directory: %w", err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// Load reads the session file for id.
+func (s *FileStore) Load(ctx context.Context, id string) ([]protocol.Message, error) {
+	path, err := s.path(id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session: %w", err)
+	}
+
+	var messages []protocol.Message
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return nil, fmt.Errorf("failed to parse session: %w", err)
+	}
+
+	return messages, nil
+}
+
+// Save writes the session file for id.
+// The file is written to a temporary path and renamed so readers never see a partial write.
+func (s *FileStore) Save(ctx context.Context, id string, messages []protocol.Message) error {
+	path, err := s.path(id)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(messages, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write session: %w", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write session: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the session file for id.
+func (s *FileStore) Delete(ctx context.Context, id string) error {
+	path, err := s.path(id)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+
+	return nil
+}
+
+// path returns the file path for a session ID.
+// IDs must be non-empty and must not contain path separators.
+func (s *FileStore) path(id string) (string, error) {
+	if id == "" || id == "." || id == ".." || strings.ContainsAny(id, `/\`) {
+		return "", fmt.Errorf("invalid session id: %q", id)
+	}
+	return filepath.Join(s.dir, id+".json"), nil
+}
@@ -0,0 +1,172 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/JaimeStill/go-agents/pkg/protocol"
+	"github.com/JaimeStill/go-agents/pkg/response"
+)
+
+// SummaryPrefix marks the system message that carries a rolling summary.
+const SummaryPrefix = "Summary of the earlier conversation:\n"
+
+// DefaultSummaryPrompt instructs the summarizer how to compress older turns.
+const DefaultSummaryPrompt = `Summarize the following conversation so it can replace the original messages.
+Preserve facts, decisions, names, numbers, and open questions. Be concise.`
+
+// Summarizer produces a completion for a prompt.
+// agent.Agent satisfies this interface.
+type Summarizer interface {
+	Chat(ctx context.Context, prompt string, opts ...map[string]any) (*response.ChatResponse, error)
+}
+
+// Summary compresses older turns into a rolling summary once the history
+// exceeds a threshold, keeping the most recent messages verbatim.
+// The summary is carried as a system message following any leading system
+// messages, and is folded into the next summary when compaction runs again.
+type Summary struct {
+	summarizer Summarizer
+	threshold  int
+	keep       int
+	prompt     string
+	options    map[string]any
+}
+
+// SummaryOption configures a Summary strategy.
+type SummaryOption func(*Summary)
+
+// WithPrompt sets the instructions sent to the summarizer.
+func WithPrompt(prompt string) SummaryOption {
+	return func(s *Summary) {
+		s.prompt = prompt
+	}
+}
+
+// WithOptions sets request options passed to the summarizer's Chat call.
+func WithOptions(options map[string]any) SummaryOption {
+	return func(s *Summary) {
+		s.options = options
+	}
+}
+
+// NewSummary creates a summarization strategy.
+// When the conversation (excluding leading system messages) exceeds threshold
+// messages, all but the last keep messages are replaced by a summary.
+func NewSummary(summarizer Summarizer, threshold, keep int, opts ...SummaryOption) *Summary {
+	s := &Summary{
+		summarizer: summarizer,
+		threshold:  threshold,
+		keep:       max(keep, 0),
+		prompt:     DefaultSummaryPrompt,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Apply summarizes older turns when the history exceeds the threshold.
+// Returns the history unchanged when no compaction is needed.
+func (s *Summary) Apply(ctx context.Context, messages []protocol.Message) ([]protocol.Message, error) {
+	system, rest := splitSystem(messages)
+
+	previous := ""
+	if n := len(system); n > 0 && isSummary(system[n-1]) {
+		previous = strings.TrimPrefix(system[n-1].Content.(string), SummaryPrefix)
+		system = system[:n-1]
+	}
+
+	if len(rest) <= s.threshold || len(rest) <= s.keep {
+		return messages, nil
+	}
+
+	// Keep a tool call group whole on the recent side of the split
+	split := len(rest) - s.keep
+	if split < len(rest) {
+		split = groupStart(rest, split)
+	}
+	if split == 0 {
+		return messages, nil
+	}
+	older, recent := rest[:split], rest[split:]
+
+	summary, err := s.summarize(ctx, previous, older)
+	if err != nil {
+		return nil, err
+	}
+
+	return join(
+		system,
+		[]protocol.Message{protocol.NewMessage("system", SummaryPrefix+summary)},
+		recent,
+	), nil
+}
+
+// summarize asks the summarizer to compress the previous summary and older turns.
+func (s *Summary) summarize(ctx context.Context, previous string, older []protocol.Message) (string, error) {
+	var b strings.Builder
+	b.WriteString(s.prompt)
+	b.WriteString("\n\n")
+
+	if previous != "" {
+		b.WriteString("Existing summary:\n")
+		b.WriteString(previous)
+		b.WriteString("\n\n")
+	}
+
+	b.WriteString("Conversation:\n")
+	for _, msg := range older {
+		writeMessage(&b, msg)
+	}
+
+	var opts []map[string]any
+	if s.options != nil {
+		opts = append(opts, s.options)
+	}
+
+	resp, err := s.summarizer.Chat(ctx, b.String(), opts...)
+	if err != nil {
+		return "", fmt.Errorf("summarization failed: %w", err)
+	}
+
+	summary := strings.TrimSpace(resp.Content())
+	if summary == "" {
+		return "", fmt.Errorf("summarization failed: empty response")
+	}
+
+	return summary, nil
+}
+
+// writeMessage renders a message as a line of the summarization transcript.
+// Tool calls are rendered as function invocations, and tool results name the
+// call they answer.
+func writeMessage(b *strings.Builder, msg protocol.Message) {
+	role := msg.Role
+	if msg.ToolCallID != "" {
+		role = fmt.Sprintf("%s (%s)", role, msg.ToolCallID)
+	}
+
+	if len(msg.ToolCalls) > 0 {
+		calls := make([]string, len(msg.ToolCalls))
+		for i, call := range msg.ToolCalls {
+			calls[i] = fmt.Sprintf("%s(%s) [%s]", call.Function.Name, call.Function.Arguments, call.ID)
+		}
+		if text := msg.Text(); text != "" {
+			fmt.Fprintf(b, "%s: %s\n", role, text)
+		}
+		fmt.Fprintf(b, "%s: called %s\n", role, strings.Join(calls, ", "))
+		return
+	}
+
+	fmt.Fprintf(b, "%s: %s\n", role, msg.Text())
+}
+
+// isSummary reports whether a message carries a rolling summary.
+func isSummary(msg protocol.Message) bool {
+	text, ok := msg.Content.(string)
+	return ok && msg.Role == "system" && strings.HasPrefix(text, SummaryPrefix)
+}
@@ -2,6 +2,8 @@ package mock
 
 import (
 	"context"
+	"maps"
+	"slices"
 
 	"github.com/JaimeStill/go-agents/pkg/agent"
 	"github.com/JaimeStill/go-agents/pkg/client"
@@ -12,24 +14,51 @@ import (
 )
 
 // MockAgent implements agent.Agent interface for testing.
-// All methods return predetermined responses configured during construction.
+// Methods return predetermined responses configured during construction,
+// or responses computed per call by response functions such as WithChatFunc.
+// Every call is recorded by the embedded Recorder.
+// Safe for concurrent use.
 type MockAgent struct {
+	Recorder
+
 	id string
 
 	// Protocol responses
-	chatResponse       *response.ChatResponse
-	chatError          error
-	visionResponse     *response.ChatResponse
-	visionError        error
-	toolsResponse      *response.ToolsResponse
-	toolsError         error
-	embeddingsResponse *response.EmbeddingsResponse
-	embeddingsError    error
+	chatResponse          *response.ChatResponse
+	chatError             error
+	visionResponse        *response.ChatResponse
+	visionError           error
+	toolsResponse         *response.ToolsResponse
+	toolsError            error
+	embeddingsResponse    *response.EmbeddingsResponse
+	embeddingsError       error
+	imageResponse         *response.ImageGenerationResponse
+	imageError            error
+	transcriptionResponse *response.TranscriptionResponse
+	transcriptionError    error
+	speechResponse        *response.SpeechResponse
+	speechError           error
+	rerankResponse        *response.RerankResponse
+	rerankError           error
+	moderationResponse    *response.ModerationResponse
+	moderationError       error
 
 	// Streaming responses
 	streamChunks []response.StreamingChunk
 	streamError  error
 
+	// Per-call response functions, which take precedence when set
+	chatFunc          func(Call) (*response.ChatResponse, error)
+	visionFunc        func(Call) (*response.ChatResponse, error)
+	toolsFunc         func(Call) (*response.ToolsResponse, error)
+	embeddingsFunc    func(Call) (*response.EmbeddingsResponse, error)
+	imageFunc         func(Call) (*response.ImageGenerationResponse, error)
+	transcriptionFunc func(Call) (*response.TranscriptionResponse, error)
+	speechFunc        func(Call) (*response.SpeechResponse, error)
+	rerankFunc        func(Call) (*response.RerankResponse, error)
+	moderationFunc    func(Call) (*response.ModerationResponse, error)
+	streamFunc        func(Call) ([]response.StreamingChunk, error)
+
 	// Dependencies
 	mockClient   client.Client
 	mockProvider providers.Provider
@@ -99,6 +128,46 @@ func WithEmbeddingsResponse(resp *response.EmbeddingsResponse, err error) MockAg
 	}
 }
 
+// WithImageResponse sets the image generation response and error.
+func WithImageResponse(resp *response.ImageGenerationResponse, err error) MockAgentOption {
+	return func(m *MockAgent) {
+		m.imageResponse = resp
+		m.imageError = err
+	}
+}
+
+// WithTranscriptionResponse sets the transcription response and error.
+func WithTranscriptionResponse(resp *response.TranscriptionResponse, err error) MockAgentOption {
+	return func(m *MockAgent) {
+		m.transcriptionResponse = resp
+		m.transcriptionError = err
+	}
+}
+
+// WithSpeechResponse sets the speech response and error.
+func WithSpeechResponse(resp *response.SpeechResponse, err error) MockAgentOption {
+	return func(m *MockAgent) {
+		m.speechResponse = resp
+		m.speechError = err
+	}
+}
+
+// WithRerankResponse sets the rerank response and error.
+func WithRerankResponse(resp *response.RerankResponse, err error) MockAgentOption {
+	return func(m *MockAgent) {
+		m.rerankResponse = resp
+		m.rerankError = err
+	}
+}
+
+// WithModerationResponse sets the moderation response and error.
+func WithModerationResponse(resp *response.ModerationResponse, err error) MockAgentOption {
+	return func(m *MockAgent) {
+		m.moderationResponse = resp
+		m.moderationError = err
+	}
+}
+
 // WithStreamChunks sets the streaming chunks for stream methods.
 func WithStreamChunks(chunks []response.StreamingChunk, err error) MockAgentOption {
 	return func(m *MockAgent) {
@@ -107,6 +176,78 @@ func WithStreamChunks(chunks []response.StreamingChunk, err error) MockAgentOpti
 	}
 }
 
+// WithChatFunc sets a function that computes the chat response for each call.
+// Use Responses or Sequence to return a different response per call.
+func WithChatFunc(fn func(Call) (*response.ChatResponse, error)) MockAgentOption {
+	return func(m *MockAgent) {
+		m.chatFunc = fn
+	}
+}
+
+// WithVisionFunc sets a function that computes the vision response for each call.
+func WithVisionFunc(fn func(Call) (*response.ChatResponse, error)) MockAgentOption {
+	return func(m *MockAgent) {
+		m.visionFunc = fn
+	}
+}
+
+// WithToolsFunc sets a function that computes the tools response for each call.
+func WithToolsFunc(fn func(Call) (*response.ToolsResponse, error)) MockAgentOption {
+	return func(m *MockAgent) {
+		m.toolsFunc = fn
+	}
+}
+
+// WithEmbeddingsFunc sets a function that computes the embeddings response for each call.
+func WithEmbeddingsFunc(fn func(Call) (*response.EmbeddingsResponse, error)) MockAgentOption {
+	return func(m *MockAgent) {
+		m.embeddingsFunc = fn
+	}
+}
+
+// WithImageFunc sets a function that computes the image generation response for each call.
+func WithImageFunc(fn func(Call) (*response.ImageGenerationResponse, error)) MockAgentOption {
+	return func(m *MockAgent) {
+		m.imageFunc = fn
+	}
+}
+
+// WithTranscriptionFunc sets a function that computes the transcription response for each call.
+func WithTranscriptionFunc(fn func(Call) (*response.TranscriptionResponse, error)) MockAgentOption {
+	return func(m *MockAgent) {
+		m.transcriptionFunc = fn
+	}
+}
+
+// WithSpeechFunc sets a function that computes the speech response for each call.
+func WithSpeechFunc(fn func(Call) (*response.SpeechResponse, error)) MockAgentOption {
+	return func(m *MockAgent) {
+		m.speechFunc = fn
+	}
+}
+
+// WithRerankFunc sets a function that computes the rerank response for each call.
+func WithRerankFunc(fn func(Call) (*response.RerankResponse, error)) MockAgentOption {
+	return func(m *MockAgent) {
+		m.rerankFunc = fn
+	}
+}
+
+// WithModerationFunc sets a function that computes the moderation response for each call.
+func WithModerationFunc(fn func(Call) (*response.ModerationResponse, error)) MockAgentOption {
+	return func(m *MockAgent) {
+		m.moderationFunc = fn
+	}
+}
+
+// WithStreamFunc sets a function that computes the streamed chunks for each
+// ChatStream, VisionStream, and SpeakStream call.
+func WithStreamFunc(fn func(Call) ([]response.StreamingChunk, error)) MockAgentOption {
+	return func(m *MockAgent) {
+		m.streamFunc = fn
+	}
+}
+
 // WithClient sets a custom client.
 func WithClient(c client.Client) MockAgentOption {
 	return func(m *MockAgent) {
@@ -148,54 +289,217 @@ func (m *MockAgent) Model() *model.Model {
 	return m.mockModel
 }
 
-// Chat returns the predetermined chat response.
+// Chat records the call and returns the chat response.
 func (m *MockAgent) Chat(ctx context.Context, prompt string, opts ...map[string]any) (*response.ChatResponse, error) {
+	call := m.record(Call{
+		Method:   MethodChat,
+		Protocol: protocol.Chat,
+		Prompt:   prompt,
+		Options:  m.mergeOptions(protocol.Chat, false, opts...),
+	})
+
+	if m.chatFunc != nil {
+		return m.chatFunc(call)
+	}
 	return m.chatResponse, m.chatError
 }
 
-// ChatStream returns a channel with predetermined streaming chunks.
+// ChatStream records the call and returns a channel with the streaming chunks.
 func (m *MockAgent) ChatStream(ctx context.Context, prompt string, opts ...map[string]any) (<-chan *response.StreamingChunk, error) {
-	if m.streamError != nil {
-		return nil, m.streamError
+	call := m.record(Call{
+		Method:   MethodChatStream,
+		Protocol: protocol.Chat,
+		Prompt:   prompt,
+		Options:  m.mergeOptions(protocol.Chat, true, opts...),
+	})
+
+	return m.stream(call)
+}
+
+// Vision records the call and returns the vision response.
+func (m *MockAgent) Vision(ctx context.Context, prompt string, images []string, opts ...map[string]any) (*response.ChatResponse, error) {
+	call := m.record(Call{
+		Method:   MethodVision,
+		Protocol: protocol.Vision,
+		Prompt:   prompt,
+		Images:   slices.Clone(images),
+		Options:  m.mergeOptions(protocol.Vision, false, opts...),
+	})
+
+	if m.visionFunc != nil {
+		return m.visionFunc(call)
 	}
+	return m.visionResponse, m.visionError
+}
 
-	ch := make(chan *response.StreamingChunk, len(m.streamChunks))
-	for i := range m.streamChunks {
-		ch <- &m.streamChunks[i]
+// VisionStream records the call and returns a channel with the streaming chunks.
+func (m *MockAgent) VisionStream(ctx context.Context, prompt string, images []string, opts ...map[string]any) (<-chan *response.StreamingChunk, error) {
+	call := m.record(Call{
+		Method:   MethodVisionStream,
+		Protocol: protocol.Vision,
+		Prompt:   prompt,
+		Images:   slices.Clone(images),
+		Options:  m.mergeOptions(protocol.Vision, true, opts...),
+	})
+
+	return m.stream(call)
+}
+
+// Tools records the call and returns the tools response.
+func (m *MockAgent) Tools(ctx context.Context, prompt string, tools []agent.Tool, opts ...map[string]any) (*response.ToolsResponse, error) {
+	call := m.record(Call{
+		Method:   MethodTools,
+		Protocol: protocol.Tools,
+		Prompt:   prompt,
+		Tools:    slices.Clone(tools),
+		Options:  m.mergeOptions(protocol.Tools, false, opts...),
+	})
+
+	if m.toolsFunc != nil {
+		return m.toolsFunc(call)
 	}
-	close(ch)
+	return m.toolsResponse, m.toolsError
+}
 
-	return ch, nil
+// Embed records the call and returns the embeddings response.
+func (m *MockAgent) Embed(ctx context.Context, input string, opts ...map[string]any) (*response.EmbeddingsResponse, error) {
+	call := m.record(Call{
+		Method:   MethodEmbed,
+		Protocol: protocol.Embeddings,
+		Input:    input,
+		Options:  m.mergeOptions(protocol.Embeddings, false, opts...),
+	})
+
+	if m.embeddingsFunc != nil {
+		return m.embeddingsFunc(call)
+	}
+	return m.embeddingsResponse, m.embeddingsError
 }
 
-// Vision returns the predetermined vision response.
-func (m *MockAgent) Vision(ctx context.Context, prompt string, images []string, opts ...map[string]any) (*response.ChatResponse, error) {
-	return m.visionResponse, m.visionError
+// GenerateImage records the call and returns the image generation response.
+func (m *MockAgent) GenerateImage(ctx context.Context, prompt string, opts ...map[string]any) (*response.ImageGenerationResponse, error) {
+	call := m.record(Call{
+		Method:   MethodGenerateImage,
+		Protocol: protocol.ImageGeneration,
+		Prompt:   prompt,
+		Options:  m.mergeOptions(protocol.ImageGeneration, false, opts...),
+	})
+
+	if m.imageFunc != nil {
+		return m.imageFunc(call)
+	}
+	return m.imageResponse, m.imageError
 }
 
-// VisionStream returns a channel with predetermined streaming chunks.
-func (m *MockAgent) VisionStream(ctx context.Context, prompt string, images []string, opts ...map[string]any) (<-chan *response.StreamingChunk, error) {
-	if m.streamError != nil {
-		return nil, m.streamError
+// Transcribe records the call and returns the transcription response.
+func (m *MockAgent) Transcribe(ctx context.Context, filename string, audio []byte, opts ...map[string]any) (*response.TranscriptionResponse, error) {
+	call := m.record(Call{
+		Method:   MethodTranscribe,
+		Protocol: protocol.Transcription,
+		Filename: filename,
+		Audio:    slices.Clone(audio),
+		Options:  m.mergeOptions(protocol.Transcription, false, opts...),
+	})
+
+	if m.transcriptionFunc != nil {
+		return m.transcriptionFunc(call)
 	}
+	return m.transcriptionResponse, m.transcriptionError
+}
 
-	ch := make(chan *response.StreamingChunk, len(m.streamChunks))
-	for i := range m.streamChunks {
-		ch <- &m.streamChunks[i]
+// Speak records the call and returns the speech response.
+func (m *MockAgent) Speak(ctx context.Context, text string, opts ...map[string]any) (*response.SpeechResponse, error) {
+	call := m.record(Call{
+		Method:   MethodSpeak,
+		Protocol: protocol.Speech,
+		Input:    text,
+		Options:  m.mergeOptions(protocol.Speech, false, opts...),
+	})
+
+	if m.speechFunc != nil {
+		return m.speechFunc(call)
 	}
-	close(ch)
+	return m.speechResponse, m.speechError
+}
 
-	return ch, nil
+// SpeakStream records the call and returns a channel with the streaming chunks.
+// Speech streams carry no stream option, matching the real agent.
+func (m *MockAgent) SpeakStream(ctx context.Context, text string, opts ...map[string]any) (<-chan *response.StreamingChunk, error) {
+	call := m.record(Call{
+		Method:   MethodSpeakStream,
+		Protocol: protocol.Speech,
+		Input:    text,
+		Options:  m.mergeOptions(protocol.Speech, false, opts...),
+	})
+
+	return m.stream(call)
 }
 
-// Tools returns the predetermined tools response.
-func (m *MockAgent) Tools(ctx context.Context, prompt string, tools []agent.Tool, opts ...map[string]any) (*response.ToolsResponse, error) {
-	return m.toolsResponse, m.toolsError
+// Rerank records the call and returns the rerank response.
+func (m *MockAgent) Rerank(ctx context.Context, query string, documents []string, opts ...map[string]any) (*response.RerankResponse, error) {
+	call := m.record(Call{
+		Method:    MethodRerank,
+		Protocol:  protocol.Rerank,
+		Prompt:    query,
+		Documents: slices.Clone(documents),
+		Options:   m.mergeOptions(protocol.Rerank, false, opts...),
+	})
+
+	if m.rerankFunc != nil {
+		return m.rerankFunc(call)
+	}
+	return m.rerankResponse, m.rerankError
 }
 
-// Embed returns the predetermined embeddings response.
-func (m *MockAgent) Embed(ctx context.Context, input string, opts ...map[string]any) (*response.EmbeddingsResponse, error) {
-	return m.embeddingsResponse, m.embeddingsError
+// Moderate records the call and returns the moderation response.
+func (m *MockAgent) Moderate(ctx context.Context, input string, opts ...map[string]any) (*response.ModerationResponse, error) {
+	call := m.record(Call{
+		Method:   MethodModerate,
+		Protocol: protocol.Moderation,
+		Input:    input,
+		Options:  m.mergeOptions(protocol.Moderation, false, opts...),
+	})
+
+	if m.moderationFunc != nil {
+		return m.moderationFunc(call)
+	}
+	return m.moderationResponse, m.moderationError
+}
+
+// stream returns a closed, buffered channel holding the chunks for call.
+func (m *MockAgent) stream(call Call) (<-chan *response.StreamingChunk, error) {
+	chunks, err := m.streamChunks, m.streamError
+	if m.streamFunc != nil {
+		chunks, err = m.streamFunc(call)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan *response.StreamingChunk, len(chunks))
+	for i := range chunks {
+		ch <- &chunks[i]
+	}
+	close(ch)
+
+	return ch, nil
+}
+
+// mergeOptions merges runtime options over the model's options for the
+// protocol, as the real agent does, and marks streaming calls.
+func (m *MockAgent) mergeOptions(proto protocol.Protocol, stream bool, opts ...map[string]any) map[string]any {
+	options := make(map[string]any)
+	if m.mockModel != nil {
+		maps.Copy(options, m.mockModel.Options[proto])
+	}
+	if len(opts) > 0 && opts[0] != nil {
+		maps.Copy(options, opts[0])
+	}
+	if stream {
+		options["stream"] = true
+	}
+	return options
 }
 
 // Verify MockAgent implements agent.Agent interface.
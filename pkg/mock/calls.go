@@ -0,0 +1,281 @@
+package mock
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/go-agents/pkg/agent"
+	"github.com/JaimeStill/go-agents/pkg/protocol"
+	"github.com/JaimeStill/go-agents/pkg/request"
+)
+
+// Method names recorded in Call.Method.
+const (
+	MethodChat          = "Chat"
+	MethodChatStream    = "ChatStream"
+	MethodVision        = "Vision"
+	MethodVisionStream  = "VisionStream"
+	MethodTools         = "Tools"
+	MethodEmbed         = "Embed"
+	MethodGenerateImage = "GenerateImage"
+	MethodTranscribe    = "Transcribe"
+	MethodSpeak         = "Speak"
+	MethodSpeakStream   = "SpeakStream"
+	MethodRerank        = "Rerank"
+	MethodModerate      = "Moderate"
+	MethodExecute       = "Execute"
+	MethodExecuteStream = "ExecuteStream"
+)
+
+// sequence orders calls across every mock in the process.
+var sequence atomic.Uint64
+
+// Call records a single invocation of a MockAgent or MockClient method.
+type Call struct {
+	// Seq orders calls across all mocks, including calls made from different
+	// goroutines. Later calls have larger values.
+	Seq uint64
+
+	// Time is when the call was received.
+	Time time.Time
+
+	// Method is the invoked method, such as MethodChat or MethodExecute.
+	Method string
+
+	// Protocol is the protocol of the call.
+	Protocol protocol.Protocol
+
+	// Prompt is the prompt passed to an agent method, including GenerateImage,
+	// or the query passed to Rerank.
+	Prompt string
+
+	// Images are the images passed to Vision and VisionStream.
+	Images []string
+
+	// Tools are the tool definitions passed to Tools.
+	Tools []agent.Tool
+
+	// Input is the text passed to Embed, Speak, SpeakStream, and Moderate.
+	Input string
+
+	// Filename and Audio are the recording passed to Transcribe.
+	Filename string
+	Audio    []byte
+
+	// Documents are the documents passed to Rerank.
+	Documents []string
+
+	// Options are the agent call's options merged over the model's
+	// configured options for the protocol, as a real agent merges them.
+	// Streaming calls include "stream": true.
+	Options map[string]any
+
+	// Request is the request passed to a client method.
+	Request request.Request
+
+	// Body is the marshaled request passed to a client method.
+	Body []byte
+}
+
+// Result is a response and error returned by one call of a sequence.
+type Result[T any] struct {
+	Response T
+	Err      error
+}
+
+// Sequence returns a response function that returns results in order, one
+// per call, repeating the last result once the sequence is exhausted.
+// Safe for concurrent use.
+func Sequence[T any](results ...Result[T]) func(Call) (T, error) {
+	var mutex sync.Mutex
+	next := 0
+
+	return func(Call) (T, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if len(results) == 0 {
+			var zero T
+			return zero, nil
+		}
+
+		r := results[min(next, len(results)-1)]
+		next++
+		return r.Response, r.Err
+	}
+}
+
+// Responses returns a response function that returns responses in order
+// without errors, repeating the last response once exhausted.
+func Responses[T any](responses ...T) func(Call) (T, error) {
+	results := make([]Result[T], len(responses))
+	for i, r := range responses {
+		results[i] = Result[T]{Response: r}
+	}
+	return Sequence(results...)
+}
+
+// Recorder captures calls made to a mock. It is embedded in MockAgent and
+// MockClient and is safe for concurrent use.
+type Recorder struct {
+	mutex sync.Mutex
+	calls []Call
+}
+
+// record stamps and stores a call and returns the stored copy.
+func (r *Recorder) record(call Call) Call {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	call.Seq = sequence.Add(1)
+	call.Time = time.Now()
+	r.calls = append(r.calls, call)
+	return call
+}
+
+// Calls returns every recorded call in order.
+func (r *Recorder) Calls() []Call {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return slices.Clone(r.calls)
+}
+
+// CallsTo returns the recorded calls to method in order.
+func (r *Recorder) CallsTo(method string) []Call {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var calls []Call
+	for _, c := range r.calls {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// CallCount returns the number of recorded calls to method.
+func (r *Recorder) CallCount(method string) int {
+	return len(r.CallsTo(method))
+}
+
+// LastCall returns the most recent call to method and whether one was recorded.
+func (r *Recorder) LastCall(method string) (Call, bool) {
+	calls := r.CallsTo(method)
+	if len(calls) == 0 {
+		return Call{}, false
+	}
+	return calls[len(calls)-1], true
+}
+
+// Reset discards all recorded calls.
+func (r *Recorder) Reset() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.calls = nil
+}
+
+// AssertCalled fails the test unless method was called, and returns the most recent call.
+func (r *Recorder) AssertCalled(t testing.TB, method string) Call {
+	t.Helper()
+
+	call, ok := r.LastCall(method)
+	if !ok {
+		t.Errorf("expected %s to be called", method)
+	}
+	return call
+}
+
+// AssertNotCalled fails the test if method was called.
+func (r *Recorder) AssertNotCalled(t testing.TB, method string) {
+	t.Helper()
+
+	if n := r.CallCount(method); n > 0 {
+		t.Errorf("expected %s not to be called, got %d calls", method, n)
+	}
+}
+
+// AssertCallCount fails the test unless method was called exactly n times.
+func (r *Recorder) AssertCallCount(t testing.TB, method string, n int) {
+	t.Helper()
+
+	if got := r.CallCount(method); got != n {
+		t.Errorf("got %d calls to %s, want %d", got, method, n)
+	}
+}
+
+// AssertCalledWith fails the test unless some call to method satisfies match.
+// Returns the first matching call.
+func (r *Recorder) AssertCalledWith(t testing.TB, method string, match func(Call) bool) Call {
+	t.Helper()
+
+	calls := r.CallsTo(method)
+	for _, c := range calls {
+		if match(c) {
+			return c
+		}
+	}
+	t.Errorf("no call to %s matched (%d calls)", method, len(calls))
+	return Call{}
+}
+
+// AssertOption fails the test unless the most recent call to method had
+// option key set to want.
+func (r *Recorder) AssertOption(t testing.TB, method, key string, want any) {
+	t.Helper()
+
+	call, ok := r.LastCall(method)
+	if !ok {
+		t.Errorf("expected %s to be called", method)
+		return
+	}
+
+	got, ok := call.Options[key]
+	if !ok {
+		t.Errorf("%s option %q not set, want %v", method, key, want)
+		return
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%s option %q = %v, want %v", method, key, got, want)
+	}
+}
+
+// CallSource is a mock that records calls, such as a MockAgent or MockClient.
+type CallSource interface {
+	Calls() []Call
+}
+
+// Timeline merges the calls recorded by several mocks into the order they
+// were received, for asserting ordering across agents and goroutines.
+func Timeline(sources ...CallSource) []Call {
+	var calls []Call
+	for _, s := range sources {
+		calls = append(calls, s.Calls()...)
+	}
+	slices.SortFunc(calls, func(a, b Call) int {
+		return cmp.Compare(a.Seq, b.Seq)
+	})
+	return calls
+}
+
+// String describes the call for test failure messages.
+func (c Call) String() string {
+	switch {
+	case c.Request != nil:
+		return fmt.Sprintf("#%d %s(%s)", c.Seq, c.Method, c.Protocol)
+	case c.Input != "":
+		return fmt.Sprintf("#%d %s(%q)", c.Seq, c.Method, c.Input)
+	case c.Filename != "":
+		return fmt.Sprintf("#%d %s(%q, %d bytes)", c.Seq, c.Method, c.Filename, len(c.Audio))
+	case c.Documents != nil:
+		return fmt.Sprintf("#%d %s(%q, %d documents)", c.Seq, c.Method, c.Prompt, len(c.Documents))
+	default:
+		return fmt.Sprintf("#%d %s(%q)", c.Seq, c.Method, c.Prompt)
+	}
+}
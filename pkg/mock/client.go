@@ -11,7 +11,11 @@ import (
 )
 
 // MockClient implements client.Client interface for testing.
+// Every Execute and ExecuteStream call is recorded by the embedded Recorder.
+// Safe for concurrent use.
 type MockClient struct {
+	Recorder
+
 	healthy bool
 
 	// Configurable responses
@@ -20,6 +24,10 @@ type MockClient struct {
 	streamChunks    []*response.StreamingChunk
 	streamError     error
 	httpClient      *http.Client
+
+	// Per-call response functions, which take precedence when set
+	executeFunc func(Call) (any, error)
+	streamFunc  func(Call) ([]*response.StreamingChunk, error)
 }
 
 // NewMockClient creates a new MockClient with default configuration.
@@ -57,6 +65,21 @@ func WithStreamResponse(chunks []*response.StreamingChunk, err error) MockClient
 	}
 }
 
+// WithExecuteFunc sets a function that computes the Execute response for each call.
+// Use Responses or Sequence to return a different response per call.
+func WithExecuteFunc(fn func(Call) (any, error)) MockClientOption {
+	return func(m *MockClient) {
+		m.executeFunc = fn
+	}
+}
+
+// WithExecuteStreamFunc sets a function that computes the ExecuteStream chunks for each call.
+func WithExecuteStreamFunc(fn func(Call) ([]*response.StreamingChunk, error)) MockClientOption {
+	return func(m *MockClient) {
+		m.streamFunc = fn
+	}
+}
+
 // WithHealthy sets the health status.
 func WithHealthy(healthy bool) MockClientOption {
 	return func(m *MockClient) {
@@ -76,19 +99,31 @@ func (m *MockClient) HTTPClient() *http.Client {
 	return m.httpClient
 }
 
-// Execute returns the predetermined response.
+// Execute records the call and returns the response.
 func (m *MockClient) Execute(ctx context.Context, req request.Request) (any, error) {
+	call := m.record(requestCall(MethodExecute, req))
+
+	if m.executeFunc != nil {
+		return m.executeFunc(call)
+	}
 	return m.executeResponse, m.executeError
 }
 
-// ExecuteStream returns a channel with predetermined chunks.
+// ExecuteStream records the call and returns a channel with the chunks.
 func (m *MockClient) ExecuteStream(ctx context.Context, req request.Request) (<-chan *response.StreamingChunk, error) {
-	if m.streamError != nil {
-		return nil, m.streamError
+	call := m.record(requestCall(MethodExecuteStream, req))
+
+	chunks, err := m.streamChunks, m.streamError
+	if m.streamFunc != nil {
+		chunks, err = m.streamFunc(call)
 	}
 
-	ch := make(chan *response.StreamingChunk, len(m.streamChunks))
-	for _, chunk := range m.streamChunks {
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan *response.StreamingChunk, len(chunks))
+	for _, chunk := range chunks {
 		ch <- chunk
 	}
 	close(ch)
@@ -96,6 +131,16 @@ func (m *MockClient) ExecuteStream(ctx context.Context, req request.Request) (<-
 	return ch, nil
 }
 
+// requestCall describes a client call for recording.
+func requestCall(method string, req request.Request) Call {
+	call := Call{Method: method, Request: req}
+	if req != nil {
+		call.Protocol = req.Protocol()
+		call.Body, _ = req.Marshal()
+	}
+	return call
+}
+
 // IsHealthy returns the mock health status.
 func (m *MockClient) IsHealthy() bool {
 	return m.healthy
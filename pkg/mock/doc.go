@@ -12,6 +12,9 @@
 //
 // MockProvider: Implements providers.Provider interface with endpoint mapping
 //
+// The server subpackage provides a fake OpenAI-compatible HTTP server for
+// tests that should exercise the real providers and client.
+//
 // # Usage Example
 //
 //	// Create a mock agent with predetermined chat response
@@ -43,4 +46,33 @@
 //	for chunk := range chunks {
 //	    // Process test chunks
 //	}
+//
+// # Call Recording
+//
+// MockAgent and MockClient record every call, including the prompt, images,
+// tools, and options merged over the model's defaults, with a timestamp and
+// a sequence number ordering calls across mocks and goroutines:
+//
+//	call := mockAgent.AssertCalled(t, mock.MethodVision)
+//	mockAgent.AssertOption(t, mock.MethodVision, "temperature", 0.2)
+//	mockAgent.AssertCallCount(t, mock.MethodChat, 3)
+//
+//	for _, call := range mock.Timeline(classifier, summarizer) {
+//	    t.Log(call)
+//	}
+//
+// # Sequenced and Computed Responses
+//
+// Response functions return a different response per call or compute one
+// from the call's input:
+//
+//	mockAgent := mock.NewMockAgent(
+//	    mock.WithChatFunc(mock.Responses(first, second)),
+//	    mock.WithEmbeddingsFunc(func(c mock.Call) (*response.EmbeddingsResponse, error) {
+//	        return embed(c.Input), nil
+//	    }),
+//	)
+//
+// Sequence accepts Result values to interleave errors. Mocks are safe for
+// concurrent use.
 package mock
@@ -1,6 +1,10 @@
 package mock
 
 import (
+	"cmp"
+	"slices"
+	"strings"
+
 	"github.com/JaimeStill/go-agents/pkg/protocol"
 	"github.com/JaimeStill/go-agents/pkg/response"
 )
@@ -11,15 +15,7 @@ func NewSimpleChatAgent(id string, content string) *MockAgent {
 	chatResponse := &response.ChatResponse{
 		Model: "mock-model",
 	}
-	chatResponse.Choices = append(chatResponse.Choices, struct {
-		Index   int              `json:"index"`
-		Message protocol.Message `json:"message"`
-		Delta   *struct {
-			Role    string `json:"role,omitempty"`
-			Content string `json:"content,omitempty"`
-		} `json:"delta,omitempty"`
-		FinishReason string `json:"finish_reason,omitempty"`
-	}{
+	chatResponse.Choices = append(chatResponse.Choices, response.ChatChoice{
 		Index:   0,
 		Message: protocol.NewMessage("assistant", content),
 	})
@@ -38,19 +34,9 @@ func NewStreamingChatAgent(id string, chunks []string) *MockAgent {
 		chunk := response.StreamingChunk{
 			Model: "mock-model",
 		}
-		chunk.Choices = append(chunk.Choices, struct {
-			Index int `json:"index"`
-			Delta struct {
-				Role    string `json:"role,omitempty"`
-				Content string `json:"content,omitempty"`
-			} `json:"delta"`
-			FinishReason *string `json:"finish_reason"`
-		}{
+		chunk.Choices = append(chunk.Choices, response.StreamingChoice{
 			Index: 0,
-			Delta: struct {
-				Role    string `json:"role,omitempty"`
-				Content string `json:"content,omitempty"`
-			}{
+			Delta: response.StreamingDelta{
 				Content: content,
 			},
 		})
@@ -69,25 +55,9 @@ func NewToolsAgent(id string, toolCalls []response.ToolCall) *MockAgent {
 	toolsResponse := &response.ToolsResponse{
 		Model: "mock-model",
 	}
-	toolsResponse.Choices = append(toolsResponse.Choices, struct {
-		Index   int `json:"index"`
-		Message struct {
-			Role      string              `json:"role"`
-			Content   string              `json:"content"`
-			ToolCalls []response.ToolCall `json:"tool_calls,omitempty"`
-		} `json:"message"`
-		FinishReason string `json:"finish_reason,omitempty"`
-	}{
-		Index: 0,
-		Message: struct {
-			Role      string              `json:"role"`
-			Content   string              `json:"content"`
-			ToolCalls []response.ToolCall `json:"tool_calls,omitempty"`
-		}{
-			Role:      "assistant",
-			Content:   "",
-			ToolCalls: toolCalls,
-		},
+	toolsResponse.Choices = append(toolsResponse.Choices, response.ToolsChoice{
+		Index:   0,
+		Message: protocol.NewToolCallMessage(toolCalls...),
 	})
 
 	return NewMockAgent(
@@ -118,21 +88,101 @@ func NewEmbeddingsAgent(id string, embedding []float64) *MockAgent {
 	)
 }
 
+// NewImageAgent creates a MockAgent configured for image generation.
+// Returns one image per URL in the GenerateImage response.
+func NewImageAgent(id string, urls ...string) *MockAgent {
+	imageResponse := &response.ImageGenerationResponse{}
+	for _, url := range urls {
+		imageResponse.Data = append(imageResponse.Data, response.GeneratedImage{URL: url})
+	}
+
+	return NewMockAgent(
+		WithID(id),
+		WithImageResponse(imageResponse, nil),
+	)
+}
+
+// NewAudioAgent creates a MockAgent configured for audio protocols.
+// Transcribe returns transcript, and Speak and SpeakStream return audio.
+func NewAudioAgent(id, transcript string, audio []byte) *MockAgent {
+	return NewMockAgent(
+		WithID(id),
+		WithTranscriptionResponse(&response.TranscriptionResponse{Text: transcript}, nil),
+		WithSpeechResponse(&response.SpeechResponse{ContentType: "audio/mpeg", Audio: audio}, nil),
+		WithStreamChunks([]response.StreamingChunk{{Audio: audio}}, nil),
+	)
+}
+
+// NewRerankAgent creates a MockAgent configured for the rerank protocol.
+// Each document passed to Rerank is scored by its position in scores
+// (documents beyond the scores get zero), and results are returned in
+// descending score order, honoring "top_n" and "return_documents".
+func NewRerankAgent(id string, scores ...float64) *MockAgent {
+	return NewMockAgent(
+		WithID(id),
+		WithRerankFunc(func(call Call) (*response.RerankResponse, error) {
+			results := make([]response.RerankResult, len(call.Documents))
+			for i := range call.Documents {
+				results[i] = response.RerankResult{Index: i}
+				if i < len(scores) {
+					results[i].RelevanceScore = scores[i]
+				}
+				if call.Options["return_documents"] == true {
+					results[i].Document = call.Documents[i]
+				}
+			}
+
+			slices.SortStableFunc(results, func(a, b response.RerankResult) int {
+				return cmp.Compare(b.RelevanceScore, a.RelevanceScore)
+			})
+
+			var n int
+			switch v := call.Options["top_n"].(type) {
+			case int:
+				n = v
+			case float64:
+				n = int(v)
+			}
+			if n > 0 && n < len(results) {
+				results = results[:n]
+			}
+
+			return &response.RerankResponse{Model: "mock-model", Results: results}, nil
+		}),
+	)
+}
+
+// NewModerationAgent creates a MockAgent configured for the moderation protocol.
+// Input is flagged in each category whose terms it contains, ignoring case.
+func NewModerationAgent(id string, categories map[string][]string) *MockAgent {
+	return NewMockAgent(
+		WithID(id),
+		WithModerationFunc(func(call Call) (*response.ModerationResponse, error) {
+			input := strings.ToLower(call.Input)
+			result := response.ModerationResult{Categories: make(map[string]bool)}
+			for category, terms := range categories {
+				matched := slices.ContainsFunc(terms, func(term string) bool {
+					return strings.Contains(input, strings.ToLower(term))
+				})
+				result.Categories[category] = matched
+				result.Flagged = result.Flagged || matched
+			}
+
+			return &response.ModerationResponse{
+				Model:   "mock-model",
+				Results: []response.ModerationResult{result},
+			}, nil
+		}),
+	)
+}
+
 // NewMultiProtocolAgent creates a MockAgent configured for multiple protocols.
 // Useful for testing agents that handle different protocol types.
 func NewMultiProtocolAgent(id string) *MockAgent {
 	chatResponse := &response.ChatResponse{
 		Model: "mock-model",
 	}
-	chatResponse.Choices = append(chatResponse.Choices, struct {
-		Index   int              `json:"index"`
-		Message protocol.Message `json:"message"`
-		Delta   *struct {
-			Role    string `json:"role,omitempty"`
-			Content string `json:"content,omitempty"`
-		} `json:"delta,omitempty"`
-		FinishReason string `json:"finish_reason,omitempty"`
-	}{
+	chatResponse.Choices = append(chatResponse.Choices, response.ChatChoice{
 		Index:   0,
 		Message: protocol.NewMessage("assistant", "Mock chat response"),
 	})
@@ -140,25 +190,9 @@ func NewMultiProtocolAgent(id string) *MockAgent {
 	toolsResponse := &response.ToolsResponse{
 		Model: "mock-model",
 	}
-	toolsResponse.Choices = append(toolsResponse.Choices, struct {
-		Index   int `json:"index"`
-		Message struct {
-			Role      string              `json:"role"`
-			Content   string              `json:"content"`
-			ToolCalls []response.ToolCall `json:"tool_calls,omitempty"`
-		} `json:"message"`
-		FinishReason string `json:"finish_reason,omitempty"`
-	}{
-		Index: 0,
-		Message: struct {
-			Role      string              `json:"role"`
-			Content   string              `json:"content"`
-			ToolCalls []response.ToolCall `json:"tool_calls,omitempty"`
-		}{
-			Role:      "assistant",
-			Content:   "",
-			ToolCalls: []response.ToolCall{},
-		},
+	toolsResponse.Choices = append(toolsResponse.Choices, response.ToolsChoice{
+		Index:   0,
+		Message: protocol.NewToolCallMessage(),
 	})
 
 	embeddingsResponse := &response.EmbeddingsResponse{
@@ -192,6 +226,11 @@ func NewFailingAgent(id string, err error) *MockAgent {
 		WithVisionResponse(nil, err),
 		WithToolsResponse(nil, err),
 		WithEmbeddingsResponse(nil, err),
+		WithImageResponse(nil, err),
+		WithTranscriptionResponse(nil, err),
+		WithSpeechResponse(nil, err),
+		WithRerankResponse(nil, err),
+		WithModerationResponse(nil, err),
 		WithStreamChunks(nil, err),
 	)
 }
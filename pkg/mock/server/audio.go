@@ -0,0 +1,247 @@
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// SpeechSampleRate is the sample rate of the WAV audio returned for speech requests.
+const SpeechSampleRate = 8000
+
+// wordDuration is the length of speech synthesized, or assumed transcribed, per word.
+const wordDuration = 0.25
+
+// speechChunkSize is the size of the chunks speech audio is written in.
+const speechChunkSize = 4096
+
+// Speech returns deterministic 16-bit mono WAV audio for text: a tone whose
+// pitch is derived from text, lasting a quarter second per word.
+func Speech(text string) []byte {
+	sum := sha256.Sum256([]byte(text))
+	frequency := 220 + float64(binary.BigEndian.Uint16(sum[:2])%660)
+
+	words := max(countWords(text), 1)
+	samples := int(float64(words) * wordDuration * SpeechSampleRate)
+
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(36+samples*2))
+	buf.WriteString("WAVEfmt ")
+	for _, v := range []any{
+		uint32(16), uint16(1), uint16(1),
+		uint32(SpeechSampleRate), uint32(SpeechSampleRate * 2),
+		uint16(2), uint16(16),
+	} {
+		binary.Write(&buf, binary.LittleEndian, v)
+	}
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, uint32(samples*2))
+
+	for i := range samples {
+		v := math.Sin(2 * math.Pi * frequency * float64(i) / SpeechSampleRate)
+		binary.Write(&buf, binary.LittleEndian, int16(v*8000))
+	}
+
+	return buf.Bytes()
+}
+
+// parseMultipart decodes a multipart/form-data upload into a Request.
+// Form fields are stored in Body, with repeated "[]" fields collected as lists.
+func parseMultipart(endpoint Endpoint, r *http.Request, boundary string) (*Request, error) {
+	req := &Request{
+		Endpoint:   endpoint,
+		Path:       r.URL.Path,
+		Deployment: r.PathValue("deployment"),
+		Body:       map[string]any{},
+		Header:     r.Header.Clone(),
+	}
+
+	reader := multipart.NewReader(r.Body, boundary)
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid multipart body: %w", err)
+		}
+
+		data, err := io.ReadAll(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid multipart body: %w", err)
+		}
+
+		name := part.FormName()
+		switch {
+		case part.FileName() != "":
+			req.Filename = part.FileName()
+			req.Audio = data
+		case strings.HasSuffix(name, "[]"):
+			name = strings.TrimSuffix(name, "[]")
+			list, _ := req.Body[name].([]any)
+			req.Body[name] = append(list, string(data))
+		default:
+			req.Body[name] = string(data)
+		}
+	}
+
+	if len(req.Audio) == 0 {
+		return nil, fmt.Errorf("file is required")
+	}
+
+	req.Model, _ = req.Body["model"].(string)
+	req.Prompt, _ = req.Body["prompt"].(string)
+	if req.Model == "" {
+		req.Model = req.Deployment
+	}
+
+	return req, nil
+}
+
+// multipartBoundary returns the boundary of a multipart/form-data request.
+func multipartBoundary(r *http.Request) (string, bool) {
+	mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/form-data" {
+		return "", false
+	}
+	return params["boundary"], true
+}
+
+// writeTranscription writes the reply content as a transcript in the requested
+// response format, with segment and word timestamps for verbose_json.
+func (s *Server) writeTranscription(w http.ResponseWriter, req *Request, reply Reply) {
+	text := reply.Content
+	words := strings.Fields(text)
+	segments := transcriptSegments(words)
+
+	format, _ := req.Body["response_format"].(string)
+	switch format {
+	case "text":
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprintln(w, text)
+		return
+	case "srt", "vtt":
+		w.Header().Set("Content-Type", "text/plain")
+		if format == "vtt" {
+			fmt.Fprint(w, "WEBVTT\n\n")
+		}
+		for i, seg := range segments {
+			if format == "srt" {
+				fmt.Fprintf(w, "%d\n", i+1)
+			}
+			fmt.Fprintf(w, "%s --> %s\n%s\n\n", timestamp(seg["start"].(float64), format), timestamp(seg["end"].(float64), format), seg["text"])
+		}
+		return
+	}
+
+	result := map[string]any{"text": text}
+
+	if format == "verbose_json" {
+		language, _ := req.Body["language"].(string)
+		if language == "" {
+			language = "english"
+		}
+		result["task"] = "transcribe"
+		result["language"] = language
+		result["duration"] = float64(len(words)) * wordDuration
+
+		granularities, _ := req.Body["timestamp_granularities"].([]any)
+		if len(granularities) == 0 || slices.Contains(granularities, any("segment")) {
+			result["segments"] = segments
+		}
+		if slices.Contains(granularities, any("word")) {
+			timed := make([]map[string]any, len(words))
+			for i, word := range words {
+				timed[i] = map[string]any{
+					"word":  strings.Trim(word, ".,!?"),
+					"start": float64(i) * wordDuration,
+					"end":   float64(i+1) * wordDuration,
+				}
+			}
+			result["words"] = timed
+		}
+	} else {
+		result["usage"] = map[string]any{
+			"type":          "tokens",
+			"input_tokens":  len(req.Audio) / 1000,
+			"output_tokens": len(words),
+			"total_tokens":  len(req.Audio)/1000 + len(words),
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// transcriptSegments splits words into sentence segments timed at a quarter second per word.
+func transcriptSegments(words []string) []map[string]any {
+	var segments []map[string]any
+	start := 0
+	for i, word := range words {
+		if i < len(words)-1 && !strings.ContainsAny(word[len(word)-1:], ".!?") {
+			continue
+		}
+		segments = append(segments, map[string]any{
+			"id":    len(segments),
+			"start": float64(start) * wordDuration,
+			"end":   float64(i+1) * wordDuration,
+			"text":  strings.Join(words[start:i+1], " "),
+		})
+		start = i + 1
+	}
+	return segments
+}
+
+// timestamp formats seconds as an SRT (00:00:01,250) or VTT (00:00:01.250) timestamp.
+func timestamp(seconds float64, format string) string {
+	d := time.Duration(seconds * float64(time.Second))
+	sep := ","
+	if format == "vtt" {
+		sep = "."
+	}
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60, sep, d.Milliseconds()%1000)
+}
+
+// writeSpeech writes synthesized WAV audio in chunks, flushing each chunk so
+// clients can stream it. The reply's Audio replaces the synthesized audio.
+func (s *Server) writeSpeech(w http.ResponseWriter, r *http.Request, req *Request, reply Reply) {
+	audio := reply.Audio
+	if audio == nil {
+		audio = Speech(strings.Join(req.Input, " "))
+	}
+
+	delay := reply.ChunkDelay
+	if delay == 0 {
+		delay = s.chunkDelay
+	}
+
+	w.Header().Set("Content-Type", "audio/wav")
+	flusher, _ := w.(http.Flusher)
+
+	for i := 0; len(audio) > 0; i++ {
+		if f := reply.Fault; f != nil && f.Truncate && i >= f.TruncateAfter {
+			panic(http.ErrAbortHandler)
+		}
+		if i > 0 && !sleep(r.Context(), delay) {
+			return
+		}
+
+		n := min(speechChunkSize, len(audio))
+		w.Write(audio[:n])
+		audio = audio[n:]
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// Package server provides a fake OpenAI-compatible LLM server for tests and demos.
+//
+// Unlike the mocks in package mock, which replace interfaces, the server
+// answers real HTTP requests, so agents exercise the real providers, client,
+// retries, and response parsing. It is built on httptest and serves:
+//
+//	POST /v1/chat/completions                               (Ollama)
+//	POST /v1/embeddings                                     (Ollama)
+//	POST /v1/images/generations                             (Ollama)
+//	POST /v1/audio/transcriptions                           (Ollama)
+//	POST /v1/audio/speech                                   (Ollama)
+//	POST /v1/rerank                                         (Ollama, Cohere-style rerank)
+//	POST /rerank                                            (Ollama, TEI-style rerank)
+//	POST /v1/moderations                                    (Ollama)
+//	POST /openai/deployments/{deployment}/chat/completions  (Azure)
+//	POST /openai/deployments/{deployment}/embeddings        (Azure)
+//	POST /openai/deployments/{deployment}/images/generations (Azure)
+//	POST /openai/deployments/{deployment}/audio/transcriptions (Azure)
+//	POST /openai/deployments/{deployment}/audio/speech      (Azure)
+//	GET  /images/{name}                                     (generated images)
+//
+// # Usage
+//
+//	s := server.New(
+//	    server.WithRule(server.PromptContains("weather"), server.Reply{
+//	        ToolCalls: []server.ToolCall{{Name: "get_weather", Arguments: `{"location":"Boston"}`}},
+//	    }),
+//	    server.WithReply(server.Reply{Content: "You said: {{.Prompt}}"}),
+//	)
+//	defer s.Close()
+//
+//	cfg.Provider = s.OllamaProvider() // or s.AzureProvider("gpt-4o")
+//	a, err := agent.New(cfg)
+//
+// # Replies
+//
+// Each request is answered by the first matching rule, then the responder
+// set with WithResponder, then the default reply. Reply content containing
+// "{{" is rendered as a text/template with the Request. Streaming requests
+// receive the content as server-sent events split into Chunks, with an
+// optional delay before each chunk. Embeddings are deterministic
+// pseudo-random unit vectors derived from the input (see Embedding).
+// Generated images are solid-color PNGs derived from the prompt (see Image),
+// returned as base64 data or as URLs the server itself serves. Transcription
+// uploads are answered with the reply content as the transcript, timed at a
+// quarter second per word, and speech requests with a deterministic WAV tone
+// (see Speech) written in flushed chunks. Rerank requests score each document
+// by the fraction of query terms it contains (see RelevanceScore), and
+// moderation requests flag the reply's Flagged categories. Azure chat
+// completions carry content filter results, filtering the Flagged categories.
+// Chat requests with the "n" option receive one choice per candidate of
+// Content and Alternatives, and requests with "logprobs" receive token log
+// probabilities at the reply's TokenProbability. A reply's Reasoning is
+// returned as reasoning content, streamed ahead of the content, and counted
+// as reasoning tokens.
+//
+// # Fault Injection
+//
+// A Fault on a reply, or queued for upcoming requests with FailNext, returns
+// an error status (optionally with Retry-After), delays the first byte, or
+// drops the connection partway through a response:
+//
+//	s.FailNext(2, server.Fault{Status: 429, RetryAfter: time.Second})
+//	s.FailNext(1, server.Fault{Truncate: true, TruncateAfter: 3})
+//
+// Requests returns every request received, for assertions.
+package server
@@ -0,0 +1,34 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+	"math/rand/v2"
+)
+
+// DefaultDimensions is the length of embeddings generated by the server.
+const DefaultDimensions = 16
+
+// Embedding returns a deterministic pseudo-embedding of text with the given
+// number of dimensions. The vector is unit length and depends only on text
+// and dimensions, so identical inputs always produce identical vectors.
+func Embedding(text string, dimensions int) []float64 {
+	sum := sha256.Sum256([]byte(text))
+	rng := rand.New(rand.NewPCG(binary.LittleEndian.Uint64(sum[:8]), binary.LittleEndian.Uint64(sum[8:16])))
+
+	vector := make([]float64, dimensions)
+	var norm float64
+	for i := range vector {
+		vector[i] = rng.NormFloat64()
+		norm += vector[i] * vector[i]
+	}
+
+	norm = math.Sqrt(norm)
+	if norm > 0 {
+		for i := range vector {
+			vector[i] /= norm
+		}
+	}
+	return vector
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// DefaultImageSize is the size of generated images when a request has none.
+const DefaultImageSize = "256x256"
+
+// Image returns a deterministic PNG of the given dimensions whose color is
+// derived from prompt, so identical prompts always produce identical images.
+func Image(prompt string, width, height int) []byte {
+	sum := sha256.Sum256([]byte(prompt))
+	fill := color.RGBA{R: sum[0], G: sum[1], B: sum[2], A: 255}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		for x := range width {
+			img.Set(x, y, fill)
+		}
+	}
+
+	var buf bytes.Buffer
+	png.Encode(&buf, img)
+	return buf.Bytes()
+}
+
+// imageSeed derives the seed of the i-th image generated for prompt.
+func imageSeed(prompt string, i int) string {
+	sum := sha256.Sum256(fmt.Appendf(nil, "%s\x00%d", prompt, i))
+	return fmt.Sprintf("%x", sum[:8])
+}
+
+// parseSize parses a "WIDTHxHEIGHT" image size, using DefaultImageSize when empty.
+func parseSize(size string) (int, int, error) {
+	if size == "" || size == "auto" {
+		size = DefaultImageSize
+	}
+
+	w, h, ok := strings.Cut(size, "x")
+	width, werr := strconv.Atoi(w)
+	height, herr := strconv.Atoi(h)
+	if !ok || werr != nil || herr != nil || width < 1 || height < 1 || width > 4096 || height > 4096 {
+		return 0, 0, fmt.Errorf("invalid size %q", size)
+	}
+	return width, height, nil
+}
+
+// serveImage serves an image previously returned by URL. The path encodes
+// the prompt hash, index, and size, so no state is kept between requests.
+func (s *Server) serveImage(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimSuffix(r.PathValue("name"), ".png")
+
+	seed, size, ok := strings.Cut(name, "_")
+	width, height, err := parseSize(size)
+	if !ok || err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Write(Image(seed, width, height))
+}
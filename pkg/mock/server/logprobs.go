@@ -0,0 +1,89 @@
+package server
+
+import (
+	"math"
+	"slices"
+	"strings"
+)
+
+// SystemFingerprint is the system_fingerprint reported on chat completions.
+const SystemFingerprint = "fp_mock"
+
+// defaultTokenProbability is the probability of each sampled token when the
+// reply does not set TokenProbability.
+const defaultTokenProbability = 0.9
+
+// candidates returns the reply's Content followed by its Alternatives.
+func candidates(reply Reply) []string {
+	return append([]string{reply.Content}, reply.Alternatives...)
+}
+
+// choiceCount returns the number of choices requested with the "n" option.
+func choiceCount(req *Request) int {
+	if n, ok := req.Body["n"].(float64); ok && n > 1 {
+		return int(n)
+	}
+	return 1
+}
+
+// logprobsRequested reports whether the request set the "logprobs" option,
+// and how many top alternatives it asked for.
+func logprobsRequested(req *Request) (bool, int) {
+	enabled, _ := req.Body["logprobs"].(bool)
+	top, _ := req.Body["top_logprobs"].(float64)
+	return enabled, int(top)
+}
+
+// tokenize splits content into tokens after each space, as streams are chunked.
+func tokenize(content string) []string {
+	if content == "" {
+		return nil
+	}
+	return strings.SplitAfter(content, " ")
+}
+
+// logprobs builds the logprobs of tokens. Each token has probability p; the
+// tokens of the other candidates at the same position share the remaining
+// probability as top alternatives.
+func logprobs(tokens []string, others []string, p float64, top int) map[string]any {
+	content := make([]map[string]any, len(tokens))
+	for i, token := range tokens {
+		var alternatives []string
+		for _, other := range others {
+			if t := tokenize(other); i < len(t) && t[i] != token && !slices.Contains(alternatives, t[i]) {
+				alternatives = append(alternatives, t[i])
+			}
+		}
+
+		entries := []map[string]any{{"token": token, "logprob": math.Log(p), "bytes": tokenBytes(token)}}
+		for _, alt := range alternatives {
+			share := (1 - p) / float64(len(alternatives))
+			entries = append(entries, map[string]any{"token": alt, "logprob": math.Log(share), "bytes": tokenBytes(alt)})
+		}
+
+		content[i] = map[string]any{
+			"token":        token,
+			"logprob":      math.Log(p),
+			"bytes":        tokenBytes(token),
+			"top_logprobs": entries[:min(top, len(entries))],
+		}
+	}
+	return map[string]any{"content": content}
+}
+
+// tokenBytes returns the UTF-8 bytes of a token as integers.
+func tokenBytes(token string) []int {
+	data := make([]int, len(token))
+	for i := range len(token) {
+		data[i] = int(token[i])
+	}
+	return data
+}
+
+// tokenProbability returns the reply's TokenProbability or the default.
+func tokenProbability(reply Reply) float64 {
+	if reply.TokenProbability > 0 && reply.TokenProbability <= 1 {
+		return reply.TokenProbability
+	}
+	return defaultTokenProbability
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+)
+
+// ModerationCategories are the categories reported for moderation requests
+// and Azure content filter results.
+var ModerationCategories = []string{"harassment", "hate", "self_harm", "sexual", "violence"}
+
+// writeModeration writes one moderation result per input. Inputs are flagged
+// in the reply's Flagged categories and pass every other category.
+func (s *Server) writeModeration(w http.ResponseWriter, req *Request, reply Reply) {
+	results := make([]map[string]any, len(req.Input))
+	for i := range req.Input {
+		categories := make(map[string]bool)
+		scores := make(map[string]float64)
+		for _, category := range categoryNames(reply) {
+			flagged := slices.Contains(reply.Flagged, category)
+			categories[category] = flagged
+			scores[category] = 0.01
+			if flagged {
+				scores[category] = 0.95
+			}
+		}
+		results[i] = map[string]any{
+			"flagged":         len(reply.Flagged) > 0,
+			"categories":      categories,
+			"category_scores": scores,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"id":      fmt.Sprintf("modr-%d", len(s.Requests())),
+		"model":   req.Model,
+		"results": results,
+	})
+}
+
+// contentFilter returns Azure content_filter_results for a reply: the reply's
+// Flagged categories are filtered at high severity and the rest are safe.
+func contentFilter(reply Reply) map[string]any {
+	results := make(map[string]any)
+	for _, category := range categoryNames(reply) {
+		severity := "safe"
+		flagged := slices.Contains(reply.Flagged, category)
+		if flagged {
+			severity = "high"
+		}
+		results[category] = map[string]any{"filtered": flagged, "severity": severity}
+	}
+	return results
+}
+
+// categoryNames returns the standard categories followed by any other
+// categories flagged by the reply.
+func categoryNames(reply Reply) []string {
+	names := slices.Clone(ModerationCategories)
+	for _, category := range reply.Flagged {
+		if !slices.Contains(names, category) {
+			names = append(names, category)
+		}
+	}
+	return names
+}
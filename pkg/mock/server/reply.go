@@ -0,0 +1,223 @@
+package server
+
+import (
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Endpoint identifies the API a request was sent to.
+type Endpoint string
+
+// Endpoints served by the fake server.
+const (
+	EndpointChat       Endpoint = "chat"
+	EndpointEmbeddings Endpoint = "embeddings"
+	EndpointImages     Endpoint = "images"
+	EndpointTranscribe Endpoint = "transcriptions"
+	EndpointSpeech     Endpoint = "speech"
+	EndpointRerank     Endpoint = "rerank"
+	EndpointModeration Endpoint = "moderation"
+)
+
+// Message is a chat message received by the server, with its text content
+// extracted from multimodal content parts.
+type Message struct {
+	Role    string
+	Content string
+
+	// ToolCallID is the call a tool message responds to.
+	ToolCallID string
+}
+
+// Request describes a request received by the server. Matchers, responders,
+// and reply templates inspect it to choose a response.
+type Request struct {
+	// Endpoint is the API the request was sent to.
+	Endpoint Endpoint
+
+	// Path is the request path.
+	Path string
+
+	// Deployment is the Azure deployment name, or empty for /v1 paths.
+	Deployment string
+
+	// Model is the requested model, or the deployment name for Azure requests without one.
+	Model string
+
+	// Messages are the chat messages of a chat request.
+	Messages []Message
+
+	// Prompt is the content of the last user message, the prompt of an
+	// image generation or transcription request, the query of a rerank
+	// request, or the newline-joined input of a moderation request.
+	Prompt string
+
+	// System is the content of the first system or developer message.
+	System string
+
+	// Tools are the names of the functions offered to the model.
+	Tools []string
+
+	// Input is the text of an embeddings, speech, or moderation request, or
+	// the documents of a rerank request.
+	Input []string
+
+	// Filename and Audio are the file uploaded with a transcription request.
+	Filename string
+	Audio    []byte
+
+	// Stream reports whether a streaming response was requested.
+	Stream bool
+
+	// Body is the decoded JSON request body, or the form fields of a multipart upload.
+	Body map[string]any
+
+	// Header is the request header.
+	Header http.Header
+}
+
+// ToolCall is a function call returned in a reply.
+type ToolCall struct {
+	// Name is the function name.
+	Name string
+
+	// Arguments is the JSON-encoded function arguments.
+	Arguments string
+}
+
+// Fault injects a failure into a response.
+type Fault struct {
+	// Status returns an error response with this HTTP status, such as 429 or 503.
+	Status int
+
+	// RetryAfter sets the Retry-After header on an error response, rounded up to whole seconds.
+	RetryAfter time.Duration
+
+	// Message is the error message. Defaults to the status text.
+	Message string
+
+	// FirstByteDelay delays the response headers.
+	FirstByteDelay time.Duration
+
+	// Truncate drops the connection after TruncateAfter stream chunks
+	// without completing the stream. Non-streaming responses are cut off
+	// partway through the body.
+	Truncate      bool
+	TruncateAfter int
+}
+
+// Reply scripts a response.
+type Reply struct {
+	// Content is the assistant message. Content containing "{{" is executed as
+	// a text/template with the Request, e.g. "You said: {{.Prompt}}".
+	Content string
+
+	// ToolCalls are function calls returned instead of, or with, Content.
+	ToolCalls []ToolCall
+
+	// Reasoning is returned as the message's reasoning_content and streamed
+	// in reasoning deltas before the content. Its words are reported as
+	// reasoning tokens in the completion usage.
+	Reasoning string
+
+	// Alternatives are further candidate contents. Requests with the "n"
+	// option receive Content and then each alternative as successive choices,
+	// cycling when n exceeds the candidates, and logprobs list the tokens of
+	// the other candidates as top alternatives. Alternatives are not rendered
+	// as templates.
+	Alternatives []string
+
+	// TokenProbability is the probability reported for each sampled token
+	// when logprobs are requested. Defaults to 0.9.
+	TokenProbability float64
+
+	// Chunks are the streamed content deltas. Defaults to Content split after each space.
+	Chunks []string
+
+	// ChunkDelay is the delay before each streamed chunk. Defaults to the server's chunk delay.
+	ChunkDelay time.Duration
+
+	// FinishReason defaults to "tool_calls" when ToolCalls are set and "stop" otherwise.
+	FinishReason string
+
+	// Embedding is returned for every input of an embeddings request.
+	// Defaults to the deterministic Embedding of each input.
+	Embedding []float64
+
+	// Audio is returned for speech requests.
+	// Defaults to the deterministic Speech of the input.
+	Audio []byte
+
+	// Scores are the relevance scores of a rerank request's documents, in
+	// document order. Defaults to the RelevanceScore of each document.
+	Scores []float64
+
+	// Flagged are the categories a moderation request's inputs are flagged in.
+	// Azure chat completions report them as filtered content_filter_results.
+	Flagged []string
+
+	// Fault injects a failure instead of, or into, the response.
+	Fault *Fault
+}
+
+// Matcher reports whether a rule applies to a request.
+type Matcher func(*Request) bool
+
+// Responder builds a reply for a request.
+type Responder func(*Request) Reply
+
+// PromptContains matches requests whose prompt contains substr, ignoring case.
+func PromptContains(substr string) Matcher {
+	substr = strings.ToLower(substr)
+	return func(r *Request) bool {
+		return strings.Contains(strings.ToLower(r.Prompt), substr)
+	}
+}
+
+// PromptMatches matches requests whose prompt matches the regular expression.
+// Panics if pattern does not compile.
+func PromptMatches(pattern string) Matcher {
+	re := regexp.MustCompile(pattern)
+	return func(r *Request) bool {
+		return re.MatchString(r.Prompt)
+	}
+}
+
+// ModelIs matches requests for the named model or deployment.
+func ModelIs(name string) Matcher {
+	return func(r *Request) bool {
+		return r.Model == name || r.Deployment == name
+	}
+}
+
+// HasTool matches requests offering the named function, or any function when name is empty.
+func HasTool(name string) Matcher {
+	return func(r *Request) bool {
+		if name == "" {
+			return len(r.Tools) > 0
+		}
+		return slices.Contains(r.Tools, name)
+	}
+}
+
+// EndpointIs matches requests sent to the endpoint.
+func EndpointIs(endpoint Endpoint) Matcher {
+	return func(r *Request) bool {
+		return r.Endpoint == endpoint
+	}
+}
+
+// All matches requests matched by every matcher.
+func All(matchers ...Matcher) Matcher {
+	return func(r *Request) bool {
+		for _, m := range matchers {
+			if !m(r) {
+				return false
+			}
+		}
+		return true
+	}
+}
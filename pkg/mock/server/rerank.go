@@ -0,0 +1,130 @@
+package server
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// RelevanceScore returns a deterministic relevance score of document to query:
+// the fraction of distinct query terms that appear in document, ignoring case
+// and punctuation.
+func RelevanceScore(query, document string) float64 {
+	terms := words(query)
+	if len(terms) == 0 {
+		return 0
+	}
+
+	present := make(map[string]bool)
+	for _, w := range words(document) {
+		present[w] = true
+	}
+
+	matched := 0
+	for _, term := range terms {
+		if present[term] {
+			matched++
+		}
+	}
+	return float64(matched) / float64(len(terms))
+}
+
+// words returns the distinct lowercase words of text.
+func words(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	slices.Sort(fields)
+	return slices.Compact(fields)
+}
+
+// rerankDocuments decodes the documents of a Cohere-style ("documents") or
+// TEI-style ("texts") rerank request. Documents may be strings or {"text": ...} objects.
+func rerankDocuments(raw json.RawMessage) ([]string, error) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil, fmt.Errorf("documents must be an array")
+	}
+
+	documents := make([]string, len(items))
+	for i, item := range items {
+		if err := json.Unmarshal(item, &documents[i]); err == nil {
+			continue
+		}
+		var doc struct {
+			Text string `json:"text"`
+		}
+		if err := json.Unmarshal(item, &doc); err != nil {
+			return nil, fmt.Errorf("document %d must be a string or {\"text\": ...} object", i)
+		}
+		documents[i] = doc.Text
+	}
+	return documents, nil
+}
+
+// writeRerank scores each document against the query and writes the results
+// in descending relevance order: as a Cohere-style {"results": [...]} object
+// for /v1/rerank, or a Text Embeddings Inference array for /rerank.
+// The reply's Scores replace the computed scores.
+func (s *Server) writeRerank(w http.ResponseWriter, req *Request, reply Reply) {
+	type result struct {
+		index int
+		score float64
+	}
+
+	results := make([]result, len(req.Input))
+	for i, doc := range req.Input {
+		score := RelevanceScore(req.Prompt, doc)
+		if i < len(reply.Scores) {
+			score = reply.Scores[i]
+		}
+		results[i] = result{index: i, score: score}
+	}
+	slices.SortStableFunc(results, func(a, b result) int {
+		return cmp.Compare(b.score, a.score)
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if !strings.HasPrefix(req.Path, "/v1/") {
+		returnText, _ := req.Body["return_text"].(bool)
+		data := make([]map[string]any, len(results))
+		for i, r := range results {
+			data[i] = map[string]any{"index": r.index, "score": r.score}
+			if returnText {
+				data[i]["text"] = req.Input[r.index]
+			}
+		}
+		json.NewEncoder(w).Encode(data)
+		return
+	}
+
+	if n, ok := req.Body["top_n"].(float64); ok && int(n) < len(results) {
+		results = results[:int(n)]
+	}
+	returnDocs, _ := req.Body["return_documents"].(bool)
+
+	data := make([]map[string]any, len(results))
+	for i, r := range results {
+		data[i] = map[string]any{"index": r.index, "relevance_score": r.score}
+		if returnDocs {
+			data[i]["document"] = map[string]any{"text": req.Input[r.index]}
+		}
+	}
+
+	tokens := len(strings.Fields(req.Prompt))
+	for _, doc := range req.Input {
+		tokens += len(strings.Fields(doc))
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"id":      fmt.Sprintf("rerank-%d", len(s.Requests())),
+		"model":   req.Model,
+		"results": data,
+		"usage":   map[string]any{"prompt_tokens": tokens, "total_tokens": tokens},
+	})
+}
@@ -0,0 +1,726 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/JaimeStill/go-agents/pkg/config"
+)
+
+// DefaultContent is the reply content when no rule, responder, or reply is configured.
+const DefaultContent = "This is a mock response."
+
+// AzureAPIVersion is the api_version set by Server.AzureProvider.
+const AzureAPIVersion = "2025-01-01-preview"
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithReply sets the reply used when no rule matches and no responder is set.
+func WithReply(reply Reply) Option {
+	return func(s *Server) {
+		s.reply = reply
+	}
+}
+
+// WithRule adds a rule. Rules are evaluated in order and the first match replies.
+func WithRule(match Matcher, reply Reply) Option {
+	return func(s *Server) {
+		s.rules = append(s.rules, rule{match: match, reply: reply})
+	}
+}
+
+// WithResponder sets a function that builds replies for requests no rule matches.
+func WithResponder(fn Responder) Option {
+	return func(s *Server) {
+		s.responder = fn
+	}
+}
+
+// WithChunkDelay sets the default delay before each streamed chunk.
+func WithChunkDelay(delay time.Duration) Option {
+	return func(s *Server) {
+		s.chunkDelay = delay
+	}
+}
+
+// WithDimensions sets the length of generated embeddings. Defaults to DefaultDimensions.
+func WithDimensions(dimensions int) Option {
+	return func(s *Server) {
+		s.dimensions = dimensions
+	}
+}
+
+type rule struct {
+	match Matcher
+	reply Reply
+}
+
+// Server is a fake OpenAI-compatible LLM server for tests and demos.
+// It serves the /v1 paths used by Ollama and the /openai/deployments paths
+// used by Azure over real HTTP, so requests pass through the real providers
+// and client.
+//
+// Each request is answered by the first matching rule, the responder, or
+// the default reply, in that order. Faults queued with FailNext are applied
+// to the reply in place of any fault it carries.
+type Server struct {
+	*httptest.Server
+
+	chunkDelay time.Duration
+	dimensions int
+	responder  Responder
+
+	mutex    sync.Mutex
+	reply    Reply
+	rules    []rule
+	faults   []Fault
+	requests []Request
+}
+
+// New starts a fake server. Call Close when done.
+func New(opts ...Option) *Server {
+	s := &Server{
+		dimensions: DefaultDimensions,
+		reply:      Reply{Content: DefaultContent},
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/chat/completions", s.handle(EndpointChat))
+	mux.HandleFunc("POST /v1/embeddings", s.handle(EndpointEmbeddings))
+	mux.HandleFunc("POST /openai/deployments/{deployment}/chat/completions", s.handle(EndpointChat))
+	mux.HandleFunc("POST /openai/deployments/{deployment}/embeddings", s.handle(EndpointEmbeddings))
+	mux.HandleFunc("POST /v1/images/generations", s.handle(EndpointImages))
+	mux.HandleFunc("POST /openai/deployments/{deployment}/images/generations", s.handle(EndpointImages))
+	mux.HandleFunc("POST /v1/audio/transcriptions", s.handle(EndpointTranscribe))
+	mux.HandleFunc("POST /v1/audio/speech", s.handle(EndpointSpeech))
+	mux.HandleFunc("POST /openai/deployments/{deployment}/audio/transcriptions", s.handle(EndpointTranscribe))
+	mux.HandleFunc("POST /openai/deployments/{deployment}/audio/speech", s.handle(EndpointSpeech))
+	mux.HandleFunc("POST /v1/rerank", s.handle(EndpointRerank))
+	mux.HandleFunc("POST /rerank", s.handle(EndpointRerank))
+	mux.HandleFunc("POST /v1/moderations", s.handle(EndpointModeration))
+	mux.HandleFunc("GET /images/{name}", s.serveImage)
+
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// OllamaProvider returns a provider configuration for the ollama provider pointed at the server.
+func (s *Server) OllamaProvider() *config.ProviderConfig {
+	return &config.ProviderConfig{
+		Name:    "ollama",
+		BaseURL: s.URL,
+		Options: map[string]any{},
+	}
+}
+
+// AzureProvider returns a provider configuration for the azure provider pointed at
+// the server, using API key authentication and the given deployment.
+func (s *Server) AzureProvider(deployment string) *config.ProviderConfig {
+	return &config.ProviderConfig{
+		Name:    "azure",
+		BaseURL: s.URL + "/openai",
+		Options: map[string]any{
+			"deployment":  deployment,
+			"api_version": AzureAPIVersion,
+			"auth_type":   "api_key",
+			"token":       "mock-api-key",
+		},
+	}
+}
+
+// AddRule adds a rule after those configured with WithRule.
+func (s *Server) AddRule(match Matcher, reply Reply) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.rules = append(s.rules, rule{match: match, reply: reply})
+}
+
+// FailNext injects fault into the next n requests, replacing any fault of their replies.
+// Useful for exercising retries: FailNext(2, Fault{Status: 429}) fails twice
+// and then lets requests succeed.
+func (s *Server) FailNext(n int, fault Fault) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for range n {
+		s.faults = append(s.faults, fault)
+	}
+}
+
+// Requests returns the requests received so far, in arrival order.
+func (s *Server) Requests() []Request {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// handle returns the handler for an endpoint.
+func (s *Server) handle(endpoint Endpoint) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, err := parseRequest(endpoint, r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error(), 0)
+			return
+		}
+
+		if req.Deployment != "" && r.URL.Query().Get("api-version") == "" {
+			writeError(w, http.StatusNotFound, "api-version query parameter is required", 0)
+			return
+		}
+
+		reply := s.resolve(req)
+
+		if f := reply.Fault; f != nil && f.FirstByteDelay > 0 {
+			if !sleep(r.Context(), f.FirstByteDelay) {
+				return
+			}
+		}
+
+		if f := reply.Fault; f != nil && f.Status != 0 {
+			message := f.Message
+			if message == "" {
+				message = http.StatusText(f.Status)
+			}
+			writeError(w, f.Status, message, f.RetryAfter)
+			return
+		}
+
+		switch endpoint {
+		case EndpointEmbeddings:
+			s.writeEmbeddings(w, req, reply)
+			return
+		case EndpointRerank:
+			s.writeRerank(w, req, reply)
+			return
+		case EndpointModeration:
+			s.writeModeration(w, req, reply)
+			return
+		}
+
+		if strings.Contains(reply.Content, "{{") {
+			content, err := render(reply.Content, req)
+			if err != nil {
+				writeError(w, http.StatusInternalServerError, err.Error(), 0)
+				return
+			}
+			reply.Content = content
+		}
+
+		switch endpoint {
+		case EndpointImages:
+			s.writeImages(w, req, reply)
+			return
+		case EndpointTranscribe:
+			s.writeTranscription(w, req, reply)
+			return
+		case EndpointSpeech:
+			s.writeSpeech(w, r, req, reply)
+			return
+		}
+
+		if req.Stream {
+			s.writeStream(w, r, req, reply)
+			return
+		}
+		s.writeChat(w, req, reply)
+	}
+}
+
+// resolve records the request and selects its reply.
+// Matchers and the responder run without the server lock held.
+func (s *Server) resolve(req *Request) Reply {
+	s.mutex.Lock()
+	s.requests = append(s.requests, *req)
+	reply := s.reply
+	rules := slices.Clone(s.rules)
+	var fault *Fault
+	if len(s.faults) > 0 {
+		f := s.faults[0]
+		fault = &f
+		s.faults = s.faults[1:]
+	}
+	s.mutex.Unlock()
+
+	if i := slices.IndexFunc(rules, func(r rule) bool { return r.match(req) }); i >= 0 {
+		reply = rules[i].reply
+	} else if s.responder != nil {
+		reply = s.responder(req)
+	}
+
+	if fault != nil {
+		reply.Fault = fault
+	}
+
+	return reply
+}
+
+// writeChat writes a chat completion with one choice per requested "n",
+// cycling through the reply's Content and Alternatives.
+func (s *Server) writeChat(w http.ResponseWriter, req *Request, reply Reply) {
+	contents := candidates(reply)
+	withLogprobs, top := logprobsRequested(req)
+
+	choices := make([]map[string]any, choiceCount(req))
+	for i := range choices {
+		content := contents[i%len(contents)]
+		message := map[string]any{
+			"role":    "assistant",
+			"content": content,
+		}
+		if reply.Reasoning != "" {
+			message["reasoning_content"] = reply.Reasoning
+		}
+		if len(reply.ToolCalls) > 0 {
+			message["tool_calls"] = toolCalls(reply.ToolCalls)
+		}
+
+		choices[i] = map[string]any{
+			"index":         i,
+			"message":       message,
+			"finish_reason": finishReason(reply),
+		}
+		if withLogprobs {
+			others := slices.DeleteFunc(slices.Clone(contents), func(c string) bool { return c == content })
+			choices[i]["logprobs"] = logprobs(tokenize(content), others, tokenProbability(reply), top)
+		}
+		if req.Deployment != "" {
+			choices[i]["content_filter_results"] = contentFilter(reply)
+		}
+	}
+
+	completion := map[string]any{
+		"id":                 "chatcmpl-mock",
+		"object":             "chat.completion",
+		"created":            time.Now().Unix(),
+		"model":              req.Model,
+		"system_fingerprint": SystemFingerprint,
+		"choices":            choices,
+		"usage":              usage(req, reply),
+	}
+
+	if req.Deployment != "" {
+		completion["prompt_filter_results"] = []map[string]any{{
+			"prompt_index":           0,
+			"content_filter_results": contentFilter(Reply{}),
+		}}
+	}
+
+	data, _ := json.Marshal(completion)
+	w.Header().Set("Content-Type", "application/json")
+
+	if f := reply.Fault; f != nil && f.Truncate {
+		w.Write(data[:len(data)/2])
+		w.(http.Flusher).Flush()
+		panic(http.ErrAbortHandler)
+	}
+
+	w.Write(data)
+}
+
+// writeStream writes a chat completion as server-sent events.
+func (s *Server) writeStream(w http.ResponseWriter, r *http.Request, req *Request, reply Reply) {
+	chunks := reply.Chunks
+	if chunks == nil && reply.Content != "" {
+		chunks = strings.SplitAfter(reply.Content, " ")
+	}
+
+	delay := reply.ChunkDelay
+	if delay == 0 {
+		delay = s.chunkDelay
+	}
+
+	var deltas []map[string]any
+	var tokenLogprobs []any
+	if reply.Reasoning != "" {
+		for _, reasoning := range strings.SplitAfter(reply.Reasoning, " ") {
+			deltas = append(deltas, map[string]any{"reasoning_content": reasoning})
+			tokenLogprobs = append(tokenLogprobs, nil)
+		}
+	}
+	for _, content := range chunks {
+		deltas = append(deltas, map[string]any{"content": content})
+	}
+	if len(deltas) > 0 {
+		deltas[0]["role"] = "assistant"
+	}
+
+	if withLogprobs, top := logprobsRequested(req); withLogprobs {
+		lp := logprobs(chunks, reply.Alternatives, tokenProbability(reply), top)
+		for _, token := range lp["content"].([]map[string]any) {
+			tokenLogprobs = append(tokenLogprobs, map[string]any{"content": []any{token}})
+		}
+	}
+	if len(reply.ToolCalls) > 0 {
+		calls := toolCalls(reply.ToolCalls)
+		for i := range calls {
+			calls[i]["index"] = i
+		}
+		deltas = append(deltas, map[string]any{"tool_calls": calls})
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher := w.(http.Flusher)
+	flusher.Flush()
+
+	event := func(payload any) {
+		data, _ := json.Marshal(payload)
+		fmt.Fprintf(w, "data: %s\n\n", data)
+		flusher.Flush()
+	}
+
+	chunk := func(delta map[string]any, finish any, lp any) map[string]any {
+		choice := map[string]any{"index": 0, "delta": delta, "finish_reason": finish}
+		if lp != nil {
+			choice["logprobs"] = lp
+		}
+		return map[string]any{
+			"id":                 "chatcmpl-mock",
+			"object":             "chat.completion.chunk",
+			"created":            time.Now().Unix(),
+			"model":              req.Model,
+			"system_fingerprint": SystemFingerprint,
+			"choices":            []map[string]any{choice},
+		}
+	}
+
+	for i, delta := range deltas {
+		if f := reply.Fault; f != nil && f.Truncate && i >= f.TruncateAfter {
+			panic(http.ErrAbortHandler)
+		}
+		if delay > 0 && !sleep(r.Context(), delay) {
+			return
+		}
+		var lp any
+		if i < len(tokenLogprobs) {
+			lp = tokenLogprobs[i]
+		}
+		event(chunk(delta, nil, lp))
+	}
+
+	if f := reply.Fault; f != nil && f.Truncate {
+		panic(http.ErrAbortHandler)
+	}
+
+	event(chunk(map[string]any{}, finishReason(reply), nil))
+
+	if options, ok := req.Body["stream_options"].(map[string]any); ok && options["include_usage"] == true {
+		event(map[string]any{
+			"id":      "chatcmpl-mock",
+			"object":  "chat.completion.chunk",
+			"created": time.Now().Unix(),
+			"model":   req.Model,
+			"choices": []any{},
+			"usage":   usage(req, reply),
+		})
+	}
+
+	fmt.Fprint(w, "data: [DONE]\n\n")
+	flusher.Flush()
+}
+
+// writeEmbeddings writes an embeddings response with one vector per input.
+func (s *Server) writeEmbeddings(w http.ResponseWriter, req *Request, reply Reply) {
+	data := make([]map[string]any, len(req.Input))
+	tokens := 0
+	for i, input := range req.Input {
+		embedding := reply.Embedding
+		if embedding == nil {
+			embedding = Embedding(input, s.dimensions)
+		}
+		data[i] = map[string]any{"object": "embedding", "index": i, "embedding": embedding}
+		tokens += countWords(input)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"object": "list",
+		"data":   data,
+		"model":  req.Model,
+		"usage":  map[string]int{"prompt_tokens": tokens, "total_tokens": tokens},
+	})
+}
+
+// writeImages writes an image generation response with n images of the
+// requested size, as URLs served by the server or as base64 PNG data.
+// A reply's Content, when set, is returned as each image's revised prompt.
+func (s *Server) writeImages(w http.ResponseWriter, req *Request, reply Reply) {
+	size, _ := req.Body["size"].(string)
+	width, height, err := parseSize(size)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error(), 0)
+		return
+	}
+
+	n := 1
+	if v, ok := req.Body["n"].(float64); ok && v >= 1 {
+		n = int(v)
+	}
+
+	format, _ := req.Body["response_format"].(string)
+
+	data := make([]map[string]any, n)
+	for i := range data {
+		seed := imageSeed(req.Prompt, i)
+		image := map[string]any{}
+		if format == "b64_json" {
+			image["b64_json"] = base64.StdEncoding.EncodeToString(Image(seed, width, height))
+		} else {
+			image["url"] = fmt.Sprintf("%s/images/%s_%dx%d.png", s.URL, seed, width, height)
+		}
+		if reply.Content != "" && reply.Content != DefaultContent {
+			image["revised_prompt"] = reply.Content
+		}
+		data[i] = image
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"created": time.Now().Unix(),
+		"data":    data,
+	})
+}
+
+// parseRequest decodes a JSON or multipart request body into a Request.
+func parseRequest(endpoint Endpoint, r *http.Request) (*Request, error) {
+	if boundary, ok := multipartBoundary(r); ok {
+		return parseMultipart(endpoint, r, boundary)
+	}
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request: %w", err)
+	}
+
+	var body map[string]any
+	if err := json.Unmarshal(data, &body); err != nil {
+		return nil, fmt.Errorf("invalid JSON body: %w", err)
+	}
+
+	var raw struct {
+		Model    string `json:"model"`
+		Stream   bool   `json:"stream"`
+		Messages []struct {
+			Role       string          `json:"role"`
+			Content    json.RawMessage `json:"content"`
+			ToolCallID string          `json:"tool_call_id"`
+		} `json:"messages"`
+		Tools []struct {
+			Function struct {
+				Name string `json:"name"`
+			} `json:"function"`
+		} `json:"tools"`
+		Input     json.RawMessage `json:"input"`
+		Prompt    string          `json:"prompt"`
+		Query     string          `json:"query"`
+		Documents json.RawMessage `json:"documents"`
+		Texts     json.RawMessage `json:"texts"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	req := &Request{
+		Endpoint:   endpoint,
+		Path:       r.URL.Path,
+		Deployment: r.PathValue("deployment"),
+		Model:      raw.Model,
+		Stream:     raw.Stream,
+		Body:       body,
+		Header:     r.Header.Clone(),
+	}
+
+	if req.Model == "" {
+		req.Model = req.Deployment
+	}
+
+	for _, m := range raw.Messages {
+		msg := Message{Role: m.Role, Content: textContent(m.Content), ToolCallID: m.ToolCallID}
+		req.Messages = append(req.Messages, msg)
+
+		switch m.Role {
+		case "user":
+			req.Prompt = msg.Content
+		case "system", "developer":
+			if req.System == "" {
+				req.System = msg.Content
+			}
+		}
+	}
+
+	for _, t := range raw.Tools {
+		req.Tools = append(req.Tools, t.Function.Name)
+	}
+
+	if endpoint == EndpointImages {
+		req.Prompt = raw.Prompt
+	}
+
+	if endpoint == EndpointRerank {
+		documents := raw.Documents
+		if documents == nil {
+			documents = raw.Texts
+		}
+		if raw.Query == "" || documents == nil {
+			return nil, fmt.Errorf("query and documents are required")
+		}
+		req.Prompt = raw.Query
+		if req.Input, err = rerankDocuments(documents); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(raw.Input) > 0 {
+		var single string
+		if err := json.Unmarshal(raw.Input, &single); err == nil {
+			req.Input = []string{single}
+		} else if err := json.Unmarshal(raw.Input, &req.Input); err != nil {
+			return nil, fmt.Errorf("input must be a string or array of strings")
+		}
+	}
+
+	if endpoint == EndpointModeration {
+		req.Prompt = strings.Join(req.Input, "\n")
+	}
+
+	return req, nil
+}
+
+// textContent extracts text from string content or from the text parts of
+// multimodal content.
+func textContent(content json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(content, &text); err == nil {
+		return text
+	}
+
+	var parts []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(content, &parts); err != nil {
+		return ""
+	}
+
+	var texts []string
+	for _, p := range parts {
+		if p.Type == "text" {
+			texts = append(texts, p.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// render executes a reply template with the request.
+func render(content string, req *Request) (string, error) {
+	tmpl, err := template.New("reply").Parse(content)
+	if err != nil {
+		return "", fmt.Errorf("invalid reply template: %w", err)
+	}
+
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, req); err != nil {
+		return "", fmt.Errorf("failed to render reply template: %w", err)
+	}
+	return out.String(), nil
+}
+
+// toolCalls encodes tool calls in the OpenAI wire format.
+func toolCalls(calls []ToolCall) []map[string]any {
+	encoded := make([]map[string]any, len(calls))
+	for i, call := range calls {
+		arguments := call.Arguments
+		if arguments == "" {
+			arguments = "{}"
+		}
+		encoded[i] = map[string]any{
+			"id":   fmt.Sprintf("call_mock_%d", i),
+			"type": "function",
+			"function": map[string]any{
+				"name":      call.Name,
+				"arguments": arguments,
+			},
+		}
+	}
+	return encoded
+}
+
+// finishReason returns the reply's finish reason or its default.
+func finishReason(reply Reply) string {
+	switch {
+	case reply.FinishReason != "":
+		return reply.FinishReason
+	case len(reply.ToolCalls) > 0:
+		return "tool_calls"
+	default:
+		return "stop"
+	}
+}
+
+// usage approximates token usage by counting words.
+func usage(req *Request, reply Reply) map[string]any {
+	prompt := 0
+	for _, m := range req.Messages {
+		prompt += countWords(m.Content)
+	}
+	reasoning := countWords(reply.Reasoning)
+	output := countWords(reply.Content) + reasoning
+	tokens := map[string]any{
+		"prompt_tokens":     prompt,
+		"completion_tokens": output,
+		"total_tokens":      prompt + output,
+	}
+	if reasoning > 0 {
+		tokens["completion_tokens_details"] = map[string]int{"reasoning_tokens": reasoning}
+	}
+	return tokens
+}
+
+func countWords(s string) int {
+	return len(strings.Fields(s))
+}
+
+// writeError writes an OpenAI-style error response.
+func writeError(w http.ResponseWriter, status int, message string, retryAfter time.Duration) {
+	if retryAfter > 0 {
+		w.Header().Set("Retry-After", fmt.Sprint(int(math.Ceil(retryAfter.Seconds()))))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{
+		"error": map[string]any{
+			"code":    fmt.Sprint(status),
+			"message": message,
+		},
+	})
+}
+
+// sleep waits for d or until ctx is done. Returns false if ctx ended first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -1,9 +1,12 @@
 // Package model provides the Model type representing a configured LLM model at runtime.
-// It stores the model name and protocol-specific default options,
-// bridging JSON configuration with runtime domain types.
+// It stores the model name, protocol-specific default options, and what the
+// model supports, bridging JSON configuration with runtime domain types.
 package model
 
 import (
+	"cmp"
+	"slices"
+
 	"github.com/JaimeStill/go-agents/pkg/config"
 	"github.com/JaimeStill/go-agents/pkg/protocol"
 )
@@ -15,6 +18,41 @@ type Model struct {
 	// Name is the model identifier (e.g., "gpt-4o", "claude-3-opus", "llama3.1:8b")
 	Name string
 
+	// ContextWindow is the maximum number of tokens (prompt and completion) the model accepts.
+	// Zero means the limit is unknown and no pre-flight context check is performed.
+	ContextWindow int
+
+	// MaxOutputTokens is the default completion budget reserved when checking the context window.
+	MaxOutputTokens int
+
+	// Tokenizer is the encoding used to estimate prompt size.
+	// Empty means the encoding is detected from the model name.
+	Tokenizer string
+
+	// ContextPolicy selects how prompts exceeding the context window are handled.
+	// See config.ContextPolicyError and config.ContextPolicyTruncate.
+	ContextPolicy string
+
+	// Reasoning describes how requests to a reasoning model are adapted.
+	// Nil for models that are not reasoning models.
+	Reasoning *Reasoning
+
+	// Protocols are the protocols the model supports.
+	// Empty means support is unknown and every protocol is allowed.
+	Protocols []protocol.Protocol
+
+	// Streaming reports whether the model streams responses. Nil means unknown.
+	Streaming *bool
+
+	// JSONMode reports whether the model supports JSON response formats. Nil means unknown.
+	JSONMode *bool
+
+	// MaxImages is the maximum number of images per request. Zero means unknown.
+	MaxImages int
+
+	// MaxImageBytes is the maximum size of an inline image. Zero means unknown.
+	MaxImageBytes int
+
 	// Options holds protocol-specific default options.
 	// Keys are protocols (Chat, Vision, Tools, Embeddings).
 	// Values are option maps for that protocol (temperature, max_tokens, etc.)
@@ -24,10 +62,32 @@ type Model struct {
 // New creates a Model from a ModelConfig.
 // Handles conversion from string-keyed configuration to Protocol-keyed runtime model.
 // This bridges the gap between JSON configuration structure and runtime domain type.
+// Metadata not set in cfg is filled from the built-in catalog (see config.CatalogModel).
 func New(cfg *config.ModelConfig) *Model {
+	cfg = cfg.Resolve()
+
 	model := &Model{
-		Name:    cfg.Name,
-		Options: make(map[protocol.Protocol]map[string]any),
+		Name:            cfg.Name,
+		ContextWindow:   cfg.Window(),
+		MaxOutputTokens: cfg.OutputLimit(),
+		Tokenizer:       cfg.Tokenizer,
+		ContextPolicy:   cfg.ContextPolicy,
+		Streaming:       cfg.Streaming,
+		JSONMode:        cfg.JSONMode,
+		MaxImages:       cfg.ImageLimit(),
+		MaxImageBytes:   cfg.ImageBytesLimit(),
+		Options:         make(map[protocol.Protocol]map[string]any),
+	}
+
+	for _, name := range cfg.Protocols {
+		model.Protocols = append(model.Protocols, protocol.Protocol(name))
+	}
+
+	if r := cfg.Reasoning; r != nil {
+		model.Reasoning = &Reasoning{
+			Effort:     protocol.ReasoningEffort(r.Effort),
+			SystemRole: cmp.Or(r.SystemRole, config.SystemRoleDeveloper),
+		}
 	}
 
 	// Convert string keys to Protocol constants
@@ -38,3 +98,35 @@ func New(cfg *config.ModelConfig) *Model {
 
 	return model
 }
+
+// Supports reports whether the model supports the protocol.
+// Returns true when the model's protocols are unknown.
+func (m *Model) Supports(p protocol.Protocol) bool {
+	return len(m.Protocols) == 0 || slices.Contains(m.Protocols, p)
+}
+
+// SupportsTools reports whether the model supports tool calling.
+func (m *Model) SupportsTools() bool {
+	return m.Supports(protocol.Tools)
+}
+
+// SupportsStreaming reports whether the model streams responses.
+// Returns true when streaming support is unknown.
+func (m *Model) SupportsStreaming() bool {
+	return m.Streaming == nil || *m.Streaming
+}
+
+// SupportsJSONMode reports whether the model supports JSON response formats.
+// Returns true when JSON mode support is unknown.
+func (m *Model) SupportsJSONMode() bool {
+	return m.JSONMode == nil || *m.JSONMode
+}
+
+// Reasoning holds the request settings of a reasoning model.
+type Reasoning struct {
+	// Effort is the default reasoning effort. Empty leaves it to the model.
+	Effort protocol.ReasoningEffort
+
+	// SystemRole is the role system messages are sent with ("developer", "system", or "user").
+	SystemRole string
+}
@@ -0,0 +1,157 @@
+package protocol
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// ContentType identifies the kind of a ContentPart.
+type ContentType string
+
+const (
+	// ContentText is a text segment.
+	ContentText ContentType = "text"
+
+	// ContentImageURL is an image referenced by URL or base64 data URI.
+	ContentImageURL ContentType = "image_url"
+
+	// ContentAudio is base64-encoded audio input.
+	ContentAudio ContentType = "input_audio"
+
+	// ContentFile is a document passed inline or by uploaded file ID.
+	ContentFile ContentType = "file"
+)
+
+// ContentPart is one segment of multimodal message content.
+// A Message whose Content is a []ContentPart can interleave text, images,
+// audio, and documents in any turn of a conversation.
+// The JSON encoding follows the OpenAI content-part format.
+type ContentPart struct {
+	Type       ContentType `json:"type"`
+	Text       string      `json:"text,omitempty"`
+	ImageURL   *ImageURL   `json:"image_url,omitempty"`
+	InputAudio *InputAudio `json:"input_audio,omitempty"`
+	File       *File       `json:"file,omitempty"`
+}
+
+// ImageURL references an image by URL or base64 data URI.
+// Detail is "low", "high", or "auto"; empty uses the provider default.
+type ImageURL struct {
+	URL    string `json:"url"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// InputAudio holds base64-encoded audio and its format, such as "wav" or "mp3".
+type InputAudio struct {
+	Data   string `json:"data"`
+	Format string `json:"format"`
+}
+
+// File holds a document either inline as a base64 data URI in FileData,
+// or as a reference to a previously uploaded file in FileID.
+type File struct {
+	FileID   string `json:"file_id,omitempty"`
+	Filename string `json:"filename,omitempty"`
+	FileData string `json:"file_data,omitempty"`
+}
+
+// TextPart creates a text content part.
+func TextPart(text string) ContentPart {
+	return ContentPart{Type: ContentText, Text: text}
+}
+
+// ImageURLPart creates an image part referencing url, which may be a remote
+// URL or a data URI.
+func ImageURLPart(url, detail string) ContentPart {
+	return ContentPart{
+		Type:     ContentImageURL,
+		ImageURL: &ImageURL{URL: url, Detail: detail},
+	}
+}
+
+// ImageDataPart creates an image part from raw image bytes, encoded as a
+// base64 data URI with the given MIME type (e.g. "image/png").
+func ImageDataPart(mimeType string, data []byte, detail string) ContentPart {
+	return ImageURLPart(DataURI(mimeType, data), detail)
+}
+
+// AudioPart creates an audio part from raw audio bytes in the given format.
+func AudioPart(data []byte, format string) ContentPart {
+	return ContentPart{
+		Type: ContentAudio,
+		InputAudio: &InputAudio{
+			Data:   base64.StdEncoding.EncodeToString(data),
+			Format: format,
+		},
+	}
+}
+
+// FilePart creates an inline document part from raw file bytes.
+func FilePart(filename, mimeType string, data []byte) ContentPart {
+	return ContentPart{
+		Type: ContentFile,
+		File: &File{
+			Filename: filename,
+			FileData: DataURI(mimeType, data),
+		},
+	}
+}
+
+// FileIDPart creates a document part referencing an uploaded file.
+func FileIDPart(id string) ContentPart {
+	return ContentPart{
+		Type: ContentFile,
+		File: &File{FileID: id},
+	}
+}
+
+// DataURI encodes data as a base64 data URI with the given MIME type.
+func DataURI(mimeType string, data []byte) string {
+	return "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data)
+}
+
+// Validate checks that the part carries the payload its Type requires.
+func (p ContentPart) Validate() error {
+	switch p.Type {
+	case ContentText:
+		return nil
+	case ContentImageURL:
+		if p.ImageURL == nil || p.ImageURL.URL == "" {
+			return fmt.Errorf("image_url content part requires a url")
+		}
+	case ContentAudio:
+		if p.InputAudio == nil || p.InputAudio.Data == "" {
+			return fmt.Errorf("input_audio content part requires data")
+		}
+		if p.InputAudio.Format == "" {
+			return fmt.Errorf("input_audio content part requires a format")
+		}
+	case ContentFile:
+		if p.File == nil || (p.File.FileID == "" && p.File.FileData == "") {
+			return fmt.Errorf("file content part requires file_id or file_data")
+		}
+	default:
+		return fmt.Errorf("unsupported content part type: %q", p.Type)
+	}
+	return nil
+}
+
+// MIMEType returns the MIME type of an inline image or file part,
+// or an empty string for remote URLs, file IDs, and other part types.
+func (p ContentPart) MIMEType() string {
+	var uri string
+	switch {
+	case p.ImageURL != nil:
+		uri = p.ImageURL.URL
+	case p.File != nil:
+		uri = p.File.FileData
+	}
+
+	header, _, ok := strings.Cut(uri, ",")
+	if !ok || !strings.HasPrefix(header, "data:") {
+		return ""
+	}
+	mimeType, _, _ := strings.Cut(strings.TrimPrefix(header, "data:"), ";")
+	return mimeType
+}
@@ -1,21 +1,190 @@
 package protocol
 
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
 // Message represents a single message in a conversation.
-// The Role indicates the message sender (user, assistant, system),
-// and Content can be either a string for text or a structured object
-// for multimodal content (e.g., vision protocol with images).
+// The Role indicates the message sender (system, user, assistant, tool),
+// and Content can be either a string for text or a []ContentPart
+// for multimodal content interleaving text, images, audio, and documents.
+//
+// Assistant messages that request function calls carry ToolCalls and may
+// have nil Content. Tool messages return a call's result in Content and
+// reference the call with ToolCallID.
 type Message struct {
 	Role    string `json:"role"`
 	Content any    `json:"content"`
+
+	// Name optionally identifies the participant, or the function for tool messages.
+	Name string `json:"name,omitempty"`
+
+	// ToolCalls are the function calls requested by an assistant message.
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+
+	// ToolCallID is the ID of the call a tool message responds to.
+	ToolCallID string `json:"tool_call_id,omitempty"`
+
+	// Refusal is the model's refusal message when it declines to respond.
+	Refusal string `json:"refusal,omitempty"`
+
+	// Reasoning is the reasoning text returned by reasoning models.
+	// It is preserved in history but not sent back to providers.
+	Reasoning string `json:"reasoning_content,omitempty"`
+}
+
+// ToolCall represents a function call requested by the model.
+// Contains the call ID, type, and function details.
+type ToolCall struct {
+	ID       string           `json:"id"`
+	Type     string           `json:"type"`
+	Function ToolCallFunction `json:"function"`
+}
+
+// ToolCallFunction contains the details of a function to be called.
+// Name specifies the function name, and Arguments contains JSON-encoded parameters.
+type ToolCallFunction struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// DecodeArguments unmarshals the JSON-encoded arguments into v.
+func (f ToolCallFunction) DecodeArguments(v any) error {
+	if err := json.Unmarshal([]byte(f.Arguments), v); err != nil {
+		return fmt.Errorf("invalid arguments for %s: %w", f.Name, err)
+	}
+	return nil
 }
 
 // NewMessage creates a new Message with the specified role and content.
-// Content can be a string for text or a structured object for multimodal inputs.
+// Content can be a string for text or a []ContentPart for multimodal inputs.
 //
 // Example:
 //
 //	msg := protocol.NewMessage("user", "Hello, world!")
-//	visionMsg := protocol.NewMessage("user", []map[string]any{...})
+//	visionMsg := protocol.NewMessage("user", []protocol.ContentPart{...})
 func NewMessage(role string, content any) Message {
 	return Message{Role: role, Content: content}
 }
+
+// NewContentMessage creates a Message whose content is the given parts.
+//
+// Example:
+//
+//	msg := protocol.NewContentMessage("user",
+//	    protocol.TextPart("Compare these diagrams:"),
+//	    protocol.ImageDataPart("image/png", before, "high"),
+//	    protocol.ImageDataPart("image/png", after, "high"),
+//	)
+func NewContentMessage(role string, parts ...ContentPart) Message {
+	return Message{Role: role, Content: parts}
+}
+
+// NewToolCallMessage creates an assistant message requesting the given
+// function calls, for replaying a model's tool calls in conversation history.
+func NewToolCallMessage(calls ...ToolCall) Message {
+	return Message{Role: "assistant", ToolCalls: calls}
+}
+
+// NewToolResultMessage creates a tool message returning the result of the
+// function call identified by toolCallID.
+//
+// Example:
+//
+//	for _, call := range resp.Choices[0].Message.ToolCalls {
+//	    result := execute(call.Function)
+//	    messages = append(messages, protocol.NewToolResultMessage(call.ID, result))
+//	}
+func NewToolResultMessage(toolCallID, content string) Message {
+	return Message{Role: "tool", Content: content, ToolCallID: toolCallID}
+}
+
+// Parts returns the message content as content parts.
+// String content is returned as a single text part; other structured
+// content is converted through its JSON encoding.
+func (m Message) Parts() ([]ContentPart, error) {
+	switch v := m.Content.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []ContentPart{TextPart(v)}, nil
+	case []ContentPart:
+		return v, nil
+	case ContentPart:
+		return []ContentPart{v}, nil
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode message content: %w", err)
+		}
+		var parts []ContentPart
+		if err := json.Unmarshal(data, &parts); err != nil {
+			return nil, fmt.Errorf("message content is not a list of content parts: %w", err)
+		}
+		return parts, nil
+	}
+}
+
+// Text returns the text of the message: string content as-is, or the text
+// parts of multimodal content joined by newlines.
+func (m Message) Text() string {
+	switch v := m.Content.(type) {
+	case string:
+		return v
+	default:
+		parts, err := m.Parts()
+		if err != nil {
+			return ""
+		}
+		texts := make([]string, 0, len(parts))
+		for _, p := range parts {
+			if p.Type == ContentText {
+				texts = append(texts, p.Text)
+			}
+		}
+		return strings.Join(texts, "\n")
+	}
+}
+
+// UnmarshalJSON decodes string content as a string and array content as
+// []ContentPart, so multimodal history survives a JSON round trip.
+// Reasoning is read from "reasoning_content" or, as Ollama reports it, "reasoning".
+func (m *Message) UnmarshalJSON(data []byte) error {
+	type message Message
+	raw := struct {
+		*message
+		Content         json.RawMessage `json:"content"`
+		OllamaReasoning string          `json:"reasoning"`
+	}{message: (*message)(m)}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	if m.Reasoning == "" {
+		m.Reasoning = raw.OllamaReasoning
+	}
+
+	m.Content = nil
+
+	content := strings.TrimSpace(string(raw.Content))
+	switch {
+	case content == "" || content == "null":
+	case strings.HasPrefix(content, "["):
+		var parts []ContentPart
+		if err := json.Unmarshal(raw.Content, &parts); err != nil {
+			return fmt.Errorf("failed to decode content parts: %w", err)
+		}
+		m.Content = parts
+	default:
+		var v any
+		if err := json.Unmarshal(raw.Content, &v); err != nil {
+			return err
+		}
+		m.Content = v
+	}
+
+	return nil
+}
@@ -1,6 +1,15 @@
 // Package protocol provides the foundation types for LLM interaction protocols.
 // It defines the Protocol type representing different LLM capabilities
 // and the Message type for conversation structures.
+//
+// Message content is either a string or a []ContentPart interleaving text,
+// images, audio, and documents in any turn:
+//
+//	msg := protocol.NewContentMessage("user",
+//	    protocol.TextPart("What changed between these pages?"),
+//	    protocol.ImageDataPart("image/png", page1, "high"),
+//	    protocol.ImageDataPart("image/png", page2, "high"),
+//	)
 package protocol
 
 import "strings"
@@ -21,13 +30,29 @@ const (
 
 	// Embeddings represents text vectorization for semantic search.
 	Embeddings Protocol = "embeddings"
+
+	// ImageGeneration represents creating images from a text prompt.
+	ImageGeneration Protocol = "image_generation"
+
+	// Transcription represents converting recorded speech to text.
+	Transcription Protocol = "transcription"
+
+	// Speech represents synthesizing spoken audio from text.
+	Speech Protocol = "speech"
+
+	// Rerank represents ordering documents by relevance to a query.
+	Rerank Protocol = "rerank"
+
+	// Moderation represents classifying text as harmful or safe.
+	Moderation Protocol = "moderation"
 )
 
 // IsValid checks if a protocol string is valid.
-// Returns true if the protocol is one of: chat, vision, tools, embeddings.
+// Returns true if the protocol is one of: chat, vision, tools, embeddings,
+// image_generation, transcription, speech, rerank, moderation.
 func IsValid(p string) bool {
 	switch Protocol(p) {
-	case Chat, Vision, Tools, Embeddings:
+	case Chat, Vision, Tools, Embeddings, ImageGeneration, Transcription, Speech, Rerank, Moderation:
 		return true
 	default:
 		return false
@@ -35,13 +60,19 @@ func IsValid(p string) bool {
 }
 
 // ValidProtocols returns a slice of all supported protocol values.
-// Returns protocols in order: Chat, Vision, Tools, Embeddings.
+// Returns protocols in order: Chat, Vision, Tools, Embeddings, ImageGeneration,
+// Transcription, Speech, Rerank, Moderation.
 func ValidProtocols() []Protocol {
 	return []Protocol{
 		Chat,
 		Vision,
 		Tools,
 		Embeddings,
+		ImageGeneration,
+		Transcription,
+		Speech,
+		Rerank,
+		Moderation,
 	}
 }
 
@@ -57,13 +88,14 @@ func ProtocolStrings() string {
 }
 
 // SupportsStreaming returns true if the protocol supports streaming responses.
-// Currently Chat, Vision, and Tools support streaming.
-// Embeddings does not support streaming.
+// Currently Chat, Vision, Tools, and Speech support streaming; Speech streams
+// raw audio rather than text deltas.
+// Embeddings, ImageGeneration, Transcription, Rerank, and Moderation do not support streaming.
 func (p Protocol) SupportsStreaming() bool {
 	switch p {
-	case Chat, Vision, Tools:
+	case Chat, Vision, Tools, Speech:
 		return true
-	case Embeddings:
+	case Embeddings, ImageGeneration, Transcription, Rerank, Moderation:
 		return false
 	default:
 		return false
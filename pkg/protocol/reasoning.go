@@ -0,0 +1,51 @@
+package protocol
+
+import (
+	"fmt"
+	"slices"
+)
+
+// ReasoningEffort controls how much reasoning a reasoning model performs
+// before it responds. Lower effort answers faster with fewer reasoning tokens.
+type ReasoningEffort string
+
+const (
+	// ReasoningNone disables reasoning on models that support it.
+	ReasoningNone ReasoningEffort = "none"
+
+	// ReasoningMinimal performs as little reasoning as possible.
+	ReasoningMinimal ReasoningEffort = "minimal"
+
+	// ReasoningLow favors speed and economical token usage.
+	ReasoningLow ReasoningEffort = "low"
+
+	// ReasoningMedium balances speed and reasoning depth. It is the usual model default.
+	ReasoningMedium ReasoningEffort = "medium"
+
+	// ReasoningHigh favors thorough reasoning.
+	ReasoningHigh ReasoningEffort = "high"
+)
+
+// ReasoningEfforts returns the supported reasoning efforts, from least to most reasoning.
+func ReasoningEfforts() []ReasoningEffort {
+	return []ReasoningEffort{ReasoningNone, ReasoningMinimal, ReasoningLow, ReasoningMedium, ReasoningHigh}
+}
+
+// ParseReasoningEffort converts a reasoning_effort option value to a ReasoningEffort.
+// Accepts a ReasoningEffort or a string. Returns an error for unknown efforts.
+func ParseReasoningEffort(v any) (ReasoningEffort, error) {
+	var effort ReasoningEffort
+	switch e := v.(type) {
+	case ReasoningEffort:
+		effort = e
+	case string:
+		effort = ReasoningEffort(e)
+	default:
+		return "", fmt.Errorf("reasoning_effort must be a string, got %T", v)
+	}
+
+	if !slices.Contains(ReasoningEfforts(), effort) {
+		return "", fmt.Errorf("unknown reasoning_effort %q (expected one of: %v)", effort, ReasoningEfforts())
+	}
+	return effort, nil
+}
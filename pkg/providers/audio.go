@@ -0,0 +1,251 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"path/filepath"
+	"slices"
+
+	"github.com/JaimeStill/go-agents/pkg/response"
+)
+
+// audioChunkSize is the size of the audio chunks emitted by streaming speech responses.
+const audioChunkSize = 4096
+
+// MultipartBoundary derives a multipart boundary from the content of an upload,
+// so identical uploads produce identical request bodies and cache keys.
+func MultipartBoundary(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "goagents" + hex.EncodeToString(sum[:12])
+}
+
+// MultipartContentType returns the multipart/form-data content type for a boundary.
+func MultipartContentType(boundary string) string {
+	return "multipart/form-data; boundary=" + boundary
+}
+
+func (p *BaseProvider) marshalTranscription(data any) ([]byte, error) {
+	d, ok := data.(*TranscriptionData)
+	if !ok {
+		return nil, fmt.Errorf("expected *TranscriptionData, got %T", data)
+	}
+
+	if len(d.Audio) == 0 {
+		return nil, fmt.Errorf("audio cannot be empty for transcription requests")
+	}
+
+	if err := validateTranscription(d); err != nil {
+		return nil, err
+	}
+
+	boundary := d.Boundary
+	if boundary == "" {
+		boundary = MultipartBoundary(d.Audio)
+	}
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	if err := w.SetBoundary(boundary); err != nil {
+		return nil, fmt.Errorf("invalid multipart boundary: %w", err)
+	}
+
+	filename := d.Filename
+	if filename == "" {
+		filename = "audio"
+	}
+	contentType := mime.TypeByExtension(filepath.Ext(filename))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename=%q`, filepath.Base(filename)))
+	header.Set("Content-Type", contentType)
+	part, err := w.CreatePart(header)
+	if err != nil {
+		return nil, err
+	}
+	part.Write(d.Audio)
+
+	fields := [][2]string{
+		{"model", d.Model},
+		{"language", d.Language},
+		{"prompt", d.Prompt},
+		{"response_format", d.ResponseFormat},
+	}
+	for _, g := range d.TimestampGranularities {
+		fields = append(fields, [2]string{"timestamp_granularities[]", g})
+	}
+	for _, key := range slices.Sorted(maps.Keys(d.Options)) {
+		value, err := formValue(d.Options[key])
+		if err != nil {
+			return nil, fmt.Errorf("invalid option %s: %w", key, err)
+		}
+		fields = append(fields, [2]string{key, value})
+	}
+
+	for _, field := range fields {
+		if field[1] == "" {
+			continue
+		}
+		if err := w.WriteField(field[0], field[1]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return body.Bytes(), nil
+}
+
+func (p *BaseProvider) marshalSpeech(data any) ([]byte, error) {
+	d, ok := data.(*SpeechData)
+	if !ok {
+		return nil, fmt.Errorf("expected *SpeechData, got %T", data)
+	}
+
+	if d.Input == "" {
+		return nil, fmt.Errorf("input cannot be empty for speech requests")
+	}
+
+	if err := validateSpeech(d); err != nil {
+		return nil, err
+	}
+
+	combined := make(map[string]any)
+	combined["model"] = d.Model
+	combined["input"] = d.Input
+	maps.Copy(combined, d.Options)
+	combined["voice"] = d.Voice
+
+	if d.ResponseFormat != "" {
+		combined["response_format"] = d.ResponseFormat
+	}
+	if d.Speed != 0 {
+		combined["speed"] = d.Speed
+	}
+
+	return json.Marshal(combined)
+}
+
+// validateTranscription checks the response format and timestamp granularities.
+// Timestamps are only returned in the verbose_json format.
+func validateTranscription(d *TranscriptionData) error {
+	switch d.ResponseFormat {
+	case "", "json", "verbose_json", "text", "srt", "vtt":
+	default:
+		return fmt.Errorf("unknown transcription response_format: %q", d.ResponseFormat)
+	}
+
+	for _, g := range d.TimestampGranularities {
+		if g != "word" && g != "segment" {
+			return fmt.Errorf("unknown timestamp granularity: %q", g)
+		}
+	}
+
+	if len(d.TimestampGranularities) > 0 && d.ResponseFormat != "verbose_json" {
+		return fmt.Errorf("timestamp_granularities require response_format \"verbose_json\"")
+	}
+
+	return nil
+}
+
+// validateSpeech checks the voice, audio format, and speed of a speech request.
+func validateSpeech(d *SpeechData) error {
+	if d.Voice == "" {
+		return fmt.Errorf("voice is required for speech requests")
+	}
+
+	switch d.ResponseFormat {
+	case "", "mp3", "opus", "aac", "flac", "wav", "pcm":
+	default:
+		return fmt.Errorf("unknown speech response_format: %q", d.ResponseFormat)
+	}
+
+	if d.Speed != 0 && (d.Speed < 0.25 || d.Speed > 4) {
+		return fmt.Errorf("speed must be between 0.25 and 4.0, got %v", d.Speed)
+	}
+
+	return nil
+}
+
+// formValue encodes an option as a multipart form field value.
+// Strings are sent as-is and other values as JSON.
+func formValue(v any) (string, error) {
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// processSpeech reads a binary speech response, keeping the audio content type
+// reported by the server.
+func processSpeech(resp *http.Response) (any, error) {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	speech, err := response.ParseSpeech(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" {
+		speech.ContentType = contentType
+	}
+
+	return speech, nil
+}
+
+// streamAudio emits a binary response body as chunks of raw audio as it arrives.
+// The channel is closed at the end of the body or when the context is cancelled.
+func streamAudio(ctx context.Context, resp *http.Response) <-chan any {
+	output := make(chan any)
+
+	go func() {
+		defer close(output)
+		defer resp.Body.Close()
+
+		buf := make([]byte, audioChunkSize)
+		for {
+			n, err := resp.Body.Read(buf)
+			if n > 0 {
+				chunk, _ := response.ParseSpeechStreamChunk(buf[:n])
+				select {
+				case output <- chunk:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				select {
+				case output <- &response.StreamingChunk{Error: err}:
+				case <-ctx.Done():
+				}
+				return
+			}
+		}
+	}()
+
+	return output
+}
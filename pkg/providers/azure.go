@@ -57,19 +57,72 @@ func NewAzure(c *config.ProviderConfig) (Provider, error) {
 	}, nil
 }
 
+// ValidateAzure checks that the options required by NewAzure are present
+// and that auth_type is a supported authentication method.
+func ValidateAzure(c *config.ProviderConfig) config.ValidationErrors {
+	errs := config.RequireOptions(c, "deployment", "auth_type", "token", "api_version")
+
+	if authType, ok := c.Options["auth_type"].(string); ok && authType != "" {
+		if authType != "bearer" && authType != "api_key" {
+			errs.Add("options.auth_type", "unknown auth_type %q (expected \"bearer\" or \"api_key\")", authType)
+		}
+	}
+
+	return errs
+}
+
+// AzureOptionsSchema describes the options accepted by NewAzure.
+// The token is not required because it is usually supplied at runtime
+// through a ${VAR} reference or the GOAGENTS_PROVIDER_OPTIONS_TOKEN overlay.
+func AzureOptionsSchema() *config.Schema {
+	return &config.Schema{
+		Type: "object",
+		Properties: map[string]*config.Schema{
+			"deployment":  {Type: "string", Description: "Azure OpenAI deployment name"},
+			"api_version": {Type: "string", Description: "Azure OpenAI API version (e.g. 2025-01-01-preview)"},
+			"auth_type":   {Type: "string", Enum: []any{"bearer", "api_key"}, Description: "Entra ID bearer token or API key authentication"},
+			"token":       {Type: "string", Description: "Bearer token or API key"},
+		},
+		Required: []string{"deployment", "api_version", "auth_type"},
+	}
+}
+
+// azureStrictToolsVersion is the first API version supporting strict tool schemas.
+const azureStrictToolsVersion = "2024-08-01"
+
+// Marshal converts request data to Azure's OpenAI-compatible JSON format.
+// Strict tool schemas require api_version 2024-08-01-preview or later.
+func (p *AzureProvider) Marshal(proto protocol.Protocol, data any) ([]byte, error) {
+	if d, ok := data.(*ToolsData); ok && hasStrictTool(d.Tools) && p.apiVersion < azureStrictToolsVersion {
+		return nil, fmt.Errorf("strict tool schemas require Azure api_version %s-preview or later, got %s", azureStrictToolsVersion, p.apiVersion)
+	}
+
+	return p.BaseProvider.Marshal(proto, data)
+}
+
 // Endpoint returns the full Azure OpenAI endpoint URL for a protocol.
 // Includes deployment name in path and api-version as query parameter.
 // Supports chat, vision, tools (all use /deployments/{deployment}/chat/completions),
-// and embeddings (/deployments/{deployment}/embeddings).
+// embeddings (/deployments/{deployment}/embeddings), image generation
+// (/deployments/{deployment}/images/generations), transcription
+// (/deployments/{deployment}/audio/transcriptions), and speech
+// (/deployments/{deployment}/audio/speech).
+// Rerank is not served by Azure OpenAI deployments; agents without a rerank
+// capability rank documents with the chat protocol instead. Moderation is not
+// served either: Azure screens requests with content filters, whose results
+// are parsed from chat and tools responses.
 // Returns an error if the protocol is not supported.
 func (p *AzureProvider) Endpoint(proto protocol.Protocol) (string, error) {
 	basePath := fmt.Sprintf("/deployments/%s", p.deployment)
 
 	endpoints := map[protocol.Protocol]string{
-		protocol.Chat:       basePath + "/chat/completions",
-		protocol.Vision:     basePath + "/chat/completions",
-		protocol.Tools:      basePath + "/chat/completions",
-		protocol.Embeddings: basePath + "/embeddings",
+		protocol.Chat:            basePath + "/chat/completions",
+		protocol.Vision:          basePath + "/chat/completions",
+		protocol.Tools:           basePath + "/chat/completions",
+		protocol.Embeddings:      basePath + "/embeddings",
+		protocol.ImageGeneration: basePath + "/images/generations",
+		protocol.Transcription:   basePath + "/audio/transcriptions",
+		protocol.Speech:          basePath + "/audio/speech",
 	}
 
 	endpoint, exists := endpoints[proto]
@@ -96,7 +149,8 @@ func (p *AzureProvider) PrepareRequest(ctx context.Context, proto protocol.Proto
 }
 
 // PrepareStreamRequest prepares a streaming Azure request.
-// Adds streaming-specific headers (Accept: text/event-stream, Cache-Control: no-cache).
+// Adds streaming-specific headers (Accept: text/event-stream, Cache-Control: no-cache),
+// except for speech, which streams raw audio rather than server-sent events.
 // Returns an error if the endpoint is invalid.
 func (p *AzureProvider) PrepareStreamRequest(ctx context.Context, proto protocol.Protocol, body []byte, headers map[string]string) (*Request, error) {
 	endpoint, err := p.Endpoint(proto)
@@ -107,8 +161,10 @@ func (p *AzureProvider) PrepareStreamRequest(ctx context.Context, proto protocol
 	// Clone headers to avoid mutating the original
 	streamHeaders := make(map[string]string)
 	maps.Copy(streamHeaders, headers)
-	streamHeaders["Accept"] = "text/event-stream"
-	streamHeaders["Cache-Control"] = "no-cache"
+	if proto != protocol.Speech {
+		streamHeaders["Accept"] = "text/event-stream"
+		streamHeaders["Cache-Control"] = "no-cache"
+	}
 
 	return &Request{
 		URL:     endpoint,
@@ -119,13 +175,17 @@ func (p *AzureProvider) PrepareStreamRequest(ctx context.Context, proto protocol
 
 // ProcessResponse processes a standard Azure HTTP response.
 // Returns an error if the HTTP status is not OK.
-// Uses response.Parse for protocol-aware parsing.
+// Uses response.Parse for protocol-aware parsing; speech responses are read as binary audio.
 func (p *AzureProvider) ProcessResponse(ctx context.Context, resp *http.Response, proto protocol.Protocol) (any, error) {
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(body))
 	}
 
+	if proto == protocol.Speech {
+		return processSpeech(resp)
+	}
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response: %w", err)
@@ -136,7 +196,7 @@ func (p *AzureProvider) ProcessResponse(ctx context.Context, resp *http.Response
 
 // ProcessStreamResponse processes a streaming Azure HTTP response with SSE format.
 // Azure uses "data: " prefix for server-sent events.
-// Returns a channel that emits parsed streaming chunks.
+// Returns a channel that emits parsed streaming chunks, or raw audio chunks for speech.
 // The channel is closed when the stream completes or context is cancelled.
 // Returns an error if the HTTP status is not OK.
 func (p *AzureProvider) ProcessStreamResponse(ctx context.Context, resp *http.Response, proto protocol.Protocol) (<-chan any, error) {
@@ -145,6 +205,10 @@ func (p *AzureProvider) ProcessStreamResponse(ctx context.Context, resp *http.Re
 		return nil, fmt.Errorf("request failed with status %d", resp.StatusCode)
 	}
 
+	if proto == protocol.Speech {
+		return streamAudio(ctx, resp), nil
+	}
+
 	output := make(chan any)
 
 	go func() {
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"maps"
+	"slices"
 
 	"github.com/JaimeStill/go-agents/pkg/protocol"
 )
@@ -40,6 +41,9 @@ func (p *BaseProvider) BaseURL() string {
 // Marshal converts request data to OpenAI-compatible JSON format.
 // This default implementation works for OpenAI, Azure, and Ollama providers.
 // Providers with different wire formats (Anthropic, Google) should override this method.
+// Chat, vision, and tools requests carrying Reasoning send max_tokens as
+// max_completion_tokens, system messages with the reasoning system role,
+// and the reasoning effort.
 func (p *BaseProvider) Marshal(proto protocol.Protocol, data any) ([]byte, error) {
 	switch proto {
 	case protocol.Chat:
@@ -50,6 +54,16 @@ func (p *BaseProvider) Marshal(proto protocol.Protocol, data any) ([]byte, error
 		return p.marshalTools(data)
 	case protocol.Embeddings:
 		return p.marshalEmbeddings(data)
+	case protocol.ImageGeneration:
+		return p.marshalImageGeneration(data)
+	case protocol.Transcription:
+		return p.marshalTranscription(data)
+	case protocol.Speech:
+		return p.marshalSpeech(data)
+	case protocol.Rerank:
+		return p.marshalRerank(data)
+	case protocol.Moderation:
+		return p.marshalModeration(data)
 	default:
 		return nil, fmt.Errorf("unsupported protocol: %s", proto)
 	}
@@ -61,10 +75,17 @@ func (p *BaseProvider) marshalChat(data any) ([]byte, error) {
 		return nil, fmt.Errorf("expected *ChatData, got %T", data)
 	}
 
+	if err := validateMessages(d.Messages); err != nil {
+		return nil, err
+	}
+
+	messages := outgoing(d.Messages)
+
 	combined := make(map[string]any)
 	combined["model"] = d.Model
-	combined["messages"] = d.Messages
+	combined["messages"] = messages
 	maps.Copy(combined, d.Options)
+	applyReasoning(combined, messages, d.Reasoning)
 	return json.Marshal(combined)
 }
 
@@ -78,25 +99,29 @@ func (p *BaseProvider) marshalVision(data any) ([]byte, error) {
 		return nil, fmt.Errorf("messages cannot be empty for vision requests")
 	}
 
-	if len(d.Images) == 0 {
+	if len(d.Images) == 0 && !hasImageParts(d.Messages) {
 		return nil, fmt.Errorf("images cannot be empty for vision requests")
 	}
 
+	if err := validateMessages(d.Messages); err != nil {
+		return nil, err
+	}
+
 	// Transform the last message to embed images
 	lastIdx := len(d.Messages) - 1
 	message := d.Messages[lastIdx]
 
-	var textContent string
+	// Build structured content starting with the message's own text or parts
+	var content []any
 	switch v := message.Content.(type) {
 	case string:
-		textContent = v
+		content = append(content, protocol.TextPart(v))
+	case []protocol.ContentPart:
+		for _, part := range v {
+			content = append(content, part)
+		}
 	default:
-		return nil, fmt.Errorf("message content must be a string for vision transformation")
-	}
-
-	// Build structured content starting with text
-	content := []map[string]any{
-		{"type": "text", "text": textContent},
+		return nil, fmt.Errorf("message content must be a string or []protocol.ContentPart for vision transformation")
 	}
 
 	// Add each image with embedded options
@@ -117,18 +142,15 @@ func (p *BaseProvider) marshalVision(data any) ([]byte, error) {
 	}
 
 	// Create transformed messages
-	transformedMessages := make([]protocol.Message, len(d.Messages))
-	copy(transformedMessages, d.Messages)
-	transformedMessages[lastIdx] = protocol.Message{
-		Role:    message.Role,
-		Content: content,
-	}
+	transformedMessages := outgoing(d.Messages)
+	transformedMessages[lastIdx].Content = content
 
 	// Combine model, messages, and options at root level
 	combined := make(map[string]any)
 	combined["model"] = d.Model
 	combined["messages"] = transformedMessages
 	maps.Copy(combined, d.Options)
+	applyReasoning(combined, transformedMessages, d.Reasoning)
 
 	return json.Marshal(combined)
 }
@@ -139,25 +161,48 @@ func (p *BaseProvider) marshalTools(data any) ([]byte, error) {
 		return nil, fmt.Errorf("expected *ToolsData, got %T", data)
 	}
 
+	if err := validateMessages(d.Messages); err != nil {
+		return nil, err
+	}
+
+	if err := validateTools(d); err != nil {
+		return nil, err
+	}
+
+	messages := outgoing(d.Messages)
+
 	combined := make(map[string]any)
 	combined["model"] = d.Model
-	combined["messages"] = d.Messages
+	combined["messages"] = messages
 
 	// Transform tools to OpenAI format: {"type": "function", "function": {...}}
 	openAITools := make([]map[string]any, len(d.Tools))
 	for i, tool := range d.Tools {
+		function := map[string]any{
+			"name":        tool.Name,
+			"description": tool.Description,
+			"parameters":  tool.Parameters,
+		}
+		if tool.Strict {
+			function["strict"] = true
+		}
 		openAITools[i] = map[string]any{
-			"type": "function",
-			"function": map[string]any{
-				"name":        tool.Name,
-				"description": tool.Description,
-				"parameters":  tool.Parameters,
-			},
+			"type":     "function",
+			"function": function,
 		}
 	}
 	combined["tools"] = openAITools
 
 	maps.Copy(combined, d.Options)
+	applyReasoning(combined, messages, d.Reasoning)
+
+	if d.ToolChoice != nil {
+		combined["tool_choice"] = openAIToolChoice(d.ToolChoice)
+	}
+	if d.ParallelToolCalls != nil {
+		combined["parallel_tool_calls"] = *d.ParallelToolCalls
+	}
+
 	return json.Marshal(combined)
 }
 
@@ -173,3 +218,119 @@ func (p *BaseProvider) marshalEmbeddings(data any) ([]byte, error) {
 	maps.Copy(combined, d.Options)
 	return json.Marshal(combined)
 }
+
+func (p *BaseProvider) marshalModeration(data any) ([]byte, error) {
+	d, ok := data.(*ModerationData)
+	if !ok {
+		return nil, fmt.Errorf("expected *ModerationData, got %T", data)
+	}
+
+	switch input := d.Input.(type) {
+	case string:
+		if input == "" {
+			return nil, fmt.Errorf("input cannot be empty for moderation requests")
+		}
+	case []string:
+		if len(input) == 0 {
+			return nil, fmt.Errorf("input cannot be empty for moderation requests")
+		}
+	default:
+		return nil, fmt.Errorf("moderation input must be a string or []string, got %T", d.Input)
+	}
+
+	combined := make(map[string]any)
+	if d.Model != "" {
+		combined["model"] = d.Model
+	}
+	combined["input"] = d.Input
+	maps.Copy(combined, d.Options)
+	return json.Marshal(combined)
+}
+
+func (p *BaseProvider) marshalImageGeneration(data any) ([]byte, error) {
+	d, ok := data.(*ImageGenerationData)
+	if !ok {
+		return nil, fmt.Errorf("expected *ImageGenerationData, got %T", data)
+	}
+
+	if d.Prompt == "" {
+		return nil, fmt.Errorf("prompt cannot be empty for image generation requests")
+	}
+
+	combined := make(map[string]any)
+	combined["model"] = d.Model
+	combined["prompt"] = d.Prompt
+	maps.Copy(combined, d.Options)
+
+	if d.Size != "" {
+		combined["size"] = d.Size
+	}
+	if d.Count > 0 {
+		combined["n"] = d.Count
+	}
+	if d.Quality != "" {
+		combined["quality"] = d.Quality
+	}
+	if d.ResponseFormat != "" {
+		combined["response_format"] = d.ResponseFormat
+	}
+
+	return json.Marshal(combined)
+}
+
+// validateMessages checks every content part and the tool call fields of messages.
+func validateMessages(messages []protocol.Message) error {
+	for i, msg := range messages {
+		if msg.Role == "tool" && msg.ToolCallID == "" {
+			return fmt.Errorf("message %d: tool messages require a tool_call_id", i)
+		}
+
+		for j, call := range msg.ToolCalls {
+			if call.ID == "" || call.Function.Name == "" {
+				return fmt.Errorf("message %d tool call %d: id and function name are required", i, j)
+			}
+		}
+
+		parts, ok := msg.Content.([]protocol.ContentPart)
+		if !ok {
+			continue
+		}
+		for j, part := range parts {
+			if err := part.Validate(); err != nil {
+				return fmt.Errorf("message %d part %d: %w", i, j, err)
+			}
+		}
+	}
+	return nil
+}
+
+// outgoing returns messages prepared for a request body. Reasoning returned
+// by the model is kept in history but not sent back to the provider, and
+// tool calls default to type "function".
+func outgoing(messages []protocol.Message) []protocol.Message {
+	result := make([]protocol.Message, len(messages))
+	for i, msg := range messages {
+		msg.Reasoning = ""
+		if len(msg.ToolCalls) > 0 {
+			calls := slices.Clone(msg.ToolCalls)
+			for j := range calls {
+				if calls[j].Type == "" {
+					calls[j].Type = "function"
+				}
+			}
+			msg.ToolCalls = calls
+		}
+		result[i] = msg
+	}
+	return result
+}
+
+// hasImageParts reports whether any message carries an image content part.
+func hasImageParts(messages []protocol.Message) bool {
+	return slices.ContainsFunc(messages, func(msg protocol.Message) bool {
+		parts, _ := msg.Content.([]protocol.ContentPart)
+		return slices.ContainsFunc(parts, func(p protocol.ContentPart) bool {
+			return p.Type == protocol.ContentImageURL
+		})
+	})
+}
@@ -7,6 +7,9 @@ type ChatData struct {
 	Model    string
 	Messages []protocol.Message
 	Options  map[string]any
+
+	// Reasoning adapts the request to a reasoning model. Nil for other models.
+	Reasoning *Reasoning
 }
 
 // VisionData contains the data needed to marshal a vision request.
@@ -16,6 +19,9 @@ type VisionData struct {
 	Images        []string
 	VisionOptions map[string]any
 	Options       map[string]any
+
+	// Reasoning adapts the request to a reasoning model. Nil for other models.
+	Reasoning *Reasoning
 }
 
 // ToolsData contains the data needed to marshal a tools request.
@@ -23,7 +29,19 @@ type ToolsData struct {
 	Model    string
 	Messages []protocol.Message
 	Tools    []ToolDefinition
-	Options  map[string]any
+
+	// ToolChoice controls whether and which tools the model calls.
+	// Nil uses the provider default (auto).
+	ToolChoice *ToolChoice
+
+	// ParallelToolCalls enables or disables multiple tool calls in one turn.
+	// Nil uses the provider default.
+	ParallelToolCalls *bool
+
+	// Reasoning adapts the request to a reasoning model. Nil for other models.
+	Reasoning *Reasoning
+
+	Options map[string]any
 }
 
 // ToolDefinition represents a provider-agnostic tool (function) definition.
@@ -33,6 +51,10 @@ type ToolDefinition struct {
 	Name        string         `json:"name"`
 	Description string         `json:"description"`
 	Parameters  map[string]any `json:"parameters"` // JSON Schema
+
+	// Strict requests exact schema adherence for the function's arguments.
+	// The schema must be an object with "additionalProperties": false.
+	Strict bool `json:"strict,omitempty"`
 }
 
 // EmbeddingsData contains the data needed to marshal an embeddings request.
@@ -41,3 +63,100 @@ type EmbeddingsData struct {
 	Input   any // string or []string for batch embeddings
 	Options map[string]any
 }
+
+// ImageGenerationData contains the data needed to marshal an image generation request.
+type ImageGenerationData struct {
+	Model  string
+	Prompt string
+
+	// Size is the image dimensions, such as "1024x1024". Empty uses the provider default.
+	Size string
+
+	// Count is the number of images to generate. Zero uses the provider default (1).
+	Count int
+
+	// Quality is the rendering quality, such as "standard", "hd", "low", or "high".
+	Quality string
+
+	// ResponseFormat is "url" or "b64_json". Empty uses the provider default.
+	ResponseFormat string
+
+	Options map[string]any
+}
+
+// TranscriptionData contains the data needed to marshal a transcription request.
+// Transcription requests are sent as multipart/form-data uploads.
+type TranscriptionData struct {
+	Model string
+
+	// Audio is the recording to transcribe.
+	Audio []byte
+
+	// Filename names the upload. Servers use its extension to detect the audio format.
+	Filename string
+
+	// Language is the ISO-639-1 language of the audio, such as "en". Empty lets the model detect it.
+	Language string
+
+	// Prompt guides the transcription style or continues a previous segment.
+	Prompt string
+
+	// ResponseFormat is "json", "verbose_json", "text", "srt", or "vtt". Empty uses the provider default (json).
+	ResponseFormat string
+
+	// TimestampGranularities requests "segment" and/or "word" timestamps.
+	// Requires the verbose_json response format.
+	TimestampGranularities []string
+
+	// Boundary is the multipart boundary. Requests set a boundary derived from
+	// their content so identical uploads produce identical bodies.
+	Boundary string
+
+	Options map[string]any
+}
+
+// SpeechData contains the data needed to marshal a speech request.
+type SpeechData struct {
+	Model string
+
+	// Input is the text to synthesize.
+	Input string
+
+	// Voice is the voice to speak with, such as "alloy".
+	Voice string
+
+	// ResponseFormat is the audio format: "mp3", "opus", "aac", "flac", "wav", or "pcm".
+	// Empty uses the provider default (mp3).
+	ResponseFormat string
+
+	// Speed is the playback speed from 0.25 to 4.0. Zero uses the provider default (1.0).
+	Speed float64
+
+	Options map[string]any
+}
+
+// ModerationData contains the data needed to marshal a moderation request.
+type ModerationData struct {
+	Model   string
+	Input   any // string or []string for batch moderation
+	Options map[string]any
+}
+
+// RerankData contains the data needed to marshal a rerank request.
+type RerankData struct {
+	Model string
+
+	// Query is the search query documents are ranked against.
+	Query string
+
+	// Documents are the candidate texts to rank.
+	Documents []string
+
+	// TopN limits the results to the most relevant documents. Zero returns every document.
+	TopN int
+
+	// ReturnDocuments requests the document text alongside each result.
+	ReturnDocuments bool
+
+	Options map[string]any
+}
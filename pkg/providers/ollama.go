@@ -18,33 +18,54 @@ import (
 // Supports local and remote Ollama instances with optional authentication.
 type OllamaProvider struct {
 	*BaseProvider
-	options map[string]any
+	options      map[string]any
+	rerankFormat string
 }
 
 // NewOllama creates a new OllamaProvider from configuration.
 // Automatically adds /v1 suffix to base URL if not present for OpenAI compatibility.
 // Supports optional authentication via "auth_type" and "token" options.
+// The "rerank_format" option selects the rerank wire format served by the
+// endpoint: "cohere" (default) or "tei".
 func NewOllama(c *config.ProviderConfig) (Provider, error) {
 	baseURL := c.BaseURL
 	if !strings.HasSuffix(baseURL, "/v1") {
 		baseURL = strings.TrimSuffix(baseURL, "/") + "/v1"
 	}
 
+	rerankFormat := RerankFormatCohere
+	if format, ok := c.Options["rerank_format"].(string); ok && format != "" {
+		if format != RerankFormatCohere && format != RerankFormatTEI {
+			return nil, fmt.Errorf("unknown rerank_format %q (expected %q or %q)", format, RerankFormatCohere, RerankFormatTEI)
+		}
+		rerankFormat = format
+	}
+
 	return &OllamaProvider{
 		BaseProvider: NewBaseProvider(c.Name, baseURL),
 		options:      c.Options,
+		rerankFormat: rerankFormat,
 	}, nil
 }
 
 // Endpoint returns the full Ollama endpoint URL for a protocol.
-// Supports chat, vision, tools (all use /chat/completions), and embeddings (/embeddings).
+// Supports chat, vision, tools (all use /chat/completions), embeddings (/embeddings),
+// and, on OpenAI-compatible servers, image generation (/images/generations),
+// transcription (/audio/transcriptions), speech (/audio/speech), moderation
+// (/moderations), and rerank (/rerank, or the root /rerank path for the tei
+// rerank format).
 // Returns an error if the protocol is not supported.
 func (p *OllamaProvider) Endpoint(proto protocol.Protocol) (string, error) {
 	endpoints := map[protocol.Protocol]string{
-		protocol.Chat:       "/chat/completions",
-		protocol.Vision:     "/chat/completions",
-		protocol.Tools:      "/chat/completions",
-		protocol.Embeddings: "/embeddings",
+		protocol.Chat:            "/chat/completions",
+		protocol.Vision:          "/chat/completions",
+		protocol.Tools:           "/chat/completions",
+		protocol.Embeddings:      "/embeddings",
+		protocol.ImageGeneration: "/images/generations",
+		protocol.Transcription:   "/audio/transcriptions",
+		protocol.Speech:          "/audio/speech",
+		protocol.Rerank:          "/rerank",
+		protocol.Moderation:      "/moderations",
 	}
 
 	endpoint, exists := endpoints[proto]
@@ -52,9 +73,62 @@ func (p *OllamaProvider) Endpoint(proto protocol.Protocol) (string, error) {
 		return "", fmt.Errorf("protocol %s not supported by Ollama", proto)
 	}
 
+	if proto == protocol.Rerank && p.rerankFormat == RerankFormatTEI {
+		return fmt.Sprintf("%s%s", strings.TrimSuffix(p.BaseURL(), "/v1"), endpoint), nil
+	}
+
 	return fmt.Sprintf("%s%s", p.BaseURL(), endpoint), nil
 }
 
+// Marshal converts request data to Ollama's OpenAI-compatible JSON format.
+// Ollama accepts text and image content parts; audio and file parts are rejected.
+// Ollama always lets the model choose its tool calls, so tool choices other
+// than auto, disabling parallel tool calls, and strict tool schemas are rejected.
+// Rerank requests use the wire format selected by the "rerank_format" option.
+func (p *OllamaProvider) Marshal(proto protocol.Protocol, data any) ([]byte, error) {
+	if proto == protocol.Rerank && p.rerankFormat == RerankFormatTEI {
+		return marshalTEIRerank(data)
+	}
+
+	var messages []protocol.Message
+	switch d := data.(type) {
+	case *ChatData:
+		messages = d.Messages
+	case *VisionData:
+		messages = d.Messages
+	case *ToolsData:
+		messages = d.Messages
+		if err := validateOllamaTools(d); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, msg := range messages {
+		parts, _ := msg.Content.([]protocol.ContentPart)
+		for _, part := range parts {
+			if part.Type == protocol.ContentAudio || part.Type == protocol.ContentFile {
+				return nil, fmt.Errorf("message %d: %s content parts not supported by Ollama", i, part.Type)
+			}
+		}
+	}
+
+	return p.BaseProvider.Marshal(proto, data)
+}
+
+// validateOllamaTools rejects tool controls the Ollama API does not support.
+func validateOllamaTools(d *ToolsData) error {
+	if c := d.ToolChoice; c != nil && c.Mode != ToolChoiceAuto {
+		return fmt.Errorf("tool_choice %q not supported by Ollama", c.Mode)
+	}
+	if d.ParallelToolCalls != nil && !*d.ParallelToolCalls {
+		return fmt.Errorf("disabling parallel_tool_calls not supported by Ollama")
+	}
+	if hasStrictTool(d.Tools) {
+		return fmt.Errorf("strict tool schemas not supported by Ollama")
+	}
+	return nil
+}
+
 // PrepareRequest prepares a standard (non-streaming) Ollama request.
 // Returns an error if the endpoint is invalid.
 func (p *OllamaProvider) PrepareRequest(ctx context.Context, proto protocol.Protocol, body []byte, headers map[string]string) (*Request, error) {
@@ -71,7 +145,8 @@ func (p *OllamaProvider) PrepareRequest(ctx context.Context, proto protocol.Prot
 }
 
 // PrepareStreamRequest prepares a streaming Ollama request.
-// Adds streaming-specific headers (Accept: text/event-stream, Cache-Control: no-cache).
+// Adds streaming-specific headers (Accept: text/event-stream, Cache-Control: no-cache),
+// except for speech, which streams raw audio rather than server-sent events.
 // Returns an error if the endpoint is invalid.
 func (p *OllamaProvider) PrepareStreamRequest(ctx context.Context, proto protocol.Protocol, body []byte, headers map[string]string) (*Request, error) {
 	endpoint, err := p.Endpoint(proto)
@@ -82,8 +157,10 @@ func (p *OllamaProvider) PrepareStreamRequest(ctx context.Context, proto protoco
 	// Clone headers to avoid mutating the original
 	streamHeaders := make(map[string]string)
 	maps.Copy(streamHeaders, headers)
-	streamHeaders["Accept"] = "text/event-stream"
-	streamHeaders["Cache-Control"] = "no-cache"
+	if proto != protocol.Speech {
+		streamHeaders["Accept"] = "text/event-stream"
+		streamHeaders["Cache-Control"] = "no-cache"
+	}
 
 	return &Request{
 		URL:     endpoint,
@@ -94,13 +171,17 @@ func (p *OllamaProvider) PrepareStreamRequest(ctx context.Context, proto protoco
 
 // ProcessResponse processes a standard Ollama HTTP response.
 // Returns an error if the HTTP status is not OK.
-// Uses response.Parse for protocol-aware parsing.
+// Uses response.Parse for protocol-aware parsing; speech responses are read as binary audio.
 func (p *OllamaProvider) ProcessResponse(ctx context.Context, resp *http.Response, proto protocol.Protocol) (any, error) {
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(body))
 	}
 
+	if proto == protocol.Speech {
+		return processSpeech(resp)
+	}
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response: %w", err)
@@ -111,7 +192,7 @@ func (p *OllamaProvider) ProcessResponse(ctx context.Context, resp *http.Respons
 
 // ProcessStreamResponse processes a streaming Ollama HTTP response.
 // Ollama uses SSE format with "data: " prefix.
-// Returns a channel that emits parsed streaming chunks.
+// Returns a channel that emits parsed streaming chunks, or raw audio chunks for speech.
 // The channel is closed when the stream completes or context is cancelled.
 // Returns an error if the HTTP status is not OK.
 func (p *OllamaProvider) ProcessStreamResponse(ctx context.Context, resp *http.Response, proto protocol.Protocol) (<-chan any, error) {
@@ -120,6 +201,10 @@ func (p *OllamaProvider) ProcessStreamResponse(ctx context.Context, resp *http.R
 		return nil, fmt.Errorf("request failed with status %d", resp.StatusCode)
 	}
 
+	if proto == protocol.Speech {
+		return streamAudio(ctx, resp), nil
+	}
+
 	output := make(chan any)
 
 	go func() {
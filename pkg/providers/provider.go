@@ -29,9 +29,11 @@ type Provider interface {
 	// This is called after the request is created but before it is executed.
 	SetHeaders(req *http.Request)
 
-	// Marshal converts request data to provider-specific JSON format.
-	// The data parameter should be *ChatData, *VisionData, *ToolsData, or *EmbeddingsData
-	// based on the protocol. Providers implement this to support their wire format.
+	// Marshal converts request data to the provider-specific wire format.
+	// The data parameter should be *ChatData, *VisionData, *ToolsData, *EmbeddingsData,
+	// *ImageGenerationData, *TranscriptionData, *SpeechData, *RerankData, or *ModerationData based on the protocol.
+	// Transcription data is marshaled as a multipart/form-data upload and all
+	// other data as JSON. Providers implement this to support their wire format.
 	// BaseProvider provides a default OpenAI-compatible implementation.
 	Marshal(p protocol.Protocol, data any) ([]byte, error)
 
@@ -44,11 +46,13 @@ type Provider interface {
 	PrepareStreamRequest(ctx context.Context, p protocol.Protocol, body []byte, headers map[string]string) (*Request, error)
 
 	// ProcessResponse processes a standard HTTP response and returns the parsed result.
-	// Uses response.Parse for protocol-aware parsing.
+	// Uses response.Parse for protocol-aware parsing. Speech responses are
+	// binary audio rather than JSON.
 	// Returns an error if the HTTP status is not OK or parsing fails.
 	ProcessResponse(ctx context.Context, resp *http.Response, p protocol.Protocol) (any, error)
 
 	// ProcessStreamResponse processes a streaming HTTP response and returns a channel of chunks.
+	// Speech streams are emitted as chunks of raw audio.
 	// The channel is closed when the stream completes or an error occurs.
 	// Context cancellation stops processing and closes the channel.
 	ProcessStreamResponse(ctx context.Context, resp *http.Response, p protocol.Protocol) (<-chan any, error)
@@ -60,9 +64,12 @@ type Request struct {
 	// URL is the complete endpoint URL including query parameters.
 	URL string
 
-	// Headers contains protocol-specific and provider-specific headers.
+	// Headers contains protocol-specific and provider-specific headers,
+	// including the Content-Type of Body.
 	Headers map[string]string
 
-	// Body is the marshaled request body ready for HTTP transmission.
+	// Body is the marshaled request body ready for HTTP transmission:
+	// JSON for most protocols, or a multipart/form-data upload carrying
+	// binary content such as transcription audio.
 	Body []byte
 }
@@ -0,0 +1,45 @@
+package providers
+
+import (
+	"cmp"
+
+	"github.com/JaimeStill/go-agents/pkg/protocol"
+)
+
+// Reasoning adapts a chat, vision, or tools request to a reasoning model.
+// Reasoning models take max_completion_tokens instead of max_tokens, which
+// also budgets their reasoning tokens, and expect system messages with the
+// developer role.
+type Reasoning struct {
+	// Effort is sent as reasoning_effort. Empty leaves it to the model.
+	Effort protocol.ReasoningEffort
+
+	// SystemRole is the role system messages are sent with. Defaults to "developer".
+	SystemRole string
+}
+
+// applyReasoning adapts a combined request body and its outgoing messages
+// to a reasoning model. Does nothing when r is nil.
+func applyReasoning(combined map[string]any, messages []protocol.Message, r *Reasoning) {
+	if r == nil {
+		return
+	}
+
+	role := cmp.Or(r.SystemRole, "developer")
+	for i := range messages {
+		if messages[i].Role == "system" {
+			messages[i].Role = role
+		}
+	}
+
+	if tokens, ok := combined["max_tokens"]; ok {
+		if _, set := combined["max_completion_tokens"]; !set {
+			combined["max_completion_tokens"] = tokens
+		}
+		delete(combined, "max_tokens")
+	}
+
+	if r.Effort != "" {
+		combined["reasoning_effort"] = r.Effort
+	}
+}
@@ -24,12 +24,20 @@ var register = &registry{
 }
 
 // Register registers a provider factory with the given name.
+// An optional validator is registered with config.RegisterProviderValidator
+// so ProviderConfig.Validate can check provider-specific settings.
+// Providers with options should also register an options schema with
+// config.RegisterProviderSchema for generated configuration schemas.
 // This should be called during package initialization to register custom providers.
 // Thread-safe for concurrent registration.
-func Register(name string, factory Factory) {
+func Register(name string, factory Factory, validator ...config.ProviderValidator) {
 	register.mu.Lock()
-	defer register.mu.Unlock()
 	register.factories[name] = factory
+	register.mu.Unlock()
+
+	if len(validator) > 0 && validator[0] != nil {
+		config.RegisterProviderValidator(name, validator[0])
+	}
 }
 
 // Create creates a Provider instance from configuration.
@@ -62,5 +70,6 @@ func ListProviders() []string {
 
 func init() {
 	Register("ollama", NewOllama)
-	Register("azure", NewAzure)
+	Register("azure", NewAzure, ValidateAzure)
+	config.RegisterProviderSchema("azure", AzureOptionsSchema())
 }
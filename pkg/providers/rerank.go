@@ -0,0 +1,83 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+)
+
+// Rerank wire formats selected with the "rerank_format" provider option.
+const (
+	// RerankFormatCohere is the Cohere-style body ({"query", "documents", "top_n"})
+	// also accepted by Jina, vLLM, and llama.cpp servers.
+	RerankFormatCohere = "cohere"
+
+	// RerankFormatTEI is the Hugging Face Text Embeddings Inference body
+	// ({"query", "texts", "return_text"}), served at the root /rerank path.
+	RerankFormatTEI = "tei"
+)
+
+func (p *BaseProvider) marshalRerank(data any) ([]byte, error) {
+	d, err := rerankData(data)
+	if err != nil {
+		return nil, err
+	}
+
+	combined := make(map[string]any)
+	combined["model"] = d.Model
+	combined["query"] = d.Query
+	combined["documents"] = d.Documents
+	maps.Copy(combined, d.Options)
+
+	if d.TopN > 0 {
+		combined["top_n"] = d.TopN
+	}
+	if d.ReturnDocuments {
+		combined["return_documents"] = true
+	}
+
+	return json.Marshal(combined)
+}
+
+// marshalTEIRerank converts rerank data to the Text Embeddings Inference format.
+// TEI serves a single model and always scores every document, so the model
+// and top_n are not sent; results are limited after parsing.
+func marshalTEIRerank(data any) ([]byte, error) {
+	d, err := rerankData(data)
+	if err != nil {
+		return nil, err
+	}
+
+	combined := make(map[string]any)
+	combined["query"] = d.Query
+	combined["texts"] = d.Documents
+	maps.Copy(combined, d.Options)
+
+	if d.ReturnDocuments {
+		combined["return_text"] = true
+	}
+
+	return json.Marshal(combined)
+}
+
+// rerankData asserts and validates rerank request data.
+func rerankData(data any) (*RerankData, error) {
+	d, ok := data.(*RerankData)
+	if !ok {
+		return nil, fmt.Errorf("expected *RerankData, got %T", data)
+	}
+
+	if d.Query == "" {
+		return nil, fmt.Errorf("query cannot be empty for rerank requests")
+	}
+
+	if len(d.Documents) == 0 {
+		return nil, fmt.Errorf("documents cannot be empty for rerank requests")
+	}
+
+	if d.TopN < 0 {
+		return nil, fmt.Errorf("top_n must be positive, got %d", d.TopN)
+	}
+
+	return d, nil
+}
@@ -0,0 +1,159 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ToolChoiceMode controls whether and how the model calls tools.
+type ToolChoiceMode string
+
+const (
+	// ToolChoiceAuto lets the model decide whether to call tools.
+	ToolChoiceAuto ToolChoiceMode = "auto"
+
+	// ToolChoiceNone prevents the model from calling tools.
+	ToolChoiceNone ToolChoiceMode = "none"
+
+	// ToolChoiceRequired requires the model to call at least one tool.
+	ToolChoiceRequired ToolChoiceMode = "required"
+
+	// ToolChoiceFunction requires the model to call a specific function.
+	ToolChoiceFunction ToolChoiceMode = "function"
+)
+
+// ToolChoice selects how the model uses the tools offered in a request.
+// Function names the required function when Mode is ToolChoiceFunction.
+type ToolChoice struct {
+	Mode     ToolChoiceMode
+	Function string
+}
+
+// ToolChoiceFor creates a ToolChoice requiring the model to call the named function.
+func ToolChoiceFor(name string) *ToolChoice {
+	return &ToolChoice{Mode: ToolChoiceFunction, Function: name}
+}
+
+// ParseToolChoice converts a tool_choice option value to a ToolChoice.
+// Accepts a ToolChoice or *ToolChoice, a mode string ("auto", "none",
+// "required"), or the OpenAI object form {"type": "function", "function": {"name": ...}}.
+// Returns nil for a nil value.
+func ParseToolChoice(v any) (*ToolChoice, error) {
+	switch c := v.(type) {
+	case nil:
+		return nil, nil
+	case *ToolChoice:
+		if c == nil {
+			return nil, nil
+		}
+		return c, c.Validate()
+	case ToolChoice:
+		return &c, c.Validate()
+	case ToolChoiceMode:
+		return ParseToolChoice(string(c))
+	case string:
+		choice := &ToolChoice{Mode: ToolChoiceMode(c)}
+		if choice.Mode == ToolChoiceFunction {
+			return nil, fmt.Errorf("tool_choice %q requires a function name", c)
+		}
+		return choice, choice.Validate()
+	case map[string]any:
+		data, err := json.Marshal(c)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tool_choice: %w", err)
+		}
+		var obj struct {
+			Type     string `json:"type"`
+			Function struct {
+				Name string `json:"name"`
+			} `json:"function"`
+		}
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return nil, fmt.Errorf("invalid tool_choice: %w", err)
+		}
+		if obj.Type != "function" {
+			return nil, fmt.Errorf("unsupported tool_choice type: %q", obj.Type)
+		}
+		choice := ToolChoiceFor(obj.Function.Name)
+		return choice, choice.Validate()
+	default:
+		return nil, fmt.Errorf("tool_choice must be a string, object, or ToolChoice, got %T", v)
+	}
+}
+
+// Validate checks that the mode is known and that a function choice names a function.
+func (c *ToolChoice) Validate() error {
+	switch c.Mode {
+	case ToolChoiceAuto, ToolChoiceNone, ToolChoiceRequired:
+		return nil
+	case ToolChoiceFunction:
+		if c.Function == "" {
+			return fmt.Errorf("tool_choice function requires a name")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown tool_choice mode: %q", c.Mode)
+	}
+}
+
+// validateTools checks the tool controls of d against its tool definitions.
+// A function choice must name a defined tool, and strict tools must declare
+// an object schema that disallows additional properties.
+func validateTools(d *ToolsData) error {
+	if c := d.ToolChoice; c != nil {
+		if err := c.Validate(); err != nil {
+			return err
+		}
+		if c.Mode == ToolChoiceRequired && len(d.Tools) == 0 {
+			return fmt.Errorf("tool_choice %q requires at least one tool", c.Mode)
+		}
+		if c.Mode == ToolChoiceFunction && !hasTool(d.Tools, c.Function) {
+			return fmt.Errorf("tool_choice function %q is not one of the request's tools", c.Function)
+		}
+	}
+
+	for _, tool := range d.Tools {
+		if !tool.Strict {
+			continue
+		}
+		if tool.Parameters["type"] != "object" {
+			return fmt.Errorf("strict tool %q requires an object parameters schema", tool.Name)
+		}
+		if tool.Parameters["additionalProperties"] != false {
+			return fmt.Errorf("strict tool %q requires \"additionalProperties\": false", tool.Name)
+		}
+	}
+
+	return nil
+}
+
+// hasTool reports whether tools defines a function with the given name.
+func hasTool(tools []ToolDefinition, name string) bool {
+	for _, tool := range tools {
+		if tool.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// hasStrictTool reports whether any tool requests strict schema enforcement.
+func hasStrictTool(tools []ToolDefinition) bool {
+	for _, tool := range tools {
+		if tool.Strict {
+			return true
+		}
+	}
+	return false
+}
+
+// openAIToolChoice encodes a ToolChoice in the OpenAI wire format.
+func openAIToolChoice(c *ToolChoice) any {
+	if c.Mode == ToolChoiceFunction {
+		return map[string]any{
+			"type":     "function",
+			"function": map[string]any{"name": c.Function},
+		}
+	}
+	return string(c.Mode)
+}
@@ -0,0 +1,219 @@
+package request
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/JaimeStill/go-agents/pkg/model"
+	"github.com/JaimeStill/go-agents/pkg/protocol"
+	"github.com/JaimeStill/go-agents/pkg/providers"
+)
+
+// TranscriptionRequest represents a transcription protocol request.
+// Separates the audio upload (protocol data) from model configuration options.
+// The request is sent as multipart/form-data rather than JSON.
+type TranscriptionRequest struct {
+	filename string
+	audio    []byte
+	options  map[string]any
+	provider providers.Provider
+	model    *model.Model
+}
+
+// NewTranscription creates a new TranscriptionRequest with the given components.
+// Filename names the upload; its extension identifies the audio format (mp3, wav, m4a, ...).
+// Options specify transcription settings: "language", "prompt", "response_format",
+// "timestamp_granularities", and any provider-specific options such as "temperature".
+func NewTranscription(p providers.Provider, m *model.Model, filename string, audio []byte, opts map[string]any) *TranscriptionRequest {
+	return &TranscriptionRequest{
+		filename: filename,
+		audio:    audio,
+		options:  opts,
+		provider: p,
+		model:    m,
+	}
+}
+
+// Protocol returns the Transcription protocol identifier.
+func (r *TranscriptionRequest) Protocol() protocol.Protocol {
+	return protocol.Transcription
+}
+
+// Headers returns the HTTP headers for a transcription request.
+// The multipart boundary is derived from the audio, matching the body produced by Marshal.
+func (r *TranscriptionRequest) Headers() map[string]string {
+	return map[string]string{
+		"Content-Type": providers.MultipartContentType(providers.MultipartBoundary(r.audio)),
+	}
+}
+
+// Marshal delegates to the provider for provider-specific multipart formatting.
+// The "language", "prompt", "response_format", and "timestamp_granularities"
+// options are passed to the provider as typed TranscriptionData fields.
+func (r *TranscriptionRequest) Marshal() ([]byte, error) {
+	data := &providers.TranscriptionData{
+		Model:    r.model.Name,
+		Audio:    r.audio,
+		Filename: r.filename,
+		Boundary: providers.MultipartBoundary(r.audio),
+		Options:  maps.Clone(r.options),
+	}
+
+	if err := extractTranscriptionOptions(data); err != nil {
+		return nil, err
+	}
+
+	return r.provider.Marshal(protocol.Transcription, data)
+}
+
+// Provider returns the provider for this request.
+func (r *TranscriptionRequest) Provider() providers.Provider {
+	return r.provider
+}
+
+// Model returns the model for this request.
+func (r *TranscriptionRequest) Model() *model.Model {
+	return r.model
+}
+
+// SpeechRequest represents a speech protocol request.
+// Separates the text to synthesize (protocol data) from model configuration options.
+type SpeechRequest struct {
+	input    string
+	options  map[string]any
+	provider providers.Provider
+	model    *model.Model
+}
+
+// NewSpeech creates a new SpeechRequest with the given components.
+// Input is the text to synthesize.
+// Options specify synthesis settings: "voice", "response_format", "speed",
+// and any provider-specific options such as "instructions".
+func NewSpeech(p providers.Provider, m *model.Model, input string, opts map[string]any) *SpeechRequest {
+	return &SpeechRequest{
+		input:    input,
+		options:  opts,
+		provider: p,
+		model:    m,
+	}
+}
+
+// Protocol returns the Speech protocol identifier.
+func (r *SpeechRequest) Protocol() protocol.Protocol {
+	return protocol.Speech
+}
+
+// Headers returns the HTTP headers for a speech request.
+func (r *SpeechRequest) Headers() map[string]string {
+	return map[string]string{
+		"Content-Type": "application/json",
+	}
+}
+
+// Marshal delegates to the provider for provider-specific JSON formatting.
+// The "voice", "response_format", and "speed" options are passed to the
+// provider as typed SpeechData fields.
+func (r *SpeechRequest) Marshal() ([]byte, error) {
+	data := &providers.SpeechData{
+		Model:   r.model.Name,
+		Input:   r.input,
+		Options: maps.Clone(r.options),
+	}
+
+	if err := extractSpeechOptions(data); err != nil {
+		return nil, err
+	}
+
+	return r.provider.Marshal(protocol.Speech, data)
+}
+
+// Provider returns the provider for this request.
+func (r *SpeechRequest) Provider() providers.Provider {
+	return r.provider
+}
+
+// Model returns the model for this request.
+func (r *SpeechRequest) Model() *model.Model {
+	return r.model
+}
+
+// extractTranscriptionOptions moves the typed transcription options into their fields.
+func extractTranscriptionOptions(data *providers.TranscriptionData) error {
+	if err := extractStrings(data.Options, map[string]*string{
+		"language":        &data.Language,
+		"prompt":          &data.Prompt,
+		"response_format": &data.ResponseFormat,
+	}); err != nil {
+		return err
+	}
+
+	v, ok := data.Options["timestamp_granularities"]
+	if !ok {
+		return nil
+	}
+	delete(data.Options, "timestamp_granularities")
+
+	switch g := v.(type) {
+	case nil:
+	case string:
+		data.TimestampGranularities = []string{g}
+	case []string:
+		data.TimestampGranularities = g
+	case []any:
+		for _, item := range g {
+			s, ok := item.(string)
+			if !ok {
+				return fmt.Errorf("timestamp_granularities must contain strings, got %T", item)
+			}
+			data.TimestampGranularities = append(data.TimestampGranularities, s)
+		}
+	default:
+		return fmt.Errorf("timestamp_granularities must be a list of strings, got %T", v)
+	}
+
+	return nil
+}
+
+// extractSpeechOptions moves the typed speech options into their fields.
+func extractSpeechOptions(data *providers.SpeechData) error {
+	if err := extractStrings(data.Options, map[string]*string{
+		"voice":           &data.Voice,
+		"response_format": &data.ResponseFormat,
+	}); err != nil {
+		return err
+	}
+
+	v, ok := data.Options["speed"]
+	if !ok {
+		return nil
+	}
+	delete(data.Options, "speed")
+
+	switch s := v.(type) {
+	case float64:
+		data.Speed = s
+	case int:
+		data.Speed = float64(s)
+	default:
+		return fmt.Errorf("speed must be a number, got %T", v)
+	}
+
+	return nil
+}
+
+// extractStrings moves string options into their fields, removing them from options.
+func extractStrings(options map[string]any, fields map[string]*string) error {
+	for key, field := range fields {
+		v, ok := options[key]
+		if !ok {
+			continue
+		}
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%s must be a string, got %T", key, v)
+		}
+		*field = s
+		delete(options, key)
+	}
+	return nil
+}
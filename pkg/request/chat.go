@@ -41,11 +41,19 @@ func (r *ChatRequest) Headers() map[string]string {
 }
 
 // Marshal delegates to the provider for provider-specific JSON formatting.
+// Requests to reasoning models carry the model's reasoning settings, with
+// the "reasoning_effort" option overriding its default effort.
 func (r *ChatRequest) Marshal() ([]byte, error) {
+	reasoning, options, err := extractReasoning(r.model, r.options)
+	if err != nil {
+		return nil, err
+	}
+
 	return r.provider.Marshal(protocol.Chat, &providers.ChatData{
-		Model:    r.model.Name,
-		Messages: r.messages,
-		Options:  r.options,
+		Model:     r.model.Name,
+		Messages:  r.messages,
+		Options:   options,
+		Reasoning: reasoning,
 	})
 }
 
@@ -11,4 +11,9 @@
 //	visionReq := request.NewVision(provider, model, messages, images, visionOpts, options)
 //	toolsReq := request.NewTools(provider, model, messages, tools, options)
 //	embeddingsReq := request.NewEmbeddings(provider, model, input, options)
+//	imageReq := request.NewImageGeneration(provider, model, prompt, options)
+//	transcriptionReq := request.NewTranscription(provider, model, "meeting.mp3", audio, options)
+//	speechReq := request.NewSpeech(provider, model, text, options)
+//	rerankReq := request.NewRerank(provider, model, query, documents, options)
+//	moderationReq := request.NewModeration(provider, model, input, options)
 package request
@@ -0,0 +1,128 @@
+package request
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/JaimeStill/go-agents/pkg/model"
+	"github.com/JaimeStill/go-agents/pkg/protocol"
+	"github.com/JaimeStill/go-agents/pkg/providers"
+)
+
+// ImageGenerationRequest represents an image generation protocol request.
+// Separates the prompt (protocol data) from model configuration options.
+type ImageGenerationRequest struct {
+	prompt   string
+	options  map[string]any
+	provider providers.Provider
+	model    *model.Model
+}
+
+// NewImageGeneration creates a new ImageGenerationRequest with the given components.
+// Prompt describes the image to generate.
+// Options specify generation settings: "size", "n", "quality", "response_format",
+// and any provider-specific options such as "style".
+func NewImageGeneration(p providers.Provider, m *model.Model, prompt string, opts map[string]any) *ImageGenerationRequest {
+	return &ImageGenerationRequest{
+		prompt:   prompt,
+		options:  opts,
+		provider: p,
+		model:    m,
+	}
+}
+
+// Protocol returns the ImageGeneration protocol identifier.
+func (r *ImageGenerationRequest) Protocol() protocol.Protocol {
+	return protocol.ImageGeneration
+}
+
+// Headers returns the HTTP headers for an image generation request.
+func (r *ImageGenerationRequest) Headers() map[string]string {
+	return map[string]string{
+		"Content-Type": "application/json",
+	}
+}
+
+// Marshal delegates to the provider for provider-specific JSON formatting.
+// The "size", "n", "quality", and "response_format" options are passed to
+// the provider as typed ImageGenerationData fields.
+func (r *ImageGenerationRequest) Marshal() ([]byte, error) {
+	data := &providers.ImageGenerationData{
+		Model:   r.model.Name,
+		Prompt:  r.prompt,
+		Options: maps.Clone(r.options),
+	}
+
+	if err := extractImageOptions(data); err != nil {
+		return nil, err
+	}
+
+	return r.provider.Marshal(protocol.ImageGeneration, data)
+}
+
+// Provider returns the provider for this request.
+func (r *ImageGenerationRequest) Provider() providers.Provider {
+	return r.provider
+}
+
+// Model returns the model for this request.
+func (r *ImageGenerationRequest) Model() *model.Model {
+	return r.model
+}
+
+// Count returns the number of images requested, defaulting to 1.
+func (r *ImageGenerationRequest) Count() int {
+	if n, err := positiveInt("n", r.options["n"]); err == nil && n > 0 {
+		return n
+	}
+	return 1
+}
+
+// extractImageOptions moves the typed generation options into their fields.
+func extractImageOptions(data *providers.ImageGenerationData) error {
+	if err := extractStrings(data.Options, map[string]*string{
+		"size":            &data.Size,
+		"quality":         &data.Quality,
+		"response_format": &data.ResponseFormat,
+	}); err != nil {
+		return err
+	}
+
+	if v, ok := data.Options["n"]; ok {
+		n, err := positiveInt("n", v)
+		if err != nil {
+			return err
+		}
+		data.Count = n
+		delete(data.Options, "n")
+	}
+
+	if f := data.ResponseFormat; f != "" && f != "url" && f != "b64_json" {
+		return fmt.Errorf("response_format must be \"url\" or \"b64_json\", got %q", f)
+	}
+
+	return nil
+}
+
+// positiveInt converts a count option value, which is a float64 when loaded
+// from JSON configuration, to a positive integer.
+func positiveInt(name string, v any) (int, error) {
+	var n int
+	switch c := v.(type) {
+	case int:
+		n = c
+	case int64:
+		n = int(c)
+	case float64:
+		if c != float64(int(c)) {
+			return 0, fmt.Errorf("%s must be a whole number, got %v", name, c)
+		}
+		n = int(c)
+	default:
+		return 0, fmt.Errorf("%s must be a number, got %T", name, v)
+	}
+	if n < 1 {
+		return 0, fmt.Errorf("%s must be at least 1, got %d", name, n)
+	}
+	return n, nil
+}
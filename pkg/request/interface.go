@@ -16,7 +16,8 @@ type Request interface {
 	// Headers returns the HTTP headers for this request.
 	Headers() map[string]string
 
-	// Marshal converts the request to JSON bytes.
+	// Marshal converts the request to its body bytes: JSON, or multipart/form-data
+	// for uploads. Headers carries the matching Content-Type.
 	Marshal() ([]byte, error)
 
 	// Provider returns the provider for this request.
@@ -0,0 +1,59 @@
+package request
+
+import (
+	"github.com/JaimeStill/go-agents/pkg/model"
+	"github.com/JaimeStill/go-agents/pkg/protocol"
+	"github.com/JaimeStill/go-agents/pkg/providers"
+)
+
+// ModerationRequest represents a moderation protocol request.
+// Separates input text (protocol data) from model configuration options.
+type ModerationRequest struct {
+	input    any // string or []string for batch moderation
+	options  map[string]any
+	provider providers.Provider
+	model    *model.Model
+}
+
+// NewModeration creates a new ModerationRequest with the given components.
+// Input is the text to classify (string or []string for batch).
+// Options specify provider-specific moderation settings.
+func NewModeration(p providers.Provider, m *model.Model, input any, opts map[string]any) *ModerationRequest {
+	return &ModerationRequest{
+		input:    input,
+		options:  opts,
+		provider: p,
+		model:    m,
+	}
+}
+
+// Protocol returns the Moderation protocol identifier.
+func (r *ModerationRequest) Protocol() protocol.Protocol {
+	return protocol.Moderation
+}
+
+// Headers returns the HTTP headers for a moderation request.
+func (r *ModerationRequest) Headers() map[string]string {
+	return map[string]string{
+		"Content-Type": "application/json",
+	}
+}
+
+// Marshal delegates to the provider for provider-specific JSON formatting.
+func (r *ModerationRequest) Marshal() ([]byte, error) {
+	return r.provider.Marshal(protocol.Moderation, &providers.ModerationData{
+		Model:   r.model.Name,
+		Input:   r.input,
+		Options: r.options,
+	})
+}
+
+// Provider returns the provider for this request.
+func (r *ModerationRequest) Provider() providers.Provider {
+	return r.provider
+}
+
+// Model returns the model for this request.
+func (r *ModerationRequest) Model() *model.Model {
+	return r.model
+}
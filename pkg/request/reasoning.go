@@ -0,0 +1,41 @@
+package request
+
+import (
+	"maps"
+
+	"github.com/JaimeStill/go-agents/pkg/model"
+	"github.com/JaimeStill/go-agents/pkg/protocol"
+	"github.com/JaimeStill/go-agents/pkg/providers"
+)
+
+// extractReasoning returns the reasoning settings for a request to m and the
+// options without "reasoning_effort", which overrides the model's default effort.
+// Returns nil settings and the options unmodified when m is not a reasoning model.
+func extractReasoning(m *model.Model, options map[string]any) (*providers.Reasoning, map[string]any, error) {
+	if m.Reasoning == nil {
+		return nil, options, nil
+	}
+
+	reasoning := &providers.Reasoning{
+		Effort:     m.Reasoning.Effort,
+		SystemRole: m.Reasoning.SystemRole,
+	}
+
+	effort, ok := options["reasoning_effort"]
+	if !ok {
+		return reasoning, options, nil
+	}
+
+	options = maps.Clone(options)
+	delete(options, "reasoning_effort")
+
+	if effort != nil {
+		e, err := protocol.ParseReasoningEffort(effort)
+		if err != nil {
+			return nil, nil, err
+		}
+		reasoning.Effort = e
+	}
+
+	return reasoning, options, nil
+}
@@ -0,0 +1,131 @@
+package request
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/JaimeStill/go-agents/pkg/model"
+	"github.com/JaimeStill/go-agents/pkg/protocol"
+	"github.com/JaimeStill/go-agents/pkg/providers"
+)
+
+// RerankRequest represents a rerank protocol request.
+// Separates the query and candidate documents (protocol data) from model configuration options.
+type RerankRequest struct {
+	query     string
+	documents []string
+	options   map[string]any
+	provider  providers.Provider
+	model     *model.Model
+}
+
+// NewRerank creates a new RerankRequest with the given components.
+// Documents are ranked by their relevance to query.
+// Options specify ranking settings: "top_n", "return_documents", and any
+// provider-specific options such as "max_chunks_per_doc" or "truncate".
+func NewRerank(p providers.Provider, m *model.Model, query string, documents []string, opts map[string]any) *RerankRequest {
+	return &RerankRequest{
+		query:     query,
+		documents: documents,
+		options:   opts,
+		provider:  p,
+		model:     m,
+	}
+}
+
+// Protocol returns the Rerank protocol identifier.
+func (r *RerankRequest) Protocol() protocol.Protocol {
+	return protocol.Rerank
+}
+
+// Headers returns the HTTP headers for a rerank request.
+func (r *RerankRequest) Headers() map[string]string {
+	return map[string]string{
+		"Content-Type": "application/json",
+	}
+}
+
+// Marshal delegates to the provider for provider-specific JSON formatting.
+// The "top_n" and "return_documents" options are passed to the provider as
+// typed RerankData fields.
+func (r *RerankRequest) Marshal() ([]byte, error) {
+	options := maps.Clone(r.options)
+	ranking, err := ExtractRerankOptions(options)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &providers.RerankData{
+		Model:           r.model.Name,
+		Query:           r.query,
+		Documents:       r.documents,
+		TopN:            ranking.TopN,
+		ReturnDocuments: ranking.ReturnDocuments,
+		Options:         options,
+	}
+
+	return r.provider.Marshal(protocol.Rerank, data)
+}
+
+// Provider returns the provider for this request.
+func (r *RerankRequest) Provider() providers.Provider {
+	return r.provider
+}
+
+// Model returns the model for this request.
+func (r *RerankRequest) Model() *model.Model {
+	return r.model
+}
+
+// TopN returns the number of results requested, or zero for every document.
+func (r *RerankRequest) TopN() int {
+	if v, ok := r.options["top_n"]; ok {
+		if n, err := positiveInt("top_n", v); err == nil {
+			return n
+		}
+	}
+	return 0
+}
+
+// ReturnDocuments reports whether document text was requested in the results.
+func (r *RerankRequest) ReturnDocuments() bool {
+	returnDocs, _ := r.options["return_documents"].(bool)
+	return returnDocs
+}
+
+// RerankOptions are the typed ranking options of a rerank request.
+type RerankOptions struct {
+	// TopN is the number of results to return, or zero for every document.
+	TopN int
+
+	// ReturnDocuments requests the document text in each result.
+	ReturnDocuments bool
+}
+
+// ExtractRerankOptions removes the "top_n" and "return_documents" options from
+// opts and returns them as RerankOptions. Other options are left in place.
+// Returns an error if top_n is not a positive whole number or return_documents
+// is not a boolean.
+func ExtractRerankOptions(opts map[string]any) (RerankOptions, error) {
+	var ranking RerankOptions
+
+	if v, ok := opts["top_n"]; ok {
+		n, err := positiveInt("top_n", v)
+		if err != nil {
+			return RerankOptions{}, err
+		}
+		ranking.TopN = n
+		delete(opts, "top_n")
+	}
+
+	if v, ok := opts["return_documents"]; ok {
+		returnDocs, ok := v.(bool)
+		if !ok {
+			return RerankOptions{}, fmt.Errorf("return_documents must be a boolean, got %T", v)
+		}
+		ranking.ReturnDocuments = returnDocs
+		delete(opts, "return_documents")
+	}
+
+	return ranking, nil
+}
@@ -1,6 +1,9 @@
 package request
 
 import (
+	"fmt"
+	"maps"
+
 	"github.com/JaimeStill/go-agents/pkg/model"
 	"github.com/JaimeStill/go-agents/pkg/protocol"
 	"github.com/JaimeStill/go-agents/pkg/providers"
@@ -44,13 +47,61 @@ func (r *ToolsRequest) Headers() map[string]string {
 
 // Marshal delegates to the provider for provider-specific JSON formatting.
 // Different providers use different tool formats (OpenAI, Anthropic, Google).
+// The "tool_choice" and "parallel_tool_calls" options are passed to the
+// provider as typed ToolsData fields so each provider can map and validate them.
+// Requests to reasoning models carry the model's reasoning settings, with
+// the "reasoning_effort" option overriding its default effort.
 func (r *ToolsRequest) Marshal() ([]byte, error) {
-	return r.provider.Marshal(protocol.Tools, &providers.ToolsData{
-		Model:    r.model.Name,
-		Messages: r.messages,
-		Tools:    r.tools,
-		Options:  r.options,
-	})
+	reasoning, options, err := extractReasoning(r.model, r.options)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &providers.ToolsData{
+		Model:     r.model.Name,
+		Messages:  r.messages,
+		Tools:     r.tools,
+		Options:   options,
+		Reasoning: reasoning,
+	}
+
+	if err := extractToolControls(data); err != nil {
+		return nil, err
+	}
+
+	return r.provider.Marshal(protocol.Tools, data)
+}
+
+// extractToolControls moves tool_choice and parallel_tool_calls from the
+// options into their typed fields, leaving the request's options unmodified.
+func extractToolControls(data *providers.ToolsData) error {
+	choice, hasChoice := data.Options["tool_choice"]
+	parallel, hasParallel := data.Options["parallel_tool_calls"]
+	if !hasChoice && !hasParallel {
+		return nil
+	}
+
+	data.Options = maps.Clone(data.Options)
+	delete(data.Options, "tool_choice")
+	delete(data.Options, "parallel_tool_calls")
+
+	if hasChoice {
+		c, err := providers.ParseToolChoice(choice)
+		if err != nil {
+			return err
+		}
+		data.ToolChoice = c
+	}
+
+	if hasParallel && parallel != nil {
+		p, ok := parallel.(bool)
+		if !ok {
+			return fmt.Errorf("parallel_tool_calls must be a bool, got %T", parallel)
+		}
+		data.ParallelToolCalls = &p
+	}
+
+	return nil
 }
 
 // Provider returns the provider for this request.
@@ -46,13 +46,21 @@ func (r *VisionRequest) Headers() map[string]string {
 }
 
 // Marshal delegates to the provider for provider-specific JSON formatting.
+// Requests to reasoning models carry the model's reasoning settings, with
+// the "reasoning_effort" option overriding its default effort.
 func (r *VisionRequest) Marshal() ([]byte, error) {
+	reasoning, options, err := extractReasoning(r.model, r.options)
+	if err != nil {
+		return nil, err
+	}
+
 	return r.provider.Marshal(protocol.Vision, &providers.VisionData{
 		Model:         r.model.Name,
 		Messages:      r.messages,
 		Images:        r.images,
 		VisionOptions: r.visionOptions,
-		Options:       r.options,
+		Options:       options,
+		Reasoning:     reasoning,
 	})
 }
 
@@ -0,0 +1,104 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// TranscriptionResponse represents the response from a transcription protocol request.
+// Text is always set. Language, Duration, Segments, and Words are returned
+// with the verbose_json response format; Segments and Words also require the
+// matching timestamp granularity.
+type TranscriptionResponse struct {
+	Task     string                 `json:"task,omitempty"`
+	Language string                 `json:"language,omitempty"`
+	Duration float64                `json:"duration,omitempty"`
+	Text     string                 `json:"text"`
+	Segments []TranscriptionSegment `json:"segments,omitempty"`
+	Words    []TranscriptionWord    `json:"words,omitempty"`
+	Usage    *TokenUsage            `json:"usage,omitempty"`
+}
+
+// TranscriptionSegment is a timestamped span of transcribed speech.
+// Start and End are offsets into the recording in seconds.
+type TranscriptionSegment struct {
+	ID               int     `json:"id"`
+	Seek             int     `json:"seek,omitempty"`
+	Start            float64 `json:"start"`
+	End              float64 `json:"end"`
+	Text             string  `json:"text"`
+	Tokens           []int   `json:"tokens,omitempty"`
+	Temperature      float64 `json:"temperature,omitempty"`
+	AvgLogprob       float64 `json:"avg_logprob,omitempty"`
+	CompressionRatio float64 `json:"compression_ratio,omitempty"`
+	NoSpeechProb     float64 `json:"no_speech_prob,omitempty"`
+}
+
+// TranscriptionWord is a single transcribed word with its timestamps in seconds.
+type TranscriptionWord struct {
+	Word  string  `json:"word"`
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+}
+
+// ParseTranscription parses a transcription response.
+// JSON bodies (the json and verbose_json formats) are decoded, and token usage
+// reported as input/output tokens is mapped to prompt/completion tokens.
+// Other bodies (the text, srt, and vtt formats) are returned as Text.
+func ParseTranscription(body []byte) (*TranscriptionResponse, error) {
+	if !bytes.HasPrefix(bytes.TrimSpace(body), []byte("{")) {
+		return &TranscriptionResponse{Text: strings.TrimRight(string(body), "\n")}, nil
+	}
+
+	var raw struct {
+		TranscriptionResponse
+		Usage *struct {
+			Type         string `json:"type"`
+			InputTokens  int    `json:"input_tokens"`
+			OutputTokens int    `json:"output_tokens"`
+			TotalTokens  int    `json:"total_tokens"`
+		} `json:"usage"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse transcription response: %w", err)
+	}
+
+	response := raw.TranscriptionResponse
+	if u := raw.Usage; u != nil && u.Type != "duration" {
+		response.Usage = &TokenUsage{
+			PromptTokens:     u.InputTokens,
+			CompletionTokens: u.OutputTokens,
+			TotalTokens:      u.TotalTokens,
+		}
+	}
+	return &response, nil
+}
+
+// SpeechResponse represents the response from a speech protocol request.
+// Audio holds the synthesized audio in the requested format.
+type SpeechResponse struct {
+	ContentType string
+	Audio       []byte
+}
+
+// ParseSpeech wraps a binary speech response body.
+// The content type is detected from the audio; providers replace it with the
+// Content-Type reported by the server when available.
+func ParseSpeech(body []byte) (*SpeechResponse, error) {
+	if len(body) == 0 {
+		return nil, fmt.Errorf("speech response contained no audio")
+	}
+	return &SpeechResponse{
+		ContentType: http.DetectContentType(body),
+		Audio:       body,
+	}, nil
+}
+
+// ParseSpeechStreamChunk wraps a segment of streamed speech audio in a StreamingChunk.
+// The data is copied, so callers may reuse their buffer.
+func ParseSpeechStreamChunk(data []byte) (*StreamingChunk, error) {
+	return &StreamingChunk{Audio: bytes.Clone(data)}, nil
+}
@@ -3,6 +3,7 @@ package response
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/JaimeStill/go-agents/pkg/protocol"
 )
@@ -10,20 +11,49 @@ import (
 // ChatResponse represents the response from a non-streaming chat protocol request.
 // Contains the model output, metadata, and optional token usage information.
 type ChatResponse struct {
-	ID      string `json:"id,omitempty"`
-	Object  string `json:"object,omitempty"`
-	Created int64  `json:"created,omitempty"`
-	Model   string `json:"model"`
-	Choices []struct {
-		Index   int              `json:"index"`
-		Message protocol.Message `json:"message"`
-		Delta   *struct {
-			Role    string `json:"role,omitempty"`
-			Content string `json:"content,omitempty"`
-		} `json:"delta,omitempty"`
-		FinishReason string `json:"finish_reason,omitempty"`
-	} `json:"choices"`
-	Usage *TokenUsage `json:"usage,omitempty"`
+	ID      string       `json:"id,omitempty"`
+	Object  string       `json:"object,omitempty"`
+	Created int64        `json:"created,omitempty"`
+	Model   string       `json:"model"`
+	Choices []ChatChoice `json:"choices"`
+	Usage   *TokenUsage  `json:"usage,omitempty"`
+
+	// SystemFingerprint identifies the backend configuration that served the
+	// request. Outputs are only reproducible with a seed while it is unchanged.
+	SystemFingerprint string `json:"system_fingerprint,omitempty"`
+
+	// PromptFilterResults are the Azure content filter results for the prompt.
+	PromptFilterResults []PromptFilterResult `json:"prompt_filter_results,omitempty"`
+
+	// ChoiceFilterResults are the Azure content filter results for the choices.
+	// A withheld completion has FinishReason "content_filter".
+	ChoiceFilterResults []ChoiceFilterResult `json:"-"`
+}
+
+// ChatChoice is a single completion choice of a chat response.
+type ChatChoice struct {
+	Index   int              `json:"index"`
+	Message protocol.Message `json:"message"`
+	Delta   *struct {
+		Role    string `json:"role,omitempty"`
+		Content string `json:"content,omitempty"`
+	} `json:"delta,omitempty"`
+	FinishReason string `json:"finish_reason,omitempty"`
+
+	// Logprobs are the token log probabilities of the choice, when requested
+	// with the "logprobs" option.
+	Logprobs *Logprobs `json:"logprobs,omitempty"`
+}
+
+// Content extracts the text content of the choice.
+// Handles both string content and structured content (e.g., vision responses).
+func (c *ChatChoice) Content() string {
+	switch v := c.Message.Content.(type) {
+	case string:
+		return v
+	default:
+		return fmt.Sprintf("%v", v)
+	}
 }
 
 // Content extracts the text content from the first choice in the response.
@@ -31,16 +61,77 @@ type ChatResponse struct {
 // Returns empty string if there are no choices.
 func (r *ChatResponse) Content() string {
 	if len(r.Choices) > 0 {
-		switch v := r.Choices[0].Message.Content.(type) {
-		case string:
-			return v
-		default:
-			return fmt.Sprintf("%v", v)
-		}
+		return r.Choices[0].Content()
 	}
 	return ""
 }
 
+// Contents returns the text content of every choice, in choice order.
+// Requests with the "n" option return several candidate choices.
+func (r *ChatResponse) Contents() []string {
+	contents := make([]string, len(r.Choices))
+	for i := range r.Choices {
+		contents[i] = r.Choices[i].Content()
+	}
+	return contents
+}
+
+// Choice returns the choice with the given index, or nil if there is none.
+func (r *ChatResponse) Choice(index int) *ChatChoice {
+	for i := range r.Choices {
+		if r.Choices[i].Index == index {
+			return &r.Choices[i]
+		}
+	}
+	return nil
+}
+
+// Best returns the choice with the highest mean token log probability.
+// Choices without logprobs rank below those with them; without any logprobs
+// the first choice is returned. Returns nil if there are no choices.
+func (r *ChatResponse) Best() *ChatChoice {
+	var best *ChatChoice
+	for i := range r.Choices {
+		c := &r.Choices[i]
+		switch {
+		case best == nil:
+			best = c
+		case c.Logprobs == nil:
+		case best.Logprobs == nil || c.Logprobs.Mean() > best.Logprobs.Mean():
+			best = c
+		}
+	}
+	return best
+}
+
+// Vote returns the most common content among the choices, ignoring surrounding
+// whitespace, and the fraction of choices that agree with it. Ties go to the
+// content that appears first. Returns "" and 0 if there are no choices.
+func (r *ChatResponse) Vote() (string, float64) {
+	if len(r.Choices) == 0 {
+		return "", 0
+	}
+
+	counts := make(map[string]int)
+	var order []string
+	for _, content := range r.Contents() {
+		content = strings.TrimSpace(content)
+		if counts[content] == 0 {
+			order = append(order, content)
+		}
+		counts[content]++
+	}
+
+	winner := order[0]
+	for _, content := range order[1:] {
+		if counts[content] > counts[winner] {
+			winner = content
+		}
+	}
+
+	return winner, float64(counts[winner]) / float64(len(r.Choices))
+}
+
 // ParseChat parses a chat response from JSON bytes.
 // Returns the parsed ChatResponse or an error if parsing fails.
 func ParseChat(body []byte) (*ChatResponse, error) {
@@ -48,6 +139,7 @@ func ParseChat(body []byte) (*ChatResponse, error) {
 	if err := json.Unmarshal(body, &response); err != nil {
 		return nil, fmt.Errorf("failed to parse chat response: %w", err)
 	}
+	response.ChoiceFilterResults = parseChoiceFilters(body)
 	return &response, nil
 }
 
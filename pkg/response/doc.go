@@ -1,4 +1,6 @@
 // Package response provides response types and parsing functions for LLM protocol responses.
-// It defines the structures returned from different protocol operations (chat, tools, embeddings)
-// and utilities for parsing raw JSON responses into typed structures.
+// It defines the structures returned from different protocol operations (chat, tools,
+// embeddings, image generation, transcription, speech, rerank, moderation) and utilities for parsing raw
+// responses into typed structures. Chat responses also carry token log probabilities,
+// with helpers for comparing multiple choices and scoring constrained answers.
 package response
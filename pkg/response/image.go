@@ -0,0 +1,84 @@
+package response
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// ImageGenerationResponse represents the response from an image generation protocol request.
+// Contains the generated images as URLs or base64 data, and token usage for
+// models that report it.
+type ImageGenerationResponse struct {
+	Created int64            `json:"created"`
+	Data    []GeneratedImage `json:"data"`
+	Usage   *TokenUsage      `json:"usage,omitempty"`
+}
+
+// GeneratedImage is a single generated image.
+// Exactly one of URL or B64JSON is set, depending on the requested response format.
+type GeneratedImage struct {
+	URL     string `json:"url,omitempty"`
+	B64JSON string `json:"b64_json,omitempty"`
+
+	// RevisedPrompt is the prompt the model actually used, when it rewrote the request.
+	RevisedPrompt string `json:"revised_prompt,omitempty"`
+}
+
+// Bytes decodes the base64 image data.
+// Returns an error if the image was returned as a URL.
+func (g GeneratedImage) Bytes() ([]byte, error) {
+	if g.B64JSON == "" {
+		return nil, fmt.Errorf("image has no base64 data (url: %q)", g.URL)
+	}
+	data, err := base64.StdEncoding.DecodeString(g.B64JSON)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 image: %w", err)
+	}
+	return data, nil
+}
+
+// Source returns the image as a URL or base64 data URI, the forms accepted
+// as Vision images.
+func (g GeneratedImage) Source() string {
+	if g.URL != "" {
+		return g.URL
+	}
+	data, err := g.Bytes()
+	if err != nil {
+		return ""
+	}
+	return "data:" + http.DetectContentType(data) + ";base64," + g.B64JSON
+}
+
+// ParseImageGeneration parses an image generation response from JSON bytes.
+// Usage reported as input/output tokens is mapped to prompt/completion tokens.
+// Returns the parsed ImageGenerationResponse or an error if parsing fails.
+func ParseImageGeneration(body []byte) (*ImageGenerationResponse, error) {
+	var raw struct {
+		Created int64            `json:"created"`
+		Data    []GeneratedImage `json:"data"`
+		Usage   *struct {
+			InputTokens  int `json:"input_tokens"`
+			OutputTokens int `json:"output_tokens"`
+			TotalTokens  int `json:"total_tokens"`
+		} `json:"usage"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse image generation response: %w", err)
+	}
+
+	response := &ImageGenerationResponse{
+		Created: raw.Created,
+		Data:    raw.Data,
+	}
+	if raw.Usage != nil {
+		response.Usage = &TokenUsage{
+			PromptTokens:     raw.Usage.InputTokens,
+			CompletionTokens: raw.Usage.OutputTokens,
+			TotalTokens:      raw.Usage.TotalTokens,
+		}
+	}
+	return response, nil
+}
@@ -0,0 +1,174 @@
+package response
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// Logprobs holds the log probabilities of the tokens of a choice, returned
+// when a request sets the "logprobs" option. The "top_logprobs" option adds
+// the most likely alternatives at each position.
+type Logprobs struct {
+	Content []TokenLogprob `json:"content,omitempty"`
+	Refusal []TokenLogprob `json:"refusal,omitempty"`
+}
+
+// TokenLogprob is the log probability of a generated token.
+type TokenLogprob struct {
+	Token   string  `json:"token"`
+	Logprob float64 `json:"logprob"`
+	Bytes   []int   `json:"bytes,omitempty"`
+
+	// TopLogprobs are the most likely tokens at this position, most likely first.
+	TopLogprobs []TopLogprob `json:"top_logprobs,omitempty"`
+}
+
+// TopLogprob is a likely token at a position of the output.
+type TopLogprob struct {
+	Token   string  `json:"token"`
+	Logprob float64 `json:"logprob"`
+	Bytes   []int   `json:"bytes,omitempty"`
+}
+
+// Probability returns the probability of the token, from 0 to 1.
+func (t TokenLogprob) Probability() float64 {
+	return math.Exp(t.Logprob)
+}
+
+// Probability returns the probability of the token, from 0 to 1.
+func (t TopLogprob) Probability() float64 {
+	return math.Exp(t.Logprob)
+}
+
+// Sum returns the log probability of the whole content: the sum of its token log probabilities.
+func (l *Logprobs) Sum() float64 {
+	var sum float64
+	for _, t := range l.Content {
+		sum += t.Logprob
+	}
+	return sum
+}
+
+// Mean returns the mean token log probability of the content, or 0 if there are no tokens.
+// Unlike Sum, it does not penalize longer outputs.
+func (l *Logprobs) Mean() float64 {
+	if len(l.Content) == 0 {
+		return 0
+	}
+	return l.Sum() / float64(len(l.Content))
+}
+
+// Perplexity returns exp(-Mean()): 1 for a fully certain output, higher for less certain ones.
+func (l *Logprobs) Perplexity() float64 {
+	return math.Exp(-l.Mean())
+}
+
+// Text returns the content tokens joined.
+func (l *Logprobs) Text() string {
+	var text strings.Builder
+	for _, t := range l.Content {
+		text.WriteString(t.Token)
+	}
+	return text.String()
+}
+
+// AnswerConfidence is the probability distribution over a constrained set of
+// answers, derived from token log probabilities.
+type AnswerConfidence struct {
+	// Answer is the most probable answer.
+	Answer string
+
+	// Confidence is the probability of Answer, normalized over the answers.
+	Confidence float64
+
+	// Scores are the normalized probabilities of every answer.
+	Scores map[string]float64
+
+	// Coverage is the probability mass at the answer position that belongs to
+	// any answer, before normalization. Low coverage means the model mostly
+	// considered tokens outside the answer set.
+	Coverage float64
+}
+
+// Confidence derives a confidence score for a constrained answer, such as a
+// label from a fixed set, from the log probabilities of the output.
+//
+// The answer position is the first content token that begins one of the
+// answers, ignoring case and surrounding whitespace. The probabilities of the
+// alternatives at that position (the sampled token and its TopLogprobs) are
+// credited to the answers they begin, split evenly when a token begins
+// several answers, and normalized. Requests should set "top_logprobs" so the
+// alternatives are available; without them only the sampled answer is scored.
+//
+// Returns an error if there are no answers or no token begins an answer.
+func Confidence(l *Logprobs, answers ...string) (*AnswerConfidence, error) {
+	if len(answers) == 0 {
+		return nil, fmt.Errorf("at least one answer is required")
+	}
+	if l == nil || len(l.Content) == 0 {
+		return nil, fmt.Errorf("no logprobs")
+	}
+
+	position := slices.IndexFunc(l.Content, func(t TokenLogprob) bool {
+		return len(beginsAnswers(t.Token, answers)) > 0
+	})
+	if position < 0 {
+		return nil, fmt.Errorf("no token begins any of the answers %q", answers)
+	}
+
+	token := l.Content[position]
+	alternatives := token.TopLogprobs
+	if !slices.ContainsFunc(alternatives, func(t TopLogprob) bool { return t.Token == token.Token }) {
+		alternatives = append([]TopLogprob{{Token: token.Token, Logprob: token.Logprob}}, alternatives...)
+	}
+
+	mass := make(map[string]float64, len(answers))
+	for _, answer := range answers {
+		mass[answer] = 0
+	}
+
+	var coverage float64
+	for _, alt := range alternatives {
+		matched := beginsAnswers(alt.Token, answers)
+		if len(matched) == 0 {
+			continue
+		}
+		p := alt.Probability()
+		coverage += p
+		for _, answer := range matched {
+			mass[answer] += p / float64(len(matched))
+		}
+	}
+
+	result := &AnswerConfidence{Scores: make(map[string]float64, len(answers)), Coverage: coverage}
+	for _, answer := range answers {
+		result.Scores[answer] = mass[answer] / coverage
+	}
+
+	result.Answer = slices.MaxFunc(answers, func(a, b string) int {
+		return cmp.Compare(result.Scores[a], result.Scores[b])
+	})
+	result.Confidence = result.Scores[result.Answer]
+
+	return result, nil
+}
+
+// beginsAnswers returns the answers that token begins, ignoring case and
+// surrounding whitespace. Tokens that are only whitespace begin no answer.
+func beginsAnswers(token string, answers []string) []string {
+	token = strings.ToLower(strings.TrimSpace(token))
+	if token == "" {
+		return nil
+	}
+
+	var matched []string
+	for _, answer := range answers {
+		if strings.HasPrefix(strings.ToLower(answer), token) {
+			matched = append(matched, answer)
+		}
+	}
+	return matched
+}
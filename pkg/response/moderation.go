@@ -0,0 +1,143 @@
+package response
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// ModerationResponse represents the response from a moderation protocol request.
+// Contains one result per input, in input order.
+type ModerationResponse struct {
+	ID      string             `json:"id,omitempty"`
+	Model   string             `json:"model,omitempty"`
+	Results []ModerationResult `json:"results"`
+}
+
+// Flagged reports whether any input was flagged.
+func (r *ModerationResponse) Flagged() bool {
+	return slices.ContainsFunc(r.Results, func(result ModerationResult) bool {
+		return result.Flagged
+	})
+}
+
+// ModerationResult classifies one input or response.
+// It is decoded from OpenAI-style moderation results and from Azure
+// content_filter_results, which report a severity ("safe", "low", "medium",
+// "high") or detection per category instead of scores.
+type ModerationResult struct {
+	// Flagged reports whether any category applies.
+	Flagged bool `json:"flagged"`
+
+	// Categories reports whether each category applies. For Azure content
+	// filters, a category applies when it was filtered or detected.
+	Categories map[string]bool `json:"categories,omitempty"`
+
+	// CategoryScores are the model's confidence for each category, from 0 to 1.
+	CategoryScores map[string]float64 `json:"category_scores,omitempty"`
+
+	// Severities are the Azure content filter severity levels by category.
+	Severities map[string]string `json:"severities,omitempty"`
+
+	// Filtered reports whether an Azure content filter withheld the content.
+	Filtered bool `json:"filtered,omitempty"`
+}
+
+// FlaggedCategories returns the names of the categories that apply, sorted.
+func (r *ModerationResult) FlaggedCategories() []string {
+	var names []string
+	for name, applies := range r.Categories {
+		if applies {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// UnmarshalJSON decodes an OpenAI-style moderation result or an Azure
+// content_filter_results object.
+func (r *ModerationResult) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	_, flagged := fields["flagged"]
+	_, categories := fields["categories"]
+	if flagged || categories {
+		type plain ModerationResult
+		return json.Unmarshal(data, (*plain)(r))
+	}
+
+	*r = ModerationResult{Categories: make(map[string]bool)}
+	for name, raw := range fields {
+		var filter struct {
+			Filtered bool   `json:"filtered"`
+			Detected bool   `json:"detected"`
+			Severity string `json:"severity"`
+		}
+		// Skip entries that are not category results, such as "error".
+		if name == "error" || json.Unmarshal(raw, &filter) != nil {
+			continue
+		}
+
+		applies := filter.Filtered || filter.Detected
+		r.Categories[name] = applies
+		r.Flagged = r.Flagged || applies
+		r.Filtered = r.Filtered || filter.Filtered
+
+		if filter.Severity != "" {
+			if r.Severities == nil {
+				r.Severities = make(map[string]string)
+			}
+			r.Severities[name] = filter.Severity
+		}
+	}
+
+	return nil
+}
+
+// PromptFilterResult holds the Azure content filter results for one prompt of a request.
+type PromptFilterResult struct {
+	PromptIndex   int              `json:"prompt_index"`
+	ContentFilter ModerationResult `json:"content_filter_results"`
+}
+
+// ChoiceFilterResult holds the Azure content filter results for one choice of a response.
+type ChoiceFilterResult struct {
+	Index         int              `json:"index"`
+	ContentFilter ModerationResult `json:"content_filter_results"`
+}
+
+// parseChoiceFilters extracts the content filter results reported on the
+// choices of a chat or tools response body. Returns nil if there are none.
+func parseChoiceFilters(body []byte) []ChoiceFilterResult {
+	var envelope struct {
+		Choices []struct {
+			Index         int               `json:"index"`
+			ContentFilter *ModerationResult `json:"content_filter_results"`
+		} `json:"choices"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil
+	}
+
+	var results []ChoiceFilterResult
+	for _, choice := range envelope.Choices {
+		if choice.ContentFilter != nil {
+			results = append(results, ChoiceFilterResult{Index: choice.Index, ContentFilter: *choice.ContentFilter})
+		}
+	}
+	return results
+}
+
+// ParseModeration parses a moderation response from JSON bytes.
+// Returns the parsed ModerationResponse or an error if parsing fails.
+func ParseModeration(body []byte) (*ModerationResponse, error) {
+	var response ModerationResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse moderation response: %w", err)
+	}
+	return &response, nil
+}
@@ -19,6 +19,16 @@ func Parse(p protocol.Protocol, body []byte) (any, error) {
 		return ParseTools(body)
 	case protocol.Embeddings:
 		return ParseEmbeddings(body)
+	case protocol.ImageGeneration:
+		return ParseImageGeneration(body)
+	case protocol.Transcription:
+		return ParseTranscription(body)
+	case protocol.Speech:
+		return ParseSpeech(body)
+	case protocol.Rerank:
+		return ParseRerank(body)
+	case protocol.Moderation:
+		return ParseModeration(body)
 	default:
 		return nil, fmt.Errorf("unsupported protocol: %s", p)
 	}
@@ -35,7 +45,9 @@ func ParseStreamChunk(p protocol.Protocol, data []byte) (*StreamingChunk, error)
 		return ParseVisionStreamChunk(data)
 	case protocol.Tools:
 		return ParseToolsStreamChunk(data)
-	case protocol.Embeddings:
+	case protocol.Speech:
+		return ParseSpeechStreamChunk(data)
+	case protocol.Embeddings, protocol.ImageGeneration, protocol.Transcription, protocol.Rerank, protocol.Moderation:
 		return nil, fmt.Errorf("protocol %s does not support streaming", p)
 	default:
 		return nil, fmt.Errorf("unsupported protocol: %s", p)
@@ -0,0 +1,112 @@
+package response
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// RerankResponse represents the response from a rerank protocol request.
+// Results are ordered by descending relevance.
+type RerankResponse struct {
+	ID      string         `json:"id,omitempty"`
+	Model   string         `json:"model,omitempty"`
+	Results []RerankResult `json:"results"`
+	Usage   *TokenUsage    `json:"usage,omitempty"`
+}
+
+// RerankResult is the relevance of one input document to the query.
+// Index is the document's position in the request. Document holds its text
+// when documents were requested in the results.
+type RerankResult struct {
+	Index          int     `json:"index"`
+	RelevanceScore float64 `json:"relevance_score"`
+	Document       string  `json:"document,omitempty"`
+}
+
+// Indices returns the input positions of the ranked documents, most relevant first.
+func (r *RerankResponse) Indices() []int {
+	indices := make([]int, len(r.Results))
+	for i, result := range r.Results {
+		indices[i] = result.Index
+	}
+	return indices
+}
+
+// ParseRerank parses a rerank response from JSON bytes.
+// Accepts the Cohere and Jina format ({"results": [...]}, with documents as
+// strings or {"text": ...} objects) and the Text Embeddings Inference format
+// (a bare array of {"index", "score", "text"}).
+// Results are sorted by descending relevance score.
+func ParseRerank(body []byte) (*RerankResponse, error) {
+	type rawResult struct {
+		Index          int             `json:"index"`
+		RelevanceScore *float64        `json:"relevance_score"`
+		Score          float64         `json:"score"`
+		Document       json.RawMessage `json:"document"`
+		Text           string          `json:"text"`
+	}
+
+	var raw struct {
+		ID      string      `json:"id"`
+		Model   string      `json:"model"`
+		Results []rawResult `json:"results"`
+		Usage   *TokenUsage `json:"usage"`
+	}
+
+	var err error
+	if bytes.HasPrefix(bytes.TrimSpace(body), []byte("[")) {
+		err = json.Unmarshal(body, &raw.Results)
+	} else {
+		err = json.Unmarshal(body, &raw)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse rerank response: %w", err)
+	}
+
+	response := &RerankResponse{
+		ID:      raw.ID,
+		Model:   raw.Model,
+		Results: make([]RerankResult, len(raw.Results)),
+		Usage:   raw.Usage,
+	}
+
+	for i, r := range raw.Results {
+		result := RerankResult{Index: r.Index, RelevanceScore: r.Score, Document: r.Text}
+		if r.RelevanceScore != nil {
+			result.RelevanceScore = *r.RelevanceScore
+		}
+		if len(r.Document) > 0 && string(r.Document) != "null" {
+			text, err := rerankDocument(r.Document)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse rerank response: result %d: %w", i, err)
+			}
+			result.Document = text
+		}
+		response.Results[i] = result
+	}
+
+	slices.SortStableFunc(response.Results, func(a, b RerankResult) int {
+		return cmp.Compare(b.RelevanceScore, a.RelevanceScore)
+	})
+
+	return response, nil
+}
+
+// rerankDocument decodes a returned document given as a string or {"text": ...} object.
+func rerankDocument(data json.RawMessage) (string, error) {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		return text, nil
+	}
+
+	var doc struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return "", fmt.Errorf("document must be a string or {\"text\": ...} object")
+	}
+	return doc.Text, nil
+}
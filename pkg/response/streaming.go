@@ -7,21 +7,59 @@ import (
 
 // StreamingChunk represents a single chunk from a streaming protocol response.
 // Each chunk contains incremental content in the Delta field and metadata.
+// Usage is only present on the final chunk when the provider reports streaming usage.
+// Speech streams carry raw audio in Audio instead of choices.
 // The Error field can be set during streaming to indicate processing errors.
 type StreamingChunk struct {
-	ID      string `json:"id,omitempty"`
-	Object  string `json:"object,omitempty"`
-	Created int64  `json:"created,omitempty"`
-	Model   string `json:"model"`
-	Choices []struct {
-		Index int `json:"index"`
-		Delta struct {
-			Role    string `json:"role,omitempty"`
-			Content string `json:"content,omitempty"`
-		} `json:"delta"`
-		FinishReason *string `json:"finish_reason"`
-	} `json:"choices"`
-	Error error `json:"-"`
+	ID      string            `json:"id,omitempty"`
+	Object  string            `json:"object,omitempty"`
+	Created int64             `json:"created,omitempty"`
+	Model   string            `json:"model"`
+	Choices []StreamingChoice `json:"choices"`
+	Usage   *TokenUsage       `json:"usage,omitempty"`
+	Audio   []byte            `json:"audio,omitempty"`
+	Error   error             `json:"-"`
+
+	// SystemFingerprint identifies the backend configuration that served the request.
+	SystemFingerprint string `json:"system_fingerprint,omitempty"`
+}
+
+// StreamingChoice is a single choice of a streaming chunk.
+// Requests with the "n" option stream several choices, distinguished by Index.
+type StreamingChoice struct {
+	Index        int            `json:"index"`
+	Delta        StreamingDelta `json:"delta"`
+	FinishReason *string        `json:"finish_reason"`
+
+	// Logprobs are the log probabilities of the tokens in this delta, when
+	// requested with the "logprobs" option.
+	Logprobs *Logprobs `json:"logprobs,omitempty"`
+}
+
+// StreamingDelta is the incremental message content of a streaming choice.
+// Reasoning models stream their reasoning in Reasoning before the content.
+type StreamingDelta struct {
+	Role      string `json:"role,omitempty"`
+	Content   string `json:"content,omitempty"`
+	Reasoning string `json:"reasoning_content,omitempty"`
+}
+
+// UnmarshalJSON reads Reasoning from "reasoning_content" or, as Ollama reports it, "reasoning".
+func (d *StreamingDelta) UnmarshalJSON(data []byte) error {
+	type delta StreamingDelta
+	raw := struct {
+		*delta
+		OllamaReasoning string `json:"reasoning"`
+	}{delta: (*delta)(d)}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	if d.Reasoning == "" {
+		d.Reasoning = raw.OllamaReasoning
+	}
+	return nil
 }
 
 // Content extracts the incremental content from the delta in the first choice.
@@ -33,6 +71,25 @@ func (c *StreamingChunk) Content() string {
 	return ""
 }
 
+// Reasoning extracts the incremental reasoning from the delta in the first choice.
+// Returns empty string if there are no choices or the delta carries no reasoning.
+func (c *StreamingChunk) Reasoning() string {
+	if len(c.Choices) > 0 {
+		return c.Choices[0].Delta.Reasoning
+	}
+	return ""
+}
+
+// Logprobs returns the token log probabilities of the delta in the first
+// choice, or nil if there are none. Append the Content of each chunk's
+// Logprobs to assemble the logprobs of a whole streamed output.
+func (c *StreamingChunk) Logprobs() *Logprobs {
+	if len(c.Choices) > 0 {
+		return c.Choices[0].Logprobs
+	}
+	return nil
+}
+
 // ParseChatStreamChunk parses a streaming chat chunk from JSON bytes.
 func ParseChatStreamChunk(data []byte) (*StreamingChunk, error) {
 	var chunk StreamingChunk
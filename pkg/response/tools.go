@@ -3,40 +3,68 @@ package response
 import (
 	"encoding/json"
 	"fmt"
+
+	"github.com/JaimeStill/go-agents/pkg/protocol"
 )
 
 // ToolsResponse represents the response from a tools (function calling) protocol request.
 // Contains function calls requested by the model along with metadata and token usage.
 type ToolsResponse struct {
-	ID      string `json:"id,omitempty"`
-	Object  string `json:"object,omitempty"`
-	Created int64  `json:"created,omitempty"`
-	Model   string `json:"model"`
-	Choices []struct {
-		Index   int `json:"index"`
-		Message struct {
-			Role      string     `json:"role"`
-			Content   string     `json:"content"`
-			ToolCalls []ToolCall `json:"tool_calls,omitempty"`
-		} `json:"message"`
-		FinishReason string `json:"finish_reason,omitempty"`
-	} `json:"choices"`
-	Usage *TokenUsage `json:"usage,omitempty"`
+	ID      string        `json:"id,omitempty"`
+	Object  string        `json:"object,omitempty"`
+	Created int64         `json:"created,omitempty"`
+	Model   string        `json:"model"`
+	Choices []ToolsChoice `json:"choices"`
+	Usage   *TokenUsage   `json:"usage,omitempty"`
+
+	// SystemFingerprint identifies the backend configuration that served the request.
+	SystemFingerprint string `json:"system_fingerprint,omitempty"`
+
+	// PromptFilterResults are the Azure content filter results for the prompt.
+	PromptFilterResults []PromptFilterResult `json:"prompt_filter_results,omitempty"`
+
+	// ChoiceFilterResults are the Azure content filter results for the choices.
+	ChoiceFilterResults []ChoiceFilterResult `json:"-"`
 }
 
-// ToolCall represents a function call requested by the model.
-// Contains the call ID, type, and function details.
-type ToolCall struct {
-	ID       string           `json:"id"`
-	Type     string           `json:"type"`
-	Function ToolCallFunction `json:"function"`
+// ToolsChoice is a single completion choice of a tools response.
+// Message can be appended to the conversation history as-is, followed by
+// tool result messages for each of its ToolCalls.
+type ToolsChoice struct {
+	Index        int              `json:"index"`
+	Message      protocol.Message `json:"message"`
+	FinishReason string           `json:"finish_reason,omitempty"`
+
+	// Logprobs are the token log probabilities of the choice's text content,
+	// when requested with the "logprobs" option.
+	Logprobs *Logprobs `json:"logprobs,omitempty"`
 }
 
+// ToolCall represents a function call requested by the model.
+// Alias of protocol.ToolCall so calls can be sent back in assistant messages.
+type ToolCall = protocol.ToolCall
+
 // ToolCallFunction contains the details of a function to be called.
-// Name specifies the function name, and Arguments contains JSON-encoded parameters.
-type ToolCallFunction struct {
-	Name      string `json:"name"`
-	Arguments string `json:"arguments"`
+// Alias of protocol.ToolCallFunction.
+type ToolCallFunction = protocol.ToolCallFunction
+
+// Message returns the message of the first choice, or an empty message if
+// there are no choices.
+func (r *ToolsResponse) Message() protocol.Message {
+	if len(r.Choices) > 0 {
+		return r.Choices[0].Message
+	}
+	return protocol.Message{}
+}
+
+// ToolCalls returns the function calls requested in the first choice.
+func (r *ToolsResponse) ToolCalls() []ToolCall {
+	return r.Message().ToolCalls
+}
+
+// Content returns the text content of the first choice.
+func (r *ToolsResponse) Content() string {
+	return r.Message().Text()
 }
 
 // ParseTools parses a tools response from JSON bytes.
@@ -46,5 +74,6 @@ func ParseTools(body []byte) (*ToolsResponse, error) {
 	if err := json.Unmarshal(body, &response); err != nil {
 		return nil, fmt.Errorf("failed to parse tools response: %w", err)
 	}
+	response.ChoiceFilterResults = parseChoiceFilters(body)
 	return &response, nil
 }
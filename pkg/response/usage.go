@@ -2,8 +2,30 @@ package response
 
 // TokenUsage tracks token consumption for a request/response cycle.
 // Provides counts for prompt tokens, completion tokens, and total tokens used.
+// CompletionTokensDetails breaks down completion tokens when the provider reports it.
 type TokenUsage struct {
 	PromptTokens     int `json:"prompt_tokens"`
 	CompletionTokens int `json:"completion_tokens"`
 	TotalTokens      int `json:"total_tokens"`
+
+	CompletionTokensDetails *CompletionTokensDetails `json:"completion_tokens_details,omitempty"`
+}
+
+// CompletionTokensDetails breaks down the completion tokens of a response.
+// ReasoningTokens are generated by reasoning models before the visible output;
+// they are included in CompletionTokens and billed as output.
+type CompletionTokensDetails struct {
+	ReasoningTokens          int `json:"reasoning_tokens,omitempty"`
+	AudioTokens              int `json:"audio_tokens,omitempty"`
+	AcceptedPredictionTokens int `json:"accepted_prediction_tokens,omitempty"`
+	RejectedPredictionTokens int `json:"rejected_prediction_tokens,omitempty"`
+}
+
+// ReasoningTokens returns the reasoning tokens included in the completion tokens,
+// or 0 if the provider did not report them.
+func (u *TokenUsage) ReasoningTokens() int {
+	if u == nil || u.CompletionTokensDetails == nil {
+		return 0
+	}
+	return u.CompletionTokensDetails.ReasoningTokens
 }
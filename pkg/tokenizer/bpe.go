@@ -0,0 +1,190 @@
+package tokenizer
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/dlclark/regexp2"
+)
+
+// BPE implements byte-pair encoding over a tiktoken-format rank vocabulary.
+// Text is split into pieces with the encoding's pre-tokenization pattern,
+// and each piece is merged from bytes into tokens by ascending rank.
+// Special tokens are not recognized; all input is encoded as ordinary text.
+type BPE struct {
+	name    string
+	pattern *regexp2.Regexp
+	ranks   map[string]int
+	decoder map[int]string
+}
+
+// NewBPE creates a BPE tokenizer from a rank vocabulary and pre-tokenization pattern.
+// Ranks map byte sequences to token IDs; lower ranks merge first.
+// Returns an error if the pattern cannot be compiled.
+func NewBPE(name string, ranks map[string]int, pattern string) (*BPE, error) {
+	re, err := regexp2.Compile(pattern, regexp2.None)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern for %s: %w", name, err)
+	}
+
+	decoder := make(map[int]string, len(ranks))
+	for token, rank := range ranks {
+		decoder[rank] = token
+	}
+
+	return &BPE{
+		name:    name,
+		pattern: re,
+		ranks:   ranks,
+		decoder: decoder,
+	}, nil
+}
+
+// LoadRanks reads a tiktoken-format vocabulary.
+// Each line contains a base64-encoded token and its rank separated by a space.
+// Returns an error if any line is malformed.
+func LoadRanks(r io.Reader) (map[string]int, error) {
+	ranks := make(map[string]int)
+	scanner := bufio.NewScanner(r)
+	line := 0
+
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		encoded, rankStr, ok := strings.Cut(text, " ")
+		if !ok {
+			return nil, fmt.Errorf("malformed vocabulary line %d", line)
+		}
+
+		token, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid token on vocabulary line %d: %w", line, err)
+		}
+
+		rank, err := strconv.Atoi(rankStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rank on vocabulary line %d: %w", line, err)
+		}
+
+		ranks[string(token)] = rank
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read vocabulary: %w", err)
+	}
+
+	return ranks, nil
+}
+
+// Name returns the encoding name.
+func (b *BPE) Name() string {
+	return b.name
+}
+
+// Count returns the number of tokens in text.
+func (b *BPE) Count(text string) int {
+	count := 0
+	for _, piece := range b.split(text) {
+		if _, ok := b.ranks[piece]; ok {
+			count++
+			continue
+		}
+		count += len(b.merge(piece))
+	}
+	return count
+}
+
+// Encode converts text into token IDs.
+func (b *BPE) Encode(text string) []int {
+	tokens := make([]int, 0, len(text)/3)
+	for _, piece := range b.split(text) {
+		if rank, ok := b.ranks[piece]; ok {
+			tokens = append(tokens, rank)
+			continue
+		}
+		tokens = append(tokens, b.merge(piece)...)
+	}
+	return tokens
+}
+
+// Decode converts token IDs back into text.
+// Unknown token IDs are skipped.
+func (b *BPE) Decode(tokens []int) string {
+	var builder strings.Builder
+	for _, token := range tokens {
+		builder.WriteString(b.decoder[token])
+	}
+	return builder.String()
+}
+
+// Truncate returns the longest prefix of text that fits within maxTokens.
+// Truncation happens on token boundaries; a multi-byte character split across
+// tokens is dropped rather than emitted as invalid UTF-8.
+func (b *BPE) Truncate(text string, maxTokens int) string {
+	if maxTokens <= 0 {
+		return ""
+	}
+
+	tokens := b.Encode(text)
+	if len(tokens) <= maxTokens {
+		return text
+	}
+
+	return strings.ToValidUTF8(b.Decode(tokens[:maxTokens]), "")
+}
+
+// split applies the pre-tokenization pattern to text.
+func (b *BPE) split(text string) []string {
+	var pieces []string
+	match, _ := b.pattern.FindStringMatch(text)
+	for match != nil {
+		pieces = append(pieces, match.String())
+		match, _ = b.pattern.FindNextMatch(match)
+	}
+	return pieces
+}
+
+// merge applies byte-pair merges to a piece and returns its token IDs.
+func (b *BPE) merge(piece string) []int {
+	// parts holds the start offset of each current token within piece,
+	// with a trailing sentinel at len(piece).
+	parts := make([]int, len(piece)+1)
+	for i := range parts {
+		parts[i] = i
+	}
+
+	for len(parts) > 2 {
+		minRank := math.MaxInt
+		minIdx := -1
+
+		for i := 0; i < len(parts)-2; i++ {
+			if rank, ok := b.ranks[piece[parts[i]:parts[i+2]]]; ok && rank < minRank {
+				minRank = rank
+				minIdx = i
+			}
+		}
+
+		if minIdx < 0 {
+			break
+		}
+
+		parts = append(parts[:minIdx+1], parts[minIdx+2:]...)
+	}
+
+	tokens := make([]int, 0, len(parts)-1)
+	for i := 0; i < len(parts)-1; i++ {
+		if rank, ok := b.ranks[piece[parts[i]:parts[i+1]]]; ok {
+			tokens = append(tokens, rank)
+		}
+	}
+	return tokens
+}
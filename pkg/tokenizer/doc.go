@@ -0,0 +1,41 @@
+// Package tokenizer provides local token counting for prompt size estimation.
+//
+// The Tokenizer interface counts and truncates text in model tokens. Two
+// implementations are provided:
+//
+//   - BPE: byte-pair encoding over a tiktoken-format vocabulary, matching the
+//     cl100k_base and o200k_base encodings used by OpenAI models
+//   - Heuristic: a character-based estimate for models without a known vocabulary
+//
+// # Selecting a Tokenizer
+//
+// ForModel picks the best available tokenizer for a model name, falling back
+// to the heuristic when the model is unknown:
+//
+//	t := tokenizer.ForModel("gpt-4o", "")
+//	fmt.Println(t.Name(), t.Count("Hello, world!"))
+//
+// The cl100k_base and o200k_base vocabularies are embedded, gzip-compressed,
+// so OpenAI models get exact counts offline. The heuristic is an estimator for
+// everything else: counts from it can be off by a wide margin for code,
+// non-English text, and structured data, and Name reports "heuristic" for them.
+//
+// Get returns an error wrapping ErrVocabularyUnavailable for an encoding with
+// no registered or embedded vocabulary. Other vocabularies can be registered:
+//
+//	file, _ := os.Open("cl100k_base.tiktoken")
+//	ranks, err := tokenizer.LoadRanks(file)
+//	pattern, _ := tokenizer.Pattern(tokenizer.Cl100kBase)
+//	bpe, err := tokenizer.NewBPE(tokenizer.Cl100kBase, ranks, pattern)
+//	tokenizer.Register(bpe)
+//
+// # Estimating Prompts
+//
+// CountMessages estimates a chat request including per-message overhead,
+// and ImageTokens estimates vision inputs using the tile-based image formula:
+//
+//	total := tokenizer.CountMessages(t, messages)
+//	for _, img := range images {
+//	    total += tokenizer.ImageTokens(img, "high")
+//	}
+package tokenizer
@@ -0,0 +1,67 @@
+package tokenizer
+
+import (
+	"compress/gzip"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+)
+
+//go:generate sh -c "curl -sSfL https://openaipublic.blob.core.windows.net/encodings/cl100k_base.tiktoken | gzip -9n > vocab/cl100k_base.tiktoken.gz"
+//go:generate sh -c "curl -sSfL https://openaipublic.blob.core.windows.net/encodings/o200k_base.tiktoken | gzip -9n > vocab/o200k_base.tiktoken.gz"
+
+//go:embed vocab
+var vocabularies embed.FS
+
+// ErrVocabularyUnavailable indicates an encoding's vocabulary is neither
+// registered nor embedded, so exact token counts are not available for it.
+// The cl100k_base and o200k_base vocabularies are always embedded.
+var ErrVocabularyUnavailable = errors.New("vocabulary unavailable")
+
+// patterns holds the pre-tokenization pattern for each supported encoding.
+var patterns = map[string]string{
+	Cl100kBase: `(?i:'s|'t|'re|'ve|'m|'ll|'d)|[^\r\n\p{L}\p{N}]?\p{L}+|\p{N}{1,3}| ?[^\s\p{L}\p{N}]+[\r\n]*|\s*[\r\n]+|\s+(?!\S)|\s+`,
+	O200kBase: `[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]*[\p{Ll}\p{Lm}\p{Lo}\p{M}]+(?i:'s|'t|'re|'ve|'m|'ll|'d)?` +
+		`|[^\r\n\p{L}\p{N}]?[\p{Lu}\p{Lt}\p{Lm}\p{Lo}\p{M}]+[\p{Ll}\p{Lm}\p{Lo}\p{M}]*(?i:'s|'t|'re|'ve|'m|'ll|'d)?` +
+		`|\p{N}{1,3}| ?[^\s\p{L}\p{N}]+[\r\n/]*|\s*[\r\n]+|\s+(?!\S)|\s+`,
+}
+
+// Pattern returns the pre-tokenization pattern for an encoding.
+// Useful for constructing a BPE tokenizer from an externally loaded vocabulary.
+func Pattern(encoding string) (string, bool) {
+	pattern, ok := patterns[encoding]
+	return pattern, ok
+}
+
+// loadEmbedded builds a BPE tokenizer from an embedded, gzip-compressed vocabulary.
+// Returns an error wrapping ErrVocabularyUnavailable if the encoding is unknown
+// or its vocabulary is not embedded.
+func loadEmbedded(encoding string) (*BPE, error) {
+	pattern, ok := patterns[encoding]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown encoding %s", ErrVocabularyUnavailable, encoding)
+	}
+
+	file, err := vocabularies.Open("vocab/" + encoding + ".tiktoken.gz")
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s is not embedded", ErrVocabularyUnavailable, encoding)
+		}
+		return nil, fmt.Errorf("failed to open vocabulary for %s: %w", encoding, err)
+	}
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress vocabulary for %s: %w", encoding, err)
+	}
+	defer reader.Close()
+
+	ranks, err := LoadRanks(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load vocabulary for %s: %w", encoding, err)
+	}
+
+	return NewBPE(encoding, ranks, pattern)
+}
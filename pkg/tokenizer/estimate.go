@@ -0,0 +1,144 @@
+package tokenizer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"strings"
+
+	"github.com/JaimeStill/go-agents/pkg/protocol"
+)
+
+const (
+	// tokensPerMessage is the framing overhead for each chat message.
+	tokensPerMessage = 3
+
+	// tokensPerReply primes the assistant reply in every chat request.
+	tokensPerReply = 3
+
+	// lowDetailImageTokens is the flat cost of a low-detail image.
+	lowDetailImageTokens = 85
+
+	// tileTokens is the cost of each 512px tile in a high-detail image.
+	tileTokens = 170
+
+	// unknownImageTokens is used when image dimensions cannot be determined.
+	// Equivalent to a 1024x1024 high-detail image.
+	unknownImageTokens = lowDetailImageTokens + 4*tileTokens
+)
+
+// CountMessages estimates the prompt tokens for a list of chat messages.
+// Includes per-message framing overhead and reply priming.
+// Content parts are counted per part, with images estimated by ImageTokens;
+// other structured content is counted by its JSON encoding.
+func CountMessages(t Tokenizer, messages []protocol.Message) int {
+	total := tokensPerReply
+	for _, msg := range messages {
+		total += tokensPerMessage
+		total += t.Count(msg.Role)
+		total += countContent(t, msg.Content)
+	}
+	return total
+}
+
+// CountJSON estimates the tokens of a value by its JSON encoding.
+// Used for tool definitions and structured content.
+func CountJSON(t Tokenizer, v any) int {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return 0
+	}
+	return t.Count(string(data))
+}
+
+// ImageTokens estimates the prompt tokens for an image.
+// Follows the OpenAI tile-based formula: low detail costs a flat 85 tokens;
+// high detail scales the image to fit 2048x2048, then to 768px on the shortest
+// side, and charges 170 tokens per 512px tile plus 85.
+// Dimensions are read from base64 data URIs; remote URLs and undecodable images
+// are estimated as a 1024x1024 high-detail image.
+func ImageTokens(img string, detail string) int {
+	if detail == "low" {
+		return lowDetailImageTokens
+	}
+
+	width, height, err := imageSize(img)
+	if err != nil {
+		return unknownImageTokens
+	}
+
+	return tiledImageTokens(width, height)
+}
+
+func tiledImageTokens(width, height int) int {
+	w, h := float64(width), float64(height)
+
+	if w > 2048 || h > 2048 {
+		scale := 2048 / max(w, h)
+		w, h = w*scale, h*scale
+	}
+
+	if shortest := min(w, h); shortest > 768 {
+		scale := 768 / shortest
+		w, h = w*scale, h*scale
+	}
+
+	tilesW := (int(w) + 511) / 512
+	tilesH := (int(h) + 511) / 512
+
+	return lowDetailImageTokens + tileTokens*tilesW*tilesH
+}
+
+func imageSize(img string) (int, int, error) {
+	header, data, ok := strings.Cut(img, ",")
+	if !ok || !strings.HasPrefix(header, "data:") || !strings.HasSuffix(header, ";base64") {
+		return 0, 0, fmt.Errorf("not a base64 data URI")
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid base64 image: %w", err)
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(decoded))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	return cfg.Width, cfg.Height, nil
+}
+
+func countContent(t Tokenizer, content any) int {
+	switch v := content.(type) {
+	case nil:
+		return 0
+	case string:
+		return t.Count(v)
+	case []protocol.ContentPart:
+		total := 0
+		for _, part := range v {
+			total += countPart(t, part)
+		}
+		return total
+	default:
+		return CountJSON(t, v)
+	}
+}
+
+// countPart estimates a content part: text by its tokens, images by the
+// tile formula, and audio and documents by their JSON encoding.
+func countPart(t Tokenizer, part protocol.ContentPart) int {
+	switch {
+	case part.Type == protocol.ContentText:
+		return t.Count(part.Text)
+	case part.Type == protocol.ContentImageURL && part.ImageURL != nil:
+		return ImageTokens(part.ImageURL.URL, part.ImageURL.Detail)
+	default:
+		return CountJSON(t, part)
+	}
+}
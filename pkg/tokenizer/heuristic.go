@@ -0,0 +1,60 @@
+package tokenizer
+
+import (
+	"math"
+	"unicode/utf8"
+)
+
+const (
+	// HeuristicName is the encoding name reported by the heuristic tokenizer.
+	HeuristicName = "heuristic"
+
+	// DefaultCharsPerToken is a conservative ratio for OpenAI-style BPE encodings.
+	// English prose averages about 4 characters per token; code, structured
+	// data, and non-Latin text use fewer, so 3 keeps estimates from undercounting
+	// typical prompts.
+	DefaultCharsPerToken = 3.0
+)
+
+// Heuristic estimates token counts from character length.
+// Used for models without a known vocabulary. With DefaultCharsPerToken,
+// estimates overcount English text and round up, so pre-flight context checks
+// err on the side of caution; text denser than the ratio, such as CJK, can
+// still be undercounted.
+type Heuristic struct {
+	charsPerToken float64
+}
+
+// NewHeuristic creates a heuristic tokenizer.
+// Non-positive charsPerToken values use DefaultCharsPerToken.
+func NewHeuristic(charsPerToken float64) *Heuristic {
+	if charsPerToken <= 0 {
+		charsPerToken = DefaultCharsPerToken
+	}
+	return &Heuristic{charsPerToken: charsPerToken}
+}
+
+// Name returns "heuristic".
+func (h *Heuristic) Name() string {
+	return HeuristicName
+}
+
+// Count estimates the number of tokens in text.
+func (h *Heuristic) Count(text string) int {
+	return int(math.Ceil(float64(utf8.RuneCountInString(text)) / h.charsPerToken))
+}
+
+// Truncate returns the prefix of text estimated to fit within maxTokens.
+func (h *Heuristic) Truncate(text string, maxTokens int) string {
+	if maxTokens <= 0 {
+		return ""
+	}
+
+	limit := int(float64(maxTokens) * h.charsPerToken)
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+
+	runes := []rune(text)
+	return string(runes[:limit])
+}
@@ -0,0 +1,116 @@
+package tokenizer
+
+import (
+	"strings"
+	"sync"
+)
+
+// Tokenizer counts and truncates text in model tokens.
+// Implementations must be safe for concurrent use.
+type Tokenizer interface {
+	// Name returns the encoding identifier (e.g., "cl100k_base", "heuristic").
+	Name() string
+
+	// Count returns the number of tokens in text.
+	Count(text string) int
+
+	// Truncate returns the longest prefix of text that fits within maxTokens.
+	Truncate(text string, maxTokens int) string
+}
+
+const (
+	// Cl100kBase is the encoding used by GPT-4, GPT-3.5, and text-embedding-3 models.
+	Cl100kBase = "cl100k_base"
+
+	// O200kBase is the encoding used by GPT-4o, GPT-4.1, GPT-5, and o-series models.
+	O200kBase = "o200k_base"
+)
+
+// modelPrefixes maps model name prefixes to encodings.
+// Longer prefixes are listed before shorter prefixes they contain.
+var modelPrefixes = []struct {
+	prefix   string
+	encoding string
+}{
+	{"gpt-4o", O200kBase},
+	{"gpt-4.1", O200kBase},
+	{"gpt-4.5", O200kBase},
+	{"gpt-5", O200kBase},
+	{"o1", O200kBase},
+	{"o3", O200kBase},
+	{"o4", O200kBase},
+	{"gpt-4", Cl100kBase},
+	{"gpt-35", Cl100kBase},
+	{"gpt-3.5", Cl100kBase},
+	{"text-embedding-3", Cl100kBase},
+	{"text-embedding-ada-002", Cl100kBase},
+}
+
+// EncodingForModel returns the encoding name for a model.
+// Returns an empty string if the model is not recognized.
+func EncodingForModel(model string) string {
+	name := strings.ToLower(model)
+	for _, entry := range modelPrefixes {
+		if strings.HasPrefix(name, entry.prefix) {
+			return entry.encoding
+		}
+	}
+	return ""
+}
+
+// registry holds registered tokenizers by encoding name.
+var registry = struct {
+	sync.RWMutex
+	tokenizers map[string]Tokenizer
+}{
+	tokenizers: make(map[string]Tokenizer),
+}
+
+// Register registers a tokenizer under its encoding name.
+// Registered tokenizers take precedence over embedded vocabularies.
+// Thread-safe for concurrent registration.
+func Register(t Tokenizer) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.tokenizers[t.Name()] = t
+}
+
+// Get returns the tokenizer for an encoding name.
+// Checks registered tokenizers first, then loads embedded vocabularies on first use.
+// Returns an error wrapping ErrVocabularyUnavailable if the encoding is unknown
+// or its vocabulary is unavailable.
+func Get(encoding string) (Tokenizer, error) {
+	registry.RLock()
+	t, ok := registry.tokenizers[encoding]
+	registry.RUnlock()
+	if ok {
+		return t, nil
+	}
+
+	bpe, err := loadEmbedded(encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	Register(bpe)
+	return bpe, nil
+}
+
+// ForModel returns the best available tokenizer for a model.
+// The encoding argument overrides model-based detection when non-empty.
+// Falls back to the heuristic tokenizer when the model's encoding is unknown
+// or has no vocabulary, so the result is never nil. A heuristic result
+// only estimates counts; use Get to require an exact encoding.
+func ForModel(model, encoding string) Tokenizer {
+	if encoding == "" {
+		encoding = EncodingForModel(model)
+	}
+
+	if encoding != "" && encoding != HeuristicName {
+		if t, err := Get(encoding); err == nil {
+			return t
+		}
+	}
+
+	return NewHeuristic(DefaultCharsPerToken)
+}
@@ -0,0 +1,46 @@
+// Package usage provides token usage accounting and cost estimation for agents.
+//
+// A Tracker accumulates prompt and completion tokens reported by protocol
+// responses and groups them by agent, model, protocol, and caller-supplied tags.
+// An optional PriceTable converts token and image counts into estimated cost,
+// and an optional Budget rejects further requests once a spend or token cap
+// has been reached.
+//
+// # Attaching a Tracker
+//
+// Trackers are attached to agents at creation time:
+//
+//	tracker := usage.NewTracker(
+//	    usage.WithPrices(usage.PriceTable{
+//	        "gpt-4o": {InputPer1K: 0.0025, OutputPer1K: 0.01},
+//	    }),
+//	    usage.WithBudget(usage.Budget{MaxCost: 5.00}),
+//	)
+//
+//	a, err := agent.New(cfg, agent.WithUsage(tracker))
+//
+// Every successful request records a Record on the tracker. Streaming chat and
+// vision requests ask the provider to report usage on the final chunk
+// (stream_options.include_usage) and record it when the stream ends. Responses
+// served from the client response cache are not billed and are not recorded. Once the
+// budget is exhausted, agent calls fail with an error wrapping ErrBudgetExceeded.
+//
+// # Tags
+//
+// Tags are attached to the request context and recorded alongside the usage:
+//
+//	ctx = usage.WithTags(ctx, "document:report.pdf", "stage:classify")
+//	resp, err := a.Vision(ctx, prompt, images)
+//
+// # Snapshots
+//
+// Snapshot returns a point-in-time copy of all accumulated totals, and Export
+// writes the snapshot as indented JSON:
+//
+//	snapshot := tracker.Snapshot()
+//	fmt.Printf("Estimated cost: $%.4f\n", snapshot.Total.Cost)
+//
+//	if err := tracker.Export(os.Stdout); err != nil {
+//	    log.Fatal(err)
+//	}
+package usage
@@ -0,0 +1,73 @@
+package usage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// DefaultPriceKey is the PriceTable key used when a model has no explicit price.
+const DefaultPriceKey = "*"
+
+// Price defines the cost of using a model.
+// Token prices are expressed per 1,000 tokens; image prices are per image.
+//
+// Example JSON:
+//
+//	{
+//	  "input_per_1k": 0.0025,
+//	  "output_per_1k": 0.01,
+//	  "per_image": 0.001275
+//	}
+type Price struct {
+	InputPer1K  float64 `json:"input_per_1k,omitempty"`
+	OutputPer1K float64 `json:"output_per_1k,omitempty"`
+	PerImage    float64 `json:"per_image,omitempty"`
+}
+
+// Cost computes the cost of a request with the given token and image counts.
+func (p Price) Cost(promptTokens, completionTokens, images int) float64 {
+	return float64(promptTokens)/1000*p.InputPer1K +
+		float64(completionTokens)/1000*p.OutputPer1K +
+		float64(images)*p.PerImage
+}
+
+// PriceTable maps model names to prices.
+// The DefaultPriceKey ("*") entry applies to models without an explicit price.
+type PriceTable map[string]Price
+
+// Lookup returns the price for a model.
+// Falls back to the DefaultPriceKey entry, returning false if neither exists.
+func (t PriceTable) Lookup(model string) (Price, bool) {
+	if price, ok := t[model]; ok {
+		return price, true
+	}
+	price, ok := t[DefaultPriceKey]
+	return price, ok
+}
+
+// Cost computes the cost of a request for a model.
+// Returns 0 if the table has no price for the model.
+func (t PriceTable) Cost(model string, promptTokens, completionTokens, images int) float64 {
+	price, ok := t.Lookup(model)
+	if !ok {
+		return 0
+	}
+	return price.Cost(promptTokens, completionTokens, images)
+}
+
+// LoadPriceTable loads a PriceTable from a JSON file.
+// Returns an error if the file cannot be read or the JSON is invalid.
+func LoadPriceTable(filename string) (PriceTable, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read price table: %w", err)
+	}
+
+	var table PriceTable
+	if err := json.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("failed to parse price table: %w", err)
+	}
+
+	return table, nil
+}
@@ -0,0 +1,234 @@
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/JaimeStill/go-agents/pkg/protocol"
+)
+
+// ErrBudgetExceeded is returned when a tracker's budget has been exhausted.
+var ErrBudgetExceeded = errors.New("usage budget exceeded")
+
+// Budget defines optional spend and token caps for a Tracker.
+// Zero values disable the corresponding limit.
+type Budget struct {
+	MaxCost   float64 `json:"max_cost,omitempty"`
+	MaxTokens int     `json:"max_tokens,omitempty"`
+}
+
+// Record describes the usage of a single request.
+// ReasoningTokens are included in CompletionTokens.
+type Record struct {
+	AgentID          string            `json:"agent_id"`
+	Model            string            `json:"model"`
+	Protocol         protocol.Protocol `json:"protocol"`
+	Tags             []string          `json:"tags,omitempty"`
+	PromptTokens     int               `json:"prompt_tokens"`
+	CompletionTokens int               `json:"completion_tokens"`
+	ReasoningTokens  int               `json:"reasoning_tokens,omitempty"`
+	Images           int               `json:"images,omitempty"`
+	Cost             float64           `json:"cost"`
+	Timestamp        time.Time         `json:"timestamp"`
+}
+
+// Totals accumulates usage across one or more records.
+type Totals struct {
+	Requests         int     `json:"requests"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	ReasoningTokens  int     `json:"reasoning_tokens,omitempty"`
+	TotalTokens      int     `json:"total_tokens"`
+	Images           int     `json:"images,omitempty"`
+	Cost             float64 `json:"cost"`
+}
+
+func (t *Totals) add(r Record) {
+	t.Requests++
+	t.PromptTokens += r.PromptTokens
+	t.CompletionTokens += r.CompletionTokens
+	t.ReasoningTokens += r.ReasoningTokens
+	t.TotalTokens += r.PromptTokens + r.CompletionTokens
+	t.Images += r.Images
+	t.Cost += r.Cost
+}
+
+// Snapshot is a point-in-time copy of a tracker's accumulated usage.
+type Snapshot struct {
+	Total      Totals            `json:"total"`
+	ByAgent    map[string]Totals `json:"by_agent"`
+	ByModel    map[string]Totals `json:"by_model"`
+	ByProtocol map[string]Totals `json:"by_protocol"`
+	ByTag      map[string]Totals `json:"by_tag"`
+	Budget     *Budget           `json:"budget,omitempty"`
+	Timestamp  time.Time         `json:"timestamp"`
+}
+
+// Tracker accumulates token usage and estimated cost.
+// Thread-safe for concurrent recording from multiple agents.
+type Tracker struct {
+	prices PriceTable
+	budget *Budget
+
+	mu         sync.RWMutex
+	total      Totals
+	byAgent    map[string]Totals
+	byModel    map[string]Totals
+	byProtocol map[string]Totals
+	byTag      map[string]Totals
+}
+
+// Option configures a Tracker.
+type Option func(*Tracker)
+
+// WithPrices sets the price table used to estimate cost.
+func WithPrices(prices PriceTable) Option {
+	return func(t *Tracker) {
+		t.prices = prices
+	}
+}
+
+// WithBudget sets spend and token caps for the tracker.
+func WithBudget(budget Budget) Option {
+	return func(t *Tracker) {
+		t.budget = &budget
+	}
+}
+
+// NewTracker creates a new Tracker.
+// Without a price table, all costs are recorded as 0.
+func NewTracker(opts ...Option) *Tracker {
+	t := &Tracker{
+		prices:     PriceTable{},
+		byAgent:    make(map[string]Totals),
+		byModel:    make(map[string]Totals),
+		byProtocol: make(map[string]Totals),
+		byTag:      make(map[string]Totals),
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// Check verifies the tracker's budget has not been exhausted.
+// Returns an error wrapping ErrBudgetExceeded once a cap is reached.
+// Requests already in flight are still recorded, so totals may exceed a cap
+// by the usage of concurrent requests.
+func (t *Tracker) Check() error {
+	if t.budget == nil {
+		return nil
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.budget.MaxCost > 0 && t.total.Cost >= t.budget.MaxCost {
+		return fmt.Errorf("%w: spent %.4f of %.4f", ErrBudgetExceeded, t.total.Cost, t.budget.MaxCost)
+	}
+
+	if t.budget.MaxTokens > 0 && t.total.TotalTokens >= t.budget.MaxTokens {
+		return fmt.Errorf("%w: used %d of %d tokens", ErrBudgetExceeded, t.total.TotalTokens, t.budget.MaxTokens)
+	}
+
+	return nil
+}
+
+// Record adds a usage record to the tracker.
+// Cost is computed from the price table and the timestamp is set if empty.
+// Returns the completed record.
+func (t *Tracker) Record(r Record) Record {
+	r.Cost = t.prices.Cost(r.Model, r.PromptTokens, r.CompletionTokens, r.Images)
+	if r.Timestamp.IsZero() {
+		r.Timestamp = time.Now()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.total.add(r)
+	accumulate(t.byAgent, r.AgentID, r)
+	accumulate(t.byModel, r.Model, r)
+	accumulate(t.byProtocol, string(r.Protocol), r)
+	for _, tag := range r.Tags {
+		accumulate(t.byTag, tag, r)
+	}
+
+	return r
+}
+
+// Snapshot returns a copy of the accumulated usage.
+func (t *Tracker) Snapshot() Snapshot {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	snapshot := Snapshot{
+		Total:      t.total,
+		ByAgent:    maps.Clone(t.byAgent),
+		ByModel:    maps.Clone(t.byModel),
+		ByProtocol: maps.Clone(t.byProtocol),
+		ByTag:      maps.Clone(t.byTag),
+		Timestamp:  time.Now(),
+	}
+
+	if t.budget != nil {
+		budget := *t.budget
+		snapshot.Budget = &budget
+	}
+
+	return snapshot
+}
+
+// Export writes a snapshot of the accumulated usage as indented JSON.
+func (t *Tracker) Export(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(t.Snapshot()); err != nil {
+		return fmt.Errorf("failed to export usage: %w", err)
+	}
+	return nil
+}
+
+// Reset clears all accumulated usage. The price table and budget are retained.
+func (t *Tracker) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.total = Totals{}
+	clear(t.byAgent)
+	clear(t.byModel)
+	clear(t.byProtocol)
+	clear(t.byTag)
+}
+
+func accumulate(m map[string]Totals, key string, r Record) {
+	totals := m[key]
+	totals.add(r)
+	m[key] = totals
+}
+
+type tagsKey struct{}
+
+// WithTags returns a context carrying usage tags.
+// Tags are appended to any tags already present in the context.
+func WithTags(ctx context.Context, tags ...string) context.Context {
+	existing := Tags(ctx)
+	combined := make([]string, 0, len(existing)+len(tags))
+	combined = append(combined, existing...)
+	combined = append(combined, tags...)
+	return context.WithValue(ctx, tagsKey{}, combined)
+}
+
+// Tags returns the usage tags carried by a context.
+func Tags(ctx context.Context) []string {
+	tags, _ := ctx.Value(tagsKey{}).([]string)
+	return tags
+}
@@ -3,6 +3,7 @@ package agent_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -14,6 +15,10 @@ import (
 	"github.com/JaimeStill/go-agents/pkg/response"
 )
 
+func ptr[T any](v T) *T {
+	return &v
+}
+
 func TestNew(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -24,9 +29,9 @@ func TestNew(t *testing.T) {
 		Name:         "test-agent",
 		SystemPrompt: "You are a helpful assistant.",
 		Client: &config.ClientConfig{
-			Timeout:            config.Duration(30 * time.Second),
-			ConnectionTimeout:  config.Duration(10 * time.Second),
-			ConnectionPoolSize: 10,
+			Timeout:            ptr(config.Duration(30 * time.Second)),
+			ConnectionTimeout:  ptr(config.Duration(10 * time.Second)),
+			ConnectionPoolSize: ptr(10),
 		},
 		Provider: &config.ProviderConfig{
 			Name:    "ollama",
@@ -55,6 +60,35 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestNewFromProfile(t *testing.T) {
+	data := `{
+		"providers": {"local": {"name": "ollama", "base_url": "http://localhost:11434"}},
+		"models": {"llama": {"name": "llama3.2:3b", "capabilities": {"chat": {}}}},
+		"agents": {
+			"base": {"system_prompt": "You are helpful.", "provider": "local", "model": "llama"},
+			"child": {"extends": "base", "model": {"name": "gemma3:4b"}}
+		}
+	}`
+
+	profiles, err := config.ParseProfiles([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseProfiles failed: %v", err)
+	}
+
+	a, err := agent.NewFromProfile(profiles, "child")
+	if err != nil {
+		t.Fatalf("NewFromProfile failed: %v", err)
+	}
+
+	if a.Model().Name != "gemma3:4b" {
+		t.Errorf("got model %q, want gemma3:4b", a.Model().Name)
+	}
+
+	if _, err := agent.NewFromProfile(profiles, "missing"); !errors.Is(err, config.ErrProfileNotFound) {
+		t.Errorf("got error %v, want ErrProfileNotFound", err)
+	}
+}
+
 func TestAgent_ID(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -64,9 +98,9 @@ func TestAgent_ID(t *testing.T) {
 	cfg := &config.AgentConfig{
 		Name: "test-agent",
 		Client: &config.ClientConfig{
-			Timeout:            config.Duration(30 * time.Second),
-			ConnectionTimeout:  config.Duration(10 * time.Second),
-			ConnectionPoolSize: 10,
+			Timeout:            ptr(config.Duration(30 * time.Second)),
+			ConnectionTimeout:  ptr(config.Duration(10 * time.Second)),
+			ConnectionPoolSize: ptr(10),
 		},
 		Provider: &config.ProviderConfig{
 			Name:    "ollama",
@@ -101,15 +135,7 @@ func TestAgent_Chat(t *testing.T) {
 		chatResp := response.ChatResponse{
 			Model: "test-model",
 		}
-		chatResp.Choices = append(chatResp.Choices, struct {
-			Index   int              `json:"index"`
-			Message protocol.Message `json:"message"`
-			Delta   *struct {
-				Role    string `json:"role,omitempty"`
-				Content string `json:"content,omitempty"`
-			} `json:"delta,omitempty"`
-			FinishReason string `json:"finish_reason,omitempty"`
-		}{
+		chatResp.Choices = append(chatResp.Choices, response.ChatChoice{
 			Index:   0,
 			Message: protocol.NewMessage("assistant", "Hello, how can I help you?"),
 		})
@@ -123,11 +149,11 @@ func TestAgent_Chat(t *testing.T) {
 		Name:         "test-agent",
 		SystemPrompt: "You are helpful.",
 		Client: &config.ClientConfig{
-			Timeout:            config.Duration(30 * time.Second),
-			ConnectionTimeout:  config.Duration(10 * time.Second),
-			ConnectionPoolSize: 10,
+			Timeout:            ptr(config.Duration(30 * time.Second)),
+			ConnectionTimeout:  ptr(config.Duration(10 * time.Second)),
+			ConnectionPoolSize: ptr(10),
 			Retry: config.RetryConfig{
-				MaxRetries: 0,
+				MaxRetries: new(int),
 			},
 		},
 		Provider: &config.ProviderConfig{
@@ -166,15 +192,7 @@ func TestAgent_Vision(t *testing.T) {
 		chatResp := response.ChatResponse{
 			Model: "test-model",
 		}
-		chatResp.Choices = append(chatResp.Choices, struct {
-			Index   int              `json:"index"`
-			Message protocol.Message `json:"message"`
-			Delta   *struct {
-				Role    string `json:"role,omitempty"`
-				Content string `json:"content,omitempty"`
-			} `json:"delta,omitempty"`
-			FinishReason string `json:"finish_reason,omitempty"`
-		}{
+		chatResp.Choices = append(chatResp.Choices, response.ChatChoice{
 			Index:   0,
 			Message: protocol.NewMessage("assistant", "I see a cat in the image."),
 		})
@@ -187,11 +205,11 @@ func TestAgent_Vision(t *testing.T) {
 	cfg := &config.AgentConfig{
 		Name: "test-agent",
 		Client: &config.ClientConfig{
-			Timeout:            config.Duration(30 * time.Second),
-			ConnectionTimeout:  config.Duration(10 * time.Second),
-			ConnectionPoolSize: 10,
+			Timeout:            ptr(config.Duration(30 * time.Second)),
+			ConnectionTimeout:  ptr(config.Duration(10 * time.Second)),
+			ConnectionPoolSize: ptr(10),
 			Retry: config.RetryConfig{
-				MaxRetries: 0,
+				MaxRetries: new(int),
 			},
 		},
 		Provider: &config.ProviderConfig{
@@ -231,23 +249,10 @@ func TestAgent_Tools(t *testing.T) {
 		toolsResp := response.ToolsResponse{
 			Model: "test-model",
 		}
-		toolsResp.Choices = append(toolsResp.Choices, struct {
-			Index   int `json:"index"`
-			Message struct {
-				Role      string              `json:"role"`
-				Content   string              `json:"content"`
-				ToolCalls []response.ToolCall `json:"tool_calls,omitempty"`
-			} `json:"message"`
-			FinishReason string `json:"finish_reason,omitempty"`
-		}{
+		toolsResp.Choices = append(toolsResp.Choices, response.ToolsChoice{
 			Index: 0,
-			Message: struct {
-				Role      string              `json:"role"`
-				Content   string              `json:"content"`
-				ToolCalls []response.ToolCall `json:"tool_calls,omitempty"`
-			}{
-				Role:    "assistant",
-				Content: "",
+			Message: protocol.Message{
+				Role: "assistant",
 				ToolCalls: []response.ToolCall{
 					{
 						ID:   "call_123",
@@ -269,11 +274,11 @@ func TestAgent_Tools(t *testing.T) {
 	cfg := &config.AgentConfig{
 		Name: "test-agent",
 		Client: &config.ClientConfig{
-			Timeout:            config.Duration(30 * time.Second),
-			ConnectionTimeout:  config.Duration(10 * time.Second),
-			ConnectionPoolSize: 10,
+			Timeout:            ptr(config.Duration(30 * time.Second)),
+			ConnectionTimeout:  ptr(config.Duration(10 * time.Second)),
+			ConnectionPoolSize: ptr(10),
 			Retry: config.RetryConfig{
-				MaxRetries: 0,
+				MaxRetries: new(int),
 			},
 		},
 		Provider: &config.ProviderConfig{
@@ -355,11 +360,11 @@ func TestAgent_Embed(t *testing.T) {
 	cfg := &config.AgentConfig{
 		Name: "test-agent",
 		Client: &config.ClientConfig{
-			Timeout:            config.Duration(30 * time.Second),
-			ConnectionTimeout:  config.Duration(10 * time.Second),
-			ConnectionPoolSize: 10,
+			Timeout:            ptr(config.Duration(30 * time.Second)),
+			ConnectionTimeout:  ptr(config.Duration(10 * time.Second)),
+			ConnectionPoolSize: ptr(10),
 			Retry: config.RetryConfig{
-				MaxRetries: 0,
+				MaxRetries: new(int),
 			},
 		},
 		Provider: &config.ProviderConfig{
@@ -406,9 +411,9 @@ func TestAgent_Client(t *testing.T) {
 	cfg := &config.AgentConfig{
 		Name: "test-agent",
 		Client: &config.ClientConfig{
-			Timeout:            config.Duration(30 * time.Second),
-			ConnectionTimeout:  config.Duration(10 * time.Second),
-			ConnectionPoolSize: 10,
+			Timeout:            ptr(config.Duration(30 * time.Second)),
+			ConnectionTimeout:  ptr(config.Duration(10 * time.Second)),
+			ConnectionPoolSize: ptr(10),
 		},
 		Provider: &config.ProviderConfig{
 			Name:    "ollama",
@@ -443,9 +448,9 @@ func TestAgent_Provider(t *testing.T) {
 	cfg := &config.AgentConfig{
 		Name: "test-agent",
 		Client: &config.ClientConfig{
-			Timeout:            config.Duration(30 * time.Second),
-			ConnectionTimeout:  config.Duration(10 * time.Second),
-			ConnectionPoolSize: 10,
+			Timeout:            ptr(config.Duration(30 * time.Second)),
+			ConnectionTimeout:  ptr(config.Duration(10 * time.Second)),
+			ConnectionPoolSize: ptr(10),
 		},
 		Provider: &config.ProviderConfig{
 			Name:    "ollama",
@@ -484,9 +489,9 @@ func TestAgent_Model(t *testing.T) {
 	cfg := &config.AgentConfig{
 		Name: "test-agent",
 		Client: &config.ClientConfig{
-			Timeout:            config.Duration(30 * time.Second),
-			ConnectionTimeout:  config.Duration(10 * time.Second),
-			ConnectionPoolSize: 10,
+			Timeout:            ptr(config.Duration(30 * time.Second)),
+			ConnectionTimeout:  ptr(config.Duration(10 * time.Second)),
+			ConnectionPoolSize: ptr(10),
 		},
 		Provider: &config.ProviderConfig{
 			Name:    "ollama",
@@ -0,0 +1,181 @@
+package agent_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/go-agents/pkg/agent"
+	"github.com/JaimeStill/go-agents/pkg/client"
+	"github.com/JaimeStill/go-agents/pkg/mock"
+	"github.com/JaimeStill/go-agents/pkg/response"
+)
+
+// slowAgent delays chat responses and counts calls.
+type slowAgent struct {
+	*mock.MockAgent
+	delay time.Duration
+	calls atomic.Int32
+}
+
+func newSlowAgent(id, content string, delay time.Duration) *slowAgent {
+	return &slowAgent{MockAgent: mock.NewSimpleChatAgent(id, content), delay: delay}
+}
+
+func (a *slowAgent) Chat(ctx context.Context, prompt string, opts ...map[string]any) (*response.ChatResponse, error) {
+	a.calls.Add(1)
+	select {
+	case <-time.After(a.delay):
+		return a.MockAgent.Chat(ctx, prompt, opts...)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func throttled() error {
+	return &client.HTTPStatusError{StatusCode: 429, Status: "429 Too Many Requests"}
+}
+
+func TestComposite_Fallback(t *testing.T) {
+	primary := mock.NewFailingAgent("primary", throttled())
+	secondary := mock.NewSimpleChatAgent("secondary", "from secondary")
+
+	composite, err := agent.NewFallback([]agent.Agent{primary, secondary})
+	if err != nil {
+		t.Fatalf("NewFallback failed: %v", err)
+	}
+
+	ctx, report := agent.WithServeReport(context.Background())
+	resp, err := composite.Chat(ctx, "hello")
+	if err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+
+	if resp.Content() != "from secondary" {
+		t.Errorf("got %q, want response from secondary", resp.Content())
+	}
+	if report.AgentID != "secondary" {
+		t.Errorf("got served by %q, want secondary", report.AgentID)
+	}
+	if len(report.Attempts) != 2 || report.Attempts[0].Category != client.CategoryRateLimit {
+		t.Errorf("got attempts %+v, want rate-limited primary then secondary", report.Attempts)
+	}
+}
+
+func TestComposite_NoFallbackOnPermanentError(t *testing.T) {
+	badRequest := &client.HTTPStatusError{StatusCode: 400, Status: "400 Bad Request"}
+	primary := mock.NewFailingAgent("primary", badRequest)
+	secondary := mock.NewSimpleChatAgent("secondary", "from secondary")
+
+	composite, err := agent.NewFallback([]agent.Agent{primary, secondary})
+	if err != nil {
+		t.Fatalf("NewFallback failed: %v", err)
+	}
+
+	_, err = composite.Chat(context.Background(), "hello")
+	if !errors.Is(err, badRequest) {
+		t.Errorf("got error %v, want the primary's permanent error", err)
+	}
+}
+
+func TestComposite_AllFail(t *testing.T) {
+	var served *agent.ServeReport
+
+	composite, err := agent.NewFallback(
+		[]agent.Agent{
+			mock.NewFailingAgent("a", throttled()),
+			mock.NewFailingAgent("b", &client.HTTPStatusError{StatusCode: 503, Status: "503 Service Unavailable"}),
+		},
+		agent.WithServeCallback(func(r *agent.ServeReport) { served = r }),
+	)
+	if err != nil {
+		t.Fatalf("NewFallback failed: %v", err)
+	}
+
+	_, err = composite.Chat(context.Background(), "hello")
+
+	var compositeErr *agent.CompositeError
+	if !errors.As(err, &compositeErr) {
+		t.Fatalf("got error %v, want *CompositeError", err)
+	}
+	if len(compositeErr.Attempts) != 2 {
+		t.Errorf("got %d attempts, want 2", len(compositeErr.Attempts))
+	}
+
+	var httpErr *client.HTTPStatusError
+	if !errors.As(err, &httpErr) {
+		t.Error("expected CompositeError to unwrap to member errors")
+	}
+
+	if served == nil || served.AgentID != "" || len(served.Attempts) != 2 {
+		t.Errorf("got callback report %+v, want failed report with 2 attempts", served)
+	}
+}
+
+func TestComposite_Weighted(t *testing.T) {
+	heavy := mock.NewSimpleChatAgent("heavy", "heavy")
+	light := mock.NewSimpleChatAgent("light", "light")
+
+	composite, err := agent.NewComposite([]agent.Tier{{
+		{Agent: heavy, Weight: 9},
+		{Agent: light, Weight: 1},
+	}})
+	if err != nil {
+		t.Fatalf("NewComposite failed: %v", err)
+	}
+
+	counts := map[string]int{}
+	for range 1000 {
+		ctx, report := agent.WithServeReport(context.Background())
+		if _, err := composite.Chat(ctx, "hello"); err != nil {
+			t.Fatalf("Chat failed: %v", err)
+		}
+		counts[report.AgentID]++
+	}
+
+	if counts["heavy"] < 800 || counts["light"] < 50 {
+		t.Errorf("got distribution %v, want roughly 900/100", counts)
+	}
+}
+
+func TestComposite_Hedging(t *testing.T) {
+	slow := newSlowAgent("slow", "slow", time.Second)
+	fast := newSlowAgent("fast", "fast", 5*time.Millisecond)
+
+	composite, err := agent.NewFallback(
+		[]agent.Agent{slow, fast},
+		agent.WithHedging(0.95, 20*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("NewFallback failed: %v", err)
+	}
+
+	ctx, report := agent.WithServeReport(context.Background())
+	start := time.Now()
+
+	resp, err := composite.Chat(ctx, "hello")
+	if err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("hedged call took %v, want well under the slow member's latency", elapsed)
+	}
+	if resp.Content() != "fast" || report.AgentID != "fast" {
+		t.Errorf("got %q served by %q, want fast", resp.Content(), report.AgentID)
+	}
+	if !report.Attempts[0].Hedge {
+		t.Error("expected the serving attempt to be marked as a hedge")
+	}
+	if slow.calls.Load() != 1 || fast.calls.Load() != 1 {
+		t.Errorf("got %d slow and %d fast calls, want 1 each", slow.calls.Load(), fast.calls.Load())
+	}
+}
+
+func TestComposite_Empty(t *testing.T) {
+	if _, err := agent.NewComposite([]agent.Tier{{}, {{Agent: nil}}}); err == nil {
+		t.Error("expected error for composite without members")
+	}
+}
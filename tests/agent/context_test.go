@@ -0,0 +1,145 @@
+package agent_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/JaimeStill/go-agents/pkg/agent"
+	"github.com/JaimeStill/go-agents/pkg/config"
+	"github.com/JaimeStill/go-agents/pkg/tokenizer"
+)
+
+func TestAgent_ContextPolicy(t *testing.T) {
+	var lastPrompt atomic.Value
+	var requests atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		var body struct {
+			Messages []struct {
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if len(body.Messages) > 0 {
+			lastPrompt.Store(body.Messages[len(body.Messages)-1].Content)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"model": "test-model", "choices": [{"index": 0, "message": {"role": "assistant", "content": "ok"}}]}`)
+	}))
+	defer server.Close()
+
+	longPrompt := strings.Repeat("a", 400)
+
+	tests := []struct {
+		name      string
+		policy    string
+		prompt    string
+		wantErr   bool
+		wantShort bool
+	}{
+		{name: "fits", policy: config.ContextPolicyError, prompt: "short"},
+		{name: "error policy", policy: config.ContextPolicyError, prompt: longPrompt, wantErr: true},
+		{name: "truncate policy", policy: config.ContextPolicyTruncate, prompt: longPrompt, wantShort: true},
+		{name: "disabled", policy: config.ContextPolicyNone, prompt: longPrompt},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests.Store(0)
+
+			cfg := newUsageTestConfig(server.URL)
+			cfg.Model.ContextWindow = ptr(100)
+			cfg.Model.MaxOutputTokens = ptr(20)
+			cfg.Model.ContextPolicy = tt.policy
+
+			a, err := agent.New(cfg, agent.WithTokenizer(tokenizer.NewHeuristic(4)))
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+
+			_, err = a.Chat(context.Background(), tt.prompt)
+
+			if tt.wantErr {
+				var ctxErr *agent.ContextError
+				if !errors.As(err, &ctxErr) {
+					t.Fatalf("got error %v, want *ContextError", err)
+				}
+				if !errors.Is(err, agent.ErrContextLengthExceeded) {
+					t.Error("expected error to wrap ErrContextLengthExceeded")
+				}
+				if ctxErr.Window != 100 || ctxErr.Reserved != 20 {
+					t.Errorf("got window %d reserved %d, want 100 and 20", ctxErr.Window, ctxErr.Reserved)
+				}
+				if requests.Load() != 0 {
+					t.Error("request should not be sent when context is exceeded")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Chat failed: %v", err)
+			}
+
+			sent, _ := lastPrompt.Load().(string)
+			if tt.wantShort {
+				if len(sent) >= len(tt.prompt) || len(sent) == 0 {
+					t.Errorf("got prompt length %d, want truncated below %d", len(sent), len(tt.prompt))
+				}
+			} else if sent != tt.prompt {
+				t.Errorf("prompt was modified: got length %d, want %d", len(sent), len(tt.prompt))
+			}
+		})
+	}
+}
+
+func TestAgent_ContextPolicy_OptionReserve(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"model": "test-model", "choices": [{"index": 0, "message": {"role": "assistant", "content": "ok"}}]}`)
+	}))
+	defer server.Close()
+
+	cfg := newUsageTestConfig(server.URL)
+	cfg.Model.ContextWindow = ptr(100)
+	cfg.Model.ContextPolicy = config.ContextPolicyError
+
+	a, err := agent.New(cfg, agent.WithTokenizer(tokenizer.NewHeuristic(4)))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	if _, err := a.Chat(context.Background(), "hello"); err != nil {
+		t.Fatalf("Chat without reserve failed: %v", err)
+	}
+
+	_, err = a.Chat(context.Background(), "hello", map[string]any{"max_tokens": 95})
+	if !errors.Is(err, agent.ErrContextLengthExceeded) {
+		t.Errorf("got error %v, want ErrContextLengthExceeded", err)
+	}
+}
+
+func TestAgent_ContextPolicy_UnavailableTokenizer(t *testing.T) {
+	cfg := newUsageTestConfig("http://localhost:0")
+	cfg.Model.ContextWindow = ptr(100)
+	cfg.Model.ContextPolicy = config.ContextPolicyError
+	cfg.Model.Tokenizer = "unknown_encoding"
+
+	if _, err := agent.New(cfg); !errors.Is(err, tokenizer.ErrVocabularyUnavailable) {
+		t.Errorf("got error %v, want ErrVocabularyUnavailable", err)
+	}
+
+	cfg.Model.Tokenizer = tokenizer.HeuristicName
+	if _, err := agent.New(cfg); err != nil {
+		t.Errorf("New with the heuristic tokenizer failed: %v", err)
+	}
+}
@@ -0,0 +1,175 @@
+package agent_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/go-agents/pkg/agent"
+	"github.com/JaimeStill/go-agents/pkg/config"
+)
+
+func newContentServer(content string, gate <-chan struct{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if gate != nil {
+			<-gate
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{
+			"model": "test-model",
+			"choices": [{"index": 0, "message": {"role": "assistant", "content": %q}}]
+		}`, content)
+	}))
+}
+
+func TestReloadable_InFlightFinishesOnOldAgent(t *testing.T) {
+	gate := make(chan struct{})
+	oldServer := newContentServer("old", gate)
+	defer oldServer.Close()
+	newServer := newContentServer("new", nil)
+	defer newServer.Close()
+
+	r, err := agent.NewReloadable(newUsageTestConfig(oldServer.URL))
+	if err != nil {
+		t.Fatalf("NewReloadable failed: %v", err)
+	}
+	id := r.ID()
+	previous := r.Current()
+
+	var events []agent.ReloadEvent
+	r.Subscribe(func(e agent.ReloadEvent) { events = append(events, e) })
+
+	inflight := make(chan string, 1)
+	go func() {
+		resp, err := r.Chat(context.Background(), "Hello")
+		if err != nil {
+			inflight <- err.Error()
+			return
+		}
+		inflight <- resp.Content()
+	}()
+
+	// Wait for the in-flight request to be routed to the old agent.
+	time.Sleep(50 * time.Millisecond)
+
+	if err := r.Reload(newUsageTestConfig(newServer.URL)); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+
+	resp, err := r.Chat(context.Background(), "Hello")
+	if err != nil {
+		t.Fatalf("Chat after reload failed: %v", err)
+	}
+	if resp.Content() != "new" {
+		t.Errorf("got %q after reload, want new", resp.Content())
+	}
+
+	close(gate)
+	if got := <-inflight; got != "old" {
+		t.Errorf("in-flight request got %q, want old", got)
+	}
+
+	if r.ID() != id {
+		t.Error("ID changed across reload")
+	}
+
+	if len(events) != 1 || events[0].Err != nil || events[0].Previous != previous || events[0].Current != r.Current() {
+		t.Errorf("got events %+v, want one successful swap", events)
+	}
+}
+
+func TestReloadable_InvalidConfigKeepsAgent(t *testing.T) {
+	server := newContentServer("ok", nil)
+	defer server.Close()
+
+	r, err := agent.NewReloadable(newUsageTestConfig(server.URL))
+	if err != nil {
+		t.Fatalf("NewReloadable failed: %v", err)
+	}
+	current := r.Current()
+
+	var event agent.ReloadEvent
+	r.Subscribe(func(e agent.ReloadEvent) { event = e })
+
+	invalid := newUsageTestConfig(server.URL)
+	invalid.Provider.Name = ""
+
+	if err := r.Reload(invalid); err == nil {
+		t.Fatal("expected error for invalid config")
+	}
+
+	if r.Current() != current {
+		t.Error("invalid config replaced the current agent")
+	}
+
+	if event.Err == nil || event.Current != current {
+		t.Errorf("got event %+v, want failure keeping the current agent", event)
+	}
+
+	if _, err := r.Chat(context.Background(), "Hello"); err != nil {
+		t.Errorf("Chat after failed reload: %v", err)
+	}
+}
+
+func TestReloadable_Watch(t *testing.T) {
+	oldServer := newContentServer("old", nil)
+	defer oldServer.Close()
+	newServer := newContentServer("new", nil)
+	defer newServer.Close()
+
+	filename := filepath.Join(t.TempDir(), "agent.yaml")
+	write := func(url string) {
+		data := fmt.Sprintf("provider:\n  name: ollama\n  base_url: %s\nmodel:\n  name: test-model\n", url)
+		if err := os.WriteFile(filename, []byte(data), 0o600); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+	}
+	write(oldServer.URL)
+
+	w, err := config.NewWatcher(filename, config.WithWatchInterval(0), config.WithReloadSignals())
+	if err != nil {
+		t.Fatalf("NewWatcher failed: %v", err)
+	}
+
+	r, err := agent.NewReloadable(w.Config())
+	if err != nil {
+		t.Fatalf("NewReloadable failed: %v", err)
+	}
+	defer r.Watch(w)()
+
+	var failures int
+	r.Subscribe(func(e agent.ReloadEvent) {
+		if e.Err != nil {
+			failures++
+		}
+	})
+
+	write(newServer.URL)
+	if err := w.Reload(); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+
+	resp, err := r.Chat(context.Background(), "Hello")
+	if err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+	if resp.Content() != "new" {
+		t.Errorf("got %q, want new", resp.Content())
+	}
+
+	if err := os.WriteFile(filename, []byte("provider:\n  name: \"\"\n"), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	if err := w.Reload(); err == nil {
+		t.Fatal("expected validation error")
+	}
+
+	if failures != 1 {
+		t.Errorf("got %d failure events, want 1", failures)
+	}
+}
@@ -0,0 +1,88 @@
+package agent_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/go-agents/pkg/agent"
+	"github.com/JaimeStill/go-agents/pkg/mock"
+)
+
+func TestChatRerank(t *testing.T) {
+	chat := mock.NewSimpleChatAgent("ranker", "Scores:\n```json\n[0.2, 0.95, 1.4]\n```")
+	documents := []string{"Berlin is in Germany.", "Paris is the capital of France.", "France borders Spain."}
+
+	resp, err := agent.ChatRerank(context.Background(), chat, "capital of France", documents, map[string]any{
+		"top_n":            2,
+		"return_documents": true,
+		"temperature":      0,
+	})
+	if err != nil {
+		t.Fatalf("ChatRerank failed: %v", err)
+	}
+
+	if len(resp.Results) != 2 {
+		t.Fatalf("got %d results, want 2", len(resp.Results))
+	}
+	if got := resp.Results[0]; got.Index != 2 || got.RelevanceScore != 1 || got.Document != documents[2] {
+		t.Errorf("got first result %+v, want document 2 with clamped score", got)
+	}
+	if got := resp.Results[1]; got.Index != 1 || got.RelevanceScore != 0.95 {
+		t.Errorf("got second result %+v", got)
+	}
+
+	call, _ := chat.LastCall(mock.MethodChat)
+	if !strings.Contains(call.Prompt, "[1] Paris is the capital of France.") {
+		t.Errorf("prompt does not list the documents: %q", call.Prompt)
+	}
+	if _, ok := call.Options["top_n"]; ok {
+		t.Error("top_n passed to Chat")
+	}
+	if call.Options["temperature"] != 0 {
+		t.Errorf("got temperature %v, want 0", call.Options["temperature"])
+	}
+}
+
+func TestChatRerank_InvalidReply(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply string
+	}{
+		{"no scores", "Document 1 is most relevant."},
+		{"wrong count", "[0.5]"},
+		{"not numbers", `["high", "low"]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chat := mock.NewSimpleChatAgent("ranker", tt.reply)
+			if _, err := agent.ChatRerank(context.Background(), chat, "query", []string{"a", "b"}); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestChatRerank_InvalidOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		options map[string]any
+	}{
+		{"zero top_n", map[string]any{"top_n": 0}},
+		{"fractional top_n", map[string]any{"top_n": 1.5}},
+		{"non-boolean return_documents", map[string]any{"return_documents": "yes"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chat := mock.NewSimpleChatAgent("ranker", "[0.5, 0.5]")
+			if _, err := agent.ChatRerank(context.Background(), chat, "query", []string{"a", "b"}, tt.options); err == nil {
+				t.Error("expected error")
+			}
+			if _, called := chat.LastCall(mock.MethodChat); called {
+				t.Error("Chat called despite invalid options")
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package agent_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/JaimeStill/go-agents/pkg/agent"
+	"github.com/JaimeStill/go-agents/pkg/client"
+	"github.com/JaimeStill/go-agents/pkg/config"
+	"github.com/JaimeStill/go-agents/pkg/protocol"
+)
+
+func TestAgent_SupportValidation(t *testing.T) {
+	var requests atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"model": "test-model", "choices": [{"index": 0, "message": {"role": "assistant", "content": "ok"}}]}`)
+	}))
+	defer server.Close()
+
+	noStream := false
+	noJSON := false
+	image := "data:image/png;base64," + base64.StdEncoding.EncodeToString(make([]byte, 100))
+
+	tests := []struct {
+		name      string
+		model     *config.ModelConfig
+		call      func(agent.Agent) error
+		protocol  protocol.Protocol
+		supported bool
+	}{
+		{
+			name:     "catalog embeddings model",
+			model:    &config.ModelConfig{Name: "text-embedding-3-small"},
+			protocol: protocol.Chat,
+			call: func(a agent.Agent) error {
+				_, err := a.Chat(context.Background(), "hello")
+				return err
+			},
+		},
+		{
+			name:     "streaming",
+			model:    &config.ModelConfig{Name: "test-model", Streaming: &noStream},
+			protocol: protocol.Chat,
+			call: func(a agent.Agent) error {
+				_, err := a.ChatStream(context.Background(), "hello")
+				return err
+			},
+		},
+		{
+			name:     "json mode",
+			model:    &config.ModelConfig{Name: "test-model", JSONMode: &noJSON},
+			protocol: protocol.Chat,
+			call: func(a agent.Agent) error {
+				_, err := a.Chat(context.Background(), "hello", map[string]any{
+					"response_format": map[string]any{"type": "json_object"},
+				})
+				return err
+			},
+		},
+		{
+			name:     "max output",
+			model:    &config.ModelConfig{Name: "test-model", MaxOutputTokens: ptr(100)},
+			protocol: protocol.Chat,
+			call: func(a agent.Agent) error {
+				_, err := a.Chat(context.Background(), "hello", map[string]any{"max_tokens": 500})
+				return err
+			},
+		},
+		{
+			name:     "image count",
+			model:    &config.ModelConfig{Name: "test-model", MaxImages: ptr(1)},
+			protocol: protocol.Vision,
+			call: func(a agent.Agent) error {
+				_, err := a.Vision(context.Background(), "describe", []string{image, image})
+				return err
+			},
+		},
+		{
+			name:     "image size",
+			model:    &config.ModelConfig{Name: "test-model", MaxImageBytes: ptr(50)},
+			protocol: protocol.Vision,
+			call: func(a agent.Agent) error {
+				_, err := a.Vision(context.Background(), "describe", []string{image})
+				return err
+			},
+		},
+		{
+			name:      "protocols override catalog",
+			model:     &config.ModelConfig{Name: "text-embedding-3-small", Protocols: []string{}},
+			supported: true,
+			call: func(a agent.Agent) error {
+				_, err := a.Chat(context.Background(), "hello")
+				return err
+			},
+		},
+		{
+			name:      "within limits",
+			model:     &config.ModelConfig{Name: "test-model", MaxOutputTokens: ptr(100), MaxImages: ptr(1), MaxImageBytes: ptr(100)},
+			supported: true,
+			call: func(a agent.Agent) error {
+				_, err := a.Vision(context.Background(), "describe", []string{image}, map[string]any{"max_tokens": 100})
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests.Store(0)
+
+			cfg := newUsageTestConfig(server.URL)
+			cfg.Model = tt.model
+
+			a, err := agent.New(cfg)
+			if err != nil {
+				t.Fatalf("New failed: %v", err)
+			}
+
+			err = tt.call(a)
+
+			if tt.supported {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if requests.Load() != 1 {
+					t.Errorf("got %d requests, want 1", requests.Load())
+				}
+				return
+			}
+
+			var supportErr *agent.SupportError
+			if !errors.As(err, &supportErr) {
+				t.Fatalf("got error %v, want *SupportError", err)
+			}
+			if !errors.Is(err, agent.ErrModelUnsupported) {
+				t.Error("expected error to wrap ErrModelUnsupported")
+			}
+			if supportErr.Model != tt.model.Name || supportErr.Protocol != tt.protocol {
+				t.Errorf("got model %q protocol %q, want %q %q",
+					supportErr.Model, supportErr.Protocol, tt.model.Name, tt.protocol)
+			}
+			if requests.Load() != 0 {
+				t.Errorf("got %d requests, want rejection before sending", requests.Load())
+			}
+		})
+	}
+}
+
+func TestComposite_FallbackOnUnsupported(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"model": "test-model", "choices": [{"index": 0, "message": {"role": "assistant", "content": "ok"}}]}`)
+	}))
+	defer server.Close()
+
+	embedCfg := newUsageTestConfig(server.URL)
+	embedCfg.Model.Name = "text-embedding-3-small"
+	embedder, err := agent.New(embedCfg)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	chatter, err := agent.New(newUsageTestConfig(server.URL))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	composite, err := agent.NewFallback([]agent.Agent{embedder, chatter})
+	if err != nil {
+		t.Fatalf("NewFallback failed: %v", err)
+	}
+
+	ctx, report := agent.WithServeReport(context.Background())
+	resp, err := composite.Chat(ctx, "hello")
+	if err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+
+	if resp.Content() != "ok" || report.AgentID != chatter.ID() {
+		t.Errorf("got %q served by %q, want the chat agent", resp.Content(), report.AgentID)
+	}
+	if len(report.Attempts) != 2 || report.Attempts[0].Category != client.CategoryUnsupported {
+		t.Errorf("got attempts %+v, want an unsupported first attempt", report.Attempts)
+	}
+	if !strings.Contains(report.Attempts[0].Err.Error(), "text-embedding-3-small") {
+		t.Errorf("got attempt error %v, want the model name", report.Attempts[0].Err)
+	}
+}
@@ -0,0 +1,204 @@
+package agent_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/go-agents/pkg/agent"
+	"github.com/JaimeStill/go-agents/pkg/config"
+	"github.com/JaimeStill/go-agents/pkg/usage"
+)
+
+func newUsageTestConfig(url string) *config.AgentConfig {
+	return &config.AgentConfig{
+		Name: "test-agent",
+		Client: &config.ClientConfig{
+			Timeout:            ptr(config.Duration(30 * time.Second)),
+			ConnectionTimeout:  ptr(config.Duration(10 * time.Second)),
+			ConnectionPoolSize: ptr(10),
+		},
+		Provider: &config.ProviderConfig{
+			Name:    "ollama",
+			BaseURL: url,
+		},
+		Model: &config.ModelConfig{
+			Name: "test-model",
+		},
+	}
+}
+
+func TestAgent_WithUsage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{
+			"model": "test-model",
+			"choices": [{"index": 0, "message": {"role": "assistant", "content": "ok"}}],
+			"usage": {"prompt_tokens": 1000, "completion_tokens": 500, "total_tokens": 1500}
+		}`)
+	}))
+	defer server.Close()
+
+	tracker := usage.NewTracker(
+		usage.WithPrices(usage.PriceTable{
+			"test-model": {InputPer1K: 0.01, OutputPer1K: 0.02, PerImage: 0.5},
+		}),
+	)
+
+	a, err := agent.New(newUsageTestConfig(server.URL), agent.WithUsage(tracker))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	ctx := usage.WithTags(context.Background(), "doc:1")
+
+	if _, err := a.Chat(ctx, "Hello"); err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+
+	if _, err := a.Vision(ctx, "Describe", []string{"data:image/png;base64,AA==", "data:image/png;base64,AA=="}); err != nil {
+		t.Fatalf("Vision failed: %v", err)
+	}
+
+	snapshot := tracker.Snapshot()
+
+	if snapshot.Total.Requests != 2 {
+		t.Errorf("got %d requests, want 2", snapshot.Total.Requests)
+	}
+
+	if snapshot.ByAgent[a.ID()].TotalTokens != 3000 {
+		t.Errorf("got %d agent tokens, want 3000", snapshot.ByAgent[a.ID()].TotalTokens)
+	}
+
+	if snapshot.ByProtocol["vision"].Images != 2 {
+		t.Errorf("got %d vision images, want 2", snapshot.ByProtocol["vision"].Images)
+	}
+
+	if snapshot.ByTag["doc:1"].Requests != 2 {
+		t.Errorf("got %d tagged requests, want 2", snapshot.ByTag["doc:1"].Requests)
+	}
+
+	wantCost := 2*(0.01+0.01) + 2*0.5
+	if diff := snapshot.Total.Cost - wantCost; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("got cost %f, want %f", snapshot.Total.Cost, wantCost)
+	}
+}
+
+func TestAgent_WithUsage_CacheHit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{
+			"model": "test-model",
+			"choices": [{"index": 0, "message": {"role": "assistant", "content": "ok"}}],
+			"usage": {"prompt_tokens": 10, "completion_tokens": 5, "total_tokens": 15}
+		}`)
+	}))
+	defer server.Close()
+
+	cfg := newUsageTestConfig(server.URL)
+	cfg.Client.Cache = &config.CacheConfig{Backend: config.CacheBackendMemory}
+
+	tracker := usage.NewTracker()
+	a, err := agent.New(cfg, agent.WithUsage(tracker))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	for range 2 {
+		if _, err := a.Chat(context.Background(), "Hello"); err != nil {
+			t.Fatalf("Chat failed: %v", err)
+		}
+	}
+
+	snapshot := tracker.Snapshot()
+	if snapshot.Total.Requests != 1 {
+		t.Errorf("got %d requests, want 1 with the cached response unrecorded", snapshot.Total.Requests)
+	}
+	if snapshot.Total.TotalTokens != 15 {
+		t.Errorf("got %d tokens, want 15", snapshot.Total.TotalTokens)
+	}
+}
+
+func TestAgent_WithUsage_Budget(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{
+			"model": "test-model",
+			"choices": [{"index": 0, "message": {"role": "assistant", "content": "ok"}}],
+			"usage": {"prompt_tokens": 80, "completion_tokens": 40, "total_tokens": 120}
+		}`)
+	}))
+	defer server.Close()
+
+	tracker := usage.NewTracker(usage.WithBudget(usage.Budget{MaxTokens: 100}))
+
+	a, err := agent.New(newUsageTestConfig(server.URL), agent.WithUsage(tracker))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	if _, err := a.Chat(context.Background(), "first"); err != nil {
+		t.Fatalf("first Chat failed: %v", err)
+	}
+
+	_, err = a.Chat(context.Background(), "second")
+	if !errors.Is(err, usage.ErrBudgetExceeded) {
+		t.Errorf("got error %v, want ErrBudgetExceeded", err)
+	}
+
+	if got := requests.Load(); got != 1 {
+		t.Errorf("got %d requests sent, want 1", got)
+	}
+}
+
+func TestAgent_WithUsage_Stream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			StreamOptions struct {
+				IncludeUsage bool `json:"include_usage"`
+			} `json:"stream_options"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"model\":\"test-model\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"hi\"}}]}\n\n")
+		if body.StreamOptions.IncludeUsage {
+			fmt.Fprint(w, "data: {\"model\":\"test-model\",\"choices\":[],\"usage\":{\"prompt_tokens\":7,\"completion_tokens\":3,\"total_tokens\":10}}\n\n")
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	tracker := usage.NewTracker()
+
+	a, err := agent.New(newUsageTestConfig(server.URL), agent.WithUsage(tracker))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	stream, err := a.ChatStream(context.Background(), "Hello")
+	if err != nil {
+		t.Fatalf("ChatStream failed: %v", err)
+	}
+
+	content := ""
+	for chunk := range stream {
+		content += chunk.Content()
+	}
+
+	if content != "hi" {
+		t.Errorf("got content %q, want %q", content, "hi")
+	}
+
+	if got := tracker.Snapshot().Total.TotalTokens; got != 10 {
+		t.Errorf("got %d total tokens, want 10", got)
+	}
+}
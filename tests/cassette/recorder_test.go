@@ -0,0 +1,327 @@
+package cassette_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/go-agents/pkg/agent"
+	"github.com/JaimeStill/go-agents/pkg/cassette"
+	"github.com/JaimeStill/go-agents/pkg/client"
+	"github.com/JaimeStill/go-agents/pkg/config"
+)
+
+const secretToken = "sk-test-secret-token"
+
+// newProviderServer serves chat completions as JSON or, for streaming
+// requests, as server-sent events with a delay between events.
+func newProviderServer(t *testing.T, calls *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+
+		if r.Header.Get("Accept") == "text/event-stream" {
+			w.Header().Set("Content-Type", "text/event-stream")
+			flusher := w.(http.Flusher)
+			for _, word := range []string{"Hello", " world"} {
+				fmt.Fprintf(w, "data: {\"model\":\"gpt-4o\",\"choices\":[{\"index\":0,\"delta\":{\"content\":%q}}]}\n\n", word)
+				flusher.Flush()
+				time.Sleep(20 * time.Millisecond)
+			}
+			fmt.Fprint(w, "data: [DONE]\n\n")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=abc123")
+		fmt.Fprint(w, `{
+			"model": "gpt-4o",
+			"choices": [{"index": 0, "message": {"role": "assistant", "content": "Hi there"}}]
+		}`)
+	}))
+}
+
+func newAzureConfig(url string) *config.AgentConfig {
+	cfg := config.DefaultAgentConfig()
+	cfg.Client.Retry.MaxRetries = new(int)
+	cfg.Provider = &config.ProviderConfig{
+		Name:    "azure",
+		BaseURL: url,
+		Options: map[string]any{
+			"deployment":  "gpt-4o",
+			"api_version": "2025-01-01-preview",
+			"auth_type":   "api_key",
+			"token":       secretToken,
+		},
+	}
+	cfg.Model = &config.ModelConfig{
+		Name:         "gpt-4o",
+		Capabilities: map[string]map[string]any{"chat": {"temperature": 0.5}},
+	}
+	return &cfg
+}
+
+func newRecordedAgent(t *testing.T, url string, rec *cassette.Recorder) agent.Agent {
+	t.Helper()
+
+	a, err := agent.New(newAzureConfig(url), agent.WithClientOptions(client.WithTransport(rec)))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return a
+}
+
+func streamContent(t *testing.T, a agent.Agent) string {
+	t.Helper()
+
+	chunks, err := a.ChatStream(context.Background(), "Say hello")
+	if err != nil {
+		t.Fatalf("ChatStream failed: %v", err)
+	}
+
+	var content strings.Builder
+	for chunk := range chunks {
+		if chunk.Error != nil {
+			t.Fatalf("stream error: %v", chunk.Error)
+		}
+		content.WriteString(chunk.Content())
+	}
+	return content.String()
+}
+
+func TestRecorder_RecordAndReplay(t *testing.T) {
+	var calls atomic.Int32
+	server := newProviderServer(t, &calls)
+	path := filepath.Join(t.TempDir(), "chat.json")
+
+	rec, err := cassette.New(path)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if rec.Mode() != cassette.ModeRecord {
+		t.Fatalf("got mode %s for missing cassette, want record", rec.Mode())
+	}
+
+	a := newRecordedAgent(t, server.URL, rec)
+
+	resp, err := a.Chat(context.Background(), "Hello")
+	if err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+	if resp.Content() != "Hi there" {
+		t.Errorf("got %q while recording", resp.Content())
+	}
+
+	if got := streamContent(t, a); got != "Hello world" {
+		t.Errorf("got stream %q while recording", got)
+	}
+
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// The recorded URL still points at the closed server, so replay must not touch the network.
+	serverURL := server.URL
+	server.Close()
+	recorded := calls.Load()
+
+	replay, err := cassette.New(path)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if replay.Mode() != cassette.ModeReplay {
+		t.Fatalf("got mode %s for existing cassette, want replay", replay.Mode())
+	}
+
+	a = newRecordedAgent(t, serverURL, replay)
+
+	resp, err = a.Chat(context.Background(), "Hello")
+	if err != nil {
+		t.Fatalf("replayed Chat failed: %v", err)
+	}
+	if resp.Content() != "Hi there" {
+		t.Errorf("got %q on replay", resp.Content())
+	}
+
+	if got := streamContent(t, a); got != "Hello world" {
+		t.Errorf("got stream %q on replay", got)
+	}
+
+	if calls.Load() != recorded {
+		t.Errorf("replay reached the server")
+	}
+
+	if unused := replay.Unused(); len(unused) != 0 {
+		t.Errorf("got %d unused interactions", len(unused))
+	}
+}
+
+func TestRecorder_ScrubsSecrets(t *testing.T) {
+	var calls atomic.Int32
+	server := newProviderServer(t, &calls)
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "scrub.json")
+	rec, err := cassette.New(path, cassette.WithMode(cassette.ModeRecord))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	a := newRecordedAgent(t, server.URL+"?key="+secretToken, rec)
+	if _, err := a.Chat(context.Background(), "Hello"); err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read cassette: %v", err)
+	}
+
+	if strings.Contains(string(data), secretToken) || strings.Contains(string(data), "abc123") {
+		t.Errorf("cassette contains secrets:\n%s", data)
+	}
+
+	c, err := cassette.Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	interaction := c.Interactions[0]
+	if interaction.Request.Headers.Get("Api-Key") != cassette.Redacted {
+		t.Errorf("got api-key header %q", interaction.Request.Headers.Get("Api-Key"))
+	}
+	if !strings.Contains(interaction.Request.URL, "key="+cassette.Redacted) {
+		t.Errorf("got url %q", interaction.Request.URL)
+	}
+}
+
+func TestRecorder_StreamTiming(t *testing.T) {
+	var calls atomic.Int32
+	server := newProviderServer(t, &calls)
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "stream.json")
+	rec, err := cassette.New(path, cassette.WithMode(cassette.ModeRecord))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	streamContent(t, newRecordedAgent(t, server.URL, rec))
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	c, err := cassette.Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	chunks := c.Interactions[0].Response.Chunks
+	if len(chunks) != 3 {
+		t.Fatalf("got %d chunks, want 3", len(chunks))
+	}
+	if chunks[1].Delay.ToDuration() < 15*time.Millisecond {
+		t.Errorf("got delay %v between events, want about 20ms", chunks[1].Delay.ToDuration())
+	}
+
+	realtime, err := cassette.New(path, cassette.WithRealtime())
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	start := time.Now()
+	if got := streamContent(t, newRecordedAgent(t, server.URL, realtime)); got != "Hello world" {
+		t.Errorf("got stream %q", got)
+	}
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("realtime replay took %v, want recorded delays", elapsed)
+	}
+}
+
+func TestRecorder_NoMatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.json")
+	if err := (&cassette.Cassette{}).Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	rec, err := cassette.New(path, cassette.WithMode(cassette.ModeReplay))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	_, err = newRecordedAgent(t, "http://localhost:1", rec).Chat(context.Background(), "Hello")
+	if !errors.Is(err, cassette.ErrNoInteraction) {
+		t.Errorf("got %v, want ErrNoInteraction", err)
+	}
+}
+
+func TestRecorder_ModeEnv(t *testing.T) {
+	t.Setenv(cassette.ModeEnv, "replay")
+
+	if _, err := cassette.New(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected error replaying a missing cassette")
+	}
+
+	t.Setenv(cassette.ModeEnv, "rewind")
+	if _, err := cassette.New("unused.json"); err == nil {
+		t.Error("expected error for unknown mode")
+	}
+}
+
+func TestMatchers(t *testing.T) {
+	recorded := &cassette.Request{
+		Method: "POST",
+		URL:    "http://host/chat?b=2&a=1",
+		Body:   cassette.Body(`{"model": "gpt-4o", "messages": [], "user": "alice"}`),
+	}
+
+	tests := []struct {
+		name     string
+		matcher  cassette.Matcher
+		incoming cassette.Request
+		want     bool
+	}{
+		{"normalized", cassette.DefaultMatcher, cassette.Request{Method: "POST", URL: "http://host/chat?a=1&b=2", Body: cassette.Body(`{"user":"alice","messages":[],"model":"gpt-4o"}`)}, true},
+		{"method", cassette.DefaultMatcher, cassette.Request{Method: "GET", URL: "http://host/chat?a=1&b=2", Body: recorded.Body}, false},
+		{"body", cassette.DefaultMatcher, cassette.Request{Method: "POST", URL: "http://host/chat?a=1&b=2", Body: cassette.Body(`{"model":"gpt-4o","messages":[],"user":"bob"}`)}, false},
+		{"ignoring", cassette.MatchAll(cassette.MatchMethod, cassette.MatchBodyIgnoring("user")), cassette.Request{Method: "POST", URL: "http://other/", Body: cassette.Body(`{"model":"gpt-4o","messages":[],"user":"bob"}`)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.matcher(&tt.incoming, recorded); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBody_Binary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "binary.json")
+	c := &cassette.Cassette{Interactions: []cassette.Interaction{{
+		Response: cassette.Response{Status: 200, Body: cassette.Body{0xff, 0x00, 0xfe}},
+	}}}
+	if err := c.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := cassette.Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if got := loaded.Interactions[0].Response.Body; string(got) != "\xff\x00\xfe" {
+		t.Errorf("got body %v", got)
+	}
+}
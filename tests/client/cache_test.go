@@ -0,0 +1,271 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/go-agents/pkg/client"
+	"github.com/JaimeStill/go-agents/pkg/config"
+	"github.com/JaimeStill/go-agents/pkg/model"
+	"github.com/JaimeStill/go-agents/pkg/protocol"
+	"github.com/JaimeStill/go-agents/pkg/providers"
+	"github.com/JaimeStill/go-agents/pkg/request"
+	"github.com/JaimeStill/go-agents/pkg/response"
+)
+
+// newCacheTestRequest creates a chat request against url for the given prompt.
+func newCacheTestRequest(t *testing.T, url, prompt string, options map[string]any) request.Request {
+	t.Helper()
+
+	provider, err := providers.NewOllama(&config.ProviderConfig{Name: "ollama", BaseURL: url})
+	if err != nil {
+		t.Fatalf("NewOllama failed: %v", err)
+	}
+
+	mdl := model.New(&config.ModelConfig{Name: "test-model"})
+	messages := []protocol.Message{protocol.NewMessage("user", prompt)}
+
+	return request.NewChat(provider, mdl, messages, options)
+}
+
+func newCacheTestConfig(cache *config.CacheConfig) *config.ClientConfig {
+	return &config.ClientConfig{
+		Timeout:            ptr(config.Duration(30 * time.Second)),
+		ConnectionTimeout:  ptr(config.Duration(10 * time.Second)),
+		ConnectionPoolSize: ptr(10),
+		Cache:              cache,
+	}
+}
+
+func TestClient_Execute_Cache(t *testing.T) {
+	var requests atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"model": "test-model", "choices": [{"index": 0, "message": {"role": "assistant", "content": "reply %d"}}]}`, n)
+	}))
+	defer server.Close()
+
+	backends := []struct {
+		name  string
+		cache *config.CacheConfig
+	}{
+		{name: "memory", cache: &config.CacheConfig{Backend: config.CacheBackendMemory}},
+		{name: "disk", cache: &config.CacheConfig{Backend: config.CacheBackendDisk, Dir: t.TempDir()}},
+	}
+
+	for _, tt := range backends {
+		t.Run(tt.name, func(t *testing.T) {
+			requests.Store(0)
+			c := client.New(newCacheTestConfig(tt.cache))
+			ctx := context.Background()
+
+			execute := func(ctx context.Context, prompt string) string {
+				t.Helper()
+				result, err := c.Execute(ctx, newCacheTestRequest(t, server.URL, prompt, map[string]any{}))
+				if err != nil {
+					t.Fatalf("Execute failed: %v", err)
+				}
+				return result.(*response.ChatResponse).Content()
+			}
+
+			first := execute(ctx, "Hello")
+			if second := execute(ctx, "Hello"); second != first {
+				t.Errorf("got %q from cache, want %q", second, first)
+			}
+			if requests.Load() != 1 {
+				t.Errorf("got %d requests, want 1 for identical calls", requests.Load())
+			}
+
+			execute(ctx, "Different")
+			if requests.Load() != 2 {
+				t.Errorf("got %d requests, want 2 after a different prompt", requests.Load())
+			}
+
+			if bypassed := execute(client.WithCacheBypass(ctx), "Hello"); bypassed == first {
+				t.Error("bypass returned the cached response")
+			}
+			if requests.Load() != 3 {
+				t.Errorf("got %d requests, want 3 after bypass", requests.Load())
+			}
+		})
+	}
+}
+
+func TestClient_Execute_CacheReport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"model": "test-model", "choices": [{"index": 0, "message": {"role": "assistant", "content": "ok"}}]}`)
+	}))
+	defer server.Close()
+
+	c := client.New(newCacheTestConfig(nil), client.WithCache(client.NewMemoryCache(0), time.Minute))
+
+	for i, want := range []bool{false, true} {
+		ctx, report := client.WithCacheReport(context.Background())
+		if _, err := c.Execute(ctx, newCacheTestRequest(t, server.URL, "Hello", map[string]any{})); err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+		if report.Hit != want {
+			t.Errorf("call %d: got Hit %v, want %v", i+1, report.Hit, want)
+		}
+	}
+}
+
+func TestClient_Execute_Cache_Speech(t *testing.T) {
+	var requests atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "audio/mpeg")
+		w.Write([]byte("ID3 fake audio"))
+	}))
+	defer server.Close()
+
+	provider, err := providers.NewOllama(&config.ProviderConfig{Name: "ollama", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewOllama failed: %v", err)
+	}
+	mdl := model.New(&config.ModelConfig{Name: "test-model"})
+
+	c := client.New(newCacheTestConfig(nil), client.WithCache(client.NewMemoryCache(0), time.Minute))
+
+	for i := range 2 {
+		req := request.NewSpeech(provider, mdl, "Hello", map[string]any{"voice": "alloy"})
+		result, err := c.Execute(context.Background(), req)
+		if err != nil {
+			t.Fatalf("Execute failed: %v", err)
+		}
+
+		speech := result.(*response.SpeechResponse)
+		if speech.ContentType != "audio/mpeg" {
+			t.Errorf("call %d: got content type %q, want %q", i+1, speech.ContentType, "audio/mpeg")
+		}
+		if string(speech.Audio) != "ID3 fake audio" {
+			t.Errorf("call %d: got audio %q, want %q", i+1, speech.Audio, "ID3 fake audio")
+		}
+	}
+
+	if requests.Load() != 1 {
+		t.Errorf("got %d requests, want 1 for identical calls", requests.Load())
+	}
+}
+
+func TestClient_ExecuteStream_Cache(t *testing.T) {
+	var requests atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, token := range []string{"Hel", "lo"} {
+			fmt.Fprintf(w, "data: {\"model\": \"test-model\", \"choices\": [{\"index\": 0, \"delta\": {\"content\": %q}}]}\n\n", token)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	c := client.New(newCacheTestConfig(nil), client.WithCache(client.NewMemoryCache(0), time.Minute))
+
+	collect := func() string {
+		t.Helper()
+		req := newCacheTestRequest(t, server.URL, "Hello", map[string]any{"stream": true})
+		stream, err := c.ExecuteStream(context.Background(), req)
+		if err != nil {
+			t.Fatalf("ExecuteStream failed: %v", err)
+		}
+
+		var content string
+		for chunk := range stream {
+			if chunk.Error != nil {
+				t.Fatalf("stream error: %v", chunk.Error)
+			}
+			content += chunk.Content()
+		}
+		return content
+	}
+
+	if got := collect(); got != "Hello" {
+		t.Errorf("got %q, want %q", got, "Hello")
+	}
+	if got := collect(); got != "Hello" {
+		t.Errorf("got %q from replay, want %q", got, "Hello")
+	}
+	if requests.Load() != 1 {
+		t.Errorf("got %d requests, want 1 for replayed stream", requests.Load())
+	}
+}
+
+func TestMemoryCache(t *testing.T) {
+	cache := client.NewMemoryCache(2)
+
+	cache.Set("a", []byte("1"), 0)
+	cache.Set("b", []byte("2"), 0)
+	cache.Get("a")
+	cache.Set("c", []byte("3"), 0)
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("expected least recently used entry to be evicted")
+	}
+	if v, ok := cache.Get("a"); !ok || string(v) != "1" {
+		t.Errorf("got %q, %v for recently used entry", v, ok)
+	}
+	if cache.Len() != 2 {
+		t.Errorf("got %d entries, want 2", cache.Len())
+	}
+
+	cache.Set("ttl", []byte("x"), time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := cache.Get("ttl"); ok {
+		t.Error("expected expired entry to be missing")
+	}
+
+	cache.Delete("a")
+	if _, ok := cache.Get("a"); ok {
+		t.Error("expected deleted entry to be missing")
+	}
+}
+
+func TestDiskCache(t *testing.T) {
+	dir := t.TempDir()
+
+	client.NewDiskCache(dir).Set("key", []byte("payload"), 0)
+
+	// A new instance over the same directory sees persisted entries.
+	cache := client.NewDiskCache(dir)
+	if v, ok := cache.Get("key"); !ok || string(v) != "payload" {
+		t.Errorf("got %q, %v, want persisted payload", v, ok)
+	}
+
+	cache.Set("ttl", []byte("x"), time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := cache.Get("ttl"); ok {
+		t.Error("expected expired entry to be missing")
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	base := client.CacheKey("ollama", "http://localhost/v1/chat", "m", []byte(`{"a":1}`))
+
+	variants := []string{
+		client.CacheKey("azure", "http://localhost/v1/chat", "m", []byte(`{"a":1}`)),
+		client.CacheKey("ollama", "http://localhost/v1/embeddings", "m", []byte(`{"a":1}`)),
+		client.CacheKey("ollama", "http://localhost/v1/chat", "n", []byte(`{"a":1}`)),
+		client.CacheKey("ollama", "http://localhost/v1/chat", "m", []byte(`{"a":2}`)),
+	}
+
+	for i, v := range variants {
+		if v == base {
+			t.Errorf("variant %d produced the same key", i)
+		}
+	}
+
+	if again := client.CacheKey("ollama", "http://localhost/v1/chat", "m", []byte(`{"a":1}`)); again != base {
+		t.Error("expected identical inputs to produce identical keys")
+	}
+}
@@ -0,0 +1,38 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/JaimeStill/go-agents/pkg/client"
+)
+
+func TestCategorize(t *testing.T) {
+	tests := []struct {
+		err  error
+		want client.ErrorCategory
+	}{
+		{nil, client.CategoryNone},
+		{&client.HTTPStatusError{StatusCode: 429}, client.CategoryRateLimit},
+		{&client.HTTPStatusError{StatusCode: 502}, client.CategoryServer},
+		{&client.HTTPStatusError{StatusCode: 401}, client.CategoryAuth},
+		{&client.HTTPStatusError{StatusCode: 400, Body: []byte(`{"error": {"code": "context_length_exceeded"}}`)}, client.CategoryContextLength},
+		{&client.HTTPStatusError{StatusCode: 400, Body: []byte(`{"error": {"code": "content_filter"}}`)}, client.CategoryContentFilter},
+		{&client.HTTPStatusError{StatusCode: 400}, client.CategoryInvalidRequest},
+		{fmt.Errorf("wrapped: %w", context.DeadlineExceeded), client.CategoryTimeout},
+		{context.Canceled, client.CategoryCanceled},
+		{&net.OpError{Op: "dial", Err: errors.New("connection refused")}, client.CategoryNetwork},
+		{errors.New("something else"), client.CategoryUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.want), func(t *testing.T) {
+			if got := client.Categorize(tt.err); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
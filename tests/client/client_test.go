@@ -17,11 +17,15 @@ import (
 	"github.com/JaimeStill/go-agents/pkg/response"
 )
 
+func ptr[T any](v T) *T {
+	return &v
+}
+
 func TestNew(t *testing.T) {
 	cfg := &config.ClientConfig{
-		Timeout:            config.Duration(30 * time.Second),
-		ConnectionTimeout:  config.Duration(10 * time.Second),
-		ConnectionPoolSize: 10,
+		Timeout:            ptr(config.Duration(30 * time.Second)),
+		ConnectionTimeout:  ptr(config.Duration(10 * time.Second)),
+		ConnectionPoolSize: ptr(10),
 	}
 
 	c := client.New(cfg)
@@ -37,15 +41,7 @@ func TestClient_Execute_Chat(t *testing.T) {
 		chatResp := response.ChatResponse{
 			Model: "test-model",
 		}
-		chatResp.Choices = append(chatResp.Choices, struct {
-			Index   int              `json:"index"`
-			Message protocol.Message `json:"message"`
-			Delta   *struct {
-				Role    string `json:"role,omitempty"`
-				Content string `json:"content,omitempty"`
-			} `json:"delta,omitempty"`
-			FinishReason string `json:"finish_reason,omitempty"`
-		}{
+		chatResp.Choices = append(chatResp.Choices, response.ChatChoice{
 			Index:   0,
 			Message: protocol.NewMessage("assistant", "Hello, world!"),
 		})
@@ -72,11 +68,11 @@ func TestClient_Execute_Chat(t *testing.T) {
 
 	// Create client
 	cfg := &config.ClientConfig{
-		Timeout:            config.Duration(30 * time.Second),
-		ConnectionTimeout:  config.Duration(10 * time.Second),
-		ConnectionPoolSize: 10,
+		Timeout:            ptr(config.Duration(30 * time.Second)),
+		ConnectionTimeout:  ptr(config.Duration(10 * time.Second)),
+		ConnectionPoolSize: ptr(10),
 		Retry: config.RetryConfig{
-			MaxRetries: 0, // Disable retry for this test
+			MaxRetries: new(int), // Disable retry for this test
 		},
 	}
 	c := client.New(cfg)
@@ -109,23 +105,10 @@ func TestClient_Execute_Tools(t *testing.T) {
 		toolsResp := response.ToolsResponse{
 			Model: "test-model",
 		}
-		toolsResp.Choices = append(toolsResp.Choices, struct {
-			Index   int `json:"index"`
-			Message struct {
-				Role      string              `json:"role"`
-				Content   string              `json:"content"`
-				ToolCalls []response.ToolCall `json:"tool_calls,omitempty"`
-			} `json:"message"`
-			FinishReason string `json:"finish_reason,omitempty"`
-		}{
+		toolsResp.Choices = append(toolsResp.Choices, response.ToolsChoice{
 			Index: 0,
-			Message: struct {
-				Role      string              `json:"role"`
-				Content   string              `json:"content"`
-				ToolCalls []response.ToolCall `json:"tool_calls,omitempty"`
-			}{
-				Role:    "assistant",
-				Content: "",
+			Message: protocol.Message{
+				Role: "assistant",
 				ToolCalls: []response.ToolCall{
 					{
 						ID:   "call_123",
@@ -158,11 +141,11 @@ func TestClient_Execute_Tools(t *testing.T) {
 	})
 
 	cfg := &config.ClientConfig{
-		Timeout:            config.Duration(30 * time.Second),
-		ConnectionTimeout:  config.Duration(10 * time.Second),
-		ConnectionPoolSize: 10,
+		Timeout:            ptr(config.Duration(30 * time.Second)),
+		ConnectionTimeout:  ptr(config.Duration(10 * time.Second)),
+		ConnectionPoolSize: ptr(10),
 		Retry: config.RetryConfig{
-			MaxRetries: 0,
+			MaxRetries: new(int),
 		},
 	}
 	c := client.New(cfg)
@@ -249,11 +232,11 @@ func TestClient_Execute_Embeddings(t *testing.T) {
 	})
 
 	cfg := &config.ClientConfig{
-		Timeout:            config.Duration(30 * time.Second),
-		ConnectionTimeout:  config.Duration(10 * time.Second),
-		ConnectionPoolSize: 10,
+		Timeout:            ptr(config.Duration(30 * time.Second)),
+		ConnectionTimeout:  ptr(config.Duration(10 * time.Second)),
+		ConnectionPoolSize: ptr(10),
 		Retry: config.RetryConfig{
-			MaxRetries: 0,
+			MaxRetries: new(int),
 		},
 	}
 	c := client.New(cfg)
@@ -301,11 +284,11 @@ func TestClient_Execute_HTTPError(t *testing.T) {
 	})
 
 	cfg := &config.ClientConfig{
-		Timeout:            config.Duration(30 * time.Second),
-		ConnectionTimeout:  config.Duration(10 * time.Second),
-		ConnectionPoolSize: 10,
+		Timeout:            ptr(config.Duration(30 * time.Second)),
+		ConnectionTimeout:  ptr(config.Duration(10 * time.Second)),
+		ConnectionPoolSize: ptr(10),
 		Retry: config.RetryConfig{
-			MaxRetries: 0, // Disable retry to get immediate error
+			MaxRetries: new(int), // Disable retry to get immediate error
 		},
 	}
 	c := client.New(cfg)
@@ -341,9 +324,9 @@ func TestClient_ExecuteStream_UnsupportedProtocol(t *testing.T) {
 	})
 
 	cfg := &config.ClientConfig{
-		Timeout:            config.Duration(30 * time.Second),
-		ConnectionTimeout:  config.Duration(10 * time.Second),
-		ConnectionPoolSize: 10,
+		Timeout:            ptr(config.Duration(30 * time.Second)),
+		ConnectionTimeout:  ptr(config.Duration(10 * time.Second)),
+		ConnectionPoolSize: ptr(10),
 	}
 	c := client.New(cfg)
 
@@ -358,9 +341,9 @@ func TestClient_ExecuteStream_UnsupportedProtocol(t *testing.T) {
 
 func TestClient_IsHealthy(t *testing.T) {
 	cfg := &config.ClientConfig{
-		Timeout:            config.Duration(30 * time.Second),
-		ConnectionTimeout:  config.Duration(10 * time.Second),
-		ConnectionPoolSize: 10,
+		Timeout:            ptr(config.Duration(30 * time.Second)),
+		ConnectionTimeout:  ptr(config.Duration(10 * time.Second)),
+		ConnectionPoolSize: ptr(10),
 	}
 
 	c := client.New(cfg)
@@ -372,9 +355,9 @@ func TestClient_IsHealthy(t *testing.T) {
 
 func TestClient_HTTPClient(t *testing.T) {
 	cfg := &config.ClientConfig{
-		Timeout:            config.Duration(5 * time.Second),
-		ConnectionTimeout:  config.Duration(2 * time.Second),
-		ConnectionPoolSize: 20,
+		Timeout:            ptr(config.Duration(5 * time.Second)),
+		ConnectionTimeout:  ptr(config.Duration(2 * time.Second)),
+		ConnectionPoolSize: ptr(20),
 	}
 
 	c := client.New(cfg)
@@ -73,13 +73,13 @@ func TestAgentConfig_FullConfiguration(t *testing.T) {
 		Name:         "full-agent",
 		SystemPrompt: "Test system prompt",
 		Client: &config.ClientConfig{
-			Timeout: config.Duration(24 * time.Second),
+			Timeout: ptr(config.Duration(24 * time.Second)),
 			Retry: config.RetryConfig{
-				MaxRetries:     3,
-				InitialBackoff: config.Duration(1 * time.Second),
+				MaxRetries:     ptr(3),
+				InitialBackoff: ptr(config.Duration(1 * time.Second)),
 			},
-			ConnectionPoolSize: 10,
-			ConnectionTimeout:  config.Duration(9 * time.Second),
+			ConnectionPoolSize: ptr(10),
+			ConnectionTimeout:  ptr(config.Duration(9 * time.Second)),
 		},
 		Provider: &config.ProviderConfig{
 			Name:    "azure",
@@ -185,21 +185,21 @@ func TestAgentConfig_Merge(t *testing.T) {
 			base: &config.AgentConfig{
 				Client: &config.ClientConfig{
 					Retry: config.RetryConfig{
-						MaxRetries: 3,
+						MaxRetries: ptr(3),
 					},
 				},
 			},
 			source: &config.AgentConfig{
 				Client: &config.ClientConfig{
 					Retry: config.RetryConfig{
-						MaxRetries: 5,
+						MaxRetries: ptr(5),
 					},
 				},
 			},
 			expected: &config.AgentConfig{
 				Client: &config.ClientConfig{
 					Retry: config.RetryConfig{
-						MaxRetries: 5,
+						MaxRetries: ptr(5),
 					},
 				},
 			},
@@ -282,8 +282,8 @@ func TestAgentConfig_Merge(t *testing.T) {
 				if tt.base.Client == nil {
 					t.Fatal("client is nil after merge")
 				}
-				if tt.base.Client.Retry.MaxRetries != tt.expected.Client.Retry.MaxRetries {
-					t.Errorf("got max_retries %d, want %d", tt.base.Client.Retry.MaxRetries, tt.expected.Client.Retry.MaxRetries)
+				if tt.base.Client.Retry.Retries() != tt.expected.Client.Retry.Retries() {
+					t.Errorf("got max_retries %d, want %d", tt.base.Client.Retry.Retries(), tt.expected.Client.Retry.Retries())
 				}
 			}
 
@@ -0,0 +1,93 @@
+package config_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/JaimeStill/go-agents/pkg/config"
+)
+
+func TestCatalogModel(t *testing.T) {
+	tests := []struct {
+		name      string
+		found     bool
+		protocols []string
+	}{
+		{name: "gpt-4o", found: true, protocols: []string{"chat", "vision", "tools"}},
+		{name: "GPT-4o-2024-08-06", found: true, protocols: []string{"chat", "vision", "tools"}},
+		{name: "gpt-4o-mini-transcribe", found: true, protocols: []string{"transcription"}},
+		{name: "embeddinggemma:300m", found: true, protocols: []string{"embeddings"}},
+		{name: "o3-mini", found: true, protocols: []string{"chat", "tools"}},
+		{name: "gpt-4o-custom", found: false},
+		{name: "llama3.2:3b", found: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, ok := config.CatalogModel(tt.name)
+			if ok != tt.found {
+				t.Fatalf("got found %v, want %v", ok, tt.found)
+			}
+			if !ok {
+				return
+			}
+			if entry.Name != tt.name {
+				t.Errorf("got name %q, want %q", entry.Name, tt.name)
+			}
+			if !slices.Equal(entry.Protocols, tt.protocols) {
+				t.Errorf("got protocols %v, want %v", entry.Protocols, tt.protocols)
+			}
+		})
+	}
+}
+
+func TestModelConfig_Resolve(t *testing.T) {
+	cfg := &config.ModelConfig{Name: "gpt-4o", MaxOutputTokens: ptr(4096)}
+	resolved := cfg.Resolve()
+
+	if resolved.Window() != 128000 || resolved.OutputLimit() != 4096 {
+		t.Errorf("got window %d and output %d, want catalog window with configured output",
+			resolved.Window(), resolved.OutputLimit())
+	}
+	if resolved.Streaming == nil || !*resolved.Streaming {
+		t.Error("expected catalog streaming support")
+	}
+
+	open := (&config.ModelConfig{Name: "gpt-4o", Protocols: []string{}}).Resolve()
+	if open.Protocols == nil || len(open.Protocols) != 0 {
+		t.Errorf("got protocols %v, want an empty list overriding the catalog", open.Protocols)
+	}
+
+	unknown := (&config.ModelConfig{Name: "llama3.2:3b", ContextWindow: ptr(8192)}).Resolve()
+	if unknown.Window() != 8192 || unknown.Protocols != nil {
+		t.Errorf("got %+v, want an unchanged copy", unknown)
+	}
+
+	reasoning := (&config.ModelConfig{Name: "o4-mini"}).Resolve()
+	if reasoning.Reasoning == nil {
+		t.Error("expected catalog reasoning settings for o4-mini")
+	}
+}
+
+func TestRegisterCatalogModel(t *testing.T) {
+	noStream := false
+	config.RegisterCatalogModel("catalog-test", &config.ModelConfig{
+		Name:      "ignored",
+		Protocols: []string{"chat"},
+		Streaming: &noStream,
+	})
+
+	entry, ok := config.CatalogModel("catalog-test-20260101")
+	if !ok {
+		t.Fatal("registered model not found")
+	}
+	if entry.Name != "catalog-test-20260101" || entry.Streaming == nil || *entry.Streaming {
+		t.Errorf("got %+v, want registered metadata", entry)
+	}
+
+	entry.Protocols[0] = "vision"
+	again, _ := config.CatalogModel("catalog-test")
+	if again.Protocols[0] != "chat" {
+		t.Error("modifying a returned entry changed the catalog")
+	}
+}
@@ -2,6 +2,7 @@ package config_test
 
 import (
 	"encoding/json"
+	"reflect"
 	"testing"
 	"time"
 
@@ -27,36 +28,36 @@ func TestClientConfig_Unmarshal(t *testing.T) {
 		t.Fatalf("failed to unmarshal: %v", err)
 	}
 
-	if cfg.Timeout.ToDuration() != 24*time.Second {
-		t.Errorf("got timeout %v, want 24s", cfg.Timeout.ToDuration())
+	if cfg.RequestTimeout() != 24*time.Second {
+		t.Errorf("got timeout %v, want 24s", cfg.RequestTimeout())
 	}
 
-	if cfg.Retry.MaxRetries != 3 {
-		t.Errorf("got max_retries %d, want 3", cfg.Retry.MaxRetries)
+	if cfg.Retry.Retries() != 3 {
+		t.Errorf("got max_retries %d, want 3", cfg.Retry.Retries())
 	}
 
-	if cfg.Retry.InitialBackoff.ToDuration() != 1*time.Second {
-		t.Errorf("got initial_backoff %v, want 1s", cfg.Retry.InitialBackoff.ToDuration())
+	if cfg.Retry.InitialDelay() != 1*time.Second {
+		t.Errorf("got initial_backoff %v, want 1s", cfg.Retry.InitialDelay())
 	}
 
-	if cfg.Retry.MaxBackoff.ToDuration() != 30*time.Second {
-		t.Errorf("got max_backoff %v, want 30s", cfg.Retry.MaxBackoff.ToDuration())
+	if cfg.Retry.MaxDelay() != 30*time.Second {
+		t.Errorf("got max_backoff %v, want 30s", cfg.Retry.MaxDelay())
 	}
 
-	if cfg.Retry.BackoffMultiplier != 2.0 {
-		t.Errorf("got backoff_multiplier %v, want 2.0", cfg.Retry.BackoffMultiplier)
+	if cfg.Retry.Multiplier() != 2.0 {
+		t.Errorf("got backoff_multiplier %v, want 2.0", cfg.Retry.Multiplier())
 	}
 
-	if !cfg.Retry.Jitter {
+	if !cfg.Retry.JitterEnabled() {
 		t.Error("got jitter false, want true")
 	}
 
-	if cfg.ConnectionPoolSize != 10 {
-		t.Errorf("got connection_pool_size %d, want 10", cfg.ConnectionPoolSize)
+	if cfg.PoolSize() != 10 {
+		t.Errorf("got connection_pool_size %d, want 10", cfg.PoolSize())
 	}
 
-	if cfg.ConnectionTimeout.ToDuration() != 9*time.Second {
-		t.Errorf("got connection_timeout %v, want 9s", cfg.ConnectionTimeout.ToDuration())
+	if cfg.IdleTimeout() != 9*time.Second {
+		t.Errorf("got connection_timeout %v, want 9s", cfg.IdleTimeout())
 	}
 }
 
@@ -67,59 +68,59 @@ func TestClientConfig_Defaults(t *testing.T) {
 		t.Fatal("DefaultClientConfig returned nil")
 	}
 
-	if cfg.Timeout.ToDuration() != 2*time.Minute {
-		t.Errorf("got timeout %v, want 2m", cfg.Timeout.ToDuration())
+	if cfg.RequestTimeout() != 2*time.Minute {
+		t.Errorf("got timeout %v, want 2m", cfg.RequestTimeout())
 	}
 
-	if cfg.Retry.MaxRetries != 3 {
-		t.Errorf("got max_retries %d, want 3", cfg.Retry.MaxRetries)
+	if cfg.Retry.Retries() != 3 {
+		t.Errorf("got max_retries %d, want 3", cfg.Retry.Retries())
 	}
 
-	if cfg.Retry.InitialBackoff.ToDuration() != 1*time.Second {
-		t.Errorf("got initial_backoff %v, want 1s", cfg.Retry.InitialBackoff.ToDuration())
+	if cfg.Retry.InitialDelay() != 1*time.Second {
+		t.Errorf("got initial_backoff %v, want 1s", cfg.Retry.InitialDelay())
 	}
 
-	if cfg.Retry.MaxBackoff.ToDuration() != 30*time.Second {
-		t.Errorf("got max_backoff %v, want 30s", cfg.Retry.MaxBackoff.ToDuration())
+	if cfg.Retry.MaxDelay() != 30*time.Second {
+		t.Errorf("got max_backoff %v, want 30s", cfg.Retry.MaxDelay())
 	}
 
-	if cfg.Retry.BackoffMultiplier != 2.0 {
-		t.Errorf("got backoff_multiplier %v, want 2.0", cfg.Retry.BackoffMultiplier)
+	if cfg.Retry.Multiplier() != 2.0 {
+		t.Errorf("got backoff_multiplier %v, want 2.0", cfg.Retry.Multiplier())
 	}
 
-	if !cfg.Retry.Jitter {
+	if !cfg.Retry.JitterEnabled() {
 		t.Error("got jitter false, want true")
 	}
 
-	if cfg.ConnectionPoolSize != 10 {
-		t.Errorf("got connection_pool_size %d, want 10", cfg.ConnectionPoolSize)
+	if cfg.PoolSize() != 10 {
+		t.Errorf("got connection_pool_size %d, want 10", cfg.PoolSize())
 	}
 
-	if cfg.ConnectionTimeout.ToDuration() != 30*time.Second {
-		t.Errorf("got connection_timeout %v, want 30s", cfg.ConnectionTimeout.ToDuration())
+	if cfg.IdleTimeout() != 30*time.Second {
+		t.Errorf("got connection_timeout %v, want 30s", cfg.IdleTimeout())
 	}
 }
 
 func TestRetryConfig_Defaults(t *testing.T) {
 	cfg := config.DefaultRetryConfig()
 
-	if cfg.MaxRetries != 3 {
-		t.Errorf("got max_retries %d, want 3", cfg.MaxRetries)
+	if cfg.Retries() != 3 {
+		t.Errorf("got max_retries %d, want 3", cfg.Retries())
 	}
 
-	if cfg.InitialBackoff.ToDuration() != 1*time.Second {
-		t.Errorf("got initial_backoff %v, want 1s", cfg.InitialBackoff.ToDuration())
+	if cfg.InitialDelay() != 1*time.Second {
+		t.Errorf("got initial_backoff %v, want 1s", cfg.InitialDelay())
 	}
 
-	if cfg.MaxBackoff.ToDuration() != 30*time.Second {
-		t.Errorf("got max_backoff %v, want 30s", cfg.MaxBackoff.ToDuration())
+	if cfg.MaxDelay() != 30*time.Second {
+		t.Errorf("got max_backoff %v, want 30s", cfg.MaxDelay())
 	}
 
-	if cfg.BackoffMultiplier != 2.0 {
-		t.Errorf("got backoff_multiplier %v, want 2.0", cfg.BackoffMultiplier)
+	if cfg.Multiplier() != 2.0 {
+		t.Errorf("got backoff_multiplier %v, want 2.0", cfg.Multiplier())
 	}
 
-	if !cfg.Jitter {
+	if !cfg.JitterEnabled() {
 		t.Error("got jitter false, want true")
 	}
 }
@@ -146,11 +147,11 @@ func TestClientConfig_ConnectionPooling(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cfg := &config.ClientConfig{
-				ConnectionPoolSize: tt.poolSize,
+				ConnectionPoolSize: ptr(tt.poolSize),
 			}
 
-			if cfg.ConnectionPoolSize != tt.poolSize {
-				t.Errorf("got connection_pool_size %d, want %d", cfg.ConnectionPoolSize, tt.poolSize)
+			if cfg.PoolSize() != tt.poolSize {
+				t.Errorf("got connection_pool_size %d, want %d", cfg.PoolSize(), tt.poolSize)
 			}
 		})
 	}
@@ -166,99 +167,133 @@ func TestClientConfig_Merge(t *testing.T) {
 		{
 			name: "merge timeout",
 			base: &config.ClientConfig{
-				Timeout: config.Duration(1 * time.Minute),
+				Timeout: ptr(config.Duration(1 * time.Minute)),
 			},
 			source: &config.ClientConfig{
-				Timeout: config.Duration(2 * time.Minute),
+				Timeout: ptr(config.Duration(2 * time.Minute)),
 			},
 			expected: &config.ClientConfig{
-				Timeout: config.Duration(2 * time.Minute),
+				Timeout: ptr(config.Duration(2 * time.Minute)),
 			},
 		},
 		{
 			name: "merge retry config",
 			base: &config.ClientConfig{
 				Retry: config.RetryConfig{
-					MaxRetries: 3,
+					MaxRetries: ptr(3),
 				},
 			},
 			source: &config.ClientConfig{
 				Retry: config.RetryConfig{
-					MaxRetries: 5,
+					MaxRetries: ptr(5),
 				},
 			},
 			expected: &config.ClientConfig{
 				Retry: config.RetryConfig{
-					MaxRetries: 5,
+					MaxRetries: ptr(5),
 				},
 			},
 		},
 		{
 			name: "merge connection_pool_size",
 			base: &config.ClientConfig{
-				ConnectionPoolSize: 10,
+				ConnectionPoolSize: ptr(10),
 			},
 			source: &config.ClientConfig{
-				ConnectionPoolSize: 20,
+				ConnectionPoolSize: ptr(20),
 			},
 			expected: &config.ClientConfig{
-				ConnectionPoolSize: 20,
+				ConnectionPoolSize: ptr(20),
 			},
 		},
 		{
 			name: "merge connection_timeout",
 			base: &config.ClientConfig{
-				ConnectionTimeout: config.Duration(60 * time.Second),
+				ConnectionTimeout: ptr(config.Duration(60 * time.Second)),
 			},
 			source: &config.ClientConfig{
-				ConnectionTimeout: config.Duration(90 * time.Second),
+				ConnectionTimeout: ptr(config.Duration(90 * time.Second)),
 			},
 			expected: &config.ClientConfig{
-				ConnectionTimeout: config.Duration(90 * time.Second),
+				ConnectionTimeout: ptr(config.Duration(90 * time.Second)),
 			},
 		},
 		{
-			name: "zero values preserve base",
+			name: "unset values preserve base",
 			base: &config.ClientConfig{
-				Timeout:            config.Duration(1 * time.Minute),
-				ConnectionPoolSize: 10,
+				Timeout:            ptr(config.Duration(1 * time.Minute)),
+				ConnectionPoolSize: ptr(10),
 				Retry: config.RetryConfig{
-					MaxRetries: 3,
+					MaxRetries: ptr(3),
 				},
 			},
-			source: &config.ClientConfig{
-				Timeout:            0,
-				ConnectionPoolSize: 0,
-			},
+			source: &config.ClientConfig{},
 			expected: &config.ClientConfig{
-				Timeout:            config.Duration(1 * time.Minute),
-				ConnectionPoolSize: 10,
+				Timeout:            ptr(config.Duration(1 * time.Minute)),
+				ConnectionPoolSize: ptr(10),
 				Retry: config.RetryConfig{
-					MaxRetries: 3,
+					MaxRetries: ptr(3),
 				},
 			},
 		},
+		{
+			name: "explicit zero values override base",
+			base: &config.ClientConfig{
+				Timeout:            ptr(config.Duration(1 * time.Minute)),
+				ConnectionPoolSize: ptr(10),
+				ConnectionTimeout:  ptr(config.Duration(30 * time.Second)),
+			},
+			source: &config.ClientConfig{
+				Timeout:            ptr(config.Duration(0)),
+				ConnectionPoolSize: ptr(0),
+				ConnectionTimeout:  ptr(config.Duration(0)),
+			},
+			expected: &config.ClientConfig{
+				Timeout:            ptr(config.Duration(0)),
+				ConnectionPoolSize: ptr(0),
+				ConnectionTimeout:  ptr(config.Duration(0)),
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.base.Merge(tt.source)
 
-			if tt.base.Timeout != tt.expected.Timeout {
-				t.Errorf("got timeout %v, want %v", tt.base.Timeout, tt.expected.Timeout)
+			if !reflect.DeepEqual(tt.base, tt.expected) {
+				t.Errorf("got %+v, want %+v", tt.base, tt.expected)
 			}
+		})
+	}
+}
 
-			if tt.base.Retry.MaxRetries != tt.expected.Retry.MaxRetries {
-				t.Errorf("got max_retries %d, want %d", tt.base.Retry.MaxRetries, tt.expected.Retry.MaxRetries)
-			}
+func TestClientConfig_Cache(t *testing.T) {
+	var cfg config.ClientConfig
+	if err := json.Unmarshal([]byte(`{"cache": {"backend": "disk", "dir": ".cache/responses", "ttl": "1h"}}`), &cfg); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
 
-			if tt.base.ConnectionPoolSize != tt.expected.ConnectionPoolSize {
-				t.Errorf("got connection_pool_size %d, want %d", tt.base.ConnectionPoolSize, tt.expected.ConnectionPoolSize)
-			}
+	if cfg.Cache == nil || cfg.Cache.Backend != config.CacheBackendDisk || cfg.Cache.EntryTTL() != time.Hour {
+		t.Fatalf("got cache %+v", cfg.Cache)
+	}
 
-			if tt.base.ConnectionTimeout != tt.expected.ConnectionTimeout {
-				t.Errorf("got connection_timeout %v, want %v", tt.base.ConnectionTimeout, tt.expected.ConnectionTimeout)
-			}
-		})
+	base := config.DefaultClientConfig()
+	if base.Cache != nil {
+		t.Error("expected caching to be disabled by default")
+	}
+
+	base.Merge(&cfg)
+	if base.Cache == nil || base.Cache.Dir != ".cache/responses" {
+		t.Errorf("got merged cache %+v", base.Cache)
+	}
+
+	base.Merge(&config.ClientConfig{Cache: &config.CacheConfig{Capacity: ptr(50)}})
+	if base.Cache.MaxEntries() != 50 || base.Cache.Backend != config.CacheBackendDisk {
+		t.Errorf("got merged cache %+v, want capacity override with backend preserved", base.Cache)
+	}
+
+	base.Merge(&config.ClientConfig{Cache: &config.CacheConfig{TTL: ptr(config.Duration(0))}})
+	if base.Cache.EntryTTL() != 0 || base.Cache.MaxEntries() != 50 {
+		t.Errorf("got merged cache %+v, want explicit zero ttl with capacity preserved", base.Cache)
 	}
 }
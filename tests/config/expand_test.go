@@ -0,0 +1,197 @@
+package config_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/go-agents/pkg/config"
+)
+
+func TestExpandString(t *testing.T) {
+	t.Setenv("GA_TEST_TOKEN", "secret")
+	t.Setenv("GA_TEST_EMPTY", "")
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "token.txt"), []byte("from-file\n"), 0o600); err != nil {
+		t.Fatalf("failed to write secret file: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "plain", input: "no references", want: "no references"},
+		{name: "variable", input: "${GA_TEST_TOKEN}", want: "secret"},
+		{name: "embedded", input: "Bearer ${GA_TEST_TOKEN}!", want: "Bearer secret!"},
+		{name: "default unset", input: "${GA_TEST_MISSING:-fallback}", want: "fallback"},
+		{name: "default empty", input: "${GA_TEST_EMPTY:-fallback}", want: "fallback"},
+		{name: "set but empty", input: "[${GA_TEST_EMPTY}]", want: "[]"},
+		{name: "escape", input: "$${GA_TEST_TOKEN}", want: "${GA_TEST_TOKEN}"},
+		{name: "relative file", input: "file://token.txt", want: "from-file"},
+		{name: "absolute file", input: "file://" + filepath.Join(dir, "token.txt"), want: "from-file"},
+		{name: "unset", input: "${GA_TEST_MISSING}", wantErr: true},
+		{name: "unterminated", input: "${GA_TEST_TOKEN", wantErr: true},
+		{name: "missing file", input: "file://missing.txt", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := config.ExpandString(tt.input, dir)
+
+			if tt.wantErr {
+				if !errors.Is(err, config.ErrUnresolvedReference) {
+					t.Errorf("got error %v, want ErrUnresolvedReference", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ExpandString failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadAgentConfig_Expansion(t *testing.T) {
+	t.Setenv("GA_TEST_BASE_URL", "https://example.openai.azure.com/openai")
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "token"), []byte("file-token\n"), 0o600); err != nil {
+		t.Fatalf("failed to write secret file: %v", err)
+	}
+
+	filename := filepath.Join(dir, "config.json")
+	data := `{
+		"name": "expanded",
+		"provider": {
+			"name": "azure",
+			"base_url": "${GA_TEST_BASE_URL}",
+			"options": {
+				"deployment": "${GA_TEST_DEPLOYMENT:-gpt-4o}",
+				"token": "file://token",
+				"nested": {"values": ["${GA_TEST_BASE_URL}"]}
+			}
+		},
+		"model": {"name": "gpt-4o"}
+	}`
+	if err := os.WriteFile(filename, []byte(data), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cfg, err := config.LoadAgentConfig(filename)
+	if err != nil {
+		t.Fatalf("LoadAgentConfig failed: %v", err)
+	}
+
+	if cfg.Provider.BaseURL != "https://example.openai.azure.com/openai" {
+		t.Errorf("got base_url %q", cfg.Provider.BaseURL)
+	}
+	if cfg.Provider.Options["deployment"] != "gpt-4o" {
+		t.Errorf("got deployment %v, want default", cfg.Provider.Options["deployment"])
+	}
+	if cfg.Provider.Options["token"] != "file-token" {
+		t.Errorf("got token %v, want file content", cfg.Provider.Options["token"])
+	}
+
+	nested := cfg.Provider.Options["nested"].(map[string]any)["values"].([]any)
+	if nested[0] != "https://example.openai.azure.com/openai" {
+		t.Errorf("got nested value %v", nested[0])
+	}
+}
+
+func TestLoadAgentConfig_UnresolvedReferences(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config.json")
+	data := `{"provider": {"base_url": "${GA_TEST_UNSET_URL}", "options": {"token": "${GA_TEST_UNSET_TOKEN}"}}}`
+	if err := os.WriteFile(filename, []byte(data), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	_, err := config.LoadAgentConfig(filename)
+	if !errors.Is(err, config.ErrUnresolvedReference) {
+		t.Fatalf("got error %v, want ErrUnresolvedReference", err)
+	}
+
+	for _, want := range []string{"provider.base_url", "GA_TEST_UNSET_URL", "provider.options.token", "GA_TEST_UNSET_TOKEN"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	t.Setenv("GOAGENTS_NAME", "from-env")
+	t.Setenv("GOAGENTS_PROVIDER_BASE_URL", "http://env:11434")
+	t.Setenv("GOAGENTS_PROVIDER_OPTIONS_API_KEY", "env-key")
+	t.Setenv("GOAGENTS_CLIENT_TIMEOUT", "45s")
+	t.Setenv("GOAGENTS_CLIENT_RETRY_MAX_RETRIES", "7")
+	t.Setenv("GOAGENTS_MODEL_CAPABILITIES_CHAT_MAX_TOKENS", "512")
+
+	cfg := config.DefaultAgentConfig()
+	cfg.Model.Capabilities = map[string]map[string]any{"chat": {"temperature": 0.7}}
+
+	if err := config.ApplyEnv(&cfg, config.EnvPrefix); err != nil {
+		t.Fatalf("ApplyEnv failed: %v", err)
+	}
+
+	if cfg.Name != "from-env" {
+		t.Errorf("got name %q", cfg.Name)
+	}
+	if cfg.Provider.BaseURL != "http://env:11434" {
+		t.Errorf("got base_url %q", cfg.Provider.BaseURL)
+	}
+	if cfg.Provider.Options["api_key"] != "env-key" {
+		t.Errorf("got options %v", cfg.Provider.Options)
+	}
+	if cfg.Client.RequestTimeout() != 45*time.Second {
+		t.Errorf("got timeout %v", cfg.Client.RequestTimeout())
+	}
+	if cfg.Client.Retry.Retries() != 7 {
+		t.Errorf("got max_retries %d", cfg.Client.Retry.Retries())
+	}
+
+	chat := cfg.Model.Capabilities["chat"]
+	if chat["max_tokens"] != float64(512) || chat["temperature"] != 0.7 {
+		t.Errorf("got chat capabilities %v, want max_tokens added alongside temperature", chat)
+	}
+}
+
+func TestApplyEnv_UnderscoredMapKeys(t *testing.T) {
+	t.Setenv("GOAGENTS_MODEL_CAPABILITIES_IMAGE_GENERATION_SIZE", "512x512")
+
+	cfg := config.DefaultAgentConfig()
+	cfg.Model.Name = "gpt-image-1"
+
+	if err := config.ApplyEnv(&cfg, config.EnvPrefix); err != nil {
+		t.Fatalf("ApplyEnv failed: %v", err)
+	}
+
+	if got := cfg.Model.Capabilities["image_generation"]["size"]; got != "512x512" {
+		t.Errorf("got image_generation size %v, capabilities %v", got, cfg.Model.Capabilities)
+	}
+	if _, ok := cfg.Model.Capabilities["image"]; ok {
+		t.Error("image_generation split at the first underscore")
+	}
+
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate failed: %v", err)
+	}
+}
+
+func TestApplyEnv_InvalidValue(t *testing.T) {
+	t.Setenv("GOAGENTS_CLIENT_CONNECTION_POOL_SIZE", "many")
+
+	cfg := config.DefaultAgentConfig()
+	err := config.ApplyEnv(&cfg, config.EnvPrefix)
+	if err == nil || !strings.Contains(err.Error(), "GOAGENTS_CLIENT_CONNECTION_POOL_SIZE") {
+		t.Errorf("got error %v, want error naming the variable", err)
+	}
+}
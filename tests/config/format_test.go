@@ -0,0 +1,254 @@
+package config_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/go-agents/pkg/config"
+)
+
+const formatJSON = `{
+	"name": "format-agent",
+	"system_prompt": "You are helpful.",
+	"client": {
+		"timeout": "24s",
+		"retry": {"max_retries": 0, "initial_backoff": "1s", "jitter": false},
+		"connection_timeout": 9000000000
+	},
+	"provider": {
+		"name": "azure",
+		"base_url": "https://example.openai.azure.com/openai",
+		"options": {"deployment": "gpt-4o", "auth_type": "api_key"}
+	},
+	"model": {
+		"name": "gpt-4o",
+		"capabilities": {
+			"chat": {"max_tokens": 4096, "temperature": 0.7, "stop": ["END"]},
+			"vision": {"vision_options": {"detail": "high"}}
+		}
+	}
+}`
+
+const formatYAML = `# Agent configuration with comments
+name: format-agent
+system_prompt: You are helpful.
+client:
+  timeout: 24s
+  retry:
+    max_retries: 0
+    initial_backoff: 1s
+    jitter: false
+  connection_timeout: 9000000000 # nanoseconds
+provider:
+  name: azure
+  base_url: https://example.openai.azure.com/openai
+  options:
+    deployment: gpt-4o
+    auth_type: api_key
+model:
+  name: gpt-4o
+  capabilities:
+    chat:
+      max_tokens: 4096
+      temperature: 0.7
+      stop: [END]
+    vision:
+      vision_options:
+        detail: high
+`
+
+const formatTOML = `# Agent configuration with comments
+name = "format-agent"
+system_prompt = "You are helpful."
+
+[client]
+timeout = "24s"
+connection_timeout = 9000000000
+
+[client.retry]
+max_retries = 0
+initial_backoff = "1s"
+jitter = false
+
+[provider]
+name = "azure"
+base_url = "https://example.openai.azure.com/openai"
+
+[provider.options]
+deployment = "gpt-4o"
+auth_type = "api_key"
+
+[model]
+name = "gpt-4o"
+
+[model.capabilities.chat]
+max_tokens = 4096
+temperature = 0.7
+stop = ["END"]
+
+[model.capabilities.vision.vision_options]
+detail = "high"
+`
+
+func writeConfig(t *testing.T, name, data string) string {
+	t.Helper()
+
+	filename := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(filename, []byte(data), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return filename
+}
+
+func TestFormatOf(t *testing.T) {
+	tests := map[string]config.Format{
+		"config.json":  config.FormatJSON,
+		"config.yaml":  config.FormatYAML,
+		"config.YML":   config.FormatYAML,
+		"config.toml":  config.FormatTOML,
+		"config":       config.FormatJSON,
+		"dir.yaml/cfg": config.FormatJSON,
+	}
+
+	for filename, want := range tests {
+		if got := config.FormatOf(filename); got != want {
+			t.Errorf("FormatOf(%q) = %q, want %q", filename, got, want)
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if f, err := config.ParseFormat("YML"); err != nil || f != config.FormatYAML {
+		t.Errorf("got %q, %v, want yaml", f, err)
+	}
+
+	if _, err := config.ParseFormat("xml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestLoadAgentConfig_Formats(t *testing.T) {
+	want, err := config.LoadAgentConfig(writeConfig(t, "config.json", formatJSON))
+	if err != nil {
+		t.Fatalf("LoadAgentConfig(json) failed: %v", err)
+	}
+
+	if want.Client.IdleTimeout() != 9*time.Second {
+		t.Errorf("got connection_timeout %v, want 9s", want.Client.IdleTimeout())
+	}
+
+	for name, data := range map[string]string{
+		"config.yaml": formatYAML,
+		"config.toml": formatTOML,
+	} {
+		t.Run(name, func(t *testing.T) {
+			got, err := config.LoadAgentConfig(writeConfig(t, name, data))
+			if err != nil {
+				t.Fatalf("LoadAgentConfig failed: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				gotJSON, _ := json.Marshal(got)
+				wantJSON, _ := json.Marshal(want)
+				t.Errorf("config differs from JSON equivalent:\n got %s\nwant %s", gotJSON, wantJSON)
+			}
+		})
+	}
+}
+
+func TestLoadAgentConfig_YAMLExpansion(t *testing.T) {
+	t.Setenv("GA_TEST_YAML_URL", "http://yaml-host:11434")
+
+	cfg, err := config.LoadAgentConfig(writeConfig(t, "config.yml", "provider:\n  base_url: ${GA_TEST_YAML_URL}\n"))
+	if err != nil {
+		t.Fatalf("LoadAgentConfig failed: %v", err)
+	}
+
+	if cfg.Provider.BaseURL != "http://yaml-host:11434" {
+		t.Errorf("got base_url %q", cfg.Provider.BaseURL)
+	}
+}
+
+func TestLoadAgentConfig_FormatErrors(t *testing.T) {
+	for name, data := range map[string]string{
+		"bad.yaml": "name: [unclosed\n",
+		"bad.toml": "name = \n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := config.LoadAgentConfig(writeConfig(t, name, data))
+			if err == nil || !strings.Contains(err.Error(), name) {
+				t.Errorf("got error %v, want error naming %s", err, name)
+			}
+		})
+	}
+}
+
+func TestConvert_RoundTrip(t *testing.T) {
+	var want any
+	if err := json.Unmarshal([]byte(formatJSON), &want); err != nil {
+		t.Fatalf("failed to parse JSON: %v", err)
+	}
+
+	for _, format := range []config.Format{config.FormatJSON, config.FormatYAML, config.FormatTOML} {
+		t.Run(string(format), func(t *testing.T) {
+			converted, err := config.Convert([]byte(formatJSON), config.FormatJSON, format)
+			if err != nil {
+				t.Fatalf("Convert to %s failed: %v", format, err)
+			}
+
+			back, err := config.ToJSON(converted, format)
+			if err != nil {
+				t.Fatalf("ToJSON from %s failed: %v\n%s", format, err, converted)
+			}
+
+			var got any
+			if err := json.Unmarshal(back, &got); err != nil {
+				t.Fatalf("failed to parse round trip: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("round trip through %s changed document:\n%s", format, converted)
+			}
+		})
+	}
+}
+
+func TestDump(t *testing.T) {
+	cfg := config.DefaultAgentConfig()
+	cfg.Model.Name = "llama3.2:3b"
+	cfg.Provider.Options["api_version"] = "2024-08-01"
+
+	out, err := config.Dump(cfg, config.FormatYAML)
+	if err != nil {
+		t.Fatalf("Dump failed: %v", err)
+	}
+
+	yamlText := string(out)
+	if !strings.HasPrefix(yamlText, "name: default-agent\n") {
+		t.Errorf("YAML should preserve field order, got:\n%s", yamlText)
+	}
+	for _, want := range []string{"timeout: 2m0s", "max_retries: 3", "jitter: true", `api_version: "2024-08-01"`} {
+		if !strings.Contains(yamlText, want) {
+			t.Errorf("YAML missing %q:\n%s", want, yamlText)
+		}
+	}
+
+	out, err = config.Dump(cfg, config.FormatTOML)
+	if err != nil {
+		t.Fatalf("Dump failed: %v", err)
+	}
+
+	reloaded, err := config.LoadAgentConfig(writeConfig(t, "dump.toml", string(out)))
+	if err != nil {
+		t.Fatalf("failed to load dumped TOML: %v\n%s", err, out)
+	}
+
+	if !reflect.DeepEqual(*reloaded, cfg) {
+		t.Errorf("dumped TOML did not reload to the same config:\n%s", out)
+	}
+}
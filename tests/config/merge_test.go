@@ -0,0 +1,257 @@
+package config_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/JaimeStill/go-agents/pkg/config"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// layer merges each JSON document onto the default agent configuration in order.
+func layer(t *testing.T, layers ...string) config.AgentConfig {
+	t.Helper()
+
+	cfg := config.DefaultAgentConfig()
+	for _, data := range layers {
+		var loaded config.AgentConfig
+		if err := json.Unmarshal([]byte(data), &loaded); err != nil {
+			t.Fatalf("failed to unmarshal layer: %v", err)
+		}
+		cfg.Merge(&loaded)
+	}
+	return cfg
+}
+
+func TestMerge_Layered_ExplicitZero(t *testing.T) {
+	tests := []struct {
+		name        string
+		layers      []string
+		wantRetries int
+		wantJitter  bool
+	}{
+		{
+			name:        "defaults",
+			wantRetries: 3,
+			wantJitter:  true,
+		},
+		{
+			name:        "partial retry preserves jitter",
+			layers:      []string{`{"client": {"retry": {"initial_backoff": "2s"}}}`},
+			wantRetries: 3,
+			wantJitter:  true,
+		},
+		{
+			name:        "explicit zero disables retries",
+			layers:      []string{`{"client": {"retry": {"max_retries": 0}}}`},
+			wantRetries: 0,
+			wantJitter:  true,
+		},
+		{
+			name:        "explicit false disables jitter",
+			layers:      []string{`{"client": {"retry": {"jitter": false}}}`},
+			wantRetries: 3,
+			wantJitter:  false,
+		},
+		{
+			name: "later layer re-enables",
+			layers: []string{
+				`{"client": {"retry": {"max_retries": 0, "jitter": false}}}`,
+				`{"client": {"timeout": "10s"}}`,
+				`{"client": {"retry": {"max_retries": 5, "jitter": true}}}`,
+			},
+			wantRetries: 5,
+			wantJitter:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := layer(t, tt.layers...)
+
+			if got := cfg.Client.Retry.Retries(); got != tt.wantRetries {
+				t.Errorf("got max_retries %d, want %d", got, tt.wantRetries)
+			}
+			if got := cfg.Client.Retry.JitterEnabled(); got != tt.wantJitter {
+				t.Errorf("got jitter %v, want %v", got, tt.wantJitter)
+			}
+		})
+	}
+}
+
+func TestMerge_Layered_NullRemoves(t *testing.T) {
+	cfg := layer(t,
+		`{
+			"provider": {"options": {"api_key": "base-key", "deployment": "base"}},
+			"model": {"capabilities": {
+				"chat": {"temperature": 0.7, "top_p": 0.9},
+				"vision": {"max_tokens": 1024}
+			}}
+		}`,
+		`{
+			"provider": {"options": {"api_key": null}},
+			"model": {"capabilities": {
+				"chat": {"top_p": null},
+				"vision": null,
+				"embeddings": {"dimensions": 256, "encoding": null}
+			}}
+		}`,
+	)
+
+	if _, ok := cfg.Provider.Options["api_key"]; ok {
+		t.Error("api_key should be removed by null")
+	}
+	if cfg.Provider.Options["deployment"] != "base" {
+		t.Errorf("got deployment %v, want base", cfg.Provider.Options["deployment"])
+	}
+
+	want := map[string]map[string]any{
+		"chat":       {"temperature": 0.7},
+		"embeddings": {"dimensions": float64(256)},
+	}
+	if !reflect.DeepEqual(cfg.Model.Capabilities, want) {
+		t.Errorf("got capabilities %v, want %v", cfg.Model.Capabilities, want)
+	}
+}
+
+func TestMerge_RoundTrip(t *testing.T) {
+	merged := layer(t,
+		`{
+			"name": "base",
+			"client": {"retry": {"max_retries": 5}, "cache": {"backend": "disk", "dir": ".cache", "capacity": 50, "ttl": "1h"}},
+			"provider": {"options": {"deployment": "base"}},
+			"model": {"name": "gpt-4o", "capabilities": {"chat": {"temperature": 0.7}}}
+		}`,
+		`{
+			"client": {"retry": {"max_retries": 0, "jitter": false}, "cache": {"ttl": 0}},
+			"model": {"capabilities": {"chat": {"max_tokens": 512}}}
+		}`,
+	)
+
+	data, err := json.Marshal(merged)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	reloaded := layer(t, string(data))
+	if !reflect.DeepEqual(reloaded, merged) {
+		t.Errorf("round trip changed config:\n got %+v\nwant %+v", reloaded, merged)
+	}
+
+	if reloaded.Client.Retry.MaxRetries == nil || reloaded.Client.Retry.Jitter == nil {
+		t.Fatal("explicit zero values should survive marshaling")
+	}
+	if reloaded.Client.Retry.Retries() != 0 || reloaded.Client.Retry.JitterEnabled() {
+		t.Errorf("got retries %d jitter %v, want 0 false", reloaded.Client.Retry.Retries(), reloaded.Client.Retry.JitterEnabled())
+	}
+
+	cache := reloaded.Client.Cache
+	if cache == nil || cache.TTL == nil {
+		t.Fatal("explicit zero cache ttl should survive marshaling")
+	}
+	if cache.EntryTTL() != 0 || cache.MaxEntries() != 50 {
+		t.Errorf("got cache ttl %v capacity %d, want 0 and 50", cache.EntryTTL(), cache.MaxEntries())
+	}
+}
+
+func TestMerge_Layered_ExplicitZeroFields(t *testing.T) {
+	base := `{
+		"client": {
+			"timeout": "1m",
+			"connection_pool_size": 20,
+			"connection_timeout": "15s",
+			"retry": {"initial_backoff": "2s", "max_backoff": "1m", "backoff_multiplier": 3},
+			"cache": {"capacity": 100, "ttl": "1h"}
+		},
+		"model": {"name": "gpt-4o", "context_window": 8000, "max_output_tokens": 1000, "max_images": 4, "max_image_bytes": 1024}
+	}`
+	zero := `{
+		"client": {
+			"timeout": "0s",
+			"connection_pool_size": 0,
+			"connection_timeout": 0,
+			"retry": {"initial_backoff": 0, "max_backoff": 0, "backoff_multiplier": 0},
+			"cache": {"capacity": 0, "ttl": 0}
+		},
+		"model": {"context_window": 0, "max_output_tokens": 0, "max_images": 0, "max_image_bytes": 0}
+	}`
+
+	kept := layer(t, base, `{"model": {"name": "gpt-4o"}}`)
+	cfg := layer(t, base, zero)
+
+	tests := []struct {
+		field string
+		kept  any
+		got   any
+	}{
+		{"client.timeout", kept.Client.RequestTimeout(), cfg.Client.RequestTimeout()},
+		{"client.connection_pool_size", kept.Client.PoolSize(), cfg.Client.PoolSize()},
+		{"client.connection_timeout", kept.Client.IdleTimeout(), cfg.Client.IdleTimeout()},
+		{"client.retry.initial_backoff", kept.Client.Retry.InitialDelay(), cfg.Client.Retry.InitialDelay()},
+		{"client.retry.max_backoff", kept.Client.Retry.MaxDelay(), cfg.Client.Retry.MaxDelay()},
+		{"client.retry.backoff_multiplier", kept.Client.Retry.Multiplier(), cfg.Client.Retry.Multiplier()},
+		{"client.cache.capacity", kept.Client.Cache.MaxEntries(), cfg.Client.Cache.MaxEntries()},
+		{"client.cache.ttl", kept.Client.Cache.EntryTTL(), cfg.Client.Cache.EntryTTL()},
+		{"model.context_window", kept.Model.Window(), cfg.Model.Window()},
+		{"model.max_output_tokens", kept.Model.OutputLimit(), cfg.Model.OutputLimit()},
+		{"model.max_images", kept.Model.ImageLimit(), cfg.Model.ImageLimit()},
+		{"model.max_image_bytes", kept.Model.ImageBytesLimit(), cfg.Model.ImageBytesLimit()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			if reflect.ValueOf(tt.kept).IsZero() {
+				t.Errorf("got %v after an unset layer, want the base value kept", tt.kept)
+			}
+			if !reflect.ValueOf(tt.got).IsZero() {
+				t.Errorf("got %v after an explicit zero layer, want 0", tt.got)
+			}
+		})
+	}
+
+	resolved := cfg.Model.Resolve()
+	if resolved.Window() != 0 {
+		t.Errorf("got resolved context_window %d, want explicit 0 to override the catalog", resolved.Window())
+	}
+}
+
+func TestRetryConfig_Merge_CopiesPointers(t *testing.T) {
+	base := config.DefaultRetryConfig()
+	source := config.RetryConfig{MaxRetries: ptr(1), Jitter: ptr(false)}
+
+	base.Merge(&source)
+	*source.MaxRetries = 9
+
+	if base.Retries() != 1 {
+		t.Errorf("got max_retries %d, want 1; merge should not alias source", base.Retries())
+	}
+	if base.JitterEnabled() {
+		t.Error("got jitter true, want false")
+	}
+}
+
+func TestLoadAgentConfig_ExplicitZero(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config.json")
+	data := `{"client": {"retry": {"max_retries": 0}}, "model": {"name": "llama3.2:3b"}}`
+	if err := os.WriteFile(filename, []byte(data), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cfg, err := config.LoadAgentConfig(filename)
+	if err != nil {
+		t.Fatalf("LoadAgentConfig failed: %v", err)
+	}
+
+	if cfg.Client.Retry.Retries() != 0 {
+		t.Errorf("got max_retries %d, want 0", cfg.Client.Retry.Retries())
+	}
+	if !cfg.Client.Retry.JitterEnabled() {
+		t.Error("jitter should keep its default")
+	}
+}
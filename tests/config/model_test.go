@@ -247,3 +247,67 @@ func TestModelConfig_Merge(t *testing.T) {
 		})
 	}
 }
+
+func TestModelConfig_ContextMetadata(t *testing.T) {
+	jsonData := `{
+		"name": "gpt-4o",
+		"context_window": 128000,
+		"max_output_tokens": 16384,
+		"tokenizer": "o200k_base",
+		"context_policy": "truncate"
+	}`
+
+	var source config.ModelConfig
+	if err := json.Unmarshal([]byte(jsonData), &source); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	cfg := config.DefaultModelConfig()
+	cfg.Merge(&source)
+
+	if cfg.Window() != 128000 {
+		t.Errorf("got context_window %d, want 128000", cfg.Window())
+	}
+
+	if cfg.OutputLimit() != 16384 {
+		t.Errorf("got max_output_tokens %d, want 16384", cfg.OutputLimit())
+	}
+
+	if cfg.Tokenizer != "o200k_base" {
+		t.Errorf("got tokenizer %q, want o200k_base", cfg.Tokenizer)
+	}
+
+	if cfg.ContextPolicy != config.ContextPolicyTruncate {
+		t.Errorf("got context_policy %q, want %q", cfg.ContextPolicy, config.ContextPolicyTruncate)
+	}
+
+	cfg.Merge(&config.ModelConfig{Name: "other"})
+
+	if cfg.Window() != 128000 || cfg.ContextPolicy != config.ContextPolicyTruncate {
+		t.Error("unset source fields should not override context metadata")
+	}
+}
+
+func TestModelConfig_Reasoning(t *testing.T) {
+	var source config.ModelConfig
+	if err := json.Unmarshal([]byte(`{"name": "o4-mini", "reasoning": {"effort": "high"}}`), &source); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	cfg := config.DefaultModelConfig()
+	cfg.Merge(&source)
+
+	if cfg.Reasoning == nil || cfg.Reasoning.Effort != "high" || cfg.Reasoning.SystemRole != "" {
+		t.Fatalf("got reasoning %+v, want effort high", cfg.Reasoning)
+	}
+
+	cfg.Merge(&config.ModelConfig{Reasoning: &config.ReasoningConfig{SystemRole: config.SystemRoleUser}})
+
+	if cfg.Reasoning.Effort != "high" || cfg.Reasoning.SystemRole != config.SystemRoleUser {
+		t.Errorf("got reasoning %+v, want effort high with the user system role", cfg.Reasoning)
+	}
+
+	if source.Reasoning.SystemRole != "" {
+		t.Error("merge modified the source reasoning config")
+	}
+}
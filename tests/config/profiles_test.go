@@ -0,0 +1,211 @@
+package config_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/go-agents/pkg/config"
+)
+
+const profilesJSON = `{
+	"providers": {
+		"ollama": {"name": "ollama", "base_url": "http://localhost:11434"},
+		"azure-key": {
+			"name": "azure",
+			"base_url": "https://example.openai.azure.com/openai",
+			"options": {"deployment": "gpt-4o", "api_version": "2025-01-01-preview", "auth_type": "api_key"}
+		}
+	},
+	"models": {
+		"llama": {"name": "llama3.2:3b", "capabilities": {"chat": {"temperature": 0.7}, "tools": {}}},
+		"gpt-4o": {"name": "gpt-4o", "capabilities": {"vision": {"max_tokens": 4096}}}
+	},
+	"agents": {
+		"base": {
+			"system_prompt": "base prompt",
+			"client": {"timeout": "24s", "retry": {"max_retries": 1}},
+			"provider": "ollama",
+			"model": "llama"
+		},
+		"local": {"extends": "base", "name": "local-agent"},
+		"gpt4o-key": {"extends": "base", "provider": "azure-key", "model": "gpt-4o"},
+		"gpt4o-entra": {
+			"extends": "gpt4o-key",
+			"client": {"retry": {"max_retries": 0}},
+			"provider": {"options": {"auth_type": "bearer"}},
+			"model": {"capabilities": {"vision": {"detail": "high"}}}
+		}
+	}
+}`
+
+func writeProfiles(t *testing.T, data string) string {
+	t.Helper()
+
+	filename := filepath.Join(t.TempDir(), "profiles.json")
+	if err := os.WriteFile(filename, []byte(data), 0o600); err != nil {
+		t.Fatalf("failed to write profiles: %v", err)
+	}
+	return filename
+}
+
+func TestLoadProfiles(t *testing.T) {
+	profiles, err := config.LoadProfiles(writeProfiles(t, profilesJSON))
+	if err != nil {
+		t.Fatalf("LoadProfiles failed: %v", err)
+	}
+
+	want := []string{"base", "gpt4o-entra", "gpt4o-key", "local"}
+	if got := profiles.Names(); !slices.Equal(got, want) {
+		t.Errorf("got names %v, want %v", got, want)
+	}
+}
+
+func TestProfiles_Get_Inheritance(t *testing.T) {
+	profiles, err := config.LoadProfiles(writeProfiles(t, profilesJSON))
+	if err != nil {
+		t.Fatalf("LoadProfiles failed: %v", err)
+	}
+
+	t.Run("named and inherited", func(t *testing.T) {
+		cfg, err := profiles.Get("local")
+		if err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+
+		if cfg.Name != "local-agent" {
+			t.Errorf("got name %q, want local-agent", cfg.Name)
+		}
+		if cfg.SystemPrompt != "base prompt" {
+			t.Errorf("got system_prompt %q, want inherited", cfg.SystemPrompt)
+		}
+		if cfg.Client.RequestTimeout() != 24*time.Second {
+			t.Errorf("got timeout %v, want 24s", cfg.Client.RequestTimeout())
+		}
+		if cfg.Client.Retry.InitialDelay() != time.Second {
+			t.Errorf("got initial_backoff %v, want default", cfg.Client.Retry.InitialDelay())
+		}
+		if cfg.Model.Name != "llama3.2:3b" || cfg.Provider.BaseURL != "http://localhost:11434" {
+			t.Errorf("got model %q provider %q", cfg.Model.Name, cfg.Provider.BaseURL)
+		}
+	})
+
+	t.Run("references replace inherited", func(t *testing.T) {
+		cfg, err := profiles.Get("gpt4o-key")
+		if err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+
+		if cfg.Name != "gpt4o-key" {
+			t.Errorf("got name %q, want profile name", cfg.Name)
+		}
+		if cfg.Provider.Name != "azure" || cfg.Provider.Options["auth_type"] != "api_key" {
+			t.Errorf("got provider %+v", cfg.Provider)
+		}
+		if _, ok := cfg.Model.Capabilities["chat"]; ok {
+			t.Errorf("model reference should replace inherited capabilities, got %v", cfg.Model.Capabilities)
+		}
+	})
+
+	t.Run("inline merges into inherited", func(t *testing.T) {
+		cfg, err := profiles.Get("gpt4o-entra")
+		if err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+
+		if cfg.Provider.Options["auth_type"] != "bearer" || cfg.Provider.Options["deployment"] != "gpt-4o" {
+			t.Errorf("got options %v, want bearer auth on inherited deployment", cfg.Provider.Options)
+		}
+
+		vision := cfg.Model.Capabilities["vision"]
+		if vision["detail"] != "high" || vision["max_tokens"] != float64(4096) {
+			t.Errorf("got vision options %v", vision)
+		}
+
+		if cfg.Client.Retry.Retries() != 0 {
+			t.Errorf("got max_retries %d, want explicit 0", cfg.Client.Retry.Retries())
+		}
+		if cfg.SystemPrompt != "base prompt" {
+			t.Errorf("got system_prompt %q, want inherited through two levels", cfg.SystemPrompt)
+		}
+	})
+}
+
+func TestProfiles_Get_Independent(t *testing.T) {
+	profiles, err := config.LoadProfiles(writeProfiles(t, profilesJSON))
+	if err != nil {
+		t.Fatalf("LoadProfiles failed: %v", err)
+	}
+
+	first, _ := profiles.Get("gpt4o-key")
+	first.Provider.Options["token"] = "secret"
+	first.Model.Capabilities["vision"]["max_tokens"] = 1
+
+	second, _ := profiles.Get("gpt4o-entra")
+	if _, ok := second.Provider.Options["token"]; ok {
+		t.Error("modifying one profile should not affect another")
+	}
+	if second.Model.Capabilities["vision"]["max_tokens"] != float64(4096) {
+		t.Error("modifying one profile should not affect its children")
+	}
+}
+
+func TestProfiles_Get_NotFound(t *testing.T) {
+	profiles, err := config.LoadProfiles(writeProfiles(t, profilesJSON))
+	if err != nil {
+		t.Fatalf("LoadProfiles failed: %v", err)
+	}
+
+	_, err = profiles.Get("missing")
+	if !errors.Is(err, config.ErrProfileNotFound) {
+		t.Errorf("got error %v, want ErrProfileNotFound", err)
+	}
+}
+
+func TestProfiles_Get_Env(t *testing.T) {
+	t.Setenv("GOAGENTS_PROVIDER_OPTIONS_TOKEN", "env-token")
+
+	profiles, err := config.LoadProfiles(writeProfiles(t, profilesJSON))
+	if err != nil {
+		t.Fatalf("LoadProfiles failed: %v", err)
+	}
+
+	cfg, err := profiles.Get("gpt4o-key")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if cfg.Provider.Options["token"] != "env-token" {
+		t.Errorf("got token %v, want env overlay", cfg.Provider.Options["token"])
+	}
+}
+
+func TestLoadProfiles_Errors(t *testing.T) {
+	data := `{
+		"providers": {"ollama": {"name": "ollama"}},
+		"agents": {
+			"a": {"extends": "b"},
+			"b": {"extends": "a"},
+			"orphan": {"extends": "missing"},
+			"bad-ref": {"provider": "azure", "model": "gpt-4o"}
+		}
+	}`
+
+	_, err := config.LoadProfiles(writeProfiles(t, data))
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	for _, want := range []string{
+		"agents.a.extends: inheritance cycle",
+		"agents.orphan.extends: profile not found: missing",
+		`agents.bad-ref.provider: unknown provider "azure"`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package config_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/go-agents/pkg/config"
+	_ "github.com/JaimeStill/go-agents/pkg/providers"
+)
+
+// schemaErrors checks a decoded JSON value against the subset of JSON Schema
+// produced by the config package and returns the paths of violations.
+func schemaErrors(root, s *config.Schema, v any, path string) []string {
+	if s.Ref != "" {
+		return schemaErrors(root, root.Defs[strings.TrimPrefix(s.Ref, "#/$defs/")], v, path)
+	}
+
+	var errs []string
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Sprintf("%s: %s", path, fmt.Sprintf(format, args...)))
+	}
+
+	switch s.Type {
+	case "object":
+		if _, ok := v.(map[string]any); !ok {
+			fail("want object")
+			return errs
+		}
+	case "array":
+		if _, ok := v.([]any); !ok {
+			fail("want array")
+			return errs
+		}
+	case "string":
+		if _, ok := v.(string); !ok {
+			fail("want string")
+			return errs
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			fail("want boolean")
+			return errs
+		}
+	case "number":
+		if _, ok := v.(float64); !ok {
+			fail("want number")
+			return errs
+		}
+	case "integer":
+		if f, ok := v.(float64); !ok || f != math.Trunc(f) {
+			fail("want integer")
+			return errs
+		}
+	}
+
+	if s.Enum != nil && !slices.ContainsFunc(s.Enum, func(e any) bool { return reflect.DeepEqual(e, v) }) {
+		fail("%v not in enum %v", v, s.Enum)
+	}
+
+	if s.Const != nil && !reflect.DeepEqual(s.Const, v) {
+		fail("want const %v", s.Const)
+	}
+
+	if str, ok := v.(string); ok && s.Pattern != "" && !regexp.MustCompile(s.Pattern).MatchString(str) {
+		fail("%q does not match pattern", str)
+	}
+
+	if obj, ok := v.(map[string]any); ok {
+		for _, key := range s.Required {
+			if _, ok := obj[key]; !ok {
+				fail("missing required %s", key)
+			}
+		}
+
+		for key, value := range obj {
+			child := path + "." + key
+			if s.PropertyNames != nil {
+				errs = append(errs, schemaErrors(root, s.PropertyNames, key, child)...)
+			}
+
+			if prop, ok := s.Properties[key]; ok {
+				errs = append(errs, schemaErrors(root, prop, value, child)...)
+			} else if additional, ok := s.AdditionalProperties.(*config.Schema); ok {
+				errs = append(errs, schemaErrors(root, additional, value, child)...)
+			} else if s.AdditionalProperties == false {
+				errs = append(errs, child+": unknown property")
+			}
+		}
+	}
+
+	if arr, ok := v.([]any); ok && s.Items != nil {
+		for i, item := range arr {
+			errs = append(errs, schemaErrors(root, s.Items, item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+
+	if len(s.AnyOf) > 0 && !slices.ContainsFunc(s.AnyOf, func(sub *config.Schema) bool {
+		return len(schemaErrors(root, sub, v, path)) == 0
+	}) {
+		fail("matches no anyOf schema")
+	}
+
+	if len(s.OneOf) > 0 {
+		matches := 0
+		for _, sub := range s.OneOf {
+			if len(schemaErrors(root, sub, v, path)) == 0 {
+				matches++
+			}
+		}
+		if matches != 1 {
+			fail("matches %d oneOf schemas", matches)
+		}
+	}
+
+	for _, sub := range s.AllOf {
+		errs = append(errs, schemaErrors(root, sub, v, path)...)
+	}
+
+	if s.If != nil && len(schemaErrors(root, s.If, v, path)) == 0 && s.Then != nil {
+		errs = append(errs, schemaErrors(root, s.Then, v, path)...)
+	}
+
+	return errs
+}
+
+func decodeFile(t *testing.T, filename string) any {
+	t.Helper()
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", filename, err)
+	}
+
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Fatalf("failed to parse %s: %v", filename, err)
+	}
+	return v
+}
+
+func TestAgentConfigSchema_ShippedConfigs(t *testing.T) {
+	schema := config.AgentConfigSchema()
+
+	files, err := filepath.Glob("../../tools/prompt-agent/config.*.json")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no configs found: %v", err)
+	}
+
+	for _, filename := range files {
+		t.Run(filepath.Base(filename), func(t *testing.T) {
+			if errs := schemaErrors(schema, schema, decodeFile(t, filename), "$"); len(errs) > 0 {
+				t.Errorf("schema violations:\n  %s", strings.Join(errs, "\n  "))
+			}
+		})
+	}
+}
+
+func TestProfileFileSchema_ShippedProfiles(t *testing.T) {
+	schema := config.ProfileFileSchema()
+
+	for _, filename := range []string{
+		"../../tools/prompt-agent/profiles.json",
+		"../../tools/classify-docs/profiles.json",
+	} {
+		t.Run(filename, func(t *testing.T) {
+			if errs := schemaErrors(schema, schema, decodeFile(t, filename), "$"); len(errs) > 0 {
+				t.Errorf("schema violations:\n  %s", strings.Join(errs, "\n  "))
+			}
+		})
+	}
+}
+
+func TestAgentConfigSchema_Violations(t *testing.T) {
+	doc := `{
+		"$schema": "./agent.schema.json",
+		"client": {"timout": "24s", "timeout": "24 seconds", "retry": {"max_retries": 1.5}},
+		"provider": {"name": "azure", "options": {"deployment": "gpt-4o", "auth_type": "oauth"}},
+		"model": {"capabilities": {"chat": {}, "audio": {}}, "context_policy": "drop"}
+	}`
+
+	var v any
+	if err := json.Unmarshal([]byte(doc), &v); err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	schema := config.AgentConfigSchema()
+	errs := strings.Join(schemaErrors(schema, schema, v, "$"), "\n")
+
+	for _, want := range []string{
+		"$.client.timout: unknown property",
+		"$.client.timeout: matches no anyOf schema",
+		"$.client.retry.max_retries: want integer",
+		"$.provider.options.auth_type: oauth not in enum",
+		"$.provider.options: missing required api_version",
+		"$.model.capabilities.audio: audio not in enum",
+		"$.model.context_policy: drop not in enum",
+	} {
+		if !strings.Contains(errs, want) {
+			t.Errorf("violations missing %q:\n%s", want, errs)
+		}
+	}
+
+	if strings.Contains(errs, "$schema") {
+		t.Errorf("$schema property should be allowed:\n%s", errs)
+	}
+}
+
+func TestSchemaFor_Duration(t *testing.T) {
+	schema := config.SchemaFor(config.Duration(0))
+	if len(schema.AnyOf) != 2 || schema.AnyOf[0].Type != "string" || schema.AnyOf[1].Type != "integer" {
+		t.Fatalf("got %+v, want string or integer", schema)
+	}
+
+	pattern := regexp.MustCompile(config.DurationPattern)
+	for _, valid := range []string{"24s", "1m30s", "500ms", "1.5h", "0", "-2m"} {
+		if !pattern.MatchString(valid) {
+			t.Errorf("pattern should match %q", valid)
+		}
+	}
+	for _, invalid := range []string{"", "24", "24 seconds", "1d", "s"} {
+		if pattern.MatchString(invalid) {
+			t.Errorf("pattern should not match %q", invalid)
+		}
+	}
+}
+
+func TestSchemaFor_Capabilities(t *testing.T) {
+	schema := config.SchemaFor(config.ModelConfig{})
+	capabilities := schema.Properties["capabilities"]
+
+	want := []any{"chat", "vision", "tools", "embeddings", "image_generation", "transcription", "speech", "rerank", "moderation"}
+	if !reflect.DeepEqual(capabilities.PropertyNames.Enum, want) {
+		t.Errorf("got capability keys %v, want %v", capabilities.PropertyNames.Enum, want)
+	}
+}
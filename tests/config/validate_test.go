@@ -0,0 +1,185 @@
+package config_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/go-agents/pkg/config"
+)
+
+// validationPaths returns the sorted paths reported by a validation error.
+func validationPaths(t *testing.T, err error) []string {
+	t.Helper()
+
+	var errs config.ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("got error %v, want ValidationErrors", err)
+	}
+
+	paths := make([]string, len(errs))
+	for i, e := range errs {
+		paths[i] = e.Path
+	}
+	slices.Sort(paths)
+	return paths
+}
+
+func TestAgentConfig_Validate_Defaults(t *testing.T) {
+	cfg := config.DefaultAgentConfig()
+	cfg.Model.Name = "llama3.2:3b"
+
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("default config should be valid: %v", err)
+	}
+}
+
+func TestAgentConfig_Validate_Aggregates(t *testing.T) {
+	cfg := &config.AgentConfig{
+		Client: &config.ClientConfig{
+			Retry: config.RetryConfig{
+				MaxRetries:     ptr(3),
+				InitialBackoff: ptr(config.Duration(time.Minute)),
+				MaxBackoff:     ptr(config.Duration(time.Second)),
+			},
+			Cache: &config.CacheConfig{Backend: "redis"},
+		},
+		Provider: &config.ProviderConfig{
+			Name:    "ollama",
+			BaseURL: "localhost:11434",
+		},
+		Model: &config.ModelConfig{
+			Name: "llama3.2:3b",
+			Capabilities: map[string]map[string]any{
+				"chat":  {},
+				"audio": {},
+			},
+		},
+	}
+
+	err := cfg.Validate()
+	got := validationPaths(t, err)
+	want := []string{
+		"client.cache.backend",
+		"client.retry.initial_backoff",
+		"model.capabilities.audio",
+		"provider.base_url",
+	}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("got paths %v, want %v", got, want)
+	}
+
+	if !strings.Contains(err.Error(), "4 problems") {
+		t.Errorf("error should summarize all problems: %v", err)
+	}
+}
+
+func TestRetryConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name  string
+		retry config.RetryConfig
+		want  []string
+	}{
+		{
+			name:  "retries disabled ignores backoffs",
+			retry: config.RetryConfig{MaxRetries: ptr(0)},
+		},
+		{
+			name:  "non-positive backoffs",
+			retry: config.RetryConfig{MaxRetries: ptr(2), InitialBackoff: ptr(config.Duration(0)), MaxBackoff: ptr(config.Duration(-time.Second))},
+			want:  []string{"retry.initial_backoff", "retry.max_backoff"},
+		},
+		{
+			name:  "negative retries",
+			retry: config.RetryConfig{MaxRetries: ptr(-1)},
+			want:  []string{"retry.max_retries"},
+		},
+		{
+			name:  "valid",
+			retry: config.DefaultRetryConfig(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultClientConfig()
+			cfg.Retry = tt.retry
+
+			err := cfg.Validate()
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+
+			if got := validationPaths(t, err); !slices.Equal(got, tt.want) {
+				t.Errorf("got paths %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModelConfig_Validate(t *testing.T) {
+	cfg := &config.ModelConfig{
+		ContextWindow:   ptr(1000),
+		MaxOutputTokens: ptr(1000),
+		ContextPolicy:   "drop",
+		Reasoning:       &config.ReasoningConfig{Effort: "extreme", SystemRole: "admin"},
+	}
+
+	got := validationPaths(t, cfg.Validate())
+	want := []string{"context_policy", "max_output_tokens", "name", "reasoning.effort", "reasoning.system_role"}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("got paths %v, want %v", got, want)
+	}
+}
+
+func TestModelConfig_Validate_Support(t *testing.T) {
+	cfg := &config.ModelConfig{
+		Name:          "support-model",
+		Protocols:     []string{"chat", "telepathy"},
+		MaxImages:     ptr(-1),
+		MaxImageBytes: ptr(-1),
+		Capabilities: map[string]map[string]any{
+			"chat":   {},
+			"vision": {},
+		},
+	}
+
+	got := validationPaths(t, cfg.Validate())
+	want := []string{"capabilities.vision", "max_image_bytes", "max_images", "protocols[1]"}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("got paths %v, want %v", got, want)
+	}
+}
+
+func TestProviderConfig_Validate_RegisteredValidator(t *testing.T) {
+	config.RegisterProviderValidator("validate-test", func(c *config.ProviderConfig) config.ValidationErrors {
+		return config.RequireOptions(c, "deployment", "region")
+	})
+
+	cfg := &config.ProviderConfig{
+		Name:    "validate-test",
+		BaseURL: "https://example.com",
+		Options: map[string]any{"region": "eastus", "deployment": ""},
+	}
+
+	got := validationPaths(t, cfg.Validate())
+	if !slices.Equal(got, []string{"options.deployment"}) {
+		t.Errorf("got paths %v, want [options.deployment]", got)
+	}
+
+	agentCfg := config.DefaultAgentConfig()
+	agentCfg.Provider = cfg
+	agentCfg.Model.Name = "model"
+
+	if got := validationPaths(t, agentCfg.Validate()); !slices.Equal(got, []string{"provider.options.deployment"}) {
+		t.Errorf("got paths %v, want [provider.options.deployment]", got)
+	}
+}
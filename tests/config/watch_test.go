@@ -0,0 +1,147 @@
+package config_test
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/go-agents/pkg/config"
+)
+
+const watchConfig = `{
+	"provider": {"name": "ollama", "base_url": "http://localhost:11434"},
+	"model": {"name": "llama3.2:3b", "capabilities": {"chat": {"temperature": 0.5}}}
+}`
+
+func rewrite(t *testing.T, filename, data string) {
+	t.Helper()
+
+	if err := os.WriteFile(filename, []byte(data), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+}
+
+func TestNewWatcher_InvalidConfig(t *testing.T) {
+	filename := writeConfig(t, "agent.json", `{"provider": {"name": ""}}`)
+
+	if _, err := config.NewWatcher(filename); err == nil {
+		t.Error("expected error for invalid initial config")
+	}
+}
+
+func TestWatcher_Run_DetectsChanges(t *testing.T) {
+	filename := writeConfig(t, "agent.json", watchConfig)
+
+	w, err := config.NewWatcher(filename, config.WithWatchInterval(10*time.Millisecond), config.WithReloadSignals())
+	if err != nil {
+		t.Fatalf("NewWatcher failed: %v", err)
+	}
+
+	events := make(chan config.ConfigEvent, 4)
+	w.Subscribe(func(e config.ConfigEvent) { events <- e })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Go(func() { w.Run(ctx) })
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	next := func() config.ConfigEvent {
+		t.Helper()
+		select {
+		case e := <-events:
+			return e
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for config event")
+			return config.ConfigEvent{}
+		}
+	}
+
+	rewrite(t, filename, `{
+		"provider": {"name": "ollama", "base_url": "http://localhost:11434"},
+		"model": {"name": "llama3.2:3b", "capabilities": {"chat": {"temperature": 0.9}}}
+	}`)
+
+	e := next()
+	if e.Err != nil {
+		t.Fatalf("unexpected error: %v", e.Err)
+	}
+	if e.Config.Model.Capabilities["chat"]["temperature"] != 0.9 {
+		t.Errorf("got capabilities %v, want temperature 0.9", e.Config.Model.Capabilities)
+	}
+	if e.Previous.Model.Capabilities["chat"]["temperature"] != 0.5 {
+		t.Errorf("got previous capabilities %v, want temperature 0.5", e.Previous.Model.Capabilities)
+	}
+	if w.Config() != e.Config {
+		t.Error("Config does not return the reloaded config")
+	}
+
+	active := w.Config()
+	rewrite(t, filename, `{"provider": {"name": ""}}`)
+
+	e = next()
+	if e.Err == nil || e.Config != nil {
+		t.Fatalf("got event %+v, want validation failure", e)
+	}
+	if w.Config() != active {
+		t.Error("invalid config replaced the active config")
+	}
+
+	// The same failure is reported once, not on every poll.
+	select {
+	case e := <-events:
+		t.Errorf("unexpected repeated event %+v", e)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestWatcher_Reload_SecretRotation(t *testing.T) {
+	token := writeConfig(t, "token", "first")
+	filename := writeConfig(t, "agent.json", `{
+		"provider": {"name": "ollama", "base_url": "http://localhost:11434", "options": {"token": "file://`+token+`"}},
+		"model": {"name": "llama3.2:3b"}
+	}`)
+
+	w, err := config.NewWatcher(filename, config.WithWatchInterval(0), config.WithReloadSignals())
+	if err != nil {
+		t.Fatalf("NewWatcher failed: %v", err)
+	}
+
+	var events []config.ConfigEvent
+	w.Subscribe(func(e config.ConfigEvent) { events = append(events, e) })
+
+	rewrite(t, token, "second")
+	if err := w.Reload(); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+
+	if got := w.Config().Provider.Options["token"]; got != "second" {
+		t.Errorf("got token %v, want second", got)
+	}
+
+	if len(events) != 1 || events[0].Previous.Provider.Options["token"] != "first" {
+		t.Errorf("got events %+v, want one rotation event", events)
+	}
+}
+
+func TestWatcher_Unsubscribe(t *testing.T) {
+	w, err := config.NewWatcher(writeConfig(t, "agent.json", watchConfig), config.WithWatchInterval(0), config.WithReloadSignals())
+	if err != nil {
+		t.Fatalf("NewWatcher failed: %v", err)
+	}
+
+	calls := 0
+	unsubscribe := w.Subscribe(func(config.ConfigEvent) { calls++ })
+
+	w.Reload()
+	unsubscribe()
+	w.Reload()
+
+	if calls != 1 {
+		t.Errorf("got %d calls, want 1", calls)
+	}
+}
@@ -0,0 +1,203 @@
+package guardrails_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/go-agents/pkg/guardrails"
+	"github.com/JaimeStill/go-agents/pkg/mock"
+)
+
+func TestDenylist(t *testing.T) {
+	check := guardrails.Denylist(guardrails.Redact, "project falcon", "secret")
+
+	verdict, err := check.Check(context.Background(), guardrails.Input, "Is Project Falcon still SECRET? Secretary says no.")
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+
+	if verdict.Action != guardrails.Redact {
+		t.Fatalf("got action %q, want redact", verdict.Action)
+	}
+	if want := "Is [REDACTED] still [REDACTED]? Secretary says no."; verdict.Text != want {
+		t.Errorf("got text %q, want %q", verdict.Text, want)
+	}
+
+	verdict, _ = check.Check(context.Background(), guardrails.Input, "nothing to see")
+	if verdict.Action != guardrails.Pass {
+		t.Errorf("got action %q for clean text, want pass", verdict.Action)
+	}
+}
+
+func TestRegex(t *testing.T) {
+	check := guardrails.Regex("ticket", guardrails.Annotate, regexp.MustCompile(`JIRA-\d+`))
+
+	verdict, err := check.Check(context.Background(), guardrails.Output, "See JIRA-42 and JIRA-7.")
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+
+	if verdict.Action != guardrails.Annotate || verdict.Category != "ticket" {
+		t.Errorf("got verdict %+v, want ticket annotation", verdict)
+	}
+	if !strings.Contains(verdict.Detail, "jira-42") || !strings.Contains(verdict.Detail, "jira-7") {
+		t.Errorf("got detail %q, want both matches", verdict.Detail)
+	}
+}
+
+func TestPII(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		want     string
+		category string
+	}{
+		{
+			name:     "email",
+			text:     "Contact jane.doe@example.com today.",
+			want:     "Contact [REDACTED_EMAIL] today.",
+			category: "email",
+		},
+		{
+			name:     "phone",
+			text:     "Call (555) 123-4567.",
+			want:     "Call [REDACTED_PHONE].",
+			category: "phone",
+		},
+		{
+			name:     "credit card",
+			text:     "Card 4111 1111 1111 1111 on file.",
+			want:     "Card [REDACTED_CREDIT_CARD] on file.",
+			category: "credit_card",
+		},
+		{
+			name:     "ssn",
+			text:     "SSN 123-45-6789.",
+			want:     "SSN [REDACTED_SSN].",
+			category: "ssn",
+		},
+		{
+			name:     "ip address",
+			text:     "Host 192.168.1.20 is down.",
+			want:     "Host [REDACTED_IP_ADDRESS] is down.",
+			category: "ip_address",
+		},
+	}
+
+	check := guardrails.PII(guardrails.Redact)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict, err := check.Check(context.Background(), guardrails.Input, tt.text)
+			if err != nil {
+				t.Fatalf("Check failed: %v", err)
+			}
+			if verdict.Text != tt.want || verdict.Category != tt.category {
+				t.Errorf("got %q (%s), want %q (%s)", verdict.Text, verdict.Category, tt.want, tt.category)
+			}
+		})
+	}
+
+	verdict, _ := check.Check(context.Background(), guardrails.Input, "Order 1234 5678 9012 3456 shipped.")
+	if verdict.Action != guardrails.Pass {
+		t.Errorf("got %+v for a number failing the Luhn check, want pass", verdict)
+	}
+}
+
+func TestModeration(t *testing.T) {
+	moderator := mock.NewModerationAgent("moderator", map[string][]string{
+		"violence": {"attack"},
+		"hate":     {"slur"},
+	})
+	check := guardrails.Moderation(moderator, guardrails.Block, map[string]any{"model": "omni"})
+
+	verdict, err := check.Check(context.Background(), guardrails.Input, "Plan the attack.")
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if verdict.Action != guardrails.Block || verdict.Category != "violence" {
+		t.Errorf("got verdict %+v, want violence block", verdict)
+	}
+
+	call, ok := moderator.LastCall(mock.MethodModerate)
+	if !ok || call.Input != "Plan the attack." || call.Options["model"] != "omni" {
+		t.Errorf("got call %+v", call)
+	}
+
+	verdict, _ = check.Check(context.Background(), guardrails.Input, "Plan the picnic.")
+	if verdict.Action != guardrails.Pass {
+		t.Errorf("got %+v for clean text, want pass", verdict)
+	}
+
+	failing := guardrails.Moderation(mock.NewFailingAgent("moderator", errors.New("unavailable")), guardrails.Block)
+	if _, err := failing.Check(context.Background(), guardrails.Input, "text"); err == nil {
+		t.Error("expected moderator error")
+	}
+}
+
+func TestGuardrails_Apply(t *testing.T) {
+	var observed []guardrails.Finding
+	g := guardrails.New(
+		guardrails.WithInput(
+			guardrails.PII(guardrails.Redact),
+			guardrails.Func("length", func(ctx context.Context, stage guardrails.Stage, text string) (guardrails.Verdict, error) {
+				if strings.Contains(text, "@") {
+					t.Error("later check saw unredacted text")
+				}
+				return guardrails.Verdict{Action: guardrails.Annotate, Detail: "checked"}, nil
+			}),
+		),
+		guardrails.WithOutput(guardrails.Denylist(guardrails.Block, "classified")),
+		guardrails.WithObserver(func(f guardrails.Finding) {
+			observed = append(observed, f)
+		}),
+	)
+
+	ctx, report := guardrails.WithReport(context.Background())
+
+	text, findings, err := g.Apply(ctx, guardrails.Input, "Email me at a@b.io")
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if text != "Email me at [REDACTED_EMAIL]" {
+		t.Errorf("got text %q", text)
+	}
+	if len(findings) != 2 || findings[0].Check != "pii" || findings[1].Action != guardrails.Annotate {
+		t.Errorf("got findings %+v", findings)
+	}
+
+	_, _, err = g.Apply(ctx, guardrails.Output, "This is classified.")
+	var blocked *guardrails.BlockedError
+	if !errors.As(err, &blocked) || !errors.Is(err, guardrails.ErrBlocked) {
+		t.Fatalf("got error %v, want BlockedError", err)
+	}
+	if blocked.Finding.Stage != guardrails.Output || blocked.Finding.Check != "denylist" {
+		t.Errorf("got finding %+v", blocked.Finding)
+	}
+
+	if got := report.Findings(); len(got) != 3 || !report.Blocked() {
+		t.Errorf("got report %+v, want 3 findings with a block", got)
+	}
+	if len(observed) != 3 {
+		t.Errorf("observed %d findings, want 3", len(observed))
+	}
+}
+
+func TestGuardrails_CheckError(t *testing.T) {
+	g := guardrails.New(guardrails.WithInput(
+		guardrails.Func("broken", func(ctx context.Context, stage guardrails.Stage, text string) (guardrails.Verdict, error) {
+			return guardrails.Verdict{}, errors.New("detector offline")
+		}),
+	))
+
+	_, _, err := g.Apply(context.Background(), guardrails.Input, "text")
+	if err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("got error %v, want failure naming the check", err)
+	}
+
+	if text, _, err := g.Apply(context.Background(), guardrails.Input, ""); err != nil || text != "" {
+		t.Errorf("got %q, %v for empty text, want no checks run", text, err)
+	}
+}
@@ -0,0 +1,357 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/go-agents/pkg/memory"
+	"github.com/JaimeStill/go-agents/pkg/mock"
+	"github.com/JaimeStill/go-agents/pkg/protocol"
+	"github.com/JaimeStill/go-agents/pkg/tokenizer"
+)
+
+// conversation builds a system prompt followed by n alternating user/assistant turns.
+func conversation(n int) []protocol.Message {
+	messages := []protocol.Message{protocol.NewMessage("system", "You are helpful.")}
+	for i := range n {
+		role := "user"
+		if i%2 == 1 {
+			role = "assistant"
+		}
+		messages = append(messages, protocol.NewMessage(role, fmt.Sprintf("message %d", i)))
+	}
+	return messages
+}
+
+func TestWindow(t *testing.T) {
+	ctx := context.Background()
+	messages := conversation(10)
+
+	got, err := memory.NewWindow(4).Apply(ctx, messages)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	if len(got) != 5 {
+		t.Fatalf("got %d messages, want 5", len(got))
+	}
+	if got[0].Role != "system" {
+		t.Errorf("got first role %q, want system", got[0].Role)
+	}
+	if got[1].Content != "message 6" || got[4].Content != "message 9" {
+		t.Errorf("got window %v..%v, want message 6..message 9", got[1].Content, got[4].Content)
+	}
+	if len(messages) != 11 {
+		t.Error("Apply must not modify the input")
+	}
+
+	short, _ := memory.NewWindow(20).Apply(ctx, messages)
+	if len(short) != len(messages) {
+		t.Errorf("got %d messages, want all %d", len(short), len(messages))
+	}
+}
+
+// toolConversation builds a system prompt, a question, an assistant message
+// requesting two tool calls, their results, and a final answer.
+func toolConversation() []protocol.Message {
+	call := func(id string) protocol.ToolCall {
+		return protocol.ToolCall{
+			ID:       id,
+			Type:     "function",
+			Function: protocol.ToolCallFunction{Name: "get_weather", Arguments: `{"city":"Paris"}`},
+		}
+	}
+
+	return []protocol.Message{
+		protocol.NewMessage("system", "You are helpful."),
+		protocol.NewMessage("user", "What is the weather in Paris?"),
+		protocol.NewToolCallMessage(call("call_1"), call("call_2")),
+		protocol.NewToolResultMessage("call_1", "sunny"),
+		protocol.NewToolResultMessage("call_2", "22C"),
+		protocol.NewMessage("assistant", "It is sunny and 22C."),
+	}
+}
+
+// checkToolGroups fails if a tool result is separated from its tool call message.
+func checkToolGroups(t *testing.T, messages []protocol.Message) {
+	t.Helper()
+
+	pending := map[string]bool{}
+	for _, msg := range messages {
+		for _, call := range msg.ToolCalls {
+			pending[call.ID] = true
+		}
+		if msg.Role == "tool" {
+			if !pending[msg.ToolCallID] {
+				t.Errorf("tool result %s kept without its call: %+v", msg.ToolCallID, messages)
+			}
+			delete(pending, msg.ToolCallID)
+		}
+	}
+	if len(pending) > 0 {
+		t.Errorf("tool calls kept without their results: %+v", messages)
+	}
+}
+
+func TestWindow_ToolGroups(t *testing.T) {
+	ctx := context.Background()
+	messages := toolConversation()
+
+	tests := []struct {
+		name string
+		size int
+		want int
+	}{
+		{name: "cut inside group drops it", size: 2, want: 1},
+		{name: "cut at group start keeps it", size: 4, want: 4},
+		{name: "cut before group", size: 5, want: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := memory.NewWindow(tt.size).Apply(ctx, messages)
+			if err != nil {
+				t.Fatalf("Apply failed: %v", err)
+			}
+			if len(got)-1 != tt.want {
+				t.Errorf("got %d messages after the system prompt, want %d", len(got)-1, tt.want)
+			}
+			checkToolGroups(t, got)
+		})
+	}
+
+	// A trailing group larger than the window is kept whole.
+	pending, _ := memory.NewWindow(1).Apply(ctx, messages[:5])
+	if len(pending) != 4 {
+		t.Errorf("got %d messages, want system + the whole tool group", len(pending))
+	}
+	checkToolGroups(t, pending)
+}
+
+func TestTokenWindow_ToolGroups(t *testing.T) {
+	ctx := context.Background()
+	h := tokenizer.NewHeuristic(1)
+	messages := toolConversation()
+
+	// Budget fits the system prompt, the last tool result, and the answer,
+	// which would cut between the two tool results.
+	budget := tokenizer.CountMessages(h, append(messages[:1:1], messages[4:]...))
+
+	got, err := memory.NewTokenWindow(budget, h).Apply(ctx, messages)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	if len(got) != 2 || got[1].Content != "It is sunny and 22C." {
+		t.Errorf("got %+v, want system and the final answer", got)
+	}
+	checkToolGroups(t, got)
+}
+
+func TestTokenWindow(t *testing.T) {
+	ctx := context.Background()
+	h := tokenizer.NewHeuristic(1)
+	messages := conversation(10)
+
+	// Budget exactly fits the system prompt and the last three messages.
+	budget := tokenizer.CountMessages(h, append(messages[:1:1], messages[8:]...))
+
+	got, err := memory.NewTokenWindow(budget, h).Apply(ctx, messages)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	if len(got) != 4 {
+		t.Fatalf("got %d messages, want system + 3", len(got))
+	}
+	if got[0].Role != "system" || got[3].Content != "message 9" {
+		t.Errorf("unexpected window: %v", got)
+	}
+	if tokenizer.CountMessages(h, got) > budget {
+		t.Errorf("window exceeds budget: %d > %d", tokenizer.CountMessages(h, got), budget)
+	}
+
+	// The most recent message is kept even when it alone exceeds the budget.
+	tiny, _ := memory.NewTokenWindow(1, h).Apply(ctx, messages)
+	if len(tiny) != 2 || tiny[1].Content != "message 9" {
+		t.Errorf("got %v, want system and last message", tiny)
+	}
+}
+
+func TestSummary(t *testing.T) {
+	ctx := context.Background()
+	summarizer := mock.NewSimpleChatAgent("summarizer", "the user counted to seven")
+	strategy := memory.NewSummary(summarizer, 6, 2)
+
+	unchanged, err := strategy.Apply(ctx, conversation(6))
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if len(unchanged) != 7 {
+		t.Errorf("got %d messages, want history unchanged below threshold", len(unchanged))
+	}
+
+	got, err := strategy.Apply(ctx, conversation(8))
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	if len(got) != 4 {
+		t.Fatalf("got %d messages, want system + summary + 2 recent", len(got))
+	}
+	if got[0].Content != "You are helpful." {
+		t.Errorf("got first message %v, want original system prompt", got[0].Content)
+	}
+	summary, _ := got[1].Content.(string)
+	if got[1].Role != "system" || !strings.HasPrefix(summary, memory.SummaryPrefix) {
+		t.Errorf("got summary message %+v", got[1])
+	}
+	if got[3].Content != "message 7" {
+		t.Errorf("got last message %v, want message 7", got[3].Content)
+	}
+
+	// A later compaction folds the existing summary rather than stacking a second one.
+	next := append(got, conversation(8)[1:]...)
+	rolled, err := strategy.Apply(ctx, next)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if len(rolled) != 4 {
+		t.Errorf("got %d messages, want a single rolling summary", len(rolled))
+	}
+}
+
+func TestSummary_ToolGroups(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("split inside group keeps it recent", func(t *testing.T) {
+		summarizer := mock.NewSimpleChatAgent("summarizer", "the user asked about Paris")
+
+		got, err := memory.NewSummary(summarizer, 2, 2).Apply(ctx, toolConversation())
+		if err != nil {
+			t.Fatalf("Apply failed: %v", err)
+		}
+
+		if len(got) != 6 {
+			t.Fatalf("got %d messages, want system + summary + tool group + answer", len(got))
+		}
+		if len(got[2].ToolCalls) != 2 {
+			t.Errorf("got %+v after the summary, want the tool call message", got[2])
+		}
+		checkToolGroups(t, got)
+	})
+
+	t.Run("summarized group renders tool calls", func(t *testing.T) {
+		summarizer := mock.NewSimpleChatAgent("summarizer", "the user asked about Paris")
+
+		got, err := memory.NewSummary(summarizer, 2, 1).Apply(ctx, toolConversation())
+		if err != nil {
+			t.Fatalf("Apply failed: %v", err)
+		}
+		if len(got) != 3 {
+			t.Fatalf("got %d messages, want system + summary + answer", len(got))
+		}
+
+		call, _ := summarizer.LastCall(mock.MethodChat)
+		if strings.Contains(call.Prompt, "<nil>") {
+			t.Errorf("prompt renders a nil tool call message: %q", call.Prompt)
+		}
+		for _, want := range []string{`get_weather({"city":"Paris"}) [call_1]`, "tool (call_2): 22C"} {
+			if !strings.Contains(call.Prompt, want) {
+				t.Errorf("prompt missing %q: %q", want, call.Prompt)
+			}
+		}
+	})
+}
+
+func TestSummary_Error(t *testing.T) {
+	summarizer := mock.NewFailingAgent("summarizer", errors.New("unavailable"))
+	strategy := memory.NewSummary(summarizer, 2, 1)
+
+	if _, err := strategy.Apply(context.Background(), conversation(4)); err == nil {
+		t.Error("expected error when summarization fails")
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := memory.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStore failed: %v", err)
+	}
+
+	if _, err := store.Load(ctx, "missing"); !errors.Is(err, memory.ErrNotFound) {
+		t.Errorf("got error %v, want ErrNotFound", err)
+	}
+
+	messages := conversation(3)
+	if err := store.Save(ctx, "session-1", messages); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := store.Load(ctx, "session-1")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(loaded) != len(messages) || loaded[3].Content != "message 2" {
+		t.Errorf("got %v, want %v", loaded, messages)
+	}
+
+	if err := store.Delete(ctx, "session-1"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := store.Load(ctx, "session-1"); !errors.Is(err, memory.ErrNotFound) {
+		t.Errorf("got error %v after delete, want ErrNotFound", err)
+	}
+
+	for _, id := range []string{"", "..", "../escape", `a\b`} {
+		if err := store.Save(ctx, id, messages); err == nil {
+			t.Errorf("expected error for id %q", id)
+		}
+	}
+}
+
+func TestSession(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := memory.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStore failed: %v", err)
+	}
+
+	session, err := memory.NewSession(ctx, "chat", memory.NewWindow(2), store)
+	if err != nil {
+		t.Fatalf("NewSession failed: %v", err)
+	}
+
+	for _, msg := range conversation(5) {
+		if err := session.Add(ctx, msg); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+
+	if got := session.Messages(); len(got) != 3 {
+		t.Fatalf("got %d messages, want system + 2", len(got))
+	}
+
+	restored, err := memory.NewSession(ctx, "chat", memory.NewWindow(2), store)
+	if err != nil {
+		t.Fatalf("NewSession failed: %v", err)
+	}
+
+	got := restored.Messages()
+	if len(got) != 3 || got[2].Content != "message 4" {
+		t.Errorf("got restored history %v", got)
+	}
+
+	if err := restored.Clear(ctx); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+	if _, err := store.Load(ctx, "chat"); !errors.Is(err, memory.ErrNotFound) {
+		t.Errorf("got error %v after clear, want ErrNotFound", err)
+	}
+}
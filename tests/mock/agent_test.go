@@ -27,15 +27,7 @@ func TestMockAgent_Chat(t *testing.T) {
 	expectedResponse := &response.ChatResponse{
 		Model: "test-model",
 	}
-	expectedResponse.Choices = append(expectedResponse.Choices, struct {
-		Index   int              `json:"index"`
-		Message protocol.Message `json:"message"`
-		Delta   *struct {
-			Role    string `json:"role,omitempty"`
-			Content string `json:"content,omitempty"`
-		} `json:"delta,omitempty"`
-		FinishReason string `json:"finish_reason,omitempty"`
-	}{
+	expectedResponse.Choices = append(expectedResponse.Choices, response.ChatChoice{
 		Index:   0,
 		Message: protocol.NewMessage("assistant", "Hello"),
 	})
@@ -60,15 +52,7 @@ func TestMockAgent_Vision(t *testing.T) {
 	expectedResponse := &response.ChatResponse{
 		Model: "test-model",
 	}
-	expectedResponse.Choices = append(expectedResponse.Choices, struct {
-		Index   int              `json:"index"`
-		Message protocol.Message `json:"message"`
-		Delta   *struct {
-			Role    string `json:"role,omitempty"`
-			Content string `json:"content,omitempty"`
-		} `json:"delta,omitempty"`
-		FinishReason string `json:"finish_reason,omitempty"`
-	}{
+	expectedResponse.Choices = append(expectedResponse.Choices, response.ChatChoice{
 		Index:   0,
 		Message: protocol.NewMessage("assistant", "I see an image"),
 	})
@@ -93,23 +77,10 @@ func TestMockAgent_Tools(t *testing.T) {
 	expectedResponse := &response.ToolsResponse{
 		Model: "test-model",
 	}
-	expectedResponse.Choices = append(expectedResponse.Choices, struct {
-		Index   int `json:"index"`
-		Message struct {
-			Role      string              `json:"role"`
-			Content   string              `json:"content"`
-			ToolCalls []response.ToolCall `json:"tool_calls,omitempty"`
-		} `json:"message"`
-		FinishReason string `json:"finish_reason,omitempty"`
-	}{
+	expectedResponse.Choices = append(expectedResponse.Choices, response.ToolsChoice{
 		Index: 0,
-		Message: struct {
-			Role      string              `json:"role"`
-			Content   string              `json:"content"`
-			ToolCalls []response.ToolCall `json:"tool_calls,omitempty"`
-		}{
-			Role:    "assistant",
-			Content: "",
+		Message: protocol.Message{
+			Role: "assistant",
 			ToolCalls: []response.ToolCall{
 				{
 					ID:   "call_123",
@@ -205,3 +176,106 @@ func TestNewStreamingChatAgent(t *testing.T) {
 		t.Errorf("got content %q, want %q", content, "Hello, world!")
 	}
 }
+
+func TestNewImageAgent(t *testing.T) {
+	agent := mock.NewImageAgent("test-id", "https://images.test/1.png", "https://images.test/2.png")
+
+	resp, err := agent.GenerateImage(context.Background(), "a lighthouse", map[string]any{"n": 2})
+
+	if err != nil {
+		t.Fatalf("GenerateImage failed: %v", err)
+	}
+
+	if len(resp.Data) != 2 || resp.Data[1].URL != "https://images.test/2.png" {
+		t.Errorf("got images %+v", resp.Data)
+	}
+
+	call, ok := agent.LastCall(mock.MethodGenerateImage)
+	if !ok || call.Prompt != "a lighthouse" {
+		t.Errorf("got call %+v, want prompt recorded", call)
+	}
+}
+
+func TestNewAudioAgent(t *testing.T) {
+	audio := []byte("ID3 mock audio")
+	agent := mock.NewAudioAgent("test-id", "Hello there.", audio)
+
+	transcript, err := agent.Transcribe(context.Background(), "clip.mp3", []byte("recording"))
+	if err != nil {
+		t.Fatalf("Transcribe failed: %v", err)
+	}
+	if transcript.Text != "Hello there." {
+		t.Errorf("got transcript %q", transcript.Text)
+	}
+
+	speech, err := agent.Speak(context.Background(), "Hello there.")
+	if err != nil {
+		t.Fatalf("Speak failed: %v", err)
+	}
+	if string(speech.Audio) != string(audio) {
+		t.Errorf("got audio %q", speech.Audio)
+	}
+
+	chunks, err := agent.SpeakStream(context.Background(), "Hello there.")
+	if err != nil {
+		t.Fatalf("SpeakStream failed: %v", err)
+	}
+	for chunk := range chunks {
+		if string(chunk.Audio) != string(audio) {
+			t.Errorf("got chunk audio %q", chunk.Audio)
+		}
+	}
+
+	call, ok := agent.LastCall(mock.MethodTranscribe)
+	if !ok || call.Filename != "clip.mp3" || string(call.Audio) != "recording" {
+		t.Errorf("got call %+v, want upload recorded", call)
+	}
+	if got := len(agent.CallsTo(mock.MethodSpeak)); got != 1 {
+		t.Errorf("got %d Speak calls, want 1", got)
+	}
+}
+
+func TestNewRerankAgent(t *testing.T) {
+	agent := mock.NewRerankAgent("test-id", 0.1, 0.9, 0.5)
+
+	resp, err := agent.Rerank(context.Background(), "query", []string{"a", "b", "c"}, map[string]any{
+		"top_n":            2,
+		"return_documents": true,
+	})
+	if err != nil {
+		t.Fatalf("Rerank failed: %v", err)
+	}
+
+	if len(resp.Results) != 2 || resp.Results[0].Index != 1 || resp.Results[1].Document != "c" {
+		t.Errorf("got results %+v", resp.Results)
+	}
+
+	call, ok := agent.LastCall(mock.MethodRerank)
+	if !ok || call.Prompt != "query" || len(call.Documents) != 3 {
+		t.Errorf("got call %+v, want query and documents recorded", call)
+	}
+}
+
+func TestNewModerationAgent(t *testing.T) {
+	agent := mock.NewModerationAgent("test-id", map[string][]string{
+		"violence": {"attack"},
+		"hate":     {"slur"},
+	})
+
+	resp, err := agent.Moderate(context.Background(), "Plan the ATTACK.")
+	if err != nil {
+		t.Fatalf("Moderate failed: %v", err)
+	}
+
+	if !resp.Flagged() {
+		t.Fatal("expected input to be flagged")
+	}
+	if result := resp.Results[0]; !result.Categories["violence"] || result.Categories["hate"] {
+		t.Errorf("got categories %v, want violence only", result.Categories)
+	}
+
+	call, ok := agent.LastCall(mock.MethodModerate)
+	if !ok || call.Input != "Plan the ATTACK." {
+		t.Errorf("got call %+v, want input recorded", call)
+	}
+}
@@ -0,0 +1,173 @@
+package mock_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/JaimeStill/go-agents/pkg/agent"
+	"github.com/JaimeStill/go-agents/pkg/mock"
+	"github.com/JaimeStill/go-agents/pkg/model"
+	"github.com/JaimeStill/go-agents/pkg/protocol"
+	"github.com/JaimeStill/go-agents/pkg/response"
+)
+
+// recordingT captures assertion failures without failing the test.
+type recordingT struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func chatResponse(content string) *response.ChatResponse {
+	resp := &response.ChatResponse{Model: "mock-model"}
+	resp.Choices = append(resp.Choices, response.ChatChoice{Message: protocol.NewMessage("assistant", content)})
+	return resp
+}
+
+func TestMockAgent_RecordsCalls(t *testing.T) {
+	m := mock.NewMockAgent(mock.WithModel(&model.Model{
+		Name: "mock-model",
+		Options: map[protocol.Protocol]map[string]any{
+			protocol.Vision: {"temperature": 0.2, "max_tokens": 100},
+		},
+	}))
+	ctx := context.Background()
+
+	m.Vision(ctx, "Describe", []string{"a.png", "b.png"}, map[string]any{"temperature": 0.9})
+	m.Tools(ctx, "Weather?", []agent.Tool{{Name: "get_weather"}})
+	m.Embed(ctx, "embed me")
+	m.ChatStream(ctx, "Stream")
+
+	call := m.AssertCalled(t, mock.MethodVision)
+	if call.Prompt != "Describe" || len(call.Images) != 2 || call.Protocol != protocol.Vision {
+		t.Errorf("got vision call %+v", call)
+	}
+	m.AssertOption(t, mock.MethodVision, "temperature", 0.9)
+	m.AssertOption(t, mock.MethodVision, "max_tokens", 100)
+
+	m.AssertCalledWith(t, mock.MethodTools, func(c mock.Call) bool {
+		return len(c.Tools) == 1 && c.Tools[0].Name == "get_weather"
+	})
+
+	if call, _ := m.LastCall(mock.MethodEmbed); call.Input != "embed me" {
+		t.Errorf("got embed input %q", call.Input)
+	}
+
+	m.AssertOption(t, mock.MethodChatStream, "stream", true)
+	m.AssertNotCalled(t, mock.MethodChat)
+
+	calls := m.Calls()
+	if len(calls) != 4 {
+		t.Fatalf("got %d calls, want 4", len(calls))
+	}
+	for i := 1; i < len(calls); i++ {
+		if calls[i].Seq <= calls[i-1].Seq || calls[i].Time.Before(calls[i-1].Time) {
+			t.Errorf("calls out of order: %v then %v", calls[i-1], calls[i])
+		}
+	}
+
+	m.Reset()
+	m.AssertCallCount(t, mock.MethodVision, 0)
+}
+
+func TestMockAgent_Assertions_Fail(t *testing.T) {
+	m := mock.NewMockAgent()
+	m.Chat(context.Background(), "Hello", map[string]any{"temperature": 0.5})
+
+	rt := &recordingT{}
+	m.AssertCalled(rt, mock.MethodEmbed)
+	m.AssertNotCalled(rt, mock.MethodChat)
+	m.AssertCallCount(rt, mock.MethodChat, 2)
+	m.AssertOption(rt, mock.MethodChat, "temperature", 0.7)
+	m.AssertOption(rt, mock.MethodChat, "top_p", 1.0)
+	m.AssertCalledWith(rt, mock.MethodChat, func(c mock.Call) bool { return c.Prompt == "Goodbye" })
+
+	if len(rt.failures) != 6 {
+		t.Errorf("got %d failures, want 6: %v", len(rt.failures), rt.failures)
+	}
+}
+
+func TestMockAgent_Sequence(t *testing.T) {
+	boom := errors.New("boom")
+	m := mock.NewMockAgent(mock.WithChatFunc(mock.Sequence(
+		mock.Result[*response.ChatResponse]{Response: chatResponse("first")},
+		mock.Result[*response.ChatResponse]{Err: boom},
+		mock.Result[*response.ChatResponse]{Response: chatResponse("last")},
+	)))
+	ctx := context.Background()
+
+	if resp, _ := m.Chat(ctx, "1"); resp.Content() != "first" {
+		t.Errorf("got %q, want first", resp.Content())
+	}
+	if _, err := m.Chat(ctx, "2"); !errors.Is(err, boom) {
+		t.Errorf("got %v, want boom", err)
+	}
+	for range 2 {
+		if resp, _ := m.Chat(ctx, "3"); resp.Content() != "last" {
+			t.Errorf("got %q, want last repeated", resp.Content())
+		}
+	}
+}
+
+func TestMockAgent_Func(t *testing.T) {
+	m := mock.NewMockAgent(
+		mock.WithChatFunc(func(c mock.Call) (*response.ChatResponse, error) {
+			return chatResponse("echo: " + c.Prompt), nil
+		}),
+		mock.WithStreamFunc(func(c mock.Call) ([]response.StreamingChunk, error) {
+			return make([]response.StreamingChunk, len(c.Prompt)), nil
+		}),
+	)
+
+	resp, err := m.Chat(context.Background(), "hi")
+	if err != nil || resp.Content() != "echo: hi" {
+		t.Errorf("got %v, %v", resp, err)
+	}
+
+	chunks, err := m.ChatStream(context.Background(), "four")
+	if err != nil {
+		t.Fatalf("ChatStream failed: %v", err)
+	}
+	count := 0
+	for range chunks {
+		count++
+	}
+	if count != 4 {
+		t.Errorf("got %d chunks, want 4", count)
+	}
+}
+
+func TestMockAgent_Concurrent(t *testing.T) {
+	first := mock.NewMockAgent(mock.WithChatFunc(mock.Responses(chatResponse("a"), chatResponse("b"))))
+	second := mock.NewMockAgent()
+
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Go(func() {
+			first.Chat(context.Background(), fmt.Sprint(i))
+			second.Embed(context.Background(), fmt.Sprint(i))
+		})
+	}
+	wg.Wait()
+
+	first.AssertCallCount(t, mock.MethodChat, 50)
+	second.AssertCallCount(t, mock.MethodEmbed, 50)
+
+	timeline := mock.Timeline(first, second)
+	if len(timeline) != 100 {
+		t.Fatalf("got %d timeline calls, want 100", len(timeline))
+	}
+	for i := 1; i < len(timeline); i++ {
+		if timeline[i].Seq <= timeline[i-1].Seq {
+			t.Fatalf("timeline out of order at %d", i)
+		}
+	}
+}
@@ -2,9 +2,13 @@ package mock_test
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/JaimeStill/go-agents/pkg/mock"
+	"github.com/JaimeStill/go-agents/pkg/model"
+	"github.com/JaimeStill/go-agents/pkg/protocol"
+	"github.com/JaimeStill/go-agents/pkg/request"
 	"github.com/JaimeStill/go-agents/pkg/response"
 )
 
@@ -43,14 +47,7 @@ func TestMockClient_ExecuteStream(t *testing.T) {
 	chunk := &response.StreamingChunk{
 		Model: "test-model",
 	}
-	chunk.Choices = make([]struct {
-		Index int `json:"index"`
-		Delta struct {
-			Role    string `json:"role,omitempty"`
-			Content string `json:"content,omitempty"`
-		} `json:"delta"`
-		FinishReason *string `json:"finish_reason"`
-	}, 1)
+	chunk.Choices = make([]response.StreamingChoice, 1)
 	chunk.Choices[0].Delta.Content = "Hello"
 
 	chunks := []*response.StreamingChunk{chunk}
@@ -108,3 +105,29 @@ func TestMockClient_IsHealthy(t *testing.T) {
 		})
 	}
 }
+
+func TestMockClient_RecordsCalls(t *testing.T) {
+	m := mock.NewMockClient(mock.WithExecuteFunc(mock.Responses[any]("first", "second")))
+
+	req := request.NewChat(
+		mock.NewMockProvider(mock.WithMarshalResponse([]byte(`{"messages":[{"role":"user","content":"Hello"}]}`), nil)),
+		&model.Model{Name: "mock-model"},
+		[]protocol.Message{protocol.NewMessage("user", "Hello")},
+		map[string]any{"temperature": 0.3},
+	)
+
+	for _, want := range []string{"first", "second", "second"} {
+		if got, _ := m.Execute(context.Background(), req); got != want {
+			t.Errorf("got %v, want %s", got, want)
+		}
+	}
+
+	m.AssertCallCount(t, mock.MethodExecute, 3)
+	call := m.AssertCalled(t, mock.MethodExecute)
+	if call.Protocol != protocol.Chat || call.Request != req {
+		t.Errorf("got call %v", call)
+	}
+	if !strings.Contains(string(call.Body), `"Hello"`) {
+		t.Errorf("got body %s", call.Body)
+	}
+}
@@ -0,0 +1,930 @@
+package server_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image/png"
+	"math"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/go-agents/pkg/agent"
+	"github.com/JaimeStill/go-agents/pkg/client"
+	"github.com/JaimeStill/go-agents/pkg/config"
+	"github.com/JaimeStill/go-agents/pkg/guardrails"
+	"github.com/JaimeStill/go-agents/pkg/mock/server"
+	"github.com/JaimeStill/go-agents/pkg/protocol"
+	"github.com/JaimeStill/go-agents/pkg/providers"
+	"github.com/JaimeStill/go-agents/pkg/request"
+	"github.com/JaimeStill/go-agents/pkg/response"
+	"github.com/JaimeStill/go-agents/pkg/usage"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func newAgent(t *testing.T, provider *config.ProviderConfig, mutate ...func(*config.AgentConfig)) agent.Agent {
+	t.Helper()
+
+	cfg := config.DefaultAgentConfig()
+	cfg.Client.Retry.MaxRetries = new(int)
+	cfg.Provider = provider
+	cfg.Model = &config.ModelConfig{
+		Name: "mock-model",
+		Capabilities: map[string]map[string]any{
+			"chat":       {},
+			"tools":      {},
+			"embeddings": {},
+		},
+	}
+	for _, fn := range mutate {
+		fn(&cfg)
+	}
+
+	a, err := agent.New(&cfg)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return a
+}
+
+func collect(t *testing.T, a agent.Agent, prompt string) (string, error) {
+	t.Helper()
+
+	chunks, err := a.ChatStream(context.Background(), prompt)
+	if err != nil {
+		return "", err
+	}
+
+	var content strings.Builder
+	var streamErr error
+	for chunk := range chunks {
+		if chunk.Error != nil {
+			streamErr = chunk.Error
+			continue
+		}
+		content.WriteString(chunk.Content())
+	}
+	return content.String(), streamErr
+}
+
+func TestServer_OllamaChat(t *testing.T) {
+	s := server.New()
+	defer s.Close()
+
+	resp, err := newAgent(t, s.OllamaProvider()).Chat(context.Background(), "Hello")
+	if err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+
+	if resp.Content() != server.DefaultContent {
+		t.Errorf("got %q, want default content", resp.Content())
+	}
+	if resp.Usage == nil || resp.Usage.PromptTokens != 1 {
+		t.Errorf("got usage %+v, want 1 prompt token", resp.Usage)
+	}
+
+	requests := s.Requests()
+	if len(requests) != 1 || requests[0].Prompt != "Hello" || requests[0].Model != "mock-model" {
+		t.Errorf("got requests %+v", requests)
+	}
+}
+
+func TestServer_AzureTemplate(t *testing.T) {
+	s := server.New(server.WithReply(server.Reply{Content: "{{.Deployment}} heard: {{.Prompt}}"}))
+	defer s.Close()
+
+	resp, err := newAgent(t, s.AzureProvider("gpt-4o")).Chat(context.Background(), "ping")
+	if err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+
+	if resp.Content() != "gpt-4o heard: ping" {
+		t.Errorf("got %q", resp.Content())
+	}
+
+	if key := s.Requests()[0].Header.Get("Api-Key"); key != "mock-api-key" {
+		t.Errorf("got api-key %q", key)
+	}
+}
+
+func TestServer_Rules(t *testing.T) {
+	s := server.New(
+		server.WithRule(server.PromptContains("weather"), server.Reply{
+			ToolCalls: []server.ToolCall{{Name: "get_weather", Arguments: `{"location":"Boston"}`}},
+		}),
+		server.WithResponder(func(r *server.Request) server.Reply {
+			return server.Reply{Content: strings.ToUpper(r.Prompt)}
+		}),
+	)
+	defer s.Close()
+
+	a := newAgent(t, s.OllamaProvider())
+
+	tools := []agent.Tool{{Name: "get_weather", Description: "Get the weather", Parameters: map[string]any{"type": "object"}}}
+	resp, err := a.Tools(context.Background(), "What is the Weather in Boston?", tools)
+	if err != nil {
+		t.Fatalf("Tools failed: %v", err)
+	}
+
+	calls := resp.Choices[0].Message.ToolCalls
+	if len(calls) != 1 || calls[0].Function.Name != "get_weather" || calls[0].Function.Arguments != `{"location":"Boston"}` {
+		t.Errorf("got tool calls %+v", calls)
+	}
+	if resp.Choices[0].FinishReason != "tool_calls" {
+		t.Errorf("got finish reason %q", resp.Choices[0].FinishReason)
+	}
+	if got := s.Requests()[0].Tools; !reflect.DeepEqual(got, []string{"get_weather"}) {
+		t.Errorf("got tools %v", got)
+	}
+
+	chat, err := a.Chat(context.Background(), "quiet please")
+	if err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+	if chat.Content() != "QUIET PLEASE" {
+		t.Errorf("got %q from responder", chat.Content())
+	}
+}
+
+func TestServer_Streaming(t *testing.T) {
+	s := server.New(server.WithChunkDelay(10 * time.Millisecond))
+	defer s.Close()
+
+	s.AddRule(server.PromptContains("count"), server.Reply{Chunks: []string{"one", " two", " three"}})
+
+	start := time.Now()
+	content, err := collect(t, newAgent(t, s.OllamaProvider()), "count to three")
+	if err != nil {
+		t.Fatalf("stream failed: %v", err)
+	}
+
+	if content != "one two three" {
+		t.Errorf("got %q", content)
+	}
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("stream took %v, want at least 3 chunk delays", elapsed)
+	}
+}
+
+func TestServer_Embeddings(t *testing.T) {
+	s := server.New(server.WithDimensions(8))
+	defer s.Close()
+
+	a := newAgent(t, s.OllamaProvider())
+
+	first, err := a.Embed(context.Background(), "hello world")
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	second, err := a.Embed(context.Background(), "hello world")
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	other, err := a.Embed(context.Background(), "goodbye")
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+
+	vector := first.Data[0].Embedding
+	if !reflect.DeepEqual(vector, second.Data[0].Embedding) || !reflect.DeepEqual(vector, server.Embedding("hello world", 8)) {
+		t.Error("embeddings are not deterministic")
+	}
+	if reflect.DeepEqual(vector, other.Data[0].Embedding) {
+		t.Error("different inputs produced the same embedding")
+	}
+
+	var norm float64
+	for _, v := range vector {
+		norm += v * v
+	}
+	if len(vector) != 8 || math.Abs(norm-1) > 1e-9 {
+		t.Errorf("got %d dimensions with norm %f, want 8 unit dimensions", len(vector), norm)
+	}
+}
+
+func TestServer_FaultStatus(t *testing.T) {
+	s := server.New()
+	defer s.Close()
+
+	s.FailNext(1, server.Fault{Status: http.StatusTooManyRequests, RetryAfter: 1500 * time.Millisecond})
+
+	_, err := newAgent(t, s.OllamaProvider()).Chat(context.Background(), "Hello")
+
+	var statusErr *client.HTTPStatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("got %v, want 429", err)
+	}
+	if client.Categorize(err) != client.CategoryRateLimit {
+		t.Errorf("got category %s", client.Categorize(err))
+	}
+
+	s.FailNext(1, server.Fault{Status: http.StatusTooManyRequests, RetryAfter: 1500 * time.Millisecond})
+	resp, err := http.Post(s.URL+"/v1/chat/completions", "application/json", strings.NewReader(`{"messages":[]}`))
+	if err != nil {
+		t.Fatalf("Post failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.Header.Get("Retry-After") != "2" {
+		t.Errorf("got Retry-After %q, want 2", resp.Header.Get("Retry-After"))
+	}
+}
+
+func TestServer_FaultRetried(t *testing.T) {
+	s := server.New()
+	defer s.Close()
+
+	s.FailNext(2, server.Fault{Status: http.StatusServiceUnavailable})
+
+	a := newAgent(t, s.OllamaProvider(), func(cfg *config.AgentConfig) {
+		retries := 2
+		cfg.Client.Retry.MaxRetries = &retries
+		cfg.Client.Retry.InitialBackoff = ptr(config.Duration(time.Millisecond))
+		cfg.Client.Retry.MaxBackoff = ptr(config.Duration(5 * time.Millisecond))
+	})
+
+	if _, err := a.Chat(context.Background(), "Hello"); err != nil {
+		t.Fatalf("Chat failed after retries: %v", err)
+	}
+	if n := len(s.Requests()); n != 3 {
+		t.Errorf("got %d requests, want 3", n)
+	}
+}
+
+func TestServer_SlowFirstByte(t *testing.T) {
+	s := server.New()
+	defer s.Close()
+
+	s.FailNext(1, server.Fault{FirstByteDelay: time.Second})
+
+	a := newAgent(t, s.OllamaProvider(), func(cfg *config.AgentConfig) {
+		cfg.Client.Timeout = ptr(config.Duration(50 * time.Millisecond))
+	})
+
+	if _, err := a.Chat(context.Background(), "Hello"); client.Categorize(err) != client.CategoryTimeout {
+		t.Errorf("got %v, want timeout", err)
+	}
+}
+
+func TestServer_TruncatedStream(t *testing.T) {
+	s := server.New(server.WithReply(server.Reply{Content: "one two three four"}))
+	defer s.Close()
+
+	s.FailNext(1, server.Fault{Truncate: true, TruncateAfter: 2})
+
+	content, err := collect(t, newAgent(t, s.OllamaProvider()), "Hello")
+	if content != "one two " {
+		t.Errorf("got %q, want the first two chunks", content)
+	}
+	if err == nil {
+		t.Error("expected stream error after truncation")
+	}
+}
+
+func TestServer_ToolLoop(t *testing.T) {
+	s := server.New(
+		server.WithResponder(func(r *server.Request) server.Reply {
+			last := r.Messages[len(r.Messages)-1]
+			if last.Role == "tool" {
+				return server.Reply{Content: "It is " + last.Content + " (" + last.ToolCallID + ")"}
+			}
+			return server.Reply{ToolCalls: []server.ToolCall{{Name: "get_weather", Arguments: `{"location":"Boston"}`}}}
+		}),
+	)
+	defer s.Close()
+
+	a := newAgent(t, s.OllamaProvider())
+
+	tools := []agent.Tool{{Name: "get_weather", Description: "Get the weather", Parameters: map[string]any{"type": "object"}}}
+	first, err := a.Tools(context.Background(), "Weather in Boston?", tools)
+	if err != nil {
+		t.Fatalf("Tools failed: %v", err)
+	}
+
+	messages := []protocol.Message{protocol.NewMessage("user", "Weather in Boston?"), first.Message()}
+	for _, call := range first.ToolCalls() {
+		var args struct{ Location string }
+		if err := call.Function.DecodeArguments(&args); err != nil {
+			t.Fatalf("DecodeArguments failed: %v", err)
+		}
+		messages = append(messages, protocol.NewToolResultMessage(call.ID, "sunny in "+args.Location))
+	}
+
+	defs := []providers.ToolDefinition{{Name: "get_weather", Description: "Get the weather", Parameters: map[string]any{"type": "object"}}}
+	result, err := a.Client().Execute(context.Background(), request.NewTools(a.Provider(), a.Model(), messages, defs, nil))
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	second := result.(*response.ToolsResponse)
+	if got := second.Content(); got != "It is sunny in Boston (call_mock_0)" {
+		t.Errorf("got %q", got)
+	}
+
+	sent := s.Requests()[1].Body["messages"].([]any)
+	assistant := sent[1].(map[string]any)
+	if calls, ok := assistant["tool_calls"].([]any); !ok || len(calls) != 1 {
+		t.Errorf("assistant message sent without tool_calls: %v", assistant)
+	}
+}
+
+func TestServer_ToolControls(t *testing.T) {
+	s := server.New()
+	defer s.Close()
+
+	a := newAgent(t, s.AzureProvider("gpt-4o"), func(cfg *config.AgentConfig) {
+		cfg.Model.Capabilities["tools"] = map[string]any{"tool_choice": "required"}
+	})
+
+	tools := []agent.Tool{{
+		Name:       "get_weather",
+		Parameters: map[string]any{"type": "object", "properties": map[string]any{}, "additionalProperties": false},
+		Strict:     true,
+	}}
+
+	if _, err := a.Tools(context.Background(), "weather?", tools); err != nil {
+		t.Fatalf("Tools failed: %v", err)
+	}
+	if got := s.Requests()[0].Body["tool_choice"]; got != "required" {
+		t.Errorf("got configured tool_choice %v, want required", got)
+	}
+
+	_, err := a.Tools(context.Background(), "weather?", tools, map[string]any{
+		"tool_choice":         providers.ToolChoiceFor("get_weather"),
+		"parallel_tool_calls": false,
+	})
+	if err != nil {
+		t.Fatalf("Tools failed: %v", err)
+	}
+
+	body := s.Requests()[1].Body
+	if choice, ok := body["tool_choice"].(map[string]any); !ok || choice["type"] != "function" {
+		t.Errorf("got tool_choice %v, want function object", body["tool_choice"])
+	}
+	if body["parallel_tool_calls"] != false {
+		t.Errorf("got parallel_tool_calls %v, want false", body["parallel_tool_calls"])
+	}
+	function := body["tools"].([]any)[0].(map[string]any)["function"].(map[string]any)
+	if function["strict"] != true {
+		t.Errorf("got function %v, want strict", function)
+	}
+
+	if _, err := a.Tools(context.Background(), "weather?", tools, map[string]any{"tool_choice": "sometimes"}); err == nil {
+		t.Error("expected error for unknown tool_choice")
+	}
+}
+
+func TestServer_ImageGeneration(t *testing.T) {
+	s := server.New(server.WithReply(server.Reply{Content: "A lighthouse: {{.Prompt}}"}))
+	defer s.Close()
+
+	a := newAgent(t, s.OllamaProvider(), func(cfg *config.AgentConfig) {
+		cfg.Model.Capabilities["image_generation"] = map[string]any{"size": "64x32"}
+	})
+
+	resp, err := a.GenerateImage(context.Background(), "at dusk", map[string]any{"n": 2})
+	if err != nil {
+		t.Fatalf("GenerateImage failed: %v", err)
+	}
+
+	if len(resp.Data) != 2 {
+		t.Fatalf("got %d images, want 2", len(resp.Data))
+	}
+	if resp.Data[0].URL == resp.Data[1].URL {
+		t.Error("images share a URL")
+	}
+	if got := resp.Data[0].RevisedPrompt; got != "A lighthouse: at dusk" {
+		t.Errorf("got revised prompt %q", got)
+	}
+
+	fetched, err := http.Get(resp.Data[0].URL)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	defer fetched.Body.Close()
+
+	img, err := png.Decode(fetched.Body)
+	if err != nil {
+		t.Fatalf("served image is not a PNG: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 64 || b.Dy() != 32 {
+		t.Errorf("got %dx%d image, want 64x32", b.Dx(), b.Dy())
+	}
+
+	if got := s.Requests()[0]; got.Endpoint != server.EndpointImages || got.Prompt != "at dusk" {
+		t.Errorf("got endpoint %q prompt %q", got.Endpoint, got.Prompt)
+	}
+}
+
+func TestServer_ImageGenerationBase64(t *testing.T) {
+	s := server.New()
+	defer s.Close()
+
+	a := newAgent(t, s.AzureProvider("dall-e-3"), func(cfg *config.AgentConfig) {
+		cfg.Model.Capabilities["image_generation"] = map[string]any{}
+	})
+
+	resp, err := a.GenerateImage(context.Background(), "a red barn", map[string]any{
+		"response_format": "b64_json",
+		"size":            "16x16",
+	})
+	if err != nil {
+		t.Fatalf("GenerateImage failed: %v", err)
+	}
+
+	data, err := resp.Data[0].Bytes()
+	if err != nil {
+		t.Fatalf("Bytes failed: %v", err)
+	}
+	if !strings.HasPrefix(resp.Data[0].Source(), "data:image/png;base64,") {
+		t.Errorf("got source %q, want a PNG data URI", resp.Data[0].Source())
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("image is not a PNG: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 16 || b.Dy() != 16 {
+		t.Errorf("got %dx%d image, want 16x16", b.Dx(), b.Dy())
+	}
+
+	if got := s.Requests()[0].Deployment; got != "dall-e-3" {
+		t.Errorf("got deployment %q, want dall-e-3", got)
+	}
+
+	if _, err := a.GenerateImage(context.Background(), "a red barn", map[string]any{"size": "huge"}); err == nil {
+		t.Error("expected error for invalid size")
+	}
+}
+
+func TestServer_Transcription(t *testing.T) {
+	s := server.New(server.WithReply(server.Reply{Content: "Hello there. General Kenobi!"}))
+	defer s.Close()
+
+	a := newAgent(t, s.OllamaProvider(), func(cfg *config.AgentConfig) {
+		cfg.Model.Capabilities["transcription"] = map[string]any{"language": "en"}
+	})
+
+	audio := server.Speech("Hello there. General Kenobi!")
+	resp, err := a.Transcribe(context.Background(), "clip.wav", audio, map[string]any{
+		"response_format":         "verbose_json",
+		"timestamp_granularities": []string{"segment", "word"},
+	})
+	if err != nil {
+		t.Fatalf("Transcribe failed: %v", err)
+	}
+
+	if resp.Text != "Hello there. General Kenobi!" || resp.Language != "en" {
+		t.Errorf("got text %q language %q", resp.Text, resp.Language)
+	}
+	if len(resp.Segments) != 2 || resp.Segments[1].Text != "General Kenobi!" || resp.Segments[1].Start != 0.5 {
+		t.Errorf("got segments %+v", resp.Segments)
+	}
+	if len(resp.Words) != 4 || resp.Words[3].Word != "Kenobi" || resp.Words[3].End != 1 {
+		t.Errorf("got words %+v", resp.Words)
+	}
+
+	got := s.Requests()[0]
+	if got.Endpoint != server.EndpointTranscribe || got.Filename != "clip.wav" || !bytes.Equal(got.Audio, audio) {
+		t.Errorf("got endpoint %q filename %q, audio match %v", got.Endpoint, got.Filename, bytes.Equal(got.Audio, audio))
+	}
+}
+
+func TestServer_TranscriptionSubtitles(t *testing.T) {
+	s := server.New(server.WithReply(server.Reply{Content: "One two three four five. Six."}))
+	defer s.Close()
+
+	a := newAgent(t, s.AzureProvider("whisper"), func(cfg *config.AgentConfig) {
+		cfg.Model.Capabilities["transcription"] = map[string]any{}
+	})
+
+	resp, err := a.Transcribe(context.Background(), "clip.mp3", []byte("fake audio"), map[string]any{"response_format": "srt"})
+	if err != nil {
+		t.Fatalf("Transcribe failed: %v", err)
+	}
+
+	want := "1\n00:00:00,000 --> 00:00:01,250\nOne two three four five.\n\n2\n00:00:01,250 --> 00:00:01,500\nSix."
+	if resp.Text != want {
+		t.Errorf("got %q, want %q", resp.Text, want)
+	}
+	if got := s.Requests()[0].Deployment; got != "whisper" {
+		t.Errorf("got deployment %q", got)
+	}
+}
+
+func TestServer_Speech(t *testing.T) {
+	s := server.New()
+	defer s.Close()
+
+	a := newAgent(t, s.OllamaProvider(), func(cfg *config.AgentConfig) {
+		cfg.Model.Capabilities["speech"] = map[string]any{"voice": "alloy"}
+	})
+
+	text := strings.Repeat("word ", 20)
+	want := server.Speech(text)
+
+	resp, err := a.Speak(context.Background(), text)
+	if err != nil {
+		t.Fatalf("Speak failed: %v", err)
+	}
+	if resp.ContentType != "audio/wav" || !bytes.Equal(resp.Audio, want) {
+		t.Errorf("got %s audio of %d bytes, want %d bytes of audio/wav", resp.ContentType, len(resp.Audio), len(want))
+	}
+
+	chunks, err := a.SpeakStream(context.Background(), text)
+	if err != nil {
+		t.Fatalf("SpeakStream failed: %v", err)
+	}
+
+	var streamed []byte
+	count := 0
+	for chunk := range chunks {
+		if chunk.Error != nil {
+			t.Fatalf("stream error: %v", chunk.Error)
+		}
+		streamed = append(streamed, chunk.Audio...)
+		count++
+	}
+	if !bytes.Equal(streamed, want) {
+		t.Errorf("streamed %d bytes, want %d", len(streamed), len(want))
+	}
+	if count < 2 {
+		t.Errorf("got %d chunks, want audio streamed in several", count)
+	}
+
+	if got := s.Requests()[1]; got.Endpoint != server.EndpointSpeech || got.Body["voice"] != "alloy" {
+		t.Errorf("got endpoint %q voice %v", got.Endpoint, got.Body["voice"])
+	}
+}
+
+func TestServer_Rerank(t *testing.T) {
+	s := server.New()
+	defer s.Close()
+
+	documents := []string{
+		"Berlin is the capital of Germany.",
+		"Paris is the capital of France.",
+		"France is known for wine.",
+	}
+
+	tests := []struct {
+		name   string
+		format string
+		path   string
+	}{
+		{"cohere", "", "/v1/rerank"},
+		{"tei", "tei", "/rerank"},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := s.OllamaProvider()
+			if tt.format != "" {
+				provider.Options["rerank_format"] = tt.format
+			}
+			a := newAgent(t, provider, func(cfg *config.AgentConfig) {
+				cfg.Model.Capabilities["rerank"] = map[string]any{"top_n": 2}
+			})
+
+			resp, err := a.Rerank(context.Background(), "capital of France", documents, map[string]any{"return_documents": true})
+			if err != nil {
+				t.Fatalf("Rerank failed: %v", err)
+			}
+
+			if got := resp.Indices(); len(got) != 2 || got[0] != 1 || got[1] != 0 {
+				t.Errorf("got indices %v, want [1 0]", got)
+			}
+			if resp.Results[0].RelevanceScore != 1 || resp.Results[0].Document != documents[1] {
+				t.Errorf("got top result %+v", resp.Results[0])
+			}
+
+			got := s.Requests()[i]
+			if got.Endpoint != server.EndpointRerank || got.Path != tt.path || got.Prompt != "capital of France" || len(got.Input) != 3 {
+				t.Errorf("got endpoint %q path %q query %q with %d documents", got.Endpoint, got.Path, got.Prompt, len(got.Input))
+			}
+		})
+	}
+}
+
+func TestServer_RerankChatFallback(t *testing.T) {
+	s := server.New(server.WithReply(server.Reply{Content: "[0.1, 0.8]"}))
+	defer s.Close()
+
+	resp, err := newAgent(t, s.OllamaProvider()).Rerank(context.Background(), "capital of France", []string{"Berlin", "Paris"})
+	if err != nil {
+		t.Fatalf("Rerank failed: %v", err)
+	}
+
+	if got := resp.Indices(); len(got) != 2 || got[0] != 1 {
+		t.Errorf("got indices %v, want Paris first", got)
+	}
+
+	got := s.Requests()[0]
+	if got.Endpoint != server.EndpointChat || !strings.Contains(got.Prompt, "[1] Paris") {
+		t.Errorf("got endpoint %q prompt %q", got.Endpoint, got.Prompt)
+	}
+}
+
+func TestServer_RerankRouting(t *testing.T) {
+	tests := []struct {
+		name      string
+		protocols []string
+		want      server.Endpoint
+	}{
+		{"declared rerank without options", []string{"rerank"}, server.EndpointRerank},
+		{"declared without rerank", []string{"chat"}, server.EndpointChat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := server.New(server.WithReply(server.Reply{Content: "[0.1, 0.8]"}))
+			defer s.Close()
+
+			a := newAgent(t, s.OllamaProvider(), func(cfg *config.AgentConfig) {
+				cfg.Model.Protocols = tt.protocols
+				cfg.Model.Capabilities = nil
+			})
+
+			if _, err := a.Rerank(context.Background(), "capital of France", []string{"Berlin", "Paris"}); err != nil {
+				t.Fatalf("Rerank failed: %v", err)
+			}
+
+			if got := s.Requests()[0].Endpoint; got != tt.want {
+				t.Errorf("got endpoint %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServer_Moderation(t *testing.T) {
+	s := server.New(server.WithRule(server.EndpointIs(server.EndpointModeration), server.Reply{Flagged: []string{"violence"}}))
+	defer s.Close()
+
+	resp, err := newAgent(t, s.OllamaProvider()).Moderate(context.Background(), "Plan the attack.")
+	if err != nil {
+		t.Fatalf("Moderate failed: %v", err)
+	}
+
+	if !resp.Flagged() || len(resp.Results) != 1 {
+		t.Fatalf("got %+v, want one flagged result", resp)
+	}
+	if got := resp.Results[0].FlaggedCategories(); len(got) != 1 || got[0] != "violence" {
+		t.Errorf("got flagged categories %v, want [violence]", got)
+	}
+
+	got := s.Requests()[0]
+	if got.Endpoint != server.EndpointModeration || got.Path != "/v1/moderations" || got.Prompt != "Plan the attack." {
+		t.Errorf("got endpoint %q path %q prompt %q", got.Endpoint, got.Path, got.Prompt)
+	}
+}
+
+func TestServer_AzureContentFilter(t *testing.T) {
+	s := server.New(server.WithReply(server.Reply{Flagged: []string{"violence"}, FinishReason: "content_filter"}))
+	defer s.Close()
+
+	resp, err := newAgent(t, s.AzureProvider("gpt-4o")).Chat(context.Background(), "ping")
+	if err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+
+	if len(resp.PromptFilterResults) != 1 || resp.PromptFilterResults[0].ContentFilter.Flagged {
+		t.Errorf("got prompt filter results %+v, want one unflagged result", resp.PromptFilterResults)
+	}
+	if len(resp.ChoiceFilterResults) != 1 {
+		t.Fatalf("got %d choice filter results, want 1", len(resp.ChoiceFilterResults))
+	}
+	filter := resp.ChoiceFilterResults[0].ContentFilter
+	if !filter.Filtered || filter.Severities["violence"] != "high" || filter.Severities["hate"] != "safe" {
+		t.Errorf("got choice filter %+v, want violence filtered", filter)
+	}
+}
+
+func TestServer_Guardrails(t *testing.T) {
+	s := server.New(
+		server.WithRule(server.All(server.EndpointIs(server.EndpointModeration), server.PromptContains("attack")), server.Reply{Flagged: []string{"violence"}}),
+		server.WithRule(server.PromptContains("password"), server.Reply{Content: "The password is hunter2."}),
+		server.WithReply(server.Reply{Content: "Reach me at ops@example.com"}),
+	)
+	defer s.Close()
+
+	moderator := newAgent(t, s.OllamaProvider())
+	g := guardrails.New(
+		guardrails.WithInput(
+			guardrails.PII(guardrails.Redact),
+			guardrails.Moderation(moderator, guardrails.Block),
+		),
+		guardrails.WithOutput(
+			guardrails.PII(guardrails.Redact),
+			guardrails.Denylist(guardrails.Block, "hunter2"),
+		),
+	)
+	cfg := config.DefaultAgentConfig()
+	cfg.Provider = s.OllamaProvider()
+	cfg.Model = &config.ModelConfig{Name: "mock-model", Capabilities: map[string]map[string]any{"chat": {}}}
+	a, err := agent.New(&cfg, agent.WithGuardrails(g))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	t.Run("redacts input and output", func(t *testing.T) {
+		ctx, report := guardrails.WithReport(context.Background())
+
+		resp, err := a.Chat(ctx, "My email is me@example.com")
+		if err != nil {
+			t.Fatalf("Chat failed: %v", err)
+		}
+
+		if resp.Content() != "Reach me at [REDACTED_EMAIL]" {
+			t.Errorf("got content %q", resp.Content())
+		}
+		requests := s.Requests()
+		if got := requests[len(requests)-2]; got.Endpoint != server.EndpointModeration || got.Prompt != "My email is [REDACTED_EMAIL]" {
+			t.Errorf("moderation received %q %q, want the redacted prompt", got.Endpoint, got.Prompt)
+		}
+		if got := requests[len(requests)-1].Prompt; got != "My email is [REDACTED_EMAIL]" {
+			t.Errorf("server received prompt %q", got)
+		}
+
+		findings := report.Findings()
+		if len(findings) != 2 || findings[0].Stage != guardrails.Input || findings[1].Stage != guardrails.Output {
+			t.Errorf("got findings %+v, want an input and an output redaction", findings)
+		}
+	})
+
+	t.Run("blocks flagged input", func(t *testing.T) {
+		before := len(s.Requests())
+
+		_, err := a.Chat(context.Background(), "Plan the attack.")
+		if !errors.Is(err, guardrails.ErrBlocked) {
+			t.Fatalf("got error %v, want ErrBlocked", err)
+		}
+
+		requests := s.Requests()[before:]
+		if len(requests) != 1 || requests[0].Endpoint != server.EndpointModeration {
+			t.Errorf("got %d requests after the block, want only the moderation request", len(requests))
+		}
+	})
+
+	t.Run("blocks output", func(t *testing.T) {
+		if _, err := a.Chat(context.Background(), "What is the password?"); !errors.Is(err, guardrails.ErrBlocked) {
+			t.Errorf("got error %v, want ErrBlocked", err)
+		}
+	})
+
+	t.Run("blocks streamed output", func(t *testing.T) {
+		content, err := collect(t, a, "What is the password?")
+		if !errors.Is(err, guardrails.ErrBlocked) {
+			t.Errorf("got error %v, want ErrBlocked after the stream", err)
+		}
+		if content != "The password is hunter2." {
+			t.Errorf("got content %q, want the streamed content delivered", content)
+		}
+	})
+
+	t.Run("reports streamed redactions without applying them", func(t *testing.T) {
+		ctx, report := guardrails.WithReport(context.Background())
+
+		stream, err := a.ChatStream(ctx, "Hello")
+		if err != nil {
+			t.Fatalf("ChatStream failed: %v", err)
+		}
+
+		var content string
+		for chunk := range stream {
+			if chunk.Error != nil {
+				t.Fatalf("stream error: %v", chunk.Error)
+			}
+			content += chunk.Content()
+		}
+
+		if content != "Reach me at ops@example.com" {
+			t.Errorf("got content %q, want the unredacted streamed content", content)
+		}
+		findings := report.Findings()
+		if len(findings) != 1 || findings[0].Stage != guardrails.Output || findings[0].Action != guardrails.Redact {
+			t.Errorf("got findings %+v, want one output redaction", findings)
+		}
+	})
+}
+
+func TestServer_Logprobs(t *testing.T) {
+	s := server.New(server.WithReply(server.Reply{
+		Content:          "positive",
+		Alternatives:     []string{"negative"},
+		TokenProbability: 0.8,
+	}))
+	defer s.Close()
+
+	resp, err := newAgent(t, s.OllamaProvider()).Chat(context.Background(), "Classify: great product", map[string]any{
+		"n":            3,
+		"logprobs":     true,
+		"top_logprobs": 5,
+	})
+	if err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+
+	if resp.SystemFingerprint != server.SystemFingerprint {
+		t.Errorf("got system fingerprint %q, want %q", resp.SystemFingerprint, server.SystemFingerprint)
+	}
+	if got := resp.Contents(); len(got) != 3 || got[0] != "positive" || got[1] != "negative" || got[2] != "positive" {
+		t.Fatalf("got contents %v, want positive, negative, positive", got)
+	}
+
+	answer, agreement := resp.Vote()
+	if answer != "positive" || math.Abs(agreement-2.0/3.0) > 1e-9 {
+		t.Errorf("got vote %q at %v, want positive at 2/3", answer, agreement)
+	}
+
+	confidence, err := response.Confidence(resp.Choices[0].Logprobs, "positive", "negative")
+	if err != nil {
+		t.Fatalf("Confidence failed: %v", err)
+	}
+	if confidence.Answer != "positive" || math.Abs(confidence.Confidence-0.8) > 1e-9 {
+		t.Errorf("got %+v, want positive at 0.8", confidence)
+	}
+
+	chunks, err := newAgent(t, s.OllamaProvider()).ChatStream(context.Background(), "Classify: great product", map[string]any{"logprobs": true})
+	if err != nil {
+		t.Fatalf("ChatStream failed: %v", err)
+	}
+	var tokens int
+	for chunk := range chunks {
+		if lp := chunk.Logprobs(); lp != nil {
+			tokens += len(lp.Content)
+		}
+	}
+	if tokens != 1 {
+		t.Errorf("got %d streamed token logprobs, want 1", tokens)
+	}
+}
+
+func TestServer_Reasoning(t *testing.T) {
+	s := server.New(server.WithReply(server.Reply{Content: "42", Reasoning: "Six times seven is forty-two."}))
+	defer s.Close()
+
+	tracker := usage.NewTracker()
+	cfg := config.DefaultAgentConfig()
+	cfg.Client.Retry.MaxRetries = new(int)
+	cfg.Provider = s.AzureProvider("o4-mini")
+	cfg.SystemPrompt = "Answer with a number."
+	cfg.Model = &config.ModelConfig{
+		Name:      "o4-mini",
+		Reasoning: &config.ReasoningConfig{Effort: "low"},
+		Capabilities: map[string]map[string]any{
+			"chat": {"max_tokens": 2000},
+		},
+	}
+	a, err := agent.New(&cfg, agent.WithUsage(tracker))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	resp, err := a.Chat(context.Background(), "What is six times seven?", map[string]any{"reasoning_effort": "high"})
+	if err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+
+	if resp.Content() != "42" || resp.Choices[0].Message.Reasoning != "Six times seven is forty-two." {
+		t.Errorf("got content %q and reasoning %q", resp.Content(), resp.Choices[0].Message.Reasoning)
+	}
+	if got := resp.Usage.ReasoningTokens(); got != 5 {
+		t.Errorf("got %d reasoning tokens, want 5", got)
+	}
+	if got := tracker.Snapshot().Total.ReasoningTokens; got != 5 {
+		t.Errorf("got %d tracked reasoning tokens, want 5", got)
+	}
+
+	req := s.Requests()[0]
+	if req.Body["max_completion_tokens"] != float64(2000) || req.Body["max_tokens"] != nil || req.Body["reasoning_effort"] != "high" {
+		t.Errorf("got body %v, want max_completion_tokens and high reasoning effort", req.Body)
+	}
+	if req.Messages[0].Role != "developer" || req.System != "Answer with a number." {
+		t.Errorf("got first message %+v, want the system prompt as a developer message", req.Messages[0])
+	}
+
+	chunks, err := a.ChatStream(context.Background(), "What is six times seven?")
+	if err != nil {
+		t.Fatalf("ChatStream failed: %v", err)
+	}
+	var reasoning, content strings.Builder
+	for chunk := range chunks {
+		reasoning.WriteString(chunk.Reasoning())
+		content.WriteString(chunk.Content())
+	}
+	if reasoning.String() != "Six times seven is forty-two." || content.String() != "42" {
+		t.Errorf("got streamed reasoning %q and content %q", reasoning.String(), content.String())
+	}
+	if got := s.Requests()[1].Body["reasoning_effort"]; got != "low" {
+		t.Errorf("got configured reasoning_effort %v, want low", got)
+	}
+
+	if _, err := a.Chat(context.Background(), "ping", map[string]any{"reasoning_effort": "extreme"}); err == nil {
+		t.Error("expected error for unknown reasoning_effort")
+	}
+}
@@ -0,0 +1,158 @@
+package protocol_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/go-agents/pkg/protocol"
+)
+
+func TestContentPart_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		part protocol.ContentPart
+		want string
+	}{
+		{
+			name: "text",
+			part: protocol.TextPart("hello"),
+			want: `{"type":"text","text":"hello"}`,
+		},
+		{
+			name: "image url",
+			part: protocol.ImageURLPart("https://example.com/a.png", "low"),
+			want: `{"type":"image_url","image_url":{"url":"https://example.com/a.png","detail":"low"}}`,
+		},
+		{
+			name: "image data",
+			part: protocol.ImageDataPart("image/png", []byte("png"), ""),
+			want: `{"type":"image_url","image_url":{"url":"data:image/png;base64,cG5n"}}`,
+		},
+		{
+			name: "audio",
+			part: protocol.AudioPart([]byte("wav"), "wav"),
+			want: `{"type":"input_audio","input_audio":{"data":"d2F2","format":"wav"}}`,
+		},
+		{
+			name: "file",
+			part: protocol.FilePart("a.pdf", "application/pdf", []byte("pdf")),
+			want: `{"type":"file","file":{"filename":"a.pdf","file_data":"data:application/pdf;base64,cGRm"}}`,
+		},
+		{
+			name: "file id",
+			part: protocol.FileIDPart("file-123"),
+			want: `{"type":"file","file":{"file_id":"file-123"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.part)
+			if err != nil {
+				t.Fatalf("Marshal failed: %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("got %s, want %s", data, tt.want)
+			}
+			if err := tt.part.Validate(); err != nil {
+				t.Errorf("Validate failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestContentPart_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		part protocol.ContentPart
+	}{
+		{"image without url", protocol.ContentPart{Type: protocol.ContentImageURL}},
+		{"audio without format", protocol.ContentPart{Type: protocol.ContentAudio, InputAudio: &protocol.InputAudio{Data: "AA=="}}},
+		{"empty file", protocol.ContentPart{Type: protocol.ContentFile, File: &protocol.File{}}},
+		{"unknown type", protocol.ContentPart{Type: "video"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.part.Validate(); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+}
+
+func TestContentPart_MIMEType(t *testing.T) {
+	if got := protocol.ImageDataPart("image/jpeg", []byte{1}, "").MIMEType(); got != "image/jpeg" {
+		t.Errorf("got %q, want image/jpeg", got)
+	}
+	if got := protocol.ImageURLPart("https://example.com/a.png", "").MIMEType(); got != "" {
+		t.Errorf("got %q for remote url, want empty", got)
+	}
+}
+
+func TestMessage_JSONRoundTrip(t *testing.T) {
+	original := []protocol.Message{
+		protocol.NewMessage("system", "be brief"),
+		protocol.NewContentMessage("user",
+			protocol.TextPart("Compare:"),
+			protocol.ImageDataPart("image/png", []byte("a"), "high"),
+			protocol.TextPart("and"),
+			protocol.ImageURLPart("https://example.com/b.png", ""),
+		),
+	}
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var decoded []protocol.Message
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if text, ok := decoded[0].Content.(string); !ok || text != "be brief" {
+		t.Errorf("got system content %#v, want string", decoded[0].Content)
+	}
+
+	parts, ok := decoded[1].Content.([]protocol.ContentPart)
+	if !ok {
+		t.Fatalf("got content %T, want []protocol.ContentPart", decoded[1].Content)
+	}
+	if len(parts) != 4 {
+		t.Fatalf("got %d parts, want 4", len(parts))
+	}
+	if parts[1].ImageURL == nil || parts[1].ImageURL.Detail != "high" || parts[1].MIMEType() != "image/png" {
+		t.Errorf("image part not preserved: %#v", parts[1])
+	}
+	if got := decoded[1].Text(); got != "Compare:\nand" {
+		t.Errorf("got text %q, want %q", got, "Compare:\nand")
+	}
+}
+
+func TestMessage_Parts(t *testing.T) {
+	parts, err := protocol.NewMessage("user", "hi").Parts()
+	if err != nil {
+		t.Fatalf("Parts failed: %v", err)
+	}
+	if len(parts) != 1 || parts[0].Text != "hi" {
+		t.Errorf("got %#v, want single text part", parts)
+	}
+
+	legacy := protocol.NewMessage("user", []map[string]any{
+		{"type": "text", "text": "look"},
+		{"type": "image_url", "image_url": map[string]any{"url": "https://example.com/a.png"}},
+	})
+	parts, err = legacy.Parts()
+	if err != nil {
+		t.Fatalf("Parts failed: %v", err)
+	}
+	if len(parts) != 2 || parts[1].ImageURL.URL != "https://example.com/a.png" {
+		t.Errorf("got %#v, want converted parts", parts)
+	}
+
+	if _, err := protocol.NewMessage("user", 42).Parts(); err == nil || !strings.Contains(err.Error(), "content parts") {
+		t.Errorf("expected conversion error, got %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package protocol_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/JaimeStill/go-agents/pkg/protocol"
+)
+
+func TestMessage_ToolMessagesJSON(t *testing.T) {
+	call := protocol.ToolCall{
+		ID:   "call_1",
+		Type: "function",
+		Function: protocol.ToolCallFunction{
+			Name:      "get_weather",
+			Arguments: `{"location":"Boston"}`,
+		},
+	}
+
+	tests := []struct {
+		name string
+		msg  protocol.Message
+		want string
+	}{
+		{
+			name: "assistant tool calls",
+			msg:  protocol.NewToolCallMessage(call),
+			want: `{"role":"assistant","content":null,"tool_calls":[{"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"location\":\"Boston\"}"}}]}`,
+		},
+		{
+			name: "tool result",
+			msg:  protocol.NewToolResultMessage("call_1", "sunny"),
+			want: `{"role":"tool","content":"sunny","tool_call_id":"call_1"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.msg)
+			if err != nil {
+				t.Fatalf("Marshal failed: %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("got %s, want %s", data, tt.want)
+			}
+
+			var decoded protocol.Message
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("Unmarshal failed: %v", err)
+			}
+			again, _ := json.Marshal(decoded)
+			if string(again) != tt.want {
+				t.Errorf("round trip got %s, want %s", again, tt.want)
+			}
+		})
+	}
+}
+
+func TestMessage_UnmarshalJSON_Assistant(t *testing.T) {
+	var msg protocol.Message
+	data := `{"role":"assistant","content":null,"refusal":"I can't help with that.","reasoning_content":"Policy check."}`
+	if err := json.Unmarshal([]byte(data), &msg); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if msg.Content != nil {
+		t.Errorf("got content %#v, want nil", msg.Content)
+	}
+	if msg.Refusal != "I can't help with that." {
+		t.Errorf("got refusal %q", msg.Refusal)
+	}
+	if msg.Reasoning != "Policy check." {
+		t.Errorf("got reasoning %q", msg.Reasoning)
+	}
+
+	var ollama protocol.Message
+	if err := json.Unmarshal([]byte(`{"role":"assistant","content":"4","reasoning":"2+2"}`), &ollama); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if ollama.Reasoning != "2+2" {
+		t.Errorf("got reasoning %q from reasoning field, want 2+2", ollama.Reasoning)
+	}
+}
+
+func TestToolCallFunction_DecodeArguments(t *testing.T) {
+	fn := protocol.ToolCallFunction{Name: "get_weather", Arguments: `{"location":"Boston"}`}
+
+	var args struct {
+		Location string `json:"location"`
+	}
+	if err := fn.DecodeArguments(&args); err != nil {
+		t.Fatalf("DecodeArguments failed: %v", err)
+	}
+	if args.Location != "Boston" {
+		t.Errorf("got location %q", args.Location)
+	}
+
+	fn.Arguments = `{"location":`
+	if err := fn.DecodeArguments(&args); err == nil {
+		t.Error("expected error for malformed arguments")
+	}
+}
@@ -16,6 +16,11 @@ func TestProtocol_Constants(t *testing.T) {
 		{"Vision", protocol.Vision, "vision"},
 		{"Tools", protocol.Tools, "tools"},
 		{"Embeddings", protocol.Embeddings, "embeddings"},
+		{"ImageGeneration", protocol.ImageGeneration, "image_generation"},
+		{"Transcription", protocol.Transcription, "transcription"},
+		{"Speech", protocol.Speech, "speech"},
+		{"Rerank", protocol.Rerank, "rerank"},
+		{"Moderation", protocol.Moderation, "moderation"},
 	}
 
 	for _, tt := range tests {
@@ -37,6 +42,11 @@ func TestIsValid(t *testing.T) {
 		{"vision valid", "vision", true},
 		{"tools valid", "tools", true},
 		{"embeddings valid", "embeddings", true},
+		{"image_generation valid", "image_generation", true},
+		{"transcription valid", "transcription", true},
+		{"speech valid", "speech", true},
+		{"rerank valid", "rerank", true},
+		{"moderation valid", "moderation", true},
 		{"invalid", "invalid", false},
 		{"empty string", "", false},
 		{"uppercase", "CHAT", false},
@@ -61,6 +71,11 @@ func TestValidProtocols(t *testing.T) {
 		protocol.Vision,
 		protocol.Tools,
 		protocol.Embeddings,
+		protocol.ImageGeneration,
+		protocol.Transcription,
+		protocol.Speech,
+		protocol.Rerank,
+		protocol.Moderation,
 	}
 
 	if len(result) != len(expected) {
@@ -76,7 +91,7 @@ func TestValidProtocols(t *testing.T) {
 
 func TestProtocolStrings(t *testing.T) {
 	result := protocol.ProtocolStrings()
-	expected := "chat, vision, tools, embeddings"
+	expected := "chat, vision, tools, embeddings, image_generation, transcription, speech, rerank, moderation"
 
 	if result != expected {
 		t.Errorf("got %q, want %q", result, expected)
@@ -93,6 +108,11 @@ func TestProtocol_SupportsStreaming(t *testing.T) {
 		{"Vision supports streaming", protocol.Vision, true},
 		{"Tools supports streaming", protocol.Tools, true},
 		{"Embeddings does not support streaming", protocol.Embeddings, false},
+		{"ImageGeneration does not support streaming", protocol.ImageGeneration, false},
+		{"Transcription does not support streaming", protocol.Transcription, false},
+		{"Speech supports streaming", protocol.Speech, true},
+		{"Rerank does not support streaming", protocol.Rerank, false},
+		{"Moderation does not support streaming", protocol.Moderation, false},
 	}
 
 	for _, tt := range tests {
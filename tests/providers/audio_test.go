@@ -0,0 +1,161 @@
+package providers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"testing"
+
+	"github.com/JaimeStill/go-agents/pkg/protocol"
+	"github.com/JaimeStill/go-agents/pkg/providers"
+)
+
+func TestBaseProvider_Marshal_Transcription(t *testing.T) {
+	provider := providers.NewBaseProvider("test", "https://api.test.com")
+
+	audio := []byte("ID3 fake mp3 data")
+	data := &providers.TranscriptionData{
+		Model:                  "whisper-1",
+		Audio:                  audio,
+		Filename:               "recordings/meeting.mp3",
+		Language:               "en",
+		ResponseFormat:         "verbose_json",
+		TimestampGranularities: []string{"segment", "word"},
+		Options:                map[string]any{"temperature": 0.2},
+	}
+
+	body, err := provider.Marshal(protocol.Transcription, data)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	again, err := provider.Marshal(protocol.Transcription, data)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !bytes.Equal(body, again) {
+		t.Error("identical uploads produced different bodies")
+	}
+
+	_, params, err := mime.ParseMediaType(providers.MultipartContentType(providers.MultipartBoundary(audio)))
+	if err != nil {
+		t.Fatalf("ParseMediaType failed: %v", err)
+	}
+
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	fields := map[string][]string{}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("NextPart failed: %v", err)
+		}
+		value, _ := io.ReadAll(part)
+		if part.FileName() != "" {
+			if part.FileName() != "meeting.mp3" {
+				t.Errorf("got filename %q, want meeting.mp3", part.FileName())
+			}
+			if got := part.Header.Get("Content-Type"); got != "audio/mpeg" {
+				t.Errorf("got file content type %q, want audio/mpeg", got)
+			}
+			if !bytes.Equal(value, audio) {
+				t.Error("uploaded audio does not match")
+			}
+			continue
+		}
+		fields[part.FormName()] = append(fields[part.FormName()], string(value))
+	}
+
+	expected := map[string]string{
+		"model":           "whisper-1",
+		"language":        "en",
+		"response_format": "verbose_json",
+		"temperature":     "0.2",
+	}
+	for key, want := range expected {
+		if got := fields[key]; len(got) != 1 || got[0] != want {
+			t.Errorf("got %s %v, want %q", key, got, want)
+		}
+	}
+	if got := fields["timestamp_granularities[]"]; len(got) != 2 {
+		t.Errorf("got timestamp_granularities %v, want segment and word", got)
+	}
+	if _, ok := fields["prompt"]; ok {
+		t.Error("empty prompt should be omitted")
+	}
+}
+
+func TestBaseProvider_Marshal_TranscriptionInvalid(t *testing.T) {
+	provider := providers.NewBaseProvider("test", "https://api.test.com")
+
+	tests := []struct {
+		name string
+		data *providers.TranscriptionData
+	}{
+		{"no audio", &providers.TranscriptionData{Model: "whisper-1"}},
+		{"unknown format", &providers.TranscriptionData{Model: "whisper-1", Audio: []byte("a"), ResponseFormat: "xml"}},
+		{"timestamps without verbose_json", &providers.TranscriptionData{Model: "whisper-1", Audio: []byte("a"), TimestampGranularities: []string{"word"}}},
+		{"unknown granularity", &providers.TranscriptionData{Model: "whisper-1", Audio: []byte("a"), ResponseFormat: "verbose_json", TimestampGranularities: []string{"sentence"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := provider.Marshal(protocol.Transcription, tt.data); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestBaseProvider_Marshal_Speech(t *testing.T) {
+	provider := providers.NewBaseProvider("test", "https://api.test.com")
+
+	data := &providers.SpeechData{
+		Model:          "tts-1",
+		Input:          "Meeting starts in five minutes.",
+		Voice:          "alloy",
+		ResponseFormat: "wav",
+		Speed:          1.25,
+		Options:        map[string]any{"instructions": "Speak calmly."},
+	}
+
+	body, err := provider.Marshal(protocol.Speech, data)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal(body, &result); err != nil {
+		t.Fatalf("Failed to unmarshal result: %v", err)
+	}
+
+	expected := map[string]any{
+		"model":           "tts-1",
+		"input":           "Meeting starts in five minutes.",
+		"voice":           "alloy",
+		"response_format": "wav",
+		"speed":           1.25,
+		"instructions":    "Speak calmly.",
+	}
+	for key, want := range expected {
+		if result[key] != want {
+			t.Errorf("got %s %v, want %v", key, result[key], want)
+		}
+	}
+
+	invalid := []*providers.SpeechData{
+		{Model: "tts-1", Voice: "alloy"},
+		{Model: "tts-1", Input: "hi"},
+		{Model: "tts-1", Input: "hi", Voice: "alloy", ResponseFormat: "ogg"},
+		{Model: "tts-1", Input: "hi", Voice: "alloy", Speed: 5},
+	}
+	for i, d := range invalid {
+		if _, err := provider.Marshal(protocol.Speech, d); err == nil {
+			t.Errorf("case %d: expected error", i)
+		}
+	}
+}
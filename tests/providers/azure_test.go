@@ -2,6 +2,7 @@ package providers_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/JaimeStill/go-agents/pkg/config"
@@ -145,6 +146,18 @@ func TestAzure_Endpoint(t *testing.T) {
 			protocol.Embeddings,
 			"https://my-resource.openai.azure.com/deployments/gpt-4-deployment/embeddings?api-version=2024-02-01",
 		},
+		{
+			protocol.ImageGeneration,
+			"https://my-resource.openai.azure.com/deployments/gpt-4-deployment/images/generations?api-version=2024-02-01",
+		},
+		{
+			protocol.Transcription,
+			"https://my-resource.openai.azure.com/deployments/gpt-4-deployment/audio/transcriptions?api-version=2024-02-01",
+		},
+		{
+			protocol.Speech,
+			"https://my-resource.openai.azure.com/deployments/gpt-4-deployment/audio/speech?api-version=2024-02-01",
+		},
 	}
 
 	for _, tt := range tests {
@@ -272,3 +285,40 @@ func TestAzure_PrepareStreamRequest(t *testing.T) {
 		t.Errorf("got Cache-Control header %q, want %q", request.Headers["Cache-Control"], "no-cache")
 	}
 }
+
+func TestAzure_Validate(t *testing.T) {
+	cfg := &config.ProviderConfig{
+		Name:    "azure",
+		BaseURL: "https://example.openai.azure.com/openai",
+		Options: map[string]any{
+			"auth_type":   "oauth",
+			"api_version": "2024-08-01-preview",
+		},
+	}
+
+	err := cfg.Validate()
+
+	var errs config.ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("got error %v, want ValidationErrors", err)
+	}
+
+	paths := make(map[string]bool)
+	for _, e := range errs {
+		paths[e.Path] = true
+	}
+
+	for _, want := range []string{"options.deployment", "options.token", "options.auth_type"} {
+		if !paths[want] {
+			t.Errorf("missing validation error for %s in %v", want, err)
+		}
+	}
+
+	cfg.Options["auth_type"] = "api_key"
+	cfg.Options["deployment"] = "gpt-4o"
+	cfg.Options["token"] = "key"
+
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected valid config, got %v", err)
+	}
+}
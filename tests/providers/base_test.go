@@ -2,6 +2,7 @@ package providers_test
 
 import (
 	"encoding/json"
+	"slices"
 	"testing"
 
 	"github.com/JaimeStill/go-agents/pkg/protocol"
@@ -198,3 +199,294 @@ func TestBaseProvider_Marshal_UnsupportedProtocol(t *testing.T) {
 		t.Error("expected error for unsupported protocol, got nil")
 	}
 }
+
+func TestBaseProvider_Marshal_VisionContentParts(t *testing.T) {
+	provider := providers.NewBaseProvider("test", "https://api.test.com")
+
+	visionData := &providers.VisionData{
+		Model: "gpt-4o",
+		Messages: []protocol.Message{
+			protocol.NewContentMessage("user",
+				protocol.TextPart("Here is the first page:"),
+				protocol.ImageURLPart("data:image/png;base64,AAAA", "high"),
+			),
+			protocol.NewMessage("assistant", "It shows a chart."),
+			protocol.NewContentMessage("user",
+				protocol.TextPart("Compare it with"),
+				protocol.ImageURLPart("https://example.com/page2.png", ""),
+				protocol.TextPart("and this one:"),
+			),
+		},
+		Images:        []string{"https://example.com/page3.png"},
+		VisionOptions: map[string]any{"detail": "low"},
+	}
+
+	body, err := provider.Marshal(protocol.Vision, visionData)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var result struct {
+		Messages []struct {
+			Role    string          `json:"role"`
+			Content json.RawMessage `json:"content"`
+		} `json:"messages"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		t.Fatalf("Failed to unmarshal result: %v", err)
+	}
+
+	var history []protocol.ContentPart
+	if err := json.Unmarshal(result.Messages[0].Content, &history); err != nil {
+		t.Fatalf("history content is not content parts: %v", err)
+	}
+	if len(history) != 2 || history[1].ImageURL.Detail != "high" {
+		t.Errorf("got history %#v, want text and high-detail image", history)
+	}
+
+	var last []protocol.ContentPart
+	if err := json.Unmarshal(result.Messages[2].Content, &last); err != nil {
+		t.Fatalf("last content is not content parts: %v", err)
+	}
+
+	types := make([]protocol.ContentType, len(last))
+	for i, p := range last {
+		types[i] = p.Type
+	}
+	want := []protocol.ContentType{
+		protocol.ContentText, protocol.ContentImageURL, protocol.ContentText, protocol.ContentImageURL,
+	}
+	if !slices.Equal(types, want) {
+		t.Errorf("got part types %v, want %v", types, want)
+	}
+	if last[3].ImageURL.URL != "https://example.com/page3.png" || last[3].ImageURL.Detail != "low" {
+		t.Errorf("got appended image %#v, want page3 with low detail", last[3].ImageURL)
+	}
+}
+
+func TestBaseProvider_Marshal_VisionWithoutImages(t *testing.T) {
+	provider := providers.NewBaseProvider("test", "https://api.test.com")
+
+	withParts := &providers.VisionData{
+		Model: "gpt-4o",
+		Messages: []protocol.Message{
+			protocol.NewContentMessage("user",
+				protocol.ImageURLPart("https://example.com/a.png", ""),
+				protocol.TextPart("Describe this."),
+			),
+		},
+	}
+	if _, err := provider.Marshal(protocol.Vision, withParts); err != nil {
+		t.Errorf("expected image parts to satisfy vision request, got %v", err)
+	}
+
+	textOnly := &providers.VisionData{
+		Model:    "gpt-4o",
+		Messages: []protocol.Message{protocol.NewMessage("user", "Describe this.")},
+	}
+	if _, err := provider.Marshal(protocol.Vision, textOnly); err == nil {
+		t.Error("expected error for vision request without images")
+	}
+}
+
+func TestBaseProvider_Marshal_InvalidContentPart(t *testing.T) {
+	provider := providers.NewBaseProvider("test", "https://api.test.com")
+
+	chatData := &providers.ChatData{
+		Model: "gpt-4o",
+		Messages: []protocol.Message{
+			protocol.NewContentMessage("user", protocol.ContentPart{Type: protocol.ContentImageURL}),
+		},
+	}
+
+	if _, err := provider.Marshal(protocol.Chat, chatData); err == nil {
+		t.Error("expected error for image part without url")
+	}
+}
+
+func TestBaseProvider_Marshal_ToolConversation(t *testing.T) {
+	provider := providers.NewBaseProvider("test", "https://api.test.com")
+
+	assistant := protocol.NewToolCallMessage(protocol.ToolCall{
+		ID:       "call_1",
+		Function: protocol.ToolCallFunction{Name: "get_weather", Arguments: `{"location":"Boston"}`},
+	})
+	assistant.Reasoning = "The user wants the weather."
+
+	toolsData := &providers.ToolsData{
+		Model: "gpt-4o",
+		Messages: []protocol.Message{
+			protocol.NewMessage("user", "What's the weather in Boston?"),
+			assistant,
+			protocol.NewToolResultMessage("call_1", `{"forecast":"sunny"}`),
+		},
+		Tools: []providers.ToolDefinition{{Name: "get_weather", Parameters: map[string]any{"type": "object"}}},
+	}
+
+	body, err := provider.Marshal(protocol.Tools, toolsData)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var result struct {
+		Messages []map[string]any `json:"messages"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		t.Fatalf("Failed to unmarshal result: %v", err)
+	}
+
+	sent := result.Messages[1]
+	if _, ok := sent["reasoning_content"]; ok {
+		t.Error("reasoning should not be sent to the provider")
+	}
+	calls := sent["tool_calls"].([]any)
+	if call := calls[0].(map[string]any); call["type"] != "function" || call["id"] != "call_1" {
+		t.Errorf("got tool call %v, want function call_1", call)
+	}
+
+	if tool := result.Messages[2]; tool["role"] != "tool" || tool["tool_call_id"] != "call_1" {
+		t.Errorf("got tool message %v", tool)
+	}
+
+	if toolsData.Messages[1].Reasoning == "" || toolsData.Messages[1].ToolCalls[0].Type != "" {
+		t.Error("Marshal modified the caller's messages")
+	}
+}
+
+func TestBaseProvider_Marshal_ToolMessageWithoutCallID(t *testing.T) {
+	provider := providers.NewBaseProvider("test", "https://api.test.com")
+
+	chatData := &providers.ChatData{
+		Model: "gpt-4o",
+		Messages: []protocol.Message{
+			{Role: "tool", Content: "sunny"},
+		},
+	}
+
+	if _, err := provider.Marshal(protocol.Chat, chatData); err == nil {
+		t.Error("expected error for tool message without tool_call_id")
+	}
+}
+
+func TestBaseProvider_Marshal_ImageGeneration(t *testing.T) {
+	provider := providers.NewBaseProvider("test", "https://api.test.com")
+
+	imageData := &providers.ImageGenerationData{
+		Model:          "dall-e-3",
+		Prompt:         "A lighthouse at dusk",
+		Size:           "1024x1024",
+		Count:          2,
+		Quality:        "hd",
+		ResponseFormat: "b64_json",
+		Options:        map[string]any{"style": "natural"},
+	}
+
+	body, err := provider.Marshal(protocol.ImageGeneration, imageData)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal(body, &result); err != nil {
+		t.Fatalf("Failed to unmarshal result: %v", err)
+	}
+
+	expected := map[string]any{
+		"model":           "dall-e-3",
+		"prompt":          "A lighthouse at dusk",
+		"size":            "1024x1024",
+		"n":               float64(2),
+		"quality":         "hd",
+		"response_format": "b64_json",
+		"style":           "natural",
+	}
+	for key, want := range expected {
+		if result[key] != want {
+			t.Errorf("got %s %v, want %v", key, result[key], want)
+		}
+	}
+
+	if _, err := provider.Marshal(protocol.ImageGeneration, &providers.ImageGenerationData{Model: "dall-e-3"}); err == nil {
+		t.Error("expected error for empty prompt")
+	}
+}
+
+func TestBaseProvider_Marshal_Reasoning(t *testing.T) {
+	provider := providers.NewBaseProvider("test", "https://api.test.com")
+	messages := []protocol.Message{
+		protocol.NewMessage("system", "Be terse."),
+		protocol.NewMessage("user", "Hello"),
+	}
+
+	tests := []struct {
+		name      string
+		reasoning *providers.Reasoning
+		options   map[string]any
+		role      string
+		want      map[string]any
+		absent    []string
+	}{
+		{
+			name:    "not a reasoning model",
+			options: map[string]any{"max_tokens": 100},
+			role:    "system",
+			want:    map[string]any{"max_tokens": float64(100)},
+			absent:  []string{"max_completion_tokens", "reasoning_effort"},
+		},
+		{
+			name:      "defaults",
+			reasoning: &providers.Reasoning{},
+			options:   map[string]any{"max_tokens": 100},
+			role:      "developer",
+			want:      map[string]any{"max_completion_tokens": float64(100)},
+			absent:    []string{"max_tokens", "reasoning_effort"},
+		},
+		{
+			name:      "effort and system role",
+			reasoning: &providers.Reasoning{Effort: protocol.ReasoningHigh, SystemRole: "user"},
+			options:   map[string]any{"max_tokens": 100, "max_completion_tokens": 4000},
+			role:      "user",
+			want:      map[string]any{"max_completion_tokens": float64(4000), "reasoning_effort": "high"},
+			absent:    []string{"max_tokens"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := provider.Marshal(protocol.Chat, &providers.ChatData{
+				Model:     "o4-mini",
+				Messages:  messages,
+				Options:   tt.options,
+				Reasoning: tt.reasoning,
+			})
+			if err != nil {
+				t.Fatalf("Marshal failed: %v", err)
+			}
+
+			var result map[string]any
+			if err := json.Unmarshal(body, &result); err != nil {
+				t.Fatalf("Failed to unmarshal result: %v", err)
+			}
+
+			for key, want := range tt.want {
+				if result[key] != want {
+					t.Errorf("got %s %v, want %v", key, result[key], want)
+				}
+			}
+			for _, key := range tt.absent {
+				if _, ok := result[key]; ok {
+					t.Errorf("unexpected %s in body", key)
+				}
+			}
+
+			first := result["messages"].([]any)[0].(map[string]any)
+			if first["role"] != tt.role {
+				t.Errorf("got system message role %v, want %s", first["role"], tt.role)
+			}
+		})
+	}
+
+	if messages[0].Role != "system" {
+		t.Error("Marshal modified the caller's messages")
+	}
+}
@@ -0,0 +1,62 @@
+package providers_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/JaimeStill/go-agents/pkg/protocol"
+	"github.com/JaimeStill/go-agents/pkg/providers"
+)
+
+func TestBaseProvider_Marshal_Moderation(t *testing.T) {
+	provider := providers.NewBaseProvider("test", "https://api.test.com")
+
+	tests := []struct {
+		name  string
+		data  *providers.ModerationData
+		model bool
+	}{
+		{
+			name:  "single input",
+			data:  &providers.ModerationData{Model: "omni-moderation-latest", Input: "hello"},
+			model: true,
+		},
+		{
+			name: "batch input without model",
+			data: &providers.ModerationData{Input: []string{"a", "b"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := provider.Marshal(protocol.Moderation, tt.data)
+			if err != nil {
+				t.Fatalf("Marshal failed: %v", err)
+			}
+
+			var result map[string]any
+			if err := json.Unmarshal(body, &result); err != nil {
+				t.Fatalf("Failed to unmarshal result: %v", err)
+			}
+
+			if _, ok := result["input"]; !ok {
+				t.Error("input missing from body")
+			}
+			if _, ok := result["model"]; ok != tt.model {
+				t.Errorf("got model present %v, want %v", ok, tt.model)
+			}
+		})
+	}
+
+	invalid := []*providers.ModerationData{
+		{Model: "m"},
+		{Model: "m", Input: ""},
+		{Model: "m", Input: []string{}},
+		{Model: "m", Input: 42},
+	}
+	for i, d := range invalid {
+		if _, err := provider.Marshal(protocol.Moderation, d); err == nil {
+			t.Errorf("case %d: expected error", i)
+		}
+	}
+}
@@ -108,6 +108,26 @@ func TestOllama_Endpoint(t *testing.T) {
 			protocol.Embeddings,
 			"http://localhost:11434/v1/embeddings",
 		},
+		{
+			protocol.ImageGeneration,
+			"http://localhost:11434/v1/images/generations",
+		},
+		{
+			protocol.Transcription,
+			"http://localhost:11434/v1/audio/transcriptions",
+		},
+		{
+			protocol.Speech,
+			"http://localhost:11434/v1/audio/speech",
+		},
+		{
+			protocol.Rerank,
+			"http://localhost:11434/v1/rerank",
+		},
+		{
+			protocol.Moderation,
+			"http://localhost:11434/v1/moderations",
+		},
 	}
 
 	for _, tt := range tests {
@@ -224,3 +244,36 @@ func TestOllama_PrepareStreamRequest(t *testing.T) {
 		t.Errorf("got Cache-Control header %q, want %q", request.Headers["Cache-Control"], "no-cache")
 	}
 }
+
+func TestOllama_Marshal_ContentParts(t *testing.T) {
+	provider, err := providers.NewOllama(&config.ProviderConfig{
+		Name:    "ollama",
+		BaseURL: "http://localhost:11434",
+	})
+	if err != nil {
+		t.Fatalf("NewOllama failed: %v", err)
+	}
+
+	images := &providers.ChatData{
+		Model: "llava",
+		Messages: []protocol.Message{
+			protocol.NewContentMessage("user",
+				protocol.TextPart("What is this?"),
+				protocol.ImageDataPart("image/png", []byte("png"), ""),
+			),
+		},
+	}
+	if _, err := provider.Marshal(protocol.Chat, images); err != nil {
+		t.Errorf("expected image parts to be accepted, got %v", err)
+	}
+
+	audio := &providers.ChatData{
+		Model: "llava",
+		Messages: []protocol.Message{
+			protocol.NewContentMessage("user", protocol.AudioPart([]byte("wav"), "wav")),
+		},
+	}
+	if _, err := provider.Marshal(protocol.Chat, audio); err == nil {
+		t.Error("expected error for audio part")
+	}
+}
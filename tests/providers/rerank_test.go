@@ -0,0 +1,138 @@
+package providers_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/JaimeStill/go-agents/pkg/config"
+	"github.com/JaimeStill/go-agents/pkg/protocol"
+	"github.com/JaimeStill/go-agents/pkg/providers"
+)
+
+func TestBaseProvider_Marshal_Rerank(t *testing.T) {
+	provider := providers.NewBaseProvider("test", "https://api.test.com")
+
+	data := &providers.RerankData{
+		Model:           "rerank-v3.5",
+		Query:           "capital of France",
+		Documents:       []string{"Paris is the capital of France.", "Berlin is in Germany."},
+		TopN:            1,
+		ReturnDocuments: true,
+		Options:         map[string]any{"max_chunks_per_doc": 4},
+	}
+
+	body, err := provider.Marshal(protocol.Rerank, data)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal(body, &result); err != nil {
+		t.Fatalf("Failed to unmarshal result: %v", err)
+	}
+
+	expected := map[string]any{
+		"model":              "rerank-v3.5",
+		"query":              "capital of France",
+		"top_n":              float64(1),
+		"return_documents":   true,
+		"max_chunks_per_doc": float64(4),
+	}
+	for key, want := range expected {
+		if result[key] != want {
+			t.Errorf("got %s %v, want %v", key, result[key], want)
+		}
+	}
+	if docs, _ := result["documents"].([]any); len(docs) != 2 {
+		t.Errorf("got documents %v, want 2", result["documents"])
+	}
+
+	invalid := []*providers.RerankData{
+		{Model: "rerank-v3.5", Documents: []string{"a"}},
+		{Model: "rerank-v3.5", Query: "q"},
+		{Model: "rerank-v3.5", Query: "q", Documents: []string{"a"}, TopN: -1},
+	}
+	for i, d := range invalid {
+		if _, err := provider.Marshal(protocol.Rerank, d); err == nil {
+			t.Errorf("case %d: expected error", i)
+		}
+	}
+}
+
+func TestOllama_RerankTEI(t *testing.T) {
+	provider, err := providers.NewOllama(&config.ProviderConfig{
+		Name:    "ollama",
+		BaseURL: "http://localhost:8080",
+		Options: map[string]any{"rerank_format": "tei"},
+	})
+	if err != nil {
+		t.Fatalf("NewOllama failed: %v", err)
+	}
+
+	endpoint, err := provider.Endpoint(protocol.Rerank)
+	if err != nil {
+		t.Fatalf("Endpoint failed: %v", err)
+	}
+	if endpoint != "http://localhost:8080/rerank" {
+		t.Errorf("got endpoint %q, want root /rerank path", endpoint)
+	}
+
+	body, err := provider.Marshal(protocol.Rerank, &providers.RerankData{
+		Model:           "bge-reranker-base",
+		Query:           "capital of France",
+		Documents:       []string{"Paris", "Berlin"},
+		TopN:            1,
+		ReturnDocuments: true,
+	})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal(body, &result); err != nil {
+		t.Fatalf("Failed to unmarshal result: %v", err)
+	}
+
+	if texts, _ := result["texts"].([]any); len(texts) != 2 {
+		t.Errorf("got texts %v, want 2", result["texts"])
+	}
+	if result["return_text"] != true {
+		t.Errorf("got return_text %v, want true", result["return_text"])
+	}
+	for _, key := range []string{"model", "documents", "top_n"} {
+		if _, ok := result[key]; ok {
+			t.Errorf("unexpected %s in TEI body", key)
+		}
+	}
+}
+
+func TestOllama_RerankFormatInvalid(t *testing.T) {
+	_, err := providers.NewOllama(&config.ProviderConfig{
+		Name:    "ollama",
+		BaseURL: "http://localhost:11434",
+		Options: map[string]any{"rerank_format": "jina"},
+	})
+	if err == nil {
+		t.Error("expected error for unknown rerank_format")
+	}
+}
+
+func TestAzure_RerankUnsupported(t *testing.T) {
+	provider, err := providers.NewAzure(&config.ProviderConfig{
+		Name:    "azure",
+		BaseURL: "https://my-resource.openai.azure.com",
+		Options: map[string]any{
+			"deployment":  "gpt-4-deployment",
+			"auth_type":   "api_key",
+			"token":       "test-key",
+			"api_version": "2024-02-01",
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewAzure failed: %v", err)
+	}
+
+	if _, err := provider.Endpoint(protocol.Rerank); err == nil {
+		t.Error("expected rerank to be unsupported by Azure")
+	}
+}
@@ -0,0 +1,209 @@
+package providers_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/go-agents/pkg/config"
+	"github.com/JaimeStill/go-agents/pkg/protocol"
+	"github.com/JaimeStill/go-agents/pkg/providers"
+)
+
+func weatherTool(strict bool) providers.ToolDefinition {
+	params := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"location": map[string]any{"type": "string"},
+		},
+		"required": []string{"location"},
+	}
+	if strict {
+		params["additionalProperties"] = false
+	}
+	return providers.ToolDefinition{
+		Name:        "get_weather",
+		Description: "Get weather for a location",
+		Parameters:  params,
+		Strict:      strict,
+	}
+}
+
+func toolsData(choice *providers.ToolChoice, parallel *bool, tools ...providers.ToolDefinition) *providers.ToolsData {
+	return &providers.ToolsData{
+		Model:             "gpt-4o",
+		Messages:          []protocol.Message{protocol.NewMessage("user", "Weather in Boston?")},
+		Tools:             tools,
+		ToolChoice:        choice,
+		ParallelToolCalls: parallel,
+	}
+}
+
+func TestParseToolChoice(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   any
+		want    *providers.ToolChoice
+		wantErr bool
+	}{
+		{name: "nil", value: nil, want: nil},
+		{name: "auto", value: "auto", want: &providers.ToolChoice{Mode: providers.ToolChoiceAuto}},
+		{name: "required", value: "required", want: &providers.ToolChoice{Mode: providers.ToolChoiceRequired}},
+		{name: "typed", value: providers.ToolChoiceFor("get_weather"), want: providers.ToolChoiceFor("get_weather")},
+		{
+			name:  "object",
+			value: map[string]any{"type": "function", "function": map[string]any{"name": "get_weather"}},
+			want:  providers.ToolChoiceFor("get_weather"),
+		},
+		{name: "unknown mode", value: "sometimes", wantErr: true},
+		{name: "function without name", value: "function", wantErr: true},
+		{name: "object without name", value: map[string]any{"type": "function"}, wantErr: true},
+		{name: "wrong type", value: 42, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := providers.ParseToolChoice(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseToolChoice failed: %v", err)
+			}
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBaseProvider_Marshal_ToolControls(t *testing.T) {
+	provider := providers.NewBaseProvider("test", "https://api.test.com")
+
+	parallel := false
+	body, err := provider.Marshal(protocol.Tools, toolsData(providers.ToolChoiceFor("get_weather"), &parallel, weatherTool(true)))
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var result struct {
+		ToolChoice        map[string]any `json:"tool_choice"`
+		ParallelToolCalls *bool          `json:"parallel_tool_calls"`
+		Tools             []struct {
+			Function map[string]any `json:"function"`
+		} `json:"tools"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		t.Fatalf("Failed to unmarshal result: %v", err)
+	}
+
+	if result.ToolChoice["type"] != "function" || result.ToolChoice["function"].(map[string]any)["name"] != "get_weather" {
+		t.Errorf("got tool_choice %v", result.ToolChoice)
+	}
+	if result.ParallelToolCalls == nil || *result.ParallelToolCalls {
+		t.Errorf("got parallel_tool_calls %v, want false", result.ParallelToolCalls)
+	}
+	if result.Tools[0].Function["strict"] != true {
+		t.Errorf("got function %v, want strict", result.Tools[0].Function)
+	}
+
+	body, err = provider.Marshal(protocol.Tools, toolsData(&providers.ToolChoice{Mode: providers.ToolChoiceNone}, nil, weatherTool(false)))
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !strings.Contains(string(body), `"tool_choice":"none"`) || strings.Contains(string(body), "parallel_tool_calls") || strings.Contains(string(body), "strict") {
+		t.Errorf("got body %s", body)
+	}
+}
+
+func TestBaseProvider_Marshal_ToolControlErrors(t *testing.T) {
+	provider := providers.NewBaseProvider("test", "https://api.test.com")
+
+	loose := weatherTool(false)
+	loose.Strict = true
+
+	tests := []struct {
+		name string
+		data *providers.ToolsData
+		want string
+	}{
+		{"unknown function", toolsData(providers.ToolChoiceFor("get_time"), nil, weatherTool(false)), "not one of the request's tools"},
+		{"required without tools", toolsData(&providers.ToolChoice{Mode: providers.ToolChoiceRequired}, nil), "requires at least one tool"},
+		{"strict without closed schema", toolsData(nil, nil, loose), "additionalProperties"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := provider.Marshal(protocol.Tools, tt.data)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAzure_Marshal_StrictToolsVersion(t *testing.T) {
+	newProvider := func(version string) providers.Provider {
+		p, err := providers.NewAzure(&config.ProviderConfig{
+			Name:    "azure",
+			BaseURL: "https://my-resource.openai.azure.com",
+			Options: map[string]any{
+				"deployment":  "gpt-4o",
+				"auth_type":   "api_key",
+				"token":       "test-key",
+				"api_version": version,
+			},
+		})
+		if err != nil {
+			t.Fatalf("NewAzure failed: %v", err)
+		}
+		return p
+	}
+
+	if _, err := newProvider("2024-02-01").Marshal(protocol.Tools, toolsData(nil, nil, weatherTool(true))); err == nil {
+		t.Error("expected error for strict tools on an old api_version")
+	}
+	if _, err := newProvider("2024-02-01").Marshal(protocol.Tools, toolsData(nil, nil, weatherTool(false))); err != nil {
+		t.Errorf("non-strict tools failed on old api_version: %v", err)
+	}
+	if _, err := newProvider("2024-10-21").Marshal(protocol.Tools, toolsData(nil, nil, weatherTool(true))); err != nil {
+		t.Errorf("strict tools failed on 2024-10-21: %v", err)
+	}
+}
+
+func TestOllama_Marshal_ToolControls(t *testing.T) {
+	provider, err := providers.NewOllama(&config.ProviderConfig{
+		Name:    "ollama",
+		BaseURL: "http://localhost:11434",
+	})
+	if err != nil {
+		t.Fatalf("NewOllama failed: %v", err)
+	}
+
+	enabled, disabled := true, false
+
+	if _, err := provider.Marshal(protocol.Tools, toolsData(&providers.ToolChoice{Mode: providers.ToolChoiceAuto}, &enabled, weatherTool(false))); err != nil {
+		t.Errorf("auto tool choice failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		data *providers.ToolsData
+	}{
+		{"required", toolsData(&providers.ToolChoice{Mode: providers.ToolChoiceRequired}, nil, weatherTool(false))},
+		{"function", toolsData(providers.ToolChoiceFor("get_weather"), nil, weatherTool(false))},
+		{"parallel disabled", toolsData(nil, &disabled, weatherTool(false))},
+		{"strict", toolsData(nil, nil, weatherTool(true))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := provider.Marshal(protocol.Tools, tt.data); err == nil || !strings.Contains(err.Error(), "Ollama") {
+				t.Errorf("got error %v, want unsupported by Ollama", err)
+			}
+		})
+	}
+}
@@ -1,9 +1,13 @@
 package response_test
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"math"
+	"strings"
 	"testing"
 
+	"github.com/JaimeStill/go-agents/pkg/protocol"
 	"github.com/JaimeStill/go-agents/pkg/response"
 )
 
@@ -293,3 +297,616 @@ func TestParseChat_InvalidJSON(t *testing.T) {
 		t.Error("expected error for invalid JSON, got nil")
 	}
 }
+
+func TestToolsResponse_RoundTripMessage(t *testing.T) {
+	body := []byte(`{
+		"model": "gpt-4o",
+		"choices": [{
+			"index": 0,
+			"message": {
+				"role": "assistant",
+				"content": null,
+				"tool_calls": [{
+					"id": "call_1",
+					"type": "function",
+					"function": {"name": "get_weather", "arguments": "{}"}
+				}]
+			},
+			"finish_reason": "tool_calls"
+		}]
+	}`)
+
+	resp, err := response.ParseTools(body)
+	if err != nil {
+		t.Fatalf("ParseTools failed: %v", err)
+	}
+
+	if got := resp.ToolCalls(); len(got) != 1 || got[0].ID != "call_1" {
+		t.Fatalf("got tool calls %+v", got)
+	}
+	if resp.Content() != "" {
+		t.Errorf("got content %q, want empty", resp.Content())
+	}
+
+	data, err := json.Marshal(resp.Message())
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	want := `{"role":"assistant","content":null,"tool_calls":[{"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{}"}}]}`
+	if string(data) != want {
+		t.Errorf("got %s, want %s", data, want)
+	}
+}
+
+func TestParseImageGeneration(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n")
+	jsonData := `{
+		"created": 1700000000,
+		"data": [
+			{"url": "https://images.test/1.png", "revised_prompt": "A red lighthouse"},
+			{"b64_json": "` + base64.StdEncoding.EncodeToString(png) + `"}
+		],
+		"usage": {"input_tokens": 12, "output_tokens": 4160, "total_tokens": 4172}
+	}`
+
+	resp, err := response.ParseImageGeneration([]byte(jsonData))
+	if err != nil {
+		t.Fatalf("ParseImageGeneration failed: %v", err)
+	}
+
+	if len(resp.Data) != 2 {
+		t.Fatalf("got %d images, want 2", len(resp.Data))
+	}
+
+	if resp.Data[0].Source() != "https://images.test/1.png" {
+		t.Errorf("got source %q, want the image URL", resp.Data[0].Source())
+	}
+	if resp.Data[0].RevisedPrompt != "A red lighthouse" {
+		t.Errorf("got revised prompt %q", resp.Data[0].RevisedPrompt)
+	}
+	if _, err := resp.Data[0].Bytes(); err == nil {
+		t.Error("expected error decoding a URL image")
+	}
+
+	data, err := resp.Data[1].Bytes()
+	if err != nil {
+		t.Fatalf("Bytes failed: %v", err)
+	}
+	if string(data) != string(png) {
+		t.Error("decoded image does not match")
+	}
+	if !strings.HasPrefix(resp.Data[1].Source(), "data:image/png;base64,") {
+		t.Errorf("got source %q, want a PNG data URI", resp.Data[1].Source())
+	}
+
+	if resp.Usage == nil {
+		t.Fatal("expected usage")
+	}
+	if resp.Usage.PromptTokens != 12 || resp.Usage.CompletionTokens != 4160 || resp.Usage.TotalTokens != 4172 {
+		t.Errorf("got usage %+v", *resp.Usage)
+	}
+}
+
+func TestParseTranscription(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		text     string
+		segments int
+		words    int
+		tokens   int
+	}{
+		{
+			name:   "json",
+			body:   `{"text": "Hello there.", "usage": {"type": "tokens", "input_tokens": 12, "output_tokens": 3, "total_tokens": 15}}`,
+			text:   "Hello there.",
+			tokens: 15,
+		},
+		{
+			name: "duration usage",
+			body: `{"text": "Hello there.", "usage": {"type": "duration", "seconds": 2}}`,
+			text: "Hello there.",
+		},
+		{
+			name: "verbose_json",
+			body: `{
+				"task": "transcribe",
+				"language": "english",
+				"duration": 0.5,
+				"text": "Hello there.",
+				"segments": [{"id": 0, "start": 0, "end": 0.5, "text": "Hello there."}],
+				"words": [{"word": "Hello", "start": 0, "end": 0.25}, {"word": "there", "start": 0.25, "end": 0.5}]
+			}`,
+			text:     "Hello there.",
+			segments: 1,
+			words:    2,
+		},
+		{
+			name: "text",
+			body: "Hello there.\n",
+			text: "Hello there.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := response.ParseTranscription([]byte(tt.body))
+			if err != nil {
+				t.Fatalf("ParseTranscription failed: %v", err)
+			}
+
+			if result.Text != tt.text {
+				t.Errorf("got text %q, want %q", result.Text, tt.text)
+			}
+			if len(result.Segments) != tt.segments || len(result.Words) != tt.words {
+				t.Errorf("got %d segments and %d words, want %d and %d", len(result.Segments), len(result.Words), tt.segments, tt.words)
+			}
+
+			tokens := 0
+			if result.Usage != nil {
+				tokens = result.Usage.TotalTokens
+			}
+			if tokens != tt.tokens {
+				t.Errorf("got %d total tokens, want %d", tokens, tt.tokens)
+			}
+		})
+	}
+}
+
+func TestParseSpeech(t *testing.T) {
+	result, err := response.ParseSpeech([]byte("RIFF\x24\x00\x00\x00WAVEfmt "))
+	if err != nil {
+		t.Fatalf("ParseSpeech failed: %v", err)
+	}
+	if result.ContentType != "audio/wave" {
+		t.Errorf("got content type %q, want audio/wave", result.ContentType)
+	}
+
+	if _, err := response.ParseSpeech(nil); err == nil {
+		t.Error("expected error for empty audio")
+	}
+}
+
+func TestParseRerank(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		indices  []int
+		document string
+		tokens   int
+	}{
+		{
+			name: "cohere",
+			body: `{
+				"id": "rr-1",
+				"results": [
+					{"index": 2, "relevance_score": 0.91},
+					{"index": 0, "relevance_score": 0.35}
+				],
+				"meta": {"billed_units": {"search_units": 1}}
+			}`,
+			indices: []int{2, 0},
+		},
+		{
+			name: "jina",
+			body: `{
+				"model": "jina-reranker-v2",
+				"usage": {"total_tokens": 42},
+				"results": [
+					{"index": 1, "relevance_score": 0.8, "document": {"text": "Paris is the capital."}},
+					{"index": 0, "relevance_score": 0.1, "document": {"text": "Berlin."}}
+				]
+			}`,
+			indices:  []int{1, 0},
+			document: "Paris is the capital.",
+			tokens:   42,
+		},
+		{
+			name:     "tei",
+			body:     `[{"index": 0, "score": 0.2, "text": "Berlin."}, {"index": 1, "score": 0.7, "text": "Paris."}]`,
+			indices:  []int{1, 0},
+			document: "Paris.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := response.ParseRerank([]byte(tt.body))
+			if err != nil {
+				t.Fatalf("ParseRerank failed: %v", err)
+			}
+
+			indices := result.Indices()
+			if len(indices) != len(tt.indices) {
+				t.Fatalf("got indices %v, want %v", indices, tt.indices)
+			}
+			for i := range indices {
+				if indices[i] != tt.indices[i] {
+					t.Errorf("got indices %v, want %v", indices, tt.indices)
+					break
+				}
+			}
+
+			if got := result.Results[0].Document; got != tt.document {
+				t.Errorf("got document %q, want %q", got, tt.document)
+			}
+			if result.Results[0].RelevanceScore < result.Results[1].RelevanceScore {
+				t.Error("results not ordered by descending relevance")
+			}
+
+			tokens := 0
+			if result.Usage != nil {
+				tokens = result.Usage.TotalTokens
+			}
+			if tokens != tt.tokens {
+				t.Errorf("got %d total tokens, want %d", tokens, tt.tokens)
+			}
+		})
+	}
+}
+
+func TestParseModeration(t *testing.T) {
+	body := `{
+		"id": "modr-1",
+		"model": "omni-moderation-latest",
+		"results": [
+			{
+				"flagged": true,
+				"categories": {"violence": true, "hate": false},
+				"category_scores": {"violence": 0.92, "hate": 0.01}
+			},
+			{"flagged": false, "categories": {"violence": false}}
+		]
+	}`
+
+	result, err := response.ParseModeration([]byte(body))
+	if err != nil {
+		t.Fatalf("ParseModeration failed: %v", err)
+	}
+
+	if !result.Flagged() || len(result.Results) != 2 {
+		t.Fatalf("got %+v, want two results with one flagged", result)
+	}
+	if got := result.Results[0].FlaggedCategories(); len(got) != 1 || got[0] != "violence" {
+		t.Errorf("got flagged categories %v, want [violence]", got)
+	}
+	if got := result.Results[0].CategoryScores["violence"]; got != 0.92 {
+		t.Errorf("got violence score %v, want 0.92", got)
+	}
+
+	if _, err := response.ParseModeration([]byte("not json")); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestParseChat_ContentFilter(t *testing.T) {
+	body := `{
+		"model": "gpt-4o",
+		"prompt_filter_results": [{
+			"prompt_index": 0,
+			"content_filter_results": {
+				"hate": {"filtered": false, "severity": "safe"},
+				"jailbreak": {"filtered": false, "detected": true}
+			}
+		}],
+		"choices": [{
+			"index": 0,
+			"message": {"role": "assistant", "content": ""},
+			"finish_reason": "content_filter",
+			"content_filter_results": {
+				"violence": {"filtered": true, "severity": "high"},
+				"sexual": {"filtered": false, "severity": "safe"},
+				"error": {"code": "partial", "message": "unavailable"}
+			}
+		}]
+	}`
+
+	result, err := response.ParseChat([]byte(body))
+	if err != nil {
+		t.Fatalf("ParseChat failed: %v", err)
+	}
+
+	if len(result.PromptFilterResults) != 1 {
+		t.Fatalf("got %d prompt filter results, want 1", len(result.PromptFilterResults))
+	}
+	prompt := result.PromptFilterResults[0].ContentFilter
+	if !prompt.Flagged || prompt.Filtered || !prompt.Categories["jailbreak"] || prompt.Severities["hate"] != "safe" {
+		t.Errorf("got prompt filter %+v, want jailbreak detected but not filtered", prompt)
+	}
+
+	if len(result.ChoiceFilterResults) != 1 {
+		t.Fatalf("got %d choice filter results, want 1", len(result.ChoiceFilterResults))
+	}
+	choice := result.ChoiceFilterResults[0].ContentFilter
+	if !choice.Filtered || choice.Severities["violence"] != "high" {
+		t.Errorf("got choice filter %+v, want violence filtered at high severity", choice)
+	}
+	if got := choice.FlaggedCategories(); len(got) != 1 || got[0] != "violence" {
+		t.Errorf("got flagged categories %v, want [violence]", got)
+	}
+	if _, ok := choice.Categories["error"]; ok {
+		t.Error("error entry parsed as a category")
+	}
+
+	tools, err := response.ParseTools([]byte(body))
+	if err != nil {
+		t.Fatalf("ParseTools failed: %v", err)
+	}
+	if len(tools.ChoiceFilterResults) != 1 || !tools.ChoiceFilterResults[0].ContentFilter.Filtered {
+		t.Errorf("got tools choice filters %+v", tools.ChoiceFilterResults)
+	}
+}
+
+func TestParseChat_Logprobs(t *testing.T) {
+	body := `{
+		"model": "gpt-4o",
+		"system_fingerprint": "fp_123",
+		"choices": [
+			{
+				"index": 0,
+				"message": {"role": "assistant", "content": "yes"},
+				"logprobs": {"content": [{
+					"token": "yes",
+					"logprob": -0.1,
+					"bytes": [121, 101, 115],
+					"top_logprobs": [
+						{"token": "yes", "logprob": -0.1},
+						{"token": "no", "logprob": -2.5}
+					]
+				}]}
+			},
+			{
+				"index": 1,
+				"message": {"role": "assistant", "content": "no"},
+				"logprobs": {"content": [{"token": "no", "logprob": -2.5}]}
+			},
+			{
+				"index": 2,
+				"message": {"role": "assistant", "content": " yes "}
+			}
+		]
+	}`
+
+	result, err := response.ParseChat([]byte(body))
+	if err != nil {
+		t.Fatalf("ParseChat failed: %v", err)
+	}
+
+	if result.SystemFingerprint != "fp_123" {
+		t.Errorf("got system fingerprint %q, want fp_123", result.SystemFingerprint)
+	}
+
+	lp := result.Choices[0].Logprobs
+	if lp == nil || len(lp.Content) != 1 || len(lp.Content[0].TopLogprobs) != 2 {
+		t.Fatalf("got logprobs %+v, want one token with two alternatives", lp)
+	}
+	if got := lp.Content[0].Probability(); math.Abs(got-math.Exp(-0.1)) > 1e-9 {
+		t.Errorf("got probability %v, want %v", got, math.Exp(-0.1))
+	}
+
+	if got := result.Contents(); len(got) != 3 || got[1] != "no" {
+		t.Errorf("got contents %v", got)
+	}
+	if c := result.Choice(1); c == nil || c.Content() != "no" {
+		t.Errorf("got choice 1 %+v, want content no", c)
+	}
+	if c := result.Choice(5); c != nil {
+		t.Errorf("got choice 5 %+v, want nil", c)
+	}
+	if best := result.Best(); best == nil || best.Index != 0 {
+		t.Errorf("got best %+v, want choice 0", best)
+	}
+
+	answer, agreement := result.Vote()
+	if answer != "yes" || math.Abs(agreement-2.0/3.0) > 1e-9 {
+		t.Errorf("got vote %q at %v, want yes at 2/3", answer, agreement)
+	}
+}
+
+func TestChatResponse_Vote(t *testing.T) {
+	choices := func(contents ...string) *response.ChatResponse {
+		r := &response.ChatResponse{}
+		for i, content := range contents {
+			r.Choices = append(r.Choices, response.ChatChoice{
+				Index:   i,
+				Message: protocol.NewMessage("assistant", content),
+			})
+		}
+		return r
+	}
+
+	tests := []struct {
+		name      string
+		resp      *response.ChatResponse
+		want      string
+		agreement float64
+	}{
+		{name: "majority", resp: choices("A", "B", "B"), want: "B", agreement: 2.0 / 3.0},
+		{name: "tie goes to first", resp: choices("A", "B", "B", "A"), want: "A", agreement: 0.5},
+		{name: "tie after later majority", resp: choices("B", "A", "A", "B", "C"), want: "B", agreement: 0.4},
+		{name: "whitespace ignored", resp: choices(" A", "A\n", "B"), want: "A", agreement: 2.0 / 3.0},
+		{name: "empty", resp: choices(), want: "", agreement: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, agreement := tt.resp.Vote()
+			if got != tt.want || math.Abs(agreement-tt.agreement) > 1e-9 {
+				t.Errorf("got %q at %v, want %q at %v", got, agreement, tt.want, tt.agreement)
+			}
+		})
+	}
+}
+
+func TestLogprobs_Metrics(t *testing.T) {
+	lp := &response.Logprobs{Content: []response.TokenLogprob{
+		{Token: "Hello", Logprob: -0.2},
+		{Token: " world", Logprob: -0.4},
+	}}
+
+	if got := lp.Sum(); math.Abs(got+0.6) > 1e-9 {
+		t.Errorf("got sum %v, want -0.6", got)
+	}
+	if got := lp.Mean(); math.Abs(got+0.3) > 1e-9 {
+		t.Errorf("got mean %v, want -0.3", got)
+	}
+	if got := lp.Perplexity(); math.Abs(got-math.Exp(0.3)) > 1e-9 {
+		t.Errorf("got perplexity %v, want %v", got, math.Exp(0.3))
+	}
+	if got := lp.Text(); got != "Hello world" {
+		t.Errorf("got text %q, want %q", got, "Hello world")
+	}
+
+	empty := &response.Logprobs{}
+	if empty.Mean() != 0 || empty.Perplexity() != 1 {
+		t.Errorf("got mean %v and perplexity %v for no tokens, want 0 and 1", empty.Mean(), empty.Perplexity())
+	}
+}
+
+func TestConfidence(t *testing.T) {
+	lp := &response.Logprobs{Content: []response.TokenLogprob{
+		{Token: "Label", Logprob: -0.01},
+		{Token: ":", Logprob: -0.01},
+		{
+			Token:   " pos",
+			Logprob: math.Log(0.6),
+			TopLogprobs: []response.TopLogprob{
+				{Token: " pos", Logprob: math.Log(0.6)},
+				{Token: " neg", Logprob: math.Log(0.2)},
+				{Token: " n", Logprob: math.Log(0.1)},
+				{Token: " maybe", Logprob: math.Log(0.1)},
+			},
+		},
+	}}
+
+	tests := []struct {
+		name     string
+		answers  []string
+		answer   string
+		scores   map[string]float64
+		coverage float64
+	}{
+		{
+			name:     "distinct answers",
+			answers:  []string{"positive", "negative"},
+			answer:   "positive",
+			scores:   map[string]float64{"positive": 0.6 / 0.9, "negative": 0.3 / 0.9},
+			coverage: 0.9,
+		},
+		{
+			name:     "ambiguous prefix splits mass",
+			answers:  []string{"positive", "negative", "neutral"},
+			answer:   "positive",
+			scores:   map[string]float64{"positive": 0.6 / 0.9, "negative": 0.25 / 0.9, "neutral": 0.05 / 0.9},
+			coverage: 0.9,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := response.Confidence(lp, tt.answers...)
+			if err != nil {
+				t.Fatalf("Confidence failed: %v", err)
+			}
+
+			if result.Answer != tt.answer {
+				t.Errorf("got answer %q, want %q", result.Answer, tt.answer)
+			}
+			if math.Abs(result.Coverage-tt.coverage) > 1e-9 {
+				t.Errorf("got coverage %v, want %v", result.Coverage, tt.coverage)
+			}
+			for answer, want := range tt.scores {
+				if got := result.Scores[answer]; math.Abs(got-want) > 1e-9 {
+					t.Errorf("got score %v for %q, want %v", got, answer, want)
+				}
+			}
+			if result.Confidence != result.Scores[tt.answer] {
+				t.Errorf("got confidence %v, want score of %q", result.Confidence, tt.answer)
+			}
+		})
+	}
+}
+
+func TestConfidence_Errors(t *testing.T) {
+	lp := &response.Logprobs{Content: []response.TokenLogprob{{Token: "unrelated", Logprob: -0.1}}}
+
+	if _, err := response.Confidence(lp); err == nil {
+		t.Error("expected error for no answers")
+	}
+	if _, err := response.Confidence(nil, "yes"); err == nil {
+		t.Error("expected error for nil logprobs")
+	}
+	if _, err := response.Confidence(lp, "yes", "no"); err == nil {
+		t.Error("expected error when no token begins an answer")
+	}
+}
+
+func TestStreamingChunk_Logprobs(t *testing.T) {
+	data := `{
+		"model": "gpt-4o",
+		"system_fingerprint": "fp_123",
+		"choices": [{
+			"index": 0,
+			"delta": {"content": "Hi"},
+			"logprobs": {"content": [{"token": "Hi", "logprob": -0.05}]}
+		}]
+	}`
+
+	chunk, err := response.ParseChatStreamChunk([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseChatStreamChunk failed: %v", err)
+	}
+
+	lp := chunk.Logprobs()
+	if lp == nil || lp.Text() != "Hi" || chunk.SystemFingerprint != "fp_123" {
+		t.Errorf("got logprobs %+v and fingerprint %q", lp, chunk.SystemFingerprint)
+	}
+
+	empty := &response.StreamingChunk{}
+	if empty.Logprobs() != nil {
+		t.Error("expected nil logprobs for chunk without choices")
+	}
+}
+
+func TestReasoning_UsageAndDeltas(t *testing.T) {
+	body := `{
+		"model": "o4-mini",
+		"choices": [{
+			"index": 0,
+			"message": {"role": "assistant", "content": "42", "reasoning_content": "Six times seven."}
+		}],
+		"usage": {
+			"prompt_tokens": 10,
+			"completion_tokens": 30,
+			"total_tokens": 40,
+			"completion_tokens_details": {"reasoning_tokens": 28}
+		}
+	}`
+
+	result, err := response.ParseChat([]byte(body))
+	if err != nil {
+		t.Fatalf("ParseChat failed: %v", err)
+	}
+	if got := result.Usage.ReasoningTokens(); got != 28 {
+		t.Errorf("got %d reasoning tokens, want 28", got)
+	}
+	if got := result.Choices[0].Message.Reasoning; got != "Six times seven." {
+		t.Errorf("got reasoning %q", got)
+	}
+
+	var usage *response.TokenUsage
+	if usage.ReasoningTokens() != 0 {
+		t.Error("expected 0 reasoning tokens for nil usage")
+	}
+
+	for _, key := range []string{"reasoning_content", "reasoning"} {
+		chunk, err := response.ParseChatStreamChunk([]byte(`{"choices": [{"index": 0, "delta": {"` + key + `": "Thinking"}}]}`))
+		if err != nil {
+			t.Fatalf("ParseChatStreamChunk failed: %v", err)
+		}
+		if chunk.Reasoning() != "Thinking" || chunk.Content() != "" {
+			t.Errorf("%s: got reasoning %q and content %q", key, chunk.Reasoning(), chunk.Content())
+		}
+	}
+}
@@ -0,0 +1,311 @@
+package tokenizer_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/go-agents/pkg/protocol"
+	"github.com/JaimeStill/go-agents/pkg/tokenizer"
+)
+
+// newTestBPE builds a BPE over all single bytes plus a few merges.
+func newTestBPE(t *testing.T) *tokenizer.BPE {
+	t.Helper()
+
+	ranks := make(map[string]int)
+	for i := range 256 {
+		ranks[string([]byte{byte(i)})] = i
+	}
+	ranks["he"] = 256
+	ranks["ll"] = 257
+	ranks["hell"] = 258
+	ranks["hello"] = 259
+	ranks[" w"] = 260
+
+	pattern, ok := tokenizer.Pattern(tokenizer.Cl100kBase)
+	if !ok {
+		t.Fatal("missing cl100k pattern")
+	}
+
+	bpe, err := tokenizer.NewBPE("test", ranks, pattern)
+	if err != nil {
+		t.Fatalf("NewBPE failed: %v", err)
+	}
+	return bpe
+}
+
+func TestBPE_Encode(t *testing.T) {
+	bpe := newTestBPE(t)
+
+	tests := []struct {
+		name string
+		text string
+		want []int
+	}{
+		{name: "whole word", text: "hello", want: []int{259}},
+		{name: "merged prefix", text: "hell", want: []int{258}},
+		{name: "partial merge", text: "help", want: []int{256, 'l', 'p'}},
+		{name: "leading space", text: " world", want: []int{260, 'o', 'r', 'l', 'd'}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := bpe.Encode(tt.text)
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("got tokens %v, want %v", got, tt.want)
+			}
+			if bpe.Count(tt.text) != len(tt.want) {
+				t.Errorf("got count %d, want %d", bpe.Count(tt.text), len(tt.want))
+			}
+		})
+	}
+}
+
+func TestBPE_RoundTrip(t *testing.T) {
+	bpe := newTestBPE(t)
+	text := "hello world, it's 2024!\n\nNew paragraph with ünïcödé."
+
+	if got := bpe.Decode(bpe.Encode(text)); got != text {
+		t.Errorf("round trip mismatch: got %q, want %q", got, text)
+	}
+}
+
+func TestBPE_Truncate(t *testing.T) {
+	bpe := newTestBPE(t)
+
+	if got := bpe.Truncate("hello world", 100); got != "hello world" {
+		t.Errorf("got %q, want unchanged text", got)
+	}
+
+	if got := bpe.Truncate("hello world", 2); got != "hello w" {
+		t.Errorf("got %q, want %q", got, "hello w")
+	}
+
+	if got := bpe.Truncate("hello", 0); got != "" {
+		t.Errorf("got %q, want empty string", got)
+	}
+
+	// Multi-byte characters are encoded byte by byte in the test vocabulary,
+	// so truncating mid-character must not produce invalid UTF-8.
+	if got := bpe.Truncate("é", 1); got != "" {
+		t.Errorf("got %q, want empty string for split character", got)
+	}
+}
+
+func TestLoadRanks(t *testing.T) {
+	encode := func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }
+	data := fmt.Sprintf("%s 0\n%s 1\n\n%s 2\n", encode("a"), encode("b"), encode("ab"))
+
+	ranks, err := tokenizer.LoadRanks(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("LoadRanks failed: %v", err)
+	}
+
+	if len(ranks) != 3 || ranks["ab"] != 2 {
+		t.Errorf("got ranks %v", ranks)
+	}
+
+	invalid := []string{"no-rank-here", encode("a") + " x", "!!! 1"}
+	for _, line := range invalid {
+		if _, err := tokenizer.LoadRanks(strings.NewReader(line)); err == nil {
+			t.Errorf("expected error for line %q", line)
+		}
+	}
+}
+
+func TestHeuristic(t *testing.T) {
+	h := tokenizer.NewHeuristic(4)
+
+	if h.Name() != tokenizer.HeuristicName {
+		t.Errorf("got name %q, want %q", h.Name(), tokenizer.HeuristicName)
+	}
+
+	if got := h.Count("12345678"); got != 2 {
+		t.Errorf("got count %d, want 2", got)
+	}
+
+	if got := h.Count("123456789"); got != 3 {
+		t.Errorf("got count %d, want 3 (rounded up)", got)
+	}
+
+	if got := h.Truncate("123456789", 2); got != "12345678" {
+		t.Errorf("got %q, want %q", got, "12345678")
+	}
+
+	if got := tokenizer.NewHeuristic(0).Count("123456"); got != 2 {
+		t.Errorf("got count %d with default ratio, want 2", got)
+	}
+}
+
+func TestEncodingForModel(t *testing.T) {
+	tests := []struct {
+		model string
+		want  string
+	}{
+		{"gpt-4o", tokenizer.O200kBase},
+		{"gpt-4o-mini", tokenizer.O200kBase},
+		{"o4-mini", tokenizer.O200kBase},
+		{"gpt-5-mini", tokenizer.O200kBase},
+		{"gpt-4", tokenizer.Cl100kBase},
+		{"gpt-35-turbo", tokenizer.Cl100kBase},
+		{"text-embedding-3-small", tokenizer.Cl100kBase},
+		{"llama3.2:3b", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			if got := tokenizer.EncodingForModel(tt.model); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestForModel(t *testing.T) {
+	if got := tokenizer.ForModel("llama3.2:3b", ""); got.Name() != tokenizer.HeuristicName {
+		t.Errorf("got %q for unknown model, want heuristic", got.Name())
+	}
+
+	custom := newTestBPE(t)
+	tokenizer.Register(custom)
+
+	if got := tokenizer.ForModel("gpt-4o", "test"); got.Name() != "test" {
+		t.Errorf("got %q, want registered encoding override", got.Name())
+	}
+
+	if _, err := tokenizer.Get("unknown_encoding"); !errors.Is(err, tokenizer.ErrVocabularyUnavailable) {
+		t.Errorf("got error %v, want ErrVocabularyUnavailable", err)
+	}
+}
+
+func TestForModel_EmbeddedVocabulary(t *testing.T) {
+	tests := []struct {
+		model    string
+		encoding string
+	}{
+		{"gpt-4o", tokenizer.O200kBase},
+		{"gpt-4", tokenizer.Cl100kBase},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			got := tokenizer.ForModel(tt.model, "")
+			if _, ok := got.(*tokenizer.BPE); !ok {
+				t.Fatalf("got %T (%s), want *tokenizer.BPE", got, got.Name())
+			}
+			if got.Name() != tt.encoding {
+				t.Errorf("got encoding %q, want %q", got.Name(), tt.encoding)
+			}
+		})
+	}
+}
+
+func TestEmbeddedEncodings(t *testing.T) {
+	// Reference tokens produced by tiktoken for the same encodings.
+	tests := []struct {
+		encoding string
+		text     string
+		want     []int
+	}{
+		{tokenizer.Cl100kBase, "hello world", []int{15339, 1917}},
+		{tokenizer.Cl100kBase, "tiktoken is great!", []int{83, 1609, 5963, 374, 2294, 0}},
+		{tokenizer.Cl100kBase, "The quick brown fox jumps over the lazy dog.", []int{791, 4062, 14198, 39935, 35308, 927, 279, 16053, 5679, 13}},
+		{tokenizer.O200kBase, "hello world", []int{24912, 2375}},
+		{tokenizer.O200kBase, "tiktoken is great!", []int{83, 8251, 2488, 382, 2212, 0}},
+		{tokenizer.O200kBase, "Hello, world!", []int{13225, 11, 2375, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.encoding+"/"+tt.text, func(t *testing.T) {
+			tk, err := tokenizer.Get(tt.encoding)
+			if err != nil {
+				t.Fatalf("Get failed: %v", err)
+			}
+
+			bpe, ok := tk.(*tokenizer.BPE)
+			if !ok {
+				t.Fatalf("got %T, want *tokenizer.BPE", tk)
+			}
+
+			if got := bpe.Encode(tt.text); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("got tokens %v, want %v", got, tt.want)
+			}
+			if got := bpe.Count(tt.text); got != len(tt.want) {
+				t.Errorf("got count %d, want %d", got, len(tt.want))
+			}
+			if got := bpe.Decode(tt.want); got != tt.text {
+				t.Errorf("got decoded %q, want %q", got, tt.text)
+			}
+		})
+	}
+}
+
+func TestCountMessages(t *testing.T) {
+	h := tokenizer.NewHeuristic(1)
+
+	messages := []protocol.Message{
+		protocol.NewMessage("system", "abc"),
+		protocol.NewMessage("user", "de"),
+	}
+
+	// reply priming (3) + per-message framing (3 each) + role + content
+	want := 3 + (3 + 6 + 3) + (3 + 4 + 2)
+	if got := tokenizer.CountMessages(h, messages); got != want {
+		t.Errorf("got %d, want %d", got, want)
+	}
+}
+
+func TestImageTokens(t *testing.T) {
+	dataURI := func(w, h int) string {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, w, h))); err != nil {
+			t.Fatalf("failed to encode png: %v", err)
+		}
+		return "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())
+	}
+
+	tests := []struct {
+		name   string
+		image  string
+		detail string
+		want   int
+	}{
+		{name: "low detail", image: dataURI(4096, 4096), detail: "low", want: 85},
+		{name: "single tile", image: dataURI(512, 512), detail: "high", want: 85 + 170},
+		{name: "scaled square", image: dataURI(2048, 2048), detail: "high", want: 85 + 170*4},
+		{name: "portrait page", image: dataURI(1275, 1650), detail: "", want: 85 + 170*4},
+		{name: "remote url", image: "https://example.com/cat.png", detail: "auto", want: 85 + 170*4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tokenizer.ImageTokens(tt.image, tt.detail); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountMessages_ContentParts(t *testing.T) {
+	h := tokenizer.NewHeuristic(1)
+
+	messages := []protocol.Message{
+		protocol.NewContentMessage("user",
+			protocol.TextPart("abc"),
+			protocol.ImageURLPart("https://example.com/cat.png", "low"),
+			protocol.TextPart("de"),
+		),
+	}
+
+	// reply priming (3) + framing (3) + role (4) + text (3 + 2) + low-detail image (85)
+	want := 3 + 3 + 4 + 3 + 2 + 85
+	if got := tokenizer.CountMessages(h, messages); got != want {
+		t.Errorf("got %d, want %d", got, want)
+	}
+}
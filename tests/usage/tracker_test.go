@@ -0,0 +1,276 @@
+package usage_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/JaimeStill/go-agents/pkg/protocol"
+	"github.com/JaimeStill/go-agents/pkg/usage"
+)
+
+func TestPrice_Cost(t *testing.T) {
+	price := usage.Price{InputPer1K: 0.01, OutputPer1K: 0.03, PerImage: 0.002}
+
+	got := price.Cost(2000, 500, 3)
+	want := 0.02 + 0.015 + 0.006
+
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("got cost %f, want %f", got, want)
+	}
+}
+
+func TestPriceTable_Lookup(t *testing.T) {
+	table := usage.PriceTable{
+		"gpt-4o":              {InputPer1K: 0.0025},
+		usage.DefaultPriceKey: {InputPer1K: 0.001},
+	}
+
+	tests := []struct {
+		name  string
+		model string
+		want  float64
+	}{
+		{name: "explicit model", model: "gpt-4o", want: 0.0025},
+		{name: "default fallback", model: "unknown", want: 0.001},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, ok := table.Lookup(tt.model)
+			if !ok {
+				t.Fatal("expected price to be found")
+			}
+			if price.InputPer1K != tt.want {
+				t.Errorf("got input price %f, want %f", price.InputPer1K, tt.want)
+			}
+		})
+	}
+
+	empty := usage.PriceTable{}
+	if _, ok := empty.Lookup("gpt-4o"); ok {
+		t.Error("expected no price in empty table")
+	}
+}
+
+func TestLoadPriceTable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prices.json")
+	data := `{"gpt-4o": {"input_per_1k": 0.0025, "output_per_1k": 0.01, "per_image": 0.001}}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("failed to write price table: %v", err)
+	}
+
+	table, err := usage.LoadPriceTable(path)
+	if err != nil {
+		t.Fatalf("LoadPriceTable failed: %v", err)
+	}
+
+	if table["gpt-4o"].OutputPer1K != 0.01 {
+		t.Errorf("got output price %f, want 0.01", table["gpt-4o"].OutputPer1K)
+	}
+
+	if _, err := usage.LoadPriceTable(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestTracker_Record(t *testing.T) {
+	tracker := usage.NewTracker(
+		usage.WithPrices(usage.PriceTable{
+			"gpt-4o": {InputPer1K: 1, OutputPer1K: 2},
+		}),
+	)
+
+	record := tracker.Record(usage.Record{
+		AgentID:          "agent-1",
+		Model:            "gpt-4o",
+		Protocol:         protocol.Chat,
+		Tags:             []string{"doc:a", "stage:classify"},
+		PromptTokens:     1000,
+		CompletionTokens: 500,
+	})
+
+	if record.Cost != 2 {
+		t.Errorf("got cost %f, want 2", record.Cost)
+	}
+
+	if record.Timestamp.IsZero() {
+		t.Error("expected timestamp to be set")
+	}
+
+	tracker.Record(usage.Record{
+		AgentID:          "agent-2",
+		Model:            "llama3",
+		Protocol:         protocol.Vision,
+		Tags:             []string{"doc:a"},
+		PromptTokens:     100,
+		CompletionTokens: 10,
+		Images:           2,
+	})
+
+	snapshot := tracker.Snapshot()
+
+	if snapshot.Total.Requests != 2 {
+		t.Errorf("got %d requests, want 2", snapshot.Total.Requests)
+	}
+
+	if snapshot.Total.TotalTokens != 1610 {
+		t.Errorf("got %d total tokens, want 1610", snapshot.Total.TotalTokens)
+	}
+
+	if snapshot.Total.Cost != 2 {
+		t.Errorf("got total cost %f, want 2", snapshot.Total.Cost)
+	}
+
+	if snapshot.ByAgent["agent-1"].Requests != 1 {
+		t.Errorf("got %d agent-1 requests, want 1", snapshot.ByAgent["agent-1"].Requests)
+	}
+
+	if snapshot.ByModel["llama3"].Images != 2 {
+		t.Errorf("got %d llama3 images, want 2", snapshot.ByModel["llama3"].Images)
+	}
+
+	if snapshot.ByProtocol["vision"].PromptTokens != 100 {
+		t.Errorf("got %d vision prompt tokens, want 100", snapshot.ByProtocol["vision"].PromptTokens)
+	}
+
+	if snapshot.ByTag["doc:a"].Requests != 2 {
+		t.Errorf("got %d doc:a requests, want 2", snapshot.ByTag["doc:a"].Requests)
+	}
+
+	if snapshot.ByTag["stage:classify"].Requests != 1 {
+		t.Errorf("got %d stage:classify requests, want 1", snapshot.ByTag["stage:classify"].Requests)
+	}
+}
+
+func TestTracker_SnapshotIsCopy(t *testing.T) {
+	tracker := usage.NewTracker()
+	tracker.Record(usage.Record{AgentID: "a", Model: "m", PromptTokens: 1})
+
+	snapshot := tracker.Snapshot()
+	tracker.Record(usage.Record{AgentID: "a", Model: "m", PromptTokens: 1})
+
+	if snapshot.ByAgent["a"].Requests != 1 {
+		t.Errorf("snapshot mutated by later record: got %d requests", snapshot.ByAgent["a"].Requests)
+	}
+}
+
+func TestTracker_Check(t *testing.T) {
+	tests := []struct {
+		name   string
+		budget usage.Budget
+		record usage.Record
+	}{
+		{
+			name:   "cost cap",
+			budget: usage.Budget{MaxCost: 1},
+			record: usage.Record{Model: "m", PromptTokens: 1000},
+		},
+		{
+			name:   "token cap",
+			budget: usage.Budget{MaxTokens: 100},
+			record: usage.Record{Model: "m", PromptTokens: 60, CompletionTokens: 40},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := usage.NewTracker(
+				usage.WithPrices(usage.PriceTable{"m": {InputPer1K: 1}}),
+				usage.WithBudget(tt.budget),
+			)
+
+			if err := tracker.Check(); err != nil {
+				t.Fatalf("unexpected error before usage: %v", err)
+			}
+
+			tracker.Record(tt.record)
+
+			err := tracker.Check()
+			if !errors.Is(err, usage.ErrBudgetExceeded) {
+				t.Errorf("got error %v, want ErrBudgetExceeded", err)
+			}
+		})
+	}
+}
+
+func TestTracker_CheckWithoutBudget(t *testing.T) {
+	tracker := usage.NewTracker()
+	tracker.Record(usage.Record{PromptTokens: 1_000_000})
+
+	if err := tracker.Check(); err != nil {
+		t.Errorf("unexpected error without budget: %v", err)
+	}
+}
+
+func TestTracker_Export(t *testing.T) {
+	tracker := usage.NewTracker(usage.WithBudget(usage.Budget{MaxTokens: 10}))
+	tracker.Record(usage.Record{AgentID: "a", Model: "m", Protocol: protocol.Chat, PromptTokens: 3})
+
+	var buf bytes.Buffer
+	if err := tracker.Export(&buf); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+
+	var snapshot usage.Snapshot
+	if err := json.Unmarshal(buf.Bytes(), &snapshot); err != nil {
+		t.Fatalf("failed to parse export: %v", err)
+	}
+
+	if snapshot.Total.PromptTokens != 3 {
+		t.Errorf("got %d prompt tokens, want 3", snapshot.Total.PromptTokens)
+	}
+
+	if snapshot.Budget == nil || snapshot.Budget.MaxTokens != 10 {
+		t.Errorf("got budget %+v, want max_tokens 10", snapshot.Budget)
+	}
+}
+
+func TestTracker_Reset(t *testing.T) {
+	tracker := usage.NewTracker()
+	tracker.Record(usage.Record{AgentID: "a", PromptTokens: 5})
+	tracker.Reset()
+
+	snapshot := tracker.Snapshot()
+	if snapshot.Total.Requests != 0 || len(snapshot.ByAgent) != 0 {
+		t.Errorf("expected empty snapshot after reset, got %+v", snapshot)
+	}
+}
+
+func TestTracker_Concurrent(t *testing.T) {
+	tracker := usage.NewTracker()
+
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tracker.Record(usage.Record{AgentID: "a", PromptTokens: 1})
+		}()
+	}
+	wg.Wait()
+
+	if got := tracker.Snapshot().Total.Requests; got != 50 {
+		t.Errorf("got %d requests, want 50", got)
+	}
+}
+
+func TestTags(t *testing.T) {
+	ctx := usage.WithTags(context.Background(), "a")
+	ctx = usage.WithTags(ctx, "b", "c")
+
+	tags := usage.Tags(ctx)
+	if len(tags) != 3 || tags[0] != "a" || tags[2] != "c" {
+		t.Errorf("got tags %v, want [a b c]", tags)
+	}
+
+	if tags := usage.Tags(context.Background()); tags != nil {
+		t.Errorf("got tags %v, want nil", tags)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/JaimeStill/go-agents/pkg/agent"
+	"github.com/JaimeStill/go-agents/pkg/usage"
 	"github.com/JaimeStill/go-agents/tools/classify-docs/pkg/cache"
 	"github.com/JaimeStill/go-agents/tools/classify-docs/pkg/classify"
 	"github.com/JaimeStill/go-agents/tools/classify-docs/pkg/config"
@@ -44,14 +45,23 @@ func printUsage() {
 func runGeneratePrompt(args []string) {
 	fs := flag.NewFlagSet("generate-prompt", flag.ExitOnError)
 	configPath := fs.String("config", "config.classify-gpt4o-key.json", "Path to configuration file")
+	profile := fs.String("profile", "", "Agent profile to load from the config file (defaults --config to profiles.json)")
 	token := fs.String("token", "", "API token (overrides config)")
 	referencesPath := fs.String("references", "_context", "Directory containing reference PDFs")
 	noCache := fs.Bool("no-cache", false, "Disable cache usage")
 	timeout := fs.Duration("timeout", 30*time.Minute, "Operation timeout")
+	pricesPath := fs.String("prices", "", "Price table JSON file for usage cost estimates")
+	usagePath := fs.String("usage", "", "Write a JSON usage report to this file")
 
 	fs.Parse(args)
 
-	cfg, err := config.LoadClassifyConfig(*configPath)
+	tracker, err := newTracker(*pricesPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading prices: %v\n", err)
+		os.Exit(1)
+	}
+
+	cfg, err := loadConfig(fs, *configPath, *profile)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 		os.Exit(1)
@@ -72,12 +82,14 @@ func runGeneratePrompt(args []string) {
 	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
 	defer cancel()
 
-	result, err := prompt.Generate(ctx, *cfg, *referencesPath)
+	result, err := prompt.Generate(ctx, *cfg, *referencesPath, agent.WithUsage(tracker))
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error generating prompt: %v\n", err)
 		os.Exit(1)
 	}
 
+	reportUsage(tracker, *usagePath)
+
 	fmt.Println()
 	fmt.Println("---")
 	fmt.Println(result)
@@ -87,11 +99,14 @@ func runGeneratePrompt(args []string) {
 func runClassify(args []string) {
 	fs := flag.NewFlagSet("classify", flag.ExitOnError)
 	configPath := fs.String("config", "config.classify-o4-mini.json", "Path to configuration file")
+	profile := fs.String("profile", "", "Agent profile to load from the config file (defaults --config to profiles.json)")
 	token := fs.String("token", "", "API token (overrides config)")
 	inputDir := fs.String("input", "_context/marked-documents", "Directory containing PDF documents to classify")
 	outputFile := fs.String("output", "classification-results.json", "Output JSON file path")
 	systemPromptPath := fs.String("system-prompt", ".cache/system-prompt.json", "Path to cached system prompt")
 	timeout := fs.Duration("timeout", 15*time.Minute, "Operation timeout")
+	pricesPath := fs.String("prices", "", "Price table JSON file for usage cost estimates")
+	usagePath := fs.String("usage", "", "Write a JSON usage report to this file")
 
 	fs.Parse(args)
 
@@ -101,7 +116,7 @@ func runClassify(args []string) {
 		os.Exit(1)
 	}
 
-	cfg, err := config.LoadClassifyConfig(*configPath)
+	cfg, err := loadConfig(fs, *configPath, *profile)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 		os.Exit(1)
@@ -127,7 +142,13 @@ func runClassify(args []string) {
 
 	cfg.Agent.SystemPrompt = cached.SystemPrompt
 
-	a, err := agent.New(&cfg.Agent)
+	tracker, err := newTracker(*pricesPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading prices: %v\n", err)
+		os.Exit(1)
+	}
+
+	a, err := agent.New(&cfg.Agent, agent.WithUsage(tracker))
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error creating agent: %v\n", err)
 		os.Exit(1)
@@ -150,9 +171,78 @@ func runClassify(args []string) {
 	fmt.Fprintf(os.Stderr, "Classification complete!\n")
 	fmt.Fprintf(os.Stderr, "  Results saved: %s\n\n", *outputFile)
 
+	reportUsage(tracker, *usagePath)
+
 	outputJSON(results)
 }
 
+// loadConfig loads a classify configuration file, or the named profile from a
+// profile file when profile is set. When --config is not given explicitly,
+// profiles are read from profiles.json.
+func loadConfig(fs *flag.FlagSet, configPath, profile string) (*config.ClassifyConfig, error) {
+	if profile == "" {
+		return config.LoadClassifyConfig(configPath)
+	}
+
+	configSet := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "config" {
+			configSet = true
+		}
+	})
+	if !configSet {
+		configPath = "profiles.json"
+	}
+
+	return config.LoadClassifyProfile(configPath, profile)
+}
+
+// newTracker creates a usage tracker, pricing requests from the price table
+// file when one is given.
+func newTracker(pricesPath string) (*usage.Tracker, error) {
+	if pricesPath == "" {
+		return usage.NewTracker(), nil
+	}
+
+	prices, err := usage.LoadPriceTable(pricesPath)
+	if err != nil {
+		return nil, err
+	}
+	return usage.NewTracker(usage.WithPrices(prices)), nil
+}
+
+// reportUsage prints a usage summary and writes the full report to
+// usagePath when it is set.
+func reportUsage(tracker *usage.Tracker, usagePath string) {
+	total := tracker.Snapshot().Total
+	fmt.Fprintf(os.Stderr, "Usage: %d requests, %d prompt + %d completion tokens",
+		total.Requests, total.PromptTokens, total.CompletionTokens)
+	if total.ReasoningTokens > 0 {
+		fmt.Fprintf(os.Stderr, " (%d reasoning)", total.ReasoningTokens)
+	}
+	if total.Cost > 0 {
+		fmt.Fprintf(os.Stderr, ", $%.4f", total.Cost)
+	}
+	fmt.Fprintf(os.Stderr, "\n")
+
+	if usagePath == "" {
+		return
+	}
+
+	file, err := os.Create(usagePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing usage report: %v\n", err)
+		return
+	}
+	defer file.Close()
+
+	if err := tracker.Export(file); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing usage report: %v\n", err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "  Usage report: %s\n\n", usagePath)
+}
+
 func saveResults(outputFile string, results []classify.DocumentClassification) error {
 	data, err := json.MarshalIndent(results, "", "  ")
 	if err != nil {
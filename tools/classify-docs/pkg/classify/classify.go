@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 
 	"github.com/JaimeStill/go-agents/pkg/agent"
+	"github.com/JaimeStill/go-agents/pkg/usage"
 	"github.com/JaimeStill/go-agents/tools/classify-docs/pkg/config"
 )
 
@@ -41,7 +42,8 @@ func Classify(
 
 		fmt.Fprintf(os.Stderr, "Document %d/%d: %s\n", i+1, len(pdfs), filepath.Base(pdfPath))
 
-		result, err := ClassifyDocument(ctx, cfg, a, pdfPath)
+		docCtx := usage.WithTags(ctx, "document:"+filepath.Base(pdfPath))
+		result, err := ClassifyDocument(docCtx, cfg, a, pdfPath)
 		if err != nil {
 			return nil, fmt.Errorf("failed to classify %s: %w", filepath.Base(pdfPath), err)
 		}
@@ -3,7 +3,6 @@ package config
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 
 	acfg "github.com/JaimeStill/go-agents/pkg/config"
 )
@@ -21,9 +20,9 @@ func DefaultClassifyConfig() ClassifyConfig {
 }
 
 func LoadClassifyConfig(path string) (*ClassifyConfig, error) {
-	data, err := os.ReadFile(path)
+	data, err := acfg.ReadConfigFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+		return nil, err
 	}
 
 	cfg := DefaultClassifyConfig()
@@ -35,6 +34,43 @@ func LoadClassifyConfig(path string) (*ClassifyConfig, error) {
 
 	cfg.Merge(&fileConfig)
 
+	if err := acfg.ApplyEnv(&cfg.Agent, acfg.EnvPrefix); err != nil {
+		return nil, fmt.Errorf("failed to apply environment overrides: %w", err)
+	}
+
+	return &cfg, nil
+}
+
+// LoadClassifyProfile loads the named agent profile from a profile file
+// (see acfg.ProfileFile). Processing settings are read from the file's
+// top-level processing object.
+func LoadClassifyProfile(path, profile string) (*ClassifyConfig, error) {
+	data, err := acfg.ReadConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles, err := acfg.ParseProfiles(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	agentCfg, err := profiles.Get(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	var fileConfig struct {
+		Processing ProcessingConfig `json:"processing"`
+	}
+	if err := json.Unmarshal(data, &fileConfig); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	cfg := DefaultClassifyConfig()
+	cfg.Agent = *agentCfg
+	cfg.Processing.Merge(&fileConfig.Processing)
+
 	return &cfg, nil
 }
 
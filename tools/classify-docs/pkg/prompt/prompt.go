@@ -16,7 +16,9 @@ import (
 	"github.com/JaimeStill/go-agents/tools/classify-docs/pkg/processing"
 )
 
-func Generate(ctx context.Context, cfg config.ClassifyConfig, referencesPath string) (string, error) {
+// Generate builds a classification system prompt from the reference PDFs.
+// opts are applied to the agent that processes the reference pages.
+func Generate(ctx context.Context, cfg config.ClassifyConfig, referencesPath string, opts ...agent.Option) (string, error) {
 	if err := validateInputs(referencesPath); err != nil {
 		return "", err
 	}
@@ -56,7 +58,7 @@ func Generate(ctx context.Context, cfg config.ClassifyConfig, referencesPath str
 	fmt.Fprintf(os.Stderr, "\nGenerating system prompt from %d pages...\n", len(pages))
 
 	cfg.Agent.SystemPrompt = agentSystemPrompt()
-	a, err := agent.New(&cfg.Agent)
+	a, err := agent.New(&cfg.Agent, opts...)
 	if err != nil {
 		return "", fmt.Errorf("failed to create agent: %w", err)
 	}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	acfg "github.com/JaimeStill/go-agents/pkg/config"
 	"github.com/JaimeStill/go-agents/tools/classify-docs/pkg/config"
@@ -75,6 +76,54 @@ func TestLoadClassifyConfig_NonexistentFile(t *testing.T) {
 	}
 }
 
+func TestLoadClassifyProfile(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "profiles.json")
+
+	testConfig := `{
+		"providers": {
+			"azure-gpt-4o": {
+				"name": "azure",
+				"base_url": "https://example.openai.azure.com/openai",
+				"options": {"deployment": "gpt-4o", "auth_type": "api_key"}
+			}
+		},
+		"models": {
+			"gpt-4o": {"name": "gpt-4o", "capabilities": {"vision": {}}}
+		},
+		"agents": {
+			"gpt4o-key": {"provider": "azure-gpt-4o", "model": "gpt-4o"},
+			"gpt4o-entra": {"extends": "gpt4o-key", "provider": {"options": {"auth_type": "bearer"}}}
+		},
+		"processing": {
+			"cache": {"path": ".cache/profile.json"}
+		}
+	}`
+	if err := os.WriteFile(configPath, []byte(testConfig), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	loaded, err := config.LoadClassifyProfile(configPath, "gpt4o-entra")
+	if err != nil {
+		t.Fatalf("failed to load profile: %v", err)
+	}
+
+	if loaded.Agent.Name != "gpt4o-entra" {
+		t.Errorf("expected agent name 'gpt4o-entra', got %s", loaded.Agent.Name)
+	}
+
+	if loaded.Agent.Provider.Options["auth_type"] != "bearer" {
+		t.Errorf("expected auth_type 'bearer', got %v", loaded.Agent.Provider.Options["auth_type"])
+	}
+
+	if loaded.Processing.Cache.Path != ".cache/profile.json" {
+		t.Errorf("expected cache path '.cache/profile.json', got %s", loaded.Processing.Cache.Path)
+	}
+
+	if !loaded.Processing.Cache.IsEnabled() {
+		t.Error("expected cache to remain enabled by default")
+	}
+}
+
 func TestClassifyConfig_Merge(t *testing.T) {
 	base := config.DefaultClassifyConfig()
 	base.Agent.Name = "base-agent"
@@ -92,3 +141,36 @@ func TestClassifyConfig_Merge(t *testing.T) {
 		t.Errorf("expected merged name 'override-agent', got %s", base.Agent.Name)
 	}
 }
+
+func TestLoadClassifyConfig_YAML(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+
+	testConfig := `agent:
+  name: yaml-agent
+  client:
+    timeout: 45s
+processing:
+  cache:
+    enabled: false
+`
+	if err := os.WriteFile(configPath, []byte(testConfig), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	loaded, err := config.LoadClassifyConfig(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	if loaded.Agent.Name != "yaml-agent" {
+		t.Errorf("expected agent name 'yaml-agent', got %s", loaded.Agent.Name)
+	}
+
+	if loaded.Agent.Client.RequestTimeout() != 45*time.Second {
+		t.Errorf("expected timeout 45s, got %v", loaded.Agent.Client.RequestTimeout())
+	}
+
+	if loaded.Processing.Cache.IsEnabled() {
+		t.Error("expected cache to be disabled")
+	}
+}
@@ -17,9 +17,15 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "schema" {
+		runSchema(os.Args[2:])
+		return
+	}
+
 	var (
 		configFile   = flag.String("config", "config.json", "Configuration file to use")
-		protocol     = flag.String("protocol", "chat", "Protocol to use (chat, vision, tools, embeddings)")
+		profile      = flag.String("profile", "", "Agent profile to load from the config file (defaults -config to profiles.json)")
+		protocol     = flag.String("protocol", "chat", "Protocol to use (chat, vision, tools, embeddings, image_generation, transcription, speech, rerank, moderation)")
 		prompt       = flag.String("prompt", "", "Prompt to send to the agent")
 		systemPrompt = flag.String("system-prompt", "", "System prompt (overrides config)")
 		token        = flag.String("token", "", "Authentication token (overrides config)")
@@ -27,14 +33,17 @@ func main() {
 
 		images    = flag.String("images", "", "Comma-separated image URLs/paths (for vision)")
 		toolsFile = flag.String("tools-file", "", "JSON file containing tool definitions (for tools)")
+		audio     = flag.String("audio", "", "Audio file to transcribe (for transcription)")
+		output    = flag.String("output", "", "File to write synthesized audio to (for speech)")
+		documents = flag.String("documents", "", "File of documents to rank, one per line (for rerank)")
 	)
 	flag.Parse()
 
-	if *prompt == "" {
+	if *prompt == "" && *protocol != "transcription" {
 		log.Fatal("Error: -prompt flag is required")
 	}
 
-	cfg, err := config.LoadAgentConfig(*configFile)
+	cfg, err := loadConfig(*configFile, *profile)
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
@@ -50,12 +59,16 @@ func main() {
 		cfg.SystemPrompt = *systemPrompt
 	}
 
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid config: %v", err)
+	}
+
 	a, err := agent.New(cfg)
 	if err != nil {
 		log.Fatalf("Failed to create agent: %v", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), cfg.Client.Timeout.ToDuration())
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Client.RequestTimeout())
 	defer cancel()
 
 	switch *protocol {
@@ -87,11 +100,89 @@ func main() {
 		executeTools(ctx, a, *prompt, toolList)
 	case "embeddings":
 		executeEmbeddings(ctx, a, *prompt)
+	case "image_generation":
+		executeImageGeneration(ctx, a, *prompt)
+	case "transcription":
+		if *audio == "" {
+			log.Fatal("Error: -audio flag is required for transcription protocol")
+		}
+		executeTranscription(ctx, a, *audio, *prompt)
+	case "speech":
+		if *output == "" {
+			log.Fatal("Error: -output flag is required for speech protocol")
+		}
+		if *stream {
+			executeSpeechStream(ctx, a, *prompt, *output)
+		} else {
+			executeSpeech(ctx, a, *prompt, *output)
+		}
+	case "rerank":
+		if *documents == "" {
+			log.Fatal("Error: -documents flag is required for rerank protocol")
+		}
+		executeRerank(ctx, a, *prompt, loadDocuments(*documents))
+	case "moderation":
+		executeModeration(ctx, a, *prompt)
 	default:
 		log.Fatalf("Unknown protocol: %s", *protocol)
 	}
 }
 
+// runSchema writes the JSON Schema for agent configuration files,
+// or for profile files with -profiles.
+func runSchema(args []string) {
+	fs := flag.NewFlagSet("schema", flag.ExitOnError)
+	profiles := fs.Bool("profiles", false, "Emit the schema for profile files instead of agent configuration files")
+	output := fs.String("output", "", "File to write the schema to (default: stdout)")
+	fs.Parse(args)
+
+	schema := config.AgentConfigSchema()
+	if *profiles {
+		schema = config.ProfileFileSchema()
+	}
+
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		log.Fatalf("Failed to marshal schema: %v", err)
+	}
+	data = append(data, '\n')
+
+	if *output == "" {
+		os.Stdout.Write(data)
+		return
+	}
+
+	if err := os.WriteFile(*output, data, 0644); err != nil {
+		log.Fatalf("Failed to write schema: %v", err)
+	}
+}
+
+// loadConfig loads an agent configuration file, or the named profile from a
+// profile file when profile is set. When -config is not given explicitly,
+// profiles are read from profiles.json.
+func loadConfig(configFile, profile string) (*config.AgentConfig, error) {
+	if profile == "" {
+		return config.LoadAgentConfig(configFile)
+	}
+
+	configSet := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "config" {
+			configSet = true
+		}
+	})
+	if !configSet {
+		configFile = "profiles.json"
+	}
+
+	profiles, err := config.LoadProfiles(configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return profiles.Get(profile)
+}
+
 func executeChat(ctx context.Context, agent agent.Agent, prompt string) {
 	response, err := agent.Chat(ctx, prompt)
 	if err != nil {
@@ -105,6 +196,9 @@ func executeChat(ctx context.Context, agent agent.Agent, prompt string) {
 			response.Usage.CompletionTokens,
 			response.Usage.TotalTokens,
 		)
+		if reasoning := response.Usage.ReasoningTokens(); reasoning > 0 {
+			fmt.Printf(" (%d reasoning)", reasoning)
+		}
 	}
 }
 
@@ -136,6 +230,9 @@ func executeVision(ctx context.Context, agent agent.Agent, prompt string, images
 			response.Usage.CompletionTokens,
 			response.Usage.TotalTokens,
 		)
+		if reasoning := response.Usage.ReasoningTokens(); reasoning > 0 {
+			fmt.Printf("Reasoning tokens: %d\n", reasoning)
+		}
 	}
 }
 
@@ -165,8 +262,8 @@ func executeTools(ctx context.Context, agent agent.Agent, prompt string, tools [
 	if len(response.Choices) > 0 {
 		message := response.Choices[0].Message
 
-		if message.Content != "" {
-			fmt.Printf("Response: %s\n", message.Content)
+		if text := message.Text(); text != "" {
+			fmt.Printf("Response: %s\n", text)
 		}
 
 		if len(message.ToolCalls) > 0 {
@@ -263,6 +360,158 @@ func executeEmbeddings(ctx context.Context, agent agent.Agent, input string) {
 	}
 }
 
+func executeImageGeneration(ctx context.Context, agent agent.Agent, prompt string) {
+	response, err := agent.GenerateImage(ctx, prompt)
+	if err != nil {
+		log.Fatalf("Image generation failed: %v", err)
+	}
+
+	fmt.Printf("Generated %d image(s):\n\n", len(response.Data))
+
+	for i, image := range response.Data {
+		fmt.Printf("Image [%d]:\n", i)
+		if image.URL != "" {
+			fmt.Printf("  URL: %s\n", image.URL)
+		} else if image.B64JSON != "" {
+			fmt.Printf("  Base64: %d bytes\n", len(image.B64JSON))
+		}
+		if image.RevisedPrompt != "" {
+			fmt.Printf("  Revised Prompt: %s\n", image.RevisedPrompt)
+		}
+		fmt.Println()
+	}
+
+	if response.Usage != nil {
+		fmt.Printf("Token Usage: %d total\n", response.Usage.TotalTokens)
+	}
+}
+
+func executeTranscription(ctx context.Context, agent agent.Agent, filename, prompt string) {
+	audio, err := os.ReadFile(filename)
+	if err != nil {
+		log.Fatalf("Failed to read audio file: %v", err)
+	}
+
+	opts := map[string]any{}
+	if prompt != "" {
+		opts["prompt"] = prompt
+	}
+
+	response, err := agent.Transcribe(ctx, filename, audio, opts)
+	if err != nil {
+		log.Fatalf("Transcription failed: %v", err)
+	}
+
+	fmt.Println(response.Text)
+
+	if len(response.Segments) > 0 {
+		fmt.Println()
+		for _, segment := range response.Segments {
+			fmt.Printf("[%6.2fs - %6.2fs] %s\n", segment.Start, segment.End, segment.Text)
+		}
+	}
+
+	if response.Usage != nil {
+		fmt.Printf("\nToken Usage: %d total\n", response.Usage.TotalTokens)
+	}
+}
+
+func executeSpeech(ctx context.Context, agent agent.Agent, text, output string) {
+	response, err := agent.Speak(ctx, text)
+	if err != nil {
+		log.Fatalf("Speech failed: %v", err)
+	}
+
+	if err := os.WriteFile(output, response.Audio, 0644); err != nil {
+		log.Fatalf("Failed to write audio file: %v", err)
+	}
+
+	fmt.Printf("Wrote %d bytes of %s to %s\n", len(response.Audio), response.ContentType, output)
+}
+
+func executeSpeechStream(ctx context.Context, agent agent.Agent, text, output string) {
+	stream, err := agent.SpeakStream(ctx, text)
+	if err != nil {
+		log.Fatalf("Speech stream failed: %v", err)
+	}
+
+	file, err := os.Create(output)
+	if err != nil {
+		log.Fatalf("Failed to create audio file: %v", err)
+	}
+	defer file.Close()
+
+	total := 0
+	for chunk := range stream {
+		if chunk.Error != nil {
+			log.Fatalf("Stream error: %v", chunk.Error)
+		}
+		if _, err := file.Write(chunk.Audio); err != nil {
+			log.Fatalf("Failed to write audio file: %v", err)
+		}
+		total += len(chunk.Audio)
+		fmt.Printf("\rReceived %d bytes", total)
+	}
+
+	fmt.Printf("\nWrote %d bytes to %s\n", total, output)
+}
+
+func executeRerank(ctx context.Context, agent agent.Agent, query string, documents []string) {
+	response, err := agent.Rerank(ctx, query, documents)
+	if err != nil {
+		log.Fatalf("Rerank failed: %v", err)
+	}
+
+	fmt.Printf("Ranked %d document(s):\n\n", len(response.Results))
+
+	for i, result := range response.Results {
+		fmt.Printf("%d. [%.4f] %s\n", i+1, result.RelevanceScore, documents[result.Index])
+	}
+
+	if response.Usage != nil {
+		fmt.Printf("\nToken Usage: %d total\n", response.Usage.TotalTokens)
+	}
+}
+
+func executeModeration(ctx context.Context, agent agent.Agent, input string) {
+	response, err := agent.Moderate(ctx, input)
+	if err != nil {
+		log.Fatalf("Moderation failed: %v", err)
+	}
+
+	for _, result := range response.Results {
+		if !result.Flagged {
+			fmt.Println("Not flagged")
+			continue
+		}
+
+		fmt.Println("Flagged:")
+		for _, category := range result.FlaggedCategories() {
+			fmt.Printf("  %s", category)
+			if score, ok := result.CategoryScores[category]; ok {
+				fmt.Printf(" (%.4f)", score)
+			}
+			fmt.Println()
+		}
+	}
+}
+
+func loadDocuments(filename string) []string {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		log.Fatalf("Failed to read documents file: %v", err)
+	}
+
+	var documents []string
+	for line := range strings.Lines(string(data)) {
+		if line = strings.TrimSpace(line); line != "" {
+			documents = append(documents, line)
+		}
+	}
+
+	return documents
+}
+
 func loadTools(filename string) []agent.Tool {
 	data, err := os.ReadFile(filename)
 	if err != nil {